@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/scene"
+	"github.com/reonardoleis/fcg-glcraft/world/chunk"
+)
+
+// bench measures how long the generators take to create chunks, in a square
+// around the origin like the one loaded when a world starts
+func bench(args []string) int {
+	flags := newFlagSet("bench", "")
+	radius := flags.Int("radius", 4, "chunks gerados a partir da origem em cada direção")
+	rounds := flags.Int("rounds", 3, "vezes que o quadrado de chunks é gerado")
+	seed := flags.String("seed", "2300932812397", "semente dos mundos; texto é convertido")
+	generator := flags.String("generator", "", "mede só este gerador (padrão todos)")
+	var overrides overrideFlag
+	flags.Var(&overrides, "set", "muda um parâmetro do mundo, como world.cave_threshold=0.5; pode repetir")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	r := *radius
+
+	switch {
+	case flags.NArg() > 0:
+		return usageError(flags, "unexpected argument %q", flags.Arg(0))
+	case r < 0:
+		return usageError(flags, "-radius can't be negative, got %d", *radius)
+	case *rounds < 1:
+		return usageError(flags, "-rounds must be at least 1, got %d", *rounds)
+	case *generator != "" && !validGenerator(*generator):
+		return usageError(flags, "unknown generator %q, expected one of %v", *generator, chunk.Generators)
+	}
+
+	config := configs.DefaultWorld()
+	rest, err := overrides.applyWorld(&config)
+	if err != nil {
+		return usageError(flags, "%v", err)
+	}
+	if len(rest) > 0 {
+		return usageError(flags, "only world.* options can be set, got %q", rest[0].key)
+	}
+
+	generators := chunk.Generators
+	if *generator != "" {
+		generators = []string{*generator}
+	}
+
+	now := time.Now()
+	side := 2*r + 1
+	for _, name := range generators {
+		info := scene.NewWorldInfo("bench", scene.ParseSeed(*seed, now), name, config, now)
+		w := headlessWorld(info)
+
+		var total, slowest time.Duration
+		fastest := time.Duration(1<<63 - 1)
+		for round := 0; round < *rounds; round++ {
+			for i := -r; i <= r; i++ {
+				for j := -r; j <= r; j++ {
+					start := time.Now()
+					w.NewGeneratedChunk(i, j)
+					elapsed := time.Since(start)
+
+					total += elapsed
+					if elapsed > slowest {
+						slowest = elapsed
+					}
+					if elapsed < fastest {
+						fastest = elapsed
+					}
+				}
+			}
+		}
+
+		count := side * side * *rounds
+		average := total / time.Duration(count)
+		fmt.Printf("%-8s %5d chunks  %8.2f ms/chunk  %7.1f chunks/s  min %.2f ms  max %.2f ms\n",
+			name, count, milliseconds(average), float64(count)/total.Seconds(), milliseconds(fastest), milliseconds(slowest))
+	}
+
+	return exitOK
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
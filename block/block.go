@@ -3,6 +3,7 @@ package block
 import (
 	"fmt"
 	"image"
+	"image/color"
 	"image/draw"
 	"math"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/reonardoleis/fcg-glcraft/camera"
 	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/render"
 	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
 	"github.com/reonardoleis/fcg-glcraft/geometry"
 	math2 "github.com/reonardoleis/fcg-glcraft/math"
@@ -38,7 +40,6 @@ const (
 )
 
 var (
-	cubeVaoID         geometry.GeometryInformation
 	cubeModelMatrix          = math2.Matrix_Identity()
 	dirtTexture       uint32 = 0
 	grassSideTexture  uint32 = 0
@@ -50,11 +51,9 @@ var (
 	sandTexture       uint32 = 0
 	glassTexture      uint32 = 0
 	numTexturesLoaded        = 0
-	model_uniform     int32
 	redTexture        uint32 = 0
 	coalTexture       uint32 = 0
 	ironTexture       uint32 = 0
-	black             int32
 	lastTexture       uint32 = 0
 	northRotation            = math2.Matrix_Rotate_Y((math.Pi / 180) * 90)
 	southRotation            = math2.Matrix_Rotate_Y((math.Pi / 180) * 90)
@@ -77,6 +76,9 @@ type Block struct {
 
 	Neighbors [6]byte
 
+	Lighting    [6]FaceLighting // por face, na ordem de Neighbors
+	HasLighting bool            // falso até o chunk calcular Lighting
+
 	Colliding bool
 
 	WaterForce     byte // force of the water [8 - 1]
@@ -128,6 +130,95 @@ func getBlockTexture(blockType BlockType) []uint32 {
 	return []uint32{grassSideTexture, grassSideTexture, grassSideTexture, grassSideTexture, grassTopTexture, dirtTexture}
 }
 
+// ParticleTexture is the texture of the particles of a block, e.g. when it breaks
+func ParticleTexture(blockType BlockType) uint32 {
+	return getBlockTexture(blockType)[0]
+}
+
+// TypeName returns the name of a block type, for debugging
+func TypeName(blockType BlockType) string {
+	switch blockType {
+	case BlockGrass:
+		return "grass"
+	case BlockDirt:
+		return "dirt"
+	case BlockWood:
+		return "wood"
+	case BlockLeaves:
+		return "leaves"
+	case BlockSand:
+		return "sand"
+	case BlockStone:
+		return "stone"
+	case BlockWater:
+		return "water"
+	case BlockGlass:
+		return "glass"
+	case BlockAir:
+		return "air"
+	case BlockCoal:
+		return "coal"
+	case BlockIron:
+		return "iron"
+	}
+
+	return fmt.Sprintf("unknown (%d)", blockType)
+}
+
+// Types lists every block type, air included, in the order of the constants
+func Types() []BlockType {
+	types := []BlockType{}
+	for blockType := BlockType(BlockGrass); blockType <= BlockIron; blockType++ {
+		types = append(types, blockType)
+	}
+
+	return types
+}
+
+// TypeByName is the inverse of TypeName
+func TypeByName(name string) (BlockType, bool) {
+	for _, blockType := range Types() {
+		if TypeName(blockType) == name {
+			return blockType, true
+		}
+	}
+
+	return 0, false
+}
+
+// MapColor is the color of a block type seen from above, for the world maps
+func MapColor(blockType BlockType) color.RGBA {
+	switch blockType {
+	case BlockGrass:
+		return color.RGBA{95, 159, 53, 255}
+	case BlockDirt:
+		return color.RGBA{134, 96, 67, 255}
+	case BlockWood:
+		return color.RGBA{102, 81, 51, 255}
+	case BlockLeaves:
+		return color.RGBA{48, 110, 32, 255}
+	case BlockSand:
+		return color.RGBA{219, 207, 163, 255}
+	case BlockStone:
+		return color.RGBA{125, 125, 125, 255}
+	case BlockWater:
+		return color.RGBA{47, 84, 200, 255}
+	case BlockGlass:
+		return color.RGBA{200, 230, 235, 255}
+	case BlockCoal:
+		return color.RGBA{60, 60, 60, 255}
+	case BlockIron:
+		return color.RGBA{190, 160, 140, 255}
+	}
+
+	return color.RGBA{0, 0, 0, 0}
+}
+
+// IconTexture is the texture that shows a block type in the interface (its upper face)
+func IconTexture(blockType BlockType) uint32 {
+	return getBlockTexture(blockType)[4]
+}
+
 type BlockTexture = mgl32.Vec3
 
 func getTexture(blockType BlockType) BlockTexture {
@@ -160,10 +251,20 @@ func InitBlock() {
 	glassTexture = newTexture("glass_0.png")
 	coalTexture = newTexture("coal_0.png")
 	ironTexture = newTexture("iron_0.png")
+}
 
-	model_uniform = gl.GetUniformLocation(shaders.ShaderProgramDefault, gl.Str("model\000")) // Variável da matriz "model"
-	black = gl.GetUniformLocation(shaders.ShaderProgramDefault, gl.Str("black\000"))         // Variável da matriz "model"
+// Materiais das faces, um por textura, criados sob demanda
+var faceMaterials = map[uint32]*geometry.Material{}
 
+func faceMaterial(texture uint32) *geometry.Material {
+	material, ok := faceMaterials[texture]
+	if !ok {
+		material = geometry.NewMaterial(shaders.Get("standard"), shaders.ObjectBlock, texture)
+		material.Uniforms["black"] = false
+		faceMaterials[texture] = material
+	}
+
+	return material
 }
 
 func NewBlock(x, y, z, size float32, withEdges, ephemeral bool, blockType BlockType) Block {
@@ -246,8 +347,7 @@ func newTexture(file string) uint32 {
 	gl.TexImage2D(
 		gl.TEXTURE_2D,
 		0,
-
-		gl.RGBA,
+		gl.SRGB8_ALPHA8, // texturas em sRGB, amostradas já em espaço linear
 		int32(rgba.Rect.Size().X),
 		int32(rgba.Rect.Size().Y),
 		0,
@@ -281,99 +381,107 @@ func generateFaces(faces []mgl32.Vec4) []Face {
 	return facesArr
 }
 
+// Model matrix of a face, lowering and shrinking the faces of shallow water
+func (b Block) faceMatrix(face Face) mgl32.Mat4 {
+	faceMat := math2.Matrix_Identity()
+
+	yDiff := float32(0.0)
+
+	if b.BlockType == BlockWater && face.Index < 5 && b.Neighbors[4] == 0 && !b.HasWaterAbove {
+		yDiff = 1 - ((float32(b.WaterForce) / 8) * 0.8)
+		if face.Index < 4 {
+			yDiff *= 0.5
+		}
+	}
+
+	faceMat = faceMat.Mul4(math2.Matrix_Translate(face.Position.X(), face.Position.Y()-float32(yDiff), face.Position.Z())).Mul4(rotations[face.Index])
+
+	if b.BlockType == BlockWater && b.Neighbors[4] == 0 && !b.HasWaterAbove {
+		if face.Index < 4 {
+			faceMat = faceMat.Mul4(math2.Matrix_Scale(1.0, (float32(b.WaterForce)/8)*0.8, 1.0))
+		}
+	}
+
+	return faceMat
+}
+
+func (b Block) faces() []Face {
+	return generateFaces([]mgl32.Vec4{
+		math2.North(b.Position, float32(configs.BlockSize)),
+		math2.South(b.Position, float32(configs.BlockSize)),
+		math2.East(b.Position, float32(configs.BlockSize)),
+		math2.West(b.Position, float32(configs.BlockSize)),
+		math2.Upper(b.Position, float32(configs.BlockSize)),
+		math2.Lower(b.Position, float32(configs.BlockSize)),
+	})
+}
+
+// FaceMatrix is the model matrix of a face of the block, in Neighbors order
+func (b Block) FaceMatrix(face int) mgl32.Mat4 {
+	return b.faceMatrix(b.faces()[face])
+}
+
+// DrawDepth draws only the geometry of the visible faces with program, used
+// by depth passes such as the shadow maps
+func (b Block) DrawDepth(program *shaders.Program) {
+	for _, face := range b.faces() {
+		if b.Neighbors[face.Index] == 1 {
+			continue
+		}
+
+		geometry.FaceMesh.DrawWithProgram(program, b.faceMatrix(face))
+	}
+}
+
 // Main block drawing method. Handles everything such as getting drawable faces, loading textures
 // and drawing on screen.
 func (b Block) Draw2() {
 	//
 	blockTextures := getBlockTexture(b.BlockType)
-	object_id := gl.GetUniformLocation(shaders.ShaderProgramDefault, gl.Str("object_id\000")) // Variável da matriz "model"
-
-	north := math2.North(b.Position, float32(configs.BlockSize))
-	south := math2.South(b.Position, float32(configs.BlockSize))
-	east := math2.East(b.Position, float32(configs.BlockSize))
-	west := math2.West(b.Position, float32(configs.BlockSize))
-	upper := math2.Upper(b.Position, float32(configs.BlockSize))
-	lower := math2.Lower(b.Position, float32(configs.BlockSize))
-	faces := []mgl32.Vec4{north, south, east, west, upper, lower}
 
-	facesArr := generateFaces(faces)
+	facesArr := b.faces()
 
-	if b.Transparent {
+	if b.RenderPass() == render.PassTranslucent {
 		sort.SliceStable(facesArr, func(i, j int) bool {
 			return math2.Distance(camera.ActiveCamera.Position, facesArr[i].Position) >
 				math2.Distance(camera.ActiveCamera.Position, facesArr[j].Position)
 		})
 	}
 
-	gl.BindVertexArray(geometry.Faces[0].VaoID)
-	gl.Uniform1i(black, 0)
-	gl.Uniform1i(object_id, 0)
 	for _, face := range facesArr {
 		if b.Neighbors[face.Index] == 1 {
 			continue
 		}
 
 		if !BlockEdgesOnly {
-			if !b.Colliding {
-				gl.ActiveTexture(gl.TEXTURE0)
-				gl.BindTexture(gl.TEXTURE_2D, blockTextures[face.Index])
-			} else {
-				gl.ActiveTexture(gl.TEXTURE0)
-				gl.BindTexture(gl.TEXTURE_2D, redTexture)
+			material := faceMaterial(blockTextures[face.Index])
+			if b.Colliding {
+				material = faceMaterial(redTexture)
 			}
 
-			faceMat := math2.Matrix_Identity()
+			faceMat := b.faceMatrix(face)
 
-			yDiff := float32(0.0)
-
-			if b.BlockType == BlockWater && face.Index < 5 && b.Neighbors[4] == 0 && !b.HasWaterAbove {
-				yDiff = 1 - ((float32(b.WaterForce) / 8) * 0.8)
-				if face.Index < 4 {
-					yDiff *= 0.5
-				}
+			lighting := FullyLit
+			if b.HasLighting {
+				lighting = b.Lighting[face.Index]
 			}
+			geometry.SetFaceLighting(lighting.AO, lighting.Light)
 
-			faceMat = faceMat.Mul4(math2.Matrix_Translate(face.Position.X(), face.Position.Y()-float32(yDiff), face.Position.Z())).Mul4(rotations[face.Index])
-
-			if b.BlockType == BlockWater && b.Neighbors[4] == 0 && !b.HasWaterAbove {
-				if face.Index < 4 {
-					faceMat = faceMat.Mul4(math2.Matrix_Scale(1.0, (float32(b.WaterForce)/8)*0.8, 1.0))
-				}
+			faceMesh := geometry.FaceMesh
+			if lighting.Flip {
+				faceMesh = geometry.FlippedFaceMesh
 			}
-
-			gl.UniformMatrix4fv(model_uniform, 1, false, &faceMat[0])
-
-			gl.DrawElements(
-				uint32(geometry.Faces[0].RenderingMode), // Veja slides 124-130 do documento Aula_04_Modelagem_Geometrica_3D.pdf
-				int32(geometry.Faces[0].NumIndices),
-				gl.UNSIGNED_INT,
-				geometry.Faces[0].FirstIndex,
-			)
+			faceMesh.DrawWithMaterial(material, faceMat)
 
 			if b.WithEdges {
 
 				faceMat := math2.Matrix_Identity().Mul4(math2.Matrix_Translate(face.Position.X(), face.Position.Y(), face.Position.Z())).Mul4(rotations[face.Index])
-				gl.BindVertexArray(geometry.CommonFaceEdgeGeometry.VaoID)
-				gl.UniformMatrix4fv(model_uniform, 1, false, &faceMat[0])
-				gl.Uniform1i(black, 1)
-				gl.DrawElements(
-					uint32(geometry.CommonFaceEdgeGeometry.RenderingMode), // Veja slides 124-130 do documento Aula_04_Modelagem_Geometrica_3D.pdf
-					int32(geometry.CommonFaceEdgeGeometry.NumIndices),
-					gl.UNSIGNED_INT,
-					geometry.CommonFaceEdgeGeometry.FirstIndex,
-				)
+				geometry.FaceEdgesMesh.Draw(faceMat)
 			}
 		} else {
 			faceMat := math2.Matrix_Identity().Mul4(math2.Matrix_Translate(face.Position.X(), face.Position.Y(), face.Position.Z())).Mul4(rotations[face.Index])
-			gl.BindVertexArray(geometry.CommonFaceEdgeGeometry.VaoID)
-			gl.UniformMatrix4fv(model_uniform, 1, false, &faceMat[0])
-			// gl.Uniform1i(render_as_black_uniform, 1)
-			gl.DrawElements(
-				uint32(geometry.CommonFaceEdgeGeometry.RenderingMode), // Veja slides 124-130 do documento Aula_04_Modelagem_Geometrica_3D.pdf
-				int32(geometry.CommonFaceEdgeGeometry.NumIndices),
-				gl.UNSIGNED_INT,
-				geometry.CommonFaceEdgeGeometry.FirstIndex,
-			)
+			geometry.SetFaceLighting(FullyLit.AO, FullyLit.Light)
+			geometry.FaceEdgesMesh.DrawWithMaterial(faceMaterial(blockTextures[face.Index]), faceMat)
 		}
 
 	}
@@ -0,0 +1,21 @@
+package block
+
+// Hardness is how many seconds it takes to mine a block in survival
+func Hardness(blockType BlockType) float32 {
+	switch blockType {
+	case BlockLeaves:
+		return 0.3
+	case BlockGlass:
+		return 0.45
+	case BlockGrass, BlockDirt, BlockSand:
+		return 0.75
+	case BlockWood:
+		return 2.0
+	case BlockStone:
+		return 3.0
+	case BlockCoal, BlockIron:
+		return 4.0
+	}
+
+	return 1.0
+}
@@ -0,0 +1,143 @@
+package block
+
+// Oclusão ambiente e iluminação suave por vértice. Para cada canto de uma face
+// olhamos os 3 voxels vizinhos do canto (dois laterais e a diagonal) do lado de
+// fora da face, e a luz do canto é a média da luz desses voxels.
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// MaxAO is the ambient occlusion level of an unoccluded vertex
+const MaxAO = 3
+
+// Offsets (x, y, z) to the neighbor across each face, in Block.Neighbors order
+var FaceNormals = [6][3]int{
+	{1, 0, 0},  // north
+	{-1, 0, 0}, // south
+	{0, 0, 1},  // east
+	{0, 0, -1}, // west
+	{0, 1, 0},  // upper
+	{0, -1, 0}, // lower
+}
+
+// Cantos do quadrado de geometry.BuildFace, na ordem dos seus vértices
+var faceQuadCorners = [4]mgl32.Vec4{
+	{-0.5, 0.5, 0.0, 0.0},
+	{0.5, 0.5, 0.0, 0.0},
+	{0.5, -0.5, 0.0, 0.0},
+	{-0.5, -0.5, 0.0, 0.0},
+}
+
+// Para cada face, as duas direções tangentes de cada canto já rotacionadas
+var faceCornerTangents = computeFaceCornerTangents()
+
+func computeFaceCornerTangents() [6][4][2][3]int {
+	var tangents [6][4][2][3]int
+	for face := range FaceNormals {
+		for corner, local := range faceQuadCorners {
+			rotated := rotations[face].Mul4x1(local)
+
+			axis := 0
+			for component := 0; component < 3; component++ {
+				if math.Abs(float64(rotated[component])) < 0.25 || axis > 1 {
+					continue
+				}
+				if rotated[component] > 0 {
+					tangents[face][corner][axis][component] = 1
+				} else {
+					tangents[face][corner][axis][component] = -1
+				}
+				axis++
+			}
+		}
+	}
+
+	return tangents
+}
+
+// VertexAO returns the occlusion level (0 to MaxAO, MaxAO being unoccluded) of
+// a face vertex given which of its two side voxels and its corner voxel are
+// solid. Two solid sides fully occlude the vertex, whatever the corner is.
+func VertexAO(side1, side2, corner bool) int {
+	if side1 && side2 {
+		return 0
+	}
+
+	return MaxAO - boolToInt(side1) - boolToInt(side2) - boolToInt(corner)
+}
+
+// FaceLighting holds the per-vertex values of one face, in the vertex order
+// of geometry.BuildFace
+type FaceLighting struct {
+	AO    [4]float32 // oclusão em [0, 1], 1 é sem oclusão
+	Light [4]float32 // luz suavizada em [0, 1]
+	Flip  bool       // dividir o quadrado pela diagonal 0-2 em vez da 1-3
+}
+
+// FullyLit is the lighting of a face with no occlusion and full light
+var FullyLit = FaceLighting{
+	AO:    [4]float32{1, 1, 1, 1},
+	Light: [4]float32{1, 1, 1, 1},
+}
+
+// Shade is the final brightness of a vertex
+func (fl FaceLighting) Shade(vertex int) float32 {
+	return fl.AO[vertex] * fl.Light[vertex]
+}
+
+// ComputeFaceLighting computes the lighting of a face of the block at the
+// origin. solid and light are queried with offsets from the block. The quad
+// is always split along its darker diagonal, so a single occluded corner
+// fades the same way whatever the orientation of the face (otherwise the
+// occlusion looks anisotropic).
+func ComputeFaceLighting(face int, solid func(dx, dy, dz int) bool, light func(dx, dy, dz int) float32) FaceLighting {
+	var fl FaceLighting
+	normal := FaceNormals[face]
+
+	at := func(offsets ...[3]int) [3]int {
+		sum := normal
+		for _, offset := range offsets {
+			for axis := range sum {
+				sum[axis] += offset[axis]
+			}
+		}
+		return sum
+	}
+	isSolid := func(p [3]int) bool {
+		return solid(p[0], p[1], p[2])
+	}
+
+	for corner, tangents := range faceCornerTangents[face] {
+		side1, side2, diagonal := at(tangents[0]), at(tangents[1]), at(tangents[0], tangents[1])
+		side1Solid, side2Solid := isSolid(side1), isSolid(side2)
+		// com os dois lados sólidos a luz não chega pela diagonal
+		diagonalSolid := (side1Solid && side2Solid) || isSolid(diagonal)
+
+		fl.AO[corner] = float32(VertexAO(side1Solid, side2Solid, diagonalSolid)) / MaxAO
+
+		total, count := light(normal[0], normal[1], normal[2]), float32(1)
+		for i, p := range [][3]int{side1, side2, diagonal} {
+			if [3]bool{side1Solid, side2Solid, diagonalSolid}[i] {
+				continue
+			}
+			total += light(p[0], p[1], p[2])
+			count++
+		}
+		fl.Light[corner] = total / count
+	}
+
+	fl.Flip = fl.Shade(0)+fl.Shade(2) < fl.Shade(1)+fl.Shade(3)
+
+	return fl
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
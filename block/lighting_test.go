@@ -0,0 +1,177 @@
+package block
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+const faceUpper = 4
+
+func TestVertexAO(t *testing.T) {
+	tests := []struct {
+		side1, side2, corner bool
+		want                 int
+	}{
+		{false, false, false, 3},
+		{false, false, true, 2},
+		{true, false, false, 2},
+		{false, true, false, 2},
+		{true, false, true, 1},
+		{false, true, true, 1},
+		{true, true, false, 0},
+		{true, true, true, 0},
+	}
+	for _, test := range tests {
+		if got := VertexAO(test.side1, test.side2, test.corner); got != test.want {
+			t.Errorf("VertexAO(%v, %v, %v) = %d, want %d", test.side1, test.side2, test.corner, got, test.want)
+		}
+	}
+}
+
+func TestFaceCornerTangents(t *testing.T) {
+	for face, normal := range FaceNormals {
+		corners := map[[3]int]bool{}
+		for corner, tangents := range faceCornerTangents[face] {
+			var sum [3]int
+			for _, tangent := range tangents {
+				length, dot := 0, 0
+				for axis := range tangent {
+					length += tangent[axis] * tangent[axis]
+					dot += tangent[axis] * normal[axis]
+					sum[axis] += tangent[axis]
+				}
+				if length != 1 || dot != 0 {
+					t.Errorf("face %d corner %d: tangent %v isn't a unit axis along the face", face, corner, tangent)
+				}
+			}
+			if tangents[0] == tangents[1] {
+				t.Errorf("face %d corner %d: both tangents are %v", face, corner, tangents[0])
+			}
+			corners[sum] = true
+		}
+		if len(corners) != 4 {
+			t.Errorf("face %d: corners point to %d distinct directions, want 4", face, len(corners))
+		}
+	}
+}
+
+// Índice do canto da face cujas tangentes somam direction
+func cornerTowards(t *testing.T, face int, direction [3]int) int {
+	t.Helper()
+
+	for corner, tangents := range faceCornerTangents[face] {
+		var sum [3]int
+		for axis := range sum {
+			sum[axis] = tangents[0][axis] + tangents[1][axis]
+		}
+		if sum == direction {
+			return corner
+		}
+	}
+
+	t.Fatalf("face %d has no corner towards %v", face, direction)
+	return -1
+}
+
+// Vizinhos sólidos, com coordenadas relativas ao bloco
+func solidAt(cells ...[3]int) func(dx, dy, dz int) bool {
+	return func(dx, dy, dz int) bool {
+		for _, cell := range cells {
+			if cell == [3]int{dx, dy, dz} {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func fullLight(dx, dy, dz int) float32 { return 1 }
+
+func sortedAO(fl FaceLighting) []float32 {
+	ao := append([]float32{}, fl.AO[:]...)
+	sort.Slice(ao, func(i, j int) bool { return ao[i] < ao[j] })
+	return ao
+}
+
+func closeTo(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-5
+}
+
+func TestComputeFaceLightingAO(t *testing.T) {
+	third := float32(1) / 3
+
+	tests := []struct {
+		name   string
+		solid  [][3]int
+		sorted []float32 // AO dos 4 cantos, em ordem crescente
+	}{
+		{"open", nil, []float32{1, 1, 1, 1}},
+		{"one diagonal", [][3]int{{1, 1, 1}}, []float32{2 * third, 1, 1, 1}},
+		{"one side", [][3]int{{1, 1, 0}}, []float32{2 * third, 2 * third, 1, 1}},
+		{"inner corner", [][3]int{{1, 1, 0}, {0, 1, 1}}, []float32{0, 2 * third, 2 * third, 1}},
+		{"side and diagonal", [][3]int{{1, 1, 0}, {1, 1, 1}}, []float32{third, 2 * third, 1, 1}},
+		{"step below is ignored", [][3]int{{1, 0, 0}, {1, -1, 1}}, []float32{1, 1, 1, 1}},
+		{"surrounded", [][3]int{
+			{1, 1, 0}, {-1, 1, 0}, {0, 1, 1}, {0, 1, -1},
+			{1, 1, 1}, {1, 1, -1}, {-1, 1, 1}, {-1, 1, -1},
+		}, []float32{0, 0, 0, 0}},
+	}
+	for _, test := range tests {
+		fl := ComputeFaceLighting(faceUpper, solidAt(test.solid...), fullLight)
+		got := sortedAO(fl)
+		for i := range got {
+			if !closeTo(got[i], test.sorted[i]) {
+				t.Errorf("%s: AO = %v, want %v in some order", test.name, fl.AO, test.sorted)
+				break
+			}
+		}
+	}
+}
+
+func TestComputeFaceLightingCorner(t *testing.T) {
+	// dois lados sólidos escurecem totalmente o canto entre eles
+	fl := ComputeFaceLighting(faceUpper, solidAt([3]int{1, 1, 0}, [3]int{0, 1, 1}), fullLight)
+	if corner := cornerTowards(t, faceUpper, [3]int{1, 0, 1}); fl.AO[corner] != 0 {
+		t.Errorf("inner corner AO = %v, want 0", fl.AO[corner])
+	}
+	if corner := cornerTowards(t, faceUpper, [3]int{-1, 0, -1}); fl.AO[corner] != 1 {
+		t.Errorf("opposite corner AO = %v, want 1", fl.AO[corner])
+	}
+}
+
+func TestComputeFaceLightingFlip(t *testing.T) {
+	// o quadrado é dividido pela diagonal que passa pelo canto escuro
+	for _, direction := range [][3]int{{1, 0, 1}, {1, 0, -1}, {-1, 0, 1}, {-1, 0, -1}} {
+		fl := ComputeFaceLighting(faceUpper, solidAt([3]int{direction[0], 1, direction[2]}), fullLight)
+		corner := cornerTowards(t, faceUpper, direction)
+		if want := corner == 0 || corner == 2; fl.Flip != want {
+			t.Errorf("occluded corner %d: Flip = %v, want %v", corner, fl.Flip, want)
+		}
+	}
+}
+
+func TestComputeFaceLightingSmoothLight(t *testing.T) {
+	// luz 1 só acima do bloco e 0.25 em volta: cada canto faz a média dos 4 voxels
+	light := func(dx, dy, dz int) float32 {
+		if dx == 0 && dz == 0 {
+			return 1
+		}
+		return 0.25
+	}
+	fl := ComputeFaceLighting(faceUpper, solidAt(), light)
+	for corner, value := range fl.Light {
+		if !closeTo(value, (1+3*0.25)/4) {
+			t.Errorf("corner %d light = %v, want %v", corner, value, (1+3*0.25)/4)
+		}
+	}
+
+	// voxels sólidos não entram na média
+	fl = ComputeFaceLighting(faceUpper, solidAt([3]int{1, 1, 0}, [3]int{0, 1, 1}), light)
+	if corner := cornerTowards(t, faceUpper, [3]int{1, 0, 1}); !closeTo(fl.Light[corner], 1) {
+		t.Errorf("inner corner light = %v, want only the voxel above the face", fl.Light[corner])
+	}
+	if got := fl.Shade(0); !closeTo(got, fl.AO[0]*fl.Light[0]) {
+		t.Errorf("Shade(0) = %v, want AO * light", got)
+	}
+}
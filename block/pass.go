@@ -0,0 +1,33 @@
+package block
+
+import "github.com/reonardoleis/fcg-glcraft/engine/render"
+
+// RenderPass returns the pass the block is drawn in
+func (b Block) RenderPass() render.Pass {
+	switch b.BlockType {
+	case BlockLeaves:
+		return render.PassCutout
+	case BlockWater, BlockGlass:
+		return render.PassTranslucent
+	}
+
+	return render.PassOpaque
+}
+
+// HidesInternalFace tells if the face of b towards neighbor (an index of
+// Neighbors) is hidden because both are the same translucent block. A side of
+// a water block is only hidden by water at least as high.
+func HidesInternalFace(b, neighbor *Block, face int) bool {
+	if b.BlockType != neighbor.BlockType {
+		return false
+	}
+
+	switch b.BlockType {
+	case BlockGlass:
+		return true
+	case BlockWater:
+		return FaceNormals[face][1] != 0 || neighbor.WaterForce >= b.WaterForce
+	}
+
+	return false
+}
@@ -0,0 +1,69 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/reonardoleis/fcg-glcraft/engine/render"
+)
+
+// Índices de Neighbors, na ordem de FaceNormals
+const (
+	faceNorth = 0
+	faceWest  = 3
+	faceLower = 5
+)
+
+func TestRenderPass(t *testing.T) {
+	tests := []struct {
+		blockType BlockType
+		want      render.Pass
+	}{
+		{BlockStone, render.PassOpaque},
+		{BlockGrass, render.PassOpaque},
+		{BlockSand, render.PassOpaque},
+		{BlockLeaves, render.PassCutout},
+		{BlockWater, render.PassTranslucent},
+		{BlockGlass, render.PassTranslucent},
+	}
+
+	for _, test := range tests {
+		b := Block{BlockType: test.blockType}
+		if got := b.RenderPass(); got != test.want {
+			t.Errorf("RenderPass() of type %d = %d, want %d", test.blockType, got, test.want)
+		}
+	}
+}
+
+func TestHidesInternalFace(t *testing.T) {
+	water := func(force byte) *Block {
+		return &Block{BlockType: BlockWater, WaterForce: force}
+	}
+
+	tests := []struct {
+		name     string
+		b        *Block
+		neighbor *Block
+		face     int
+		want     bool
+	}{
+		{"vidro com vidro", &Block{BlockType: BlockGlass}, &Block{BlockType: BlockGlass}, faceNorth, true},
+		{"vidro com vidro em cima", &Block{BlockType: BlockGlass}, &Block{BlockType: BlockGlass}, faceUpper, true},
+		{"vidro com água", &Block{BlockType: BlockGlass}, water(8), faceNorth, false},
+		{"água com vidro", water(8), &Block{BlockType: BlockGlass}, faceNorth, false},
+		{"água com água da mesma força", water(8), water(8), faceWest, true},
+		{"água com água mais forte", water(3), water(8), faceNorth, true},
+		// o lado da água mais fraca é mais baixo e deixa o da mais forte à mostra
+		{"água com água mais fraca", water(8), water(3), faceNorth, false},
+		{"água mais fraca embaixo", water(8), water(3), faceLower, true},
+		{"água mais fraca em cima", water(8), water(1), faceUpper, true},
+		{"folhas com folhas", &Block{BlockType: BlockLeaves}, &Block{BlockType: BlockLeaves}, faceNorth, false},
+		{"pedra com pedra", &Block{BlockType: BlockStone}, &Block{BlockType: BlockStone}, faceNorth, false},
+		{"pedra com vidro", &Block{BlockType: BlockStone}, &Block{BlockType: BlockGlass}, faceNorth, false},
+	}
+
+	for _, test := range tests {
+		if got := HidesInternalFace(test.b, test.neighbor, test.face); got != test.want {
+			t.Errorf("%s: HidesInternalFace(face %d) = %v, want %v", test.name, test.face, got, test.want)
+		}
+	}
+}
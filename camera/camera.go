@@ -3,8 +3,8 @@ package camera
 import (
 	"math"
 
-	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/configs"
 	"github.com/reonardoleis/fcg-glcraft/engine/controls"
 	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
 	"github.com/reonardoleis/fcg-glcraft/engine/window"
@@ -47,7 +47,7 @@ func NewCamera(cameraPosition mgl32.Vec4, controlHandler controls.Controls, fov
 		CameraDistance: 2.5,
 		Fov:            fov,
 		Near:           0.1,
-		Far:            40.0,
+		Far:            float32(configs.Video.LODViewDistance * configs.ChunkSize),
 		CameraTheta:    0.0,
 		CameraPhi:      0.0,
 		Type:           cameraType,
@@ -59,80 +59,51 @@ func NewCamera(cameraPosition mgl32.Vec4, controlHandler controls.Controls, fov
 	return newCamera
 }
 
-type Frustum struct {
-	Ftl mgl32.Vec4
-	Ftr mgl32.Vec4
-	Fbl mgl32.Vec4
-	Fbr mgl32.Vec4
-	Ntl mgl32.Vec4
-	Ntr mgl32.Vec4
-	Nbl mgl32.Vec4
-	Nbr mgl32.Vec4
+// ViewMatrix returns the world to camera transform
+func (c *Camera) ViewMatrix() mgl32.Mat4 {
+	return math2.Matrix_Camera_View(c.Position, c.ViewVector, c.UpVector)
 }
 
-// get the camera frustum
-func (c *Camera) GetFrustum() Frustum {
-	vv := c.ViewVector
-	/* float a = cam.nearClipPlane;//get length
-	   float A = cam.fieldOfView * 0.5f;//get angle
-	   A = A * Mathf.Deg2Rad;//convert tor radians
-	   float h = (Mathf.Tan(A) * a);//calc height
-	   float w = (h / cam.pixelHeight) * cam.pixelWidth;//deduct width
-	*/
-
-	Hfar := 2 * math.Tan(float64(c.Fov)/2) * float64(c.Far)
-	Hnear := 2 * math.Tan(float64(c.Fov)/2) * float64(c.Near)
-
-	Wnear := (Hnear / 720) * 1280
-	Wfar := (Hfar / 720) * 1280
-
-	fc := c.Position.Add(vv.Mul(c.Far))
-
-	_, u := c.GetWU()
-
-	ftl := fc.Add(c.UpVector.Mul(float32(Hfar) / 2)).Sub(u.Mul(float32(Wfar) / 2))
-	ftr := fc.Add(c.UpVector.Mul(float32(Hfar) / 2)).Add(u.Mul(float32(Wfar) / 2))
-	fbl := fc.Sub(c.UpVector.Mul(float32(Hfar) / 2)).Sub(u.Mul(float32(Wfar) / 2))
-	fbr := fc.Sub(c.UpVector.Mul(float32(Hfar) / 2)).Add(u.Mul(float32(Wfar) / 2))
-
-	nc := c.Position.Add(vv.Mul(c.Near))
-
-	ntl := nc.Add(c.UpVector.Mul(float32(Hnear) / 2)).Sub(u.Mul(float32(Wnear) / 2))
-	ntr := nc.Add(c.UpVector.Mul(float32(Hnear) / 2)).Add(u.Mul(float32(Wnear) / 2))
-	nbl := nc.Sub(c.UpVector.Mul(float32(Hnear) / 2)).Sub(u.Mul(float32(Wnear) / 2))
-	nbr := nc.Sub(c.UpVector.Mul(float32(Hnear) / 2)).Add(u.Mul(float32(Wnear) / 2))
-
-	return Frustum{
-		ftl,
-		ftr,
-		fbl,
-		fbr,
-		ntl,
-		ntr,
-		nbl,
-		nbr,
+// Aspect is the width / height ratio of the window
+func (c *Camera) Aspect() float32 {
+	if window.ScreenRatio <= 0 {
+		return 1
 	}
+
+	return float32(window.ScreenRatio)
 }
 
-// Handle mouse movement to camera positions
-func (c *Camera) HandleDeltas() {
-	if c.ControlHandler.MousePositionChanged() {
-		dx, dy := c.ControlHandler.GetMouseDeltas()
-		c.CameraTheta -= 0.01 * dx
-		c.CameraPhi -= 0.01 * dy
+// ProjectionMatrix returns the perspective projection, using the aspect ratio of the window
+func (c *Camera) ProjectionMatrix() mgl32.Mat4 {
+	return math2.Matrix_Perspective(c.Fov, c.Aspect(), -c.Near, -c.Far)
+}
 
-		phiMax := math.Pi / 2
-		phiMin := -phiMax
+// get the camera frustum
+func (c *Camera) GetFrustum() math2.Frustum {
+	return math2.NewFrustum(c.ProjectionMatrix().Mul4(c.ViewMatrix()))
+}
 
-		if c.CameraPhi > phiMax {
-			c.CameraPhi = phiMax
-		}
+// Turns the camera with the mouse, once per tick. The camera angles are the
+// only record of where the player looks.
+func (c *Camera) Turn() {
+	yaw, pitch := c.ControlHandler.LookRotation()
+	c.CameraTheta += yaw
+	c.CameraPhi += pitch
 
-		if c.CameraPhi < phiMin {
-			c.CameraPhi = phiMin
-		}
+	phiMax := math.Pi / 2
+	phiMin := -phiMax
+
+	if c.CameraPhi > phiMax {
+		c.CameraPhi = phiMax
+	}
+
+	if c.CameraPhi < phiMin {
+		c.CameraPhi = phiMin
 	}
+}
 
+// Computes the view vector from the camera angles
+func (c *Camera) updateViewVector() {
 	r := float32(c.CameraDistance)
 	vx := r * float32(math.Cos(float64(c.CameraPhi))*math.Sin(float64(c.CameraTheta)))
 	vy := r * float32(math.Sin(float64(c.CameraPhi)))
@@ -142,20 +113,10 @@ func (c *Camera) HandleDeltas() {
 	c.UpVector = mgl32.Vec4{0.0, 1.0, 0.0, 0.0}
 }
 
+// Updates the camera direction, after Turn. It doesn't touch the GPU, so it
+// also runs without a window.
 func (c *Camera) Update() {
-	switch c.Type {
-	case FirstPersonCamera:
-		c.HandleDeltas()
-	default:
-		c.HandleDeltas()
-	}
-	c.Handle()
-}
-
-// Handle camera, basically a camera Update method. Compute all needed matrixes and sends to the GPU
-func (c *Camera) Handle() {
-	viewUniform := gl.GetUniformLocation(shaders.ShaderProgramDefault, gl.Str("view\000"))             // Variável da matriz "view" em shader_vertex.glsl
-	projectionUniform := gl.GetUniformLocation(shaders.ShaderProgramDefault, gl.Str("projection\000")) // Variável da matriz "projection" em shader_vertex.glsl
+	c.updateViewVector()
 
 	if c.IsLookAt {
 
@@ -165,17 +126,16 @@ func (c *Camera) Handle() {
 
 		c.ViewVector = cameraLookatL.Sub(cameraPositionC)
 	}
+}
 
-	c.view = math2.Matrix_Camera_View(c.Position, c.ViewVector, c.UpVector)
-
-	nearplane := -c.Near
-	farplane := -c.Far
-
-	fov := c.Fov
-	c.projection = math2.Matrix_Perspective(fov, float32(window.ScreenRatio), nearplane, farplane)
+// Handle camera, computes all needed matrixes and sends them to the GPU
+func (c *Camera) Handle() {
+	c.view = c.ViewMatrix()
+	c.projection = c.ProjectionMatrix()
 
-	gl.UniformMatrix4fv(viewUniform, 1, false, &c.view[0])
-	gl.UniformMatrix4fv(projectionUniform, 1, false, &c.projection[0])
+	program := shaders.Get("standard")
+	program.SetMat4("view", c.view)             // Variável da matriz "view" em shader_vertex.glsl
+	program.SetMat4("projection", c.projection) // Variável da matriz "projection" em shader_vertex.glsl
 }
 
 func (c *Camera) SetPosition(position mgl32.Vec4) {
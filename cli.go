@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/world/chunk"
+)
+
+// Códigos de saída dos subcomandos
+const (
+	exitOK       = 0
+	exitError    = 1 // o comando falhou
+	exitUsage    = 2 // argumentos inválidos, como no pacote flag
+	exitMismatch = 3 // o replay não terminou no estado gravado
+)
+
+// A command is one of the subcommands of the executable. Run receives the
+// arguments after the name and returns the exit code.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+var commands = []command{
+	{"play", "abre o jogo, opcionalmente direto num mundo", play},
+	{"new-world", "cria um mundo sem abrir o jogo", newWorld},
+	{"info", "mostra as informações dos mundos salvos", info},
+	{"render-map", "desenha o mapa visto de cima de um mundo num PNG", renderMap},
+	{"bench", "mede o tempo de geração dos chunks", bench},
+	{"replay", "reproduz uma gravação sem janela e confere o estado final", verifyReplay},
+}
+
+// Runs the subcommand named by the first argument. Without one, or with only
+// flags, the game is played as before the subcommands existed.
+func run(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && !isHelp(args[0]) {
+		return play(args)
+	}
+
+	if isHelp(args[0]) || args[0] == "help" {
+		usage(os.Stdout)
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+	usage(os.Stderr)
+	return exitUsage
+}
+
+func isHelp(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+func usage(out *os.File) {
+	fmt.Fprintf(out, "usage: %s <command> [flags]\n\ncommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-11s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(out, "\nrun %s <command> -h for the flags of a command\n", os.Args[0])
+}
+
+// Cria o FlagSet de um subcomando; os erros são tratados por parseFlags
+func newFlagSet(name, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: %s %s [flags] %s\n", os.Args[0], name, arguments)
+		flags.PrintDefaults()
+	}
+
+	return flags
+}
+
+// Lê as flags de um subcomando. ok é false se o comando deve terminar com code.
+func parseFlags(flags *flag.FlagSet, args []string) (code int, ok bool) {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+		return exitUsage, false
+	}
+
+	return exitOK, true
+}
+
+// Termina um subcomando com erro de uso, mostrando as flags dele
+func usageError(flags *flag.FlagSet, format string, a ...interface{}) int {
+	fmt.Fprintf(flags.Output(), format+"\n", a...)
+	flags.Usage()
+	return exitUsage
+}
+
+func validGenerator(generator string) bool {
+	for _, known := range chunk.Generators {
+		if known == generator {
+			return true
+		}
+	}
+
+	return false
+}
+
+// -set chave=valor, repetido para cada configuração
+type overrideFlag []struct{ key, value string }
+
+func (o *overrideFlag) String() string {
+	return fmt.Sprint(*o)
+}
+
+func (o *overrideFlag) Set(text string) error {
+	key, value, ok := strings.Cut(text, "=")
+	if !ok {
+		return fmt.Errorf("expected key=value, got %q", text)
+	}
+
+	*o = append(*o, struct{ key, value string }{strings.TrimSpace(key), strings.TrimSpace(value)})
+	return nil
+}
+
+// Aplica as opções world.* de -set à configuração dos mundos novos e devolve
+// as demais, que são do arquivo de configurações
+func (o overrideFlag) applyWorld(config *configs.WorldConfig) (overrideFlag, error) {
+	var rest overrideFlag
+	for _, override := range o {
+		if !strings.HasPrefix(override.key, "world.") {
+			rest = append(rest, override)
+			continue
+		}
+
+		if err := configs.Set(config, strings.TrimPrefix(override.key, "world."), override.value); err != nil {
+			return nil, err
+		}
+	}
+
+	return rest, config.Validate()
+}
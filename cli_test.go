@@ -0,0 +1,136 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/reonardoleis/fcg-glcraft/configs"
+)
+
+// Descarta o que os comandos escrevem no terminal até o fim do teste
+func quiet(t *testing.T) {
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = null, null
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		null.Close()
+	})
+}
+
+func TestRun(t *testing.T) {
+	quiet(t)
+	defer func(world configs.WorldConfig) { configs.World = world }(configs.World)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"comando desconhecido", []string{"fly"}, exitUsage},
+		{"ajuda", []string{"-h"}, exitOK},
+		{"ajuda longa", []string{"--help"}, exitOK},
+		{"help", []string{"help"}, exitOK},
+		{"ajuda de um comando", []string{"bench", "-h"}, exitOK},
+		{"flag desconhecida", []string{"bench", "-fast"}, exitUsage},
+		{"argumento sobrando", []string{"bench", "extra"}, exitUsage},
+		{"raio negativo", []string{"bench", "-radius", "-1"}, exitUsage},
+		{"gerador desconhecido", []string{"bench", "-generator", "islands"}, exitUsage},
+		{"-set sem valor", []string{"bench", "-set", "world.cave_threshold"}, exitUsage},
+		{"-set com valor do tipo errado", []string{"bench", "-set", "world.cave_threshold=deep"}, exitUsage},
+		{"-set com valor fora do intervalo", []string{"bench", "-set", "world.cave_threshold=2"}, exitUsage},
+		{"-set de opção desconhecida", []string{"bench", "-set", "world.gravity=2"}, exitUsage},
+		{"bench recusa opções fora de world", []string{"bench", "-set", "video.view_distance=2"}, exitUsage},
+		{"new-world recusa opções fora de world", []string{"new-world", "-dir", dir, "-set", "video.view_distance=2", "a"}, exitUsage},
+		{"new-world sem nome", []string{"new-world", "-dir", dir}, exitUsage},
+		{
+			name: "bench",
+			args: []string{"bench", "-radius", "0", "-rounds", "1", "-generator", "flat", "-set", "world.cave_threshold=0.5"},
+			want: exitOK,
+		},
+		{"new-world", []string{"new-world", "-dir", dir, "-set", "world.tick_rate=1", "a"}, exitOK},
+		{"new-world de um mundo que já existe", []string{"new-world", "-dir", dir, "a"}, exitError},
+	}
+
+	for _, test := range tests {
+		if got := run(test.args); got != test.want {
+			t.Errorf("%s: run(%q) = %d, want %d", test.name, test.args, got, test.want)
+		}
+	}
+}
+
+func TestApplyWorld(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      []string
+		want     func(c *configs.WorldConfig)
+		wantRest []string
+		wantErr  string
+	}{
+		{name: "nada", want: func(c *configs.WorldConfig) {}},
+		{
+			name: "opções do mundo",
+			set:  []string{"world.cave_threshold=0.5", " world.size = [64, 32, 64] "},
+			want: func(c *configs.WorldConfig) {
+				c.CaveThreshold, c.Size = 0.5, [3]float32{64, 32, 64}
+			},
+		},
+		{
+			name:     "as demais ficam para o arquivo de configurações",
+			set:      []string{"video.view_distance=2", "world.tick_rate=1", "controls.sensitivity=0.02"},
+			want:     func(c *configs.WorldConfig) { c.TickRate = 1 },
+			wantRest: []string{"video.view_distance", "controls.sensitivity"},
+		},
+		{name: "opção desconhecida", set: []string{"world.gravity=2"}, wantErr: `unknown option "gravity"`},
+		{name: "valor do tipo errado", set: []string{"world.tick_rate=fast"}, wantErr: "tick_rate"},
+		{name: "valor fora do intervalo", set: []string{"world.tick_rate=0"}, wantErr: "tick_rate must be positive"},
+	}
+
+	for _, test := range tests {
+		var overrides overrideFlag
+		for _, text := range test.set {
+			if err := overrides.Set(text); err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+		}
+
+		config := configs.DefaultWorld()
+		rest, err := overrides.applyWorld(&config)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%s: error = %v, want one about %q", test.name, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		want := configs.DefaultWorld()
+		test.want(&want)
+		if !reflect.DeepEqual(config, want) {
+			t.Errorf("%s: config = %+v, want %+v", test.name, config, want)
+		}
+
+		var keys []string
+		for _, override := range rest {
+			keys = append(keys, override.key)
+		}
+		if !reflect.DeepEqual(keys, test.wantRest) {
+			t.Errorf("%s: rest = %v, want %v", test.name, keys, test.wantRest)
+		}
+	}
+
+	var overrides overrideFlag
+	if err := overrides.Set("world.tick_rate"); err == nil {
+		t.Error("-set without = was accepted")
+	}
+}
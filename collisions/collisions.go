@@ -2,7 +2,7 @@ package collisions
 
 import (
 	"github.com/go-gl/mathgl/mgl32"
-	"github.com/reonardoleis/fcg-glcraft/camera"
+	"github.com/reonardoleis/fcg-glcraft/configs"
 	math2 "github.com/reonardoleis/fcg-glcraft/math"
 )
 
@@ -70,61 +70,33 @@ func (cbb *CubeBoundingBox) UpdateBounds(position mgl32.Vec3) {
 }
 
 type FrustumCollider struct {
-	Frustum camera.Frustum
+	Frustum math2.Frustum
 }
 
-func NewFrustumCollider(frustum camera.Frustum) *FrustumCollider {
+func NewFrustumCollider(frustum math2.Frustum) *FrustumCollider {
 	return &FrustumCollider{
 		Frustum: frustum,
 	}
 }
 
-func (f *FrustumCollider) UpdateFrustum(frustum camera.Frustum) {
+func (f *FrustumCollider) UpdateFrustum(frustum math2.Frustum) {
 	f.Frustum = frustum
 }
 
-func getSmallest(values []float32) float32 {
-	smallest := values[0]
-	for _, item := range values {
-		if item < smallest {
-			smallest = item
-		}
-	}
-
-	return smallest
+// Frustum-Point collision
+func (f FrustumCollider) CollidesWithPoint(p mgl32.Vec3) bool {
+	return f.Frustum.ContainsPoint(p)
 }
 
-func getBiggest(values []float32) float32 {
-	biggest := values[0]
-	for _, item := range values {
-		if item > biggest {
-			biggest = item
-		}
-	}
-
-	return biggest
+// Frustum-AABB collision, used to cull chunks, chunk sections and blocks
+func (f FrustumCollider) CollidesWithAABB(mins, maxes mgl32.Vec3) bool {
+	return f.Frustum.IntersectsAABB(mins, maxes)
 }
 
-// OBB-Point collision
+// Frustum-Block collision, the block is a cube of side configs.BlockSize centered at p
 func (f FrustumCollider) CollidesWithBlock(p mgl32.Vec3) bool {
-	ft := f.Frustum
-	xs := []float32{ft.Ntl.X(), ft.Ntr.X(), ft.Ftl.X(), ft.Ftr.X(), ft.Nbl.X(), ft.Nbr.X(), ft.Fbr.X(), ft.Fbl.X()}
-	ys := []float32{ft.Ntl.Y(), ft.Ntr.Y(), ft.Ftl.Y(), ft.Ftr.Y(), ft.Nbl.Y(), ft.Nbr.Y(), ft.Fbr.Y(), ft.Fbl.Y()}
-	zs := []float32{ft.Ntl.Z(), ft.Ntr.Z(), ft.Ftl.Z(), ft.Ftr.Z(), ft.Nbl.Z(), ft.Nbr.Z(), ft.Fbr.Z(), ft.Fbl.Z()}
-
-	minX := getSmallest(xs)
-	maxX := getBiggest(xs)
-
-	minY := getSmallest(ys)
-	maxY := getBiggest(ys)
-
-	minZ := getSmallest(zs)
-	maxZ := getBiggest(zs)
-
-	return (p.X() >= float32(minX) && p.X() <= float32(maxX)) &&
-		(p.Y() >= float32(minY) && p.Y() <= float32(maxY)) &&
-		(p.Z() >= float32(minZ) && p.Z() <= float32(maxZ))
-
+	half := float32(configs.BlockSize) / 2
+	return f.CollidesWithAABB(p.Sub(mgl32.Vec3{half, half, half}), p.Add(mgl32.Vec3{half, half, half}))
 }
 
 type SphereCollider struct {
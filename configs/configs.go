@@ -2,29 +2,19 @@ package configs
 
 type TresholdTuple = [2]float64
 
-// Configuration
+// Layout of the world and the player. The meshes, the saved worlds and the
+// replays depend on them, so they can't change at runtime; the options that
+// can are in Video and World.
 const (
-	BlockSize          int     = 1
-	TickRate           float64 = 0.5
-	ViewDistance       float32 = 1 // [3Nx3N] chunks
-	PlayerHeight       float32 = 2 * float32(BlockSize) * 0.5
-	PlayerWidth        float32 = float32(BlockSize) * 0.6
-	HeightViewDistance float32 = 16
-	JumpHeight         float32 = 2
-	ChunkSize          int     = 16
-	WorldHeight        int     = 64
-	ChunkSmoothness    int     = 8
-	CaveThreshold      float32 = 0.4
-	CaveMinHeight      int     = WorldHeight
-	BoundingBoxMinY    float32 = 1.0
-	BoundingBoxMaxY    float32 = 0.8
-	Jumpforce          float32 = 5
-	CaveDirtThreshold  float32 = 0.25
-	BlockFallingSpeed  float32 = 25
-)
-
-var (
-	CaveCoalThreshold     TresholdTuple = TresholdTuple{0.40, 0.45}
-	CaveIronThreshold     TresholdTuple = TresholdTuple{0.45, 0.48}
-	CaveContentSmoothness int           = 8
+	BlockSize        int     = 1
+	PlayerHeight     float32 = 2 * float32(BlockSize) * 0.5
+	PlayerWidth      float32 = float32(BlockSize) * 0.6
+	ChunkSize        int     = 16
+	SectionSize      int     = 16 // altura das seções [16x16x16] de um chunk
+	WorldHeight      int     = 64
+	BoundingBoxMinY  float32 = 1.0
+	BoundingBoxMaxY  float32 = 0.8
+	MaxHealth        int     = 20       // pontos de vida do jogador, 2 por coração
+	FixedTimestep    float64 = 1.0 / 60 // segundos simulados por passo, fixos para que os replays se repitam
+	MaxStepsPerFrame int     = 5        // passos num quadro antes de descartar o atraso
 )
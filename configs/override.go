@@ -0,0 +1,53 @@
+package configs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Set changes one option of target, a struct saved as JSON, by its name in
+// the file. Nested options are separated by dots, as in "video.view_distance".
+// The value is read as JSON, or as a string if it isn't valid JSON. It is how
+// the command line overrides the files.
+func Set(target interface{}, key, value string) error {
+	data, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	path := strings.Split(key, ".")
+	current := fields
+	for _, name := range path[:len(path)-1] {
+		next, ok := current[name].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unknown option %q", key)
+		}
+		current = next
+	}
+
+	name := path[len(path)-1]
+	if _, ok := current[name]; !ok {
+		return fmt.Errorf("unknown option %q", key)
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+		parsed = value
+	}
+	current[name] = parsed
+
+	if data, err = json.Marshal(fields); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	return nil
+}
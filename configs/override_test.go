@@ -0,0 +1,64 @@
+package configs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	type inner struct {
+		Distance float32 `json:"distance"`
+		Name     string  `json:"name"`
+	}
+	type options struct {
+		Count  int        `json:"count"`
+		Ratio  [2]float64 `json:"ratio"`
+		Inner  inner      `json:"inner"`
+		Hidden int        `json:"-"`
+	}
+	initial := options{Count: 1, Ratio: [2]float64{0.1, 0.2}, Inner: inner{Distance: 3, Name: "a"}}
+
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    func(o *options)
+		wantErr string
+	}{
+		{name: "número", key: "count", value: "5", want: func(o *options) { o.Count = 5 }},
+		{name: "lista", key: "ratio", value: "[0.3, 0.4]", want: func(o *options) { o.Ratio = [2]float64{0.3, 0.4} }},
+		{name: "aninhada", key: "inner.distance", value: "2.5", want: func(o *options) { o.Inner.Distance = 2.5 }},
+		{name: "texto sem aspas", key: "inner.name", value: "b c", want: func(o *options) { o.Inner.Name = "b c" }},
+		{name: "texto que parece JSON", key: "inner.name", value: `"12"`, want: func(o *options) { o.Inner.Name = "12" }},
+		{name: "objeto parcial mantém o resto", key: "inner", value: `{"name": "d"}`, want: func(o *options) { o.Inner.Name = "d" }},
+		{name: "desconhecida", key: "size", value: "1", wantErr: `unknown option "size"`},
+		{name: "aninhada desconhecida", key: "inner.size", value: "1", wantErr: `unknown option "inner.size"`},
+		{name: "caminho desconhecido", key: "outer.distance", value: "1", wantErr: `unknown option "outer.distance"`},
+		{name: "dentro de um número", key: "count.x", value: "1", wantErr: `unknown option "count.x"`},
+		{name: "campo fora do JSON", key: "Hidden", value: "1", wantErr: `unknown option "Hidden"`},
+		{name: "tipo errado", key: "count", value: "many", wantErr: "count: json"},
+		{name: "tipo errado aninhado", key: "inner.distance", value: "[1]", wantErr: "inner.distance: json"},
+	}
+
+	for _, test := range tests {
+		target := initial
+		err := Set(&target, test.key, test.value)
+
+		want := initial
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%s: error = %v, want one about %q", test.name, err, test.wantErr)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+			test.want(&want)
+		}
+
+		if !reflect.DeepEqual(target, want) {
+			t.Errorf("%s: options = %+v, want %+v", test.name, target, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package configs
+
+import "fmt"
+
+// VideoConfig is how far and how detailed the engine draws. It is part of the
+// settings file and can change while playing, except for the shadow map and
+// the particle pool, which are only created when a world is loaded, and the
+// MSAA samples of the post-processing scene target, only read when the game
+// starts.
+type VideoConfig struct {
+	ViewDistance       float32 `json:"view_distance"`     // [3Nx3N] chunks desenhados bloco a bloco
+	LODViewDistance    int     `json:"lod_view_distance"` // chunks desenhados como heightmap simplificado (world/lod)
+	HeightViewDistance float32 `json:"height_view_distance"`
+	MaxParticles       int     `json:"max_particles"` // tamanho do pool de partículas (engine/particles)
+
+	// Janela (engine/window)
+	Fullscreen bool `json:"fullscreen"`
+	VSync      bool `json:"vsync"`
+	MSAA       int  `json:"msaa"` // amostras por pixel, 0 desliga
+
+	// Mapas de sombra do sol (engine/shadows)
+	ShadowCascades      int     `json:"shadow_cascades"`
+	ShadowMapResolution int32   `json:"shadow_map_resolution"`
+	ShadowBias          float32 `json:"shadow_bias"`
+	ShadowDistance      float32 `json:"shadow_distance"`     // até onde, a partir da câmera, há sombras
+	ShadowSplitLambda   float32 `json:"shadow_split_lambda"` // 1 divide as cascatas de forma logarítmica, 0 uniforme
+}
+
+// Limites da distância de visão, em chunks
+const (
+	MaxViewDistance    = 6
+	MaxLODViewDistance = 64
+)
+
+func DefaultVideo() VideoConfig {
+	return VideoConfig{
+		ViewDistance:        1,
+		LODViewDistance:     32,
+		HeightViewDistance:  16,
+		MaxParticles:        4096,
+		VSync:               true,
+		MSAA:                4,
+		ShadowCascades:      3,
+		ShadowMapResolution: 2048,
+		ShadowBias:          0.002,
+		ShadowDistance:      64,
+		ShadowSplitLambda:   0.75,
+	}
+}
+
+// Video is read by the engine every frame and replaced by the settings
+var Video = DefaultVideo()
+
+// Validate tells which option, if any, is out of its range
+func (v VideoConfig) Validate() error {
+	switch {
+	case v.ViewDistance < 1 || v.ViewDistance > MaxViewDistance:
+		return fmt.Errorf("view_distance must be between 1 and %d, got %v", MaxViewDistance, v.ViewDistance)
+	case v.LODViewDistance < int(v.ViewDistance) || v.LODViewDistance > MaxLODViewDistance:
+		return fmt.Errorf("lod_view_distance must be between view_distance and %d, got %d", MaxLODViewDistance, v.LODViewDistance)
+	case v.HeightViewDistance <= 0:
+		return fmt.Errorf("height_view_distance must be positive, got %v", v.HeightViewDistance)
+	case v.MaxParticles < 0:
+		return fmt.Errorf("max_particles can't be negative, got %d", v.MaxParticles)
+	case v.MSAA < 0 || v.MSAA > 16 || v.MSAA&(v.MSAA-1) != 0:
+		return fmt.Errorf("msaa must be 0 or a power of 2 up to 16, got %d", v.MSAA)
+	case v.ShadowCascades < 1:
+		return fmt.Errorf("shadow_cascades must be at least 1, got %d", v.ShadowCascades)
+	case v.ShadowMapResolution < 256 || v.ShadowMapResolution > 8192:
+		return fmt.Errorf("shadow_map_resolution must be between 256 and 8192, got %d", v.ShadowMapResolution)
+	case v.ShadowDistance <= 0:
+		return fmt.Errorf("shadow_distance must be positive, got %v", v.ShadowDistance)
+	case v.ShadowSplitLambda < 0 || v.ShadowSplitLambda > 1:
+		return fmt.Errorf("shadow_split_lambda must be between 0 and 1, got %v", v.ShadowSplitLambda)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package configs
+
+import "fmt"
+
+// WorldConfig are the generation and gameplay parameters of a world. Each
+// world saves its own, so changing the defaults doesn't change the terrain of
+// the worlds already created.
+type WorldConfig struct {
+	Size                  [3]float32    `json:"size"`      // o jogador anda de -Size até Size em x e z
+	TickRate              float64       `json:"tick_rate"` // segundos entre as atualizações dos blocos
+	ChunkSmoothness       int           `json:"chunk_smoothness"`
+	CaveThreshold         float32       `json:"cave_threshold"`
+	CaveMinHeight         int           `json:"cave_min_height"`
+	CaveDirtThreshold     float32       `json:"cave_dirt_threshold"`
+	CaveCoalThreshold     TresholdTuple `json:"cave_coal_threshold"`
+	CaveIronThreshold     TresholdTuple `json:"cave_iron_threshold"`
+	CaveContentSmoothness int           `json:"cave_content_smoothness"`
+	JumpHeight            float32       `json:"jump_height"`
+	JumpForce             float32       `json:"jump_force"`
+	BlockFallingSpeed     float32       `json:"block_falling_speed"`
+}
+
+func DefaultWorld() WorldConfig {
+	return WorldConfig{
+		Size:                  [3]float32{256, 32, 256},
+		TickRate:              0.5,
+		ChunkSmoothness:       8,
+		CaveThreshold:         0.4,
+		CaveMinHeight:         WorldHeight,
+		CaveDirtThreshold:     0.25,
+		CaveCoalThreshold:     TresholdTuple{0.40, 0.45},
+		CaveIronThreshold:     TresholdTuple{0.45, 0.48},
+		CaveContentSmoothness: 8,
+		JumpHeight:            2,
+		JumpForce:             5,
+		BlockFallingSpeed:     25,
+	}
+}
+
+// World is the config of the world being played, set when it is loaded
+var World = DefaultWorld()
+
+// Validate tells which parameter, if any, is out of its range
+func (w WorldConfig) Validate() error {
+	switch {
+	case w.Size[0] < float32(ChunkSize) || w.Size[2] < float32(ChunkSize):
+		return fmt.Errorf("size must be at least %d in x and z, got %v", ChunkSize, w.Size)
+	case w.TickRate <= 0:
+		return fmt.Errorf("tick_rate must be positive, got %v", w.TickRate)
+	case w.ChunkSmoothness < 1:
+		return fmt.Errorf("chunk_smoothness must be at least 1, got %d", w.ChunkSmoothness)
+	case w.CaveThreshold < -1 || w.CaveThreshold > 1:
+		return fmt.Errorf("cave_threshold must be between -1 and 1, got %v", w.CaveThreshold)
+	case w.CaveMinHeight < 0 || w.CaveMinHeight > WorldHeight:
+		return fmt.Errorf("cave_min_height must be between 0 and %d, got %d", WorldHeight, w.CaveMinHeight)
+	case w.CaveDirtThreshold < -1 || w.CaveDirtThreshold > 1:
+		return fmt.Errorf("cave_dirt_threshold must be between -1 and 1, got %v", w.CaveDirtThreshold)
+	case w.CaveCoalThreshold[0] > w.CaveCoalThreshold[1]:
+		return fmt.Errorf("cave_coal_threshold must be [min, max], got %v", w.CaveCoalThreshold)
+	case w.CaveIronThreshold[0] > w.CaveIronThreshold[1]:
+		return fmt.Errorf("cave_iron_threshold must be [min, max], got %v", w.CaveIronThreshold)
+	case w.CaveContentSmoothness < 1:
+		return fmt.Errorf("cave_content_smoothness must be at least 1, got %d", w.CaveContentSmoothness)
+	case w.JumpHeight <= 0:
+		return fmt.Errorf("jump_height must be positive, got %v", w.JumpHeight)
+	case w.JumpForce <= 0:
+		return fmt.Errorf("jump_force must be positive, got %v", w.JumpForce)
+	case w.BlockFallingSpeed <= 0:
+		return fmt.Errorf("block_falling_speed must be positive, got %v", w.BlockFallingSpeed)
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package console
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/block"
+)
+
+// ArgType reads one kind of argument from the words typed after a command
+type ArgType interface {
+	// Tokens is how many words the argument takes
+	Tokens() int
+	// Parse reads the value from exactly Tokens() words
+	Parse(source *Source, tokens []string) (interface{}, error)
+	// Suggest lists what may be typed as the word at index, for completion
+	Suggest(source *Source, index int) []string
+}
+
+// Int is an integer between Min and Max, both included
+type Int struct {
+	Min, Max int
+}
+
+func (Int) Tokens() int { return 1 }
+
+func (t Int) Parse(source *Source, tokens []string) (interface{}, error) {
+	value, err := strconv.Atoi(tokens[0])
+	if err != nil {
+		return nil, fmt.Errorf("expected an integer, got %q", tokens[0])
+	}
+	if value < t.Min || value > t.Max {
+		return nil, fmt.Errorf("must be between %d and %d, got %d", t.Min, t.Max, value)
+	}
+
+	return value, nil
+}
+
+func (Int) Suggest(source *Source, index int) []string { return nil }
+
+// Float is a number between Min and Max, both included
+type Float struct {
+	Min, Max float64
+}
+
+func (Float) Tokens() int { return 1 }
+
+func (t Float) Parse(source *Source, tokens []string) (interface{}, error) {
+	value, err := strconv.ParseFloat(tokens[0], 64)
+	if err != nil || math.IsNaN(value) {
+		return nil, fmt.Errorf("expected a number, got %q", tokens[0])
+	}
+	if value < t.Min || value > t.Max {
+		return nil, fmt.Errorf("must be between %v and %v, got %v", t.Min, t.Max, value)
+	}
+
+	return value, nil
+}
+
+func (Float) Suggest(source *Source, index int) []string { return nil }
+
+// Position is x y z in world coordinates. Each one may be relative to the
+// player running the command, as ~ or ~2. Block positions are rounded to the
+// block they fall in and parsed as [3]int; the others as mgl32.Vec3.
+type Position struct {
+	Block bool
+}
+
+func (Position) Tokens() int { return 3 }
+
+func (t Position) Parse(source *Source, tokens []string) (interface{}, error) {
+	var position mgl32.Vec3
+	for i, token := range tokens {
+		value, relative := token, false
+		if strings.HasPrefix(token, "~") {
+			if source.Player == nil {
+				return nil, fmt.Errorf("~ needs a player running the command")
+			}
+			value, relative = strings.TrimPrefix(token, "~"), true
+		}
+
+		var offset float64
+		if value != "" {
+			var err error
+			if offset, err = strconv.ParseFloat(value, 32); err != nil || math.IsNaN(offset) || math.IsInf(offset, 0) {
+				return nil, fmt.Errorf("expected a coordinate, got %q", token)
+			}
+		}
+
+		position[i] = float32(offset)
+		if relative {
+			position[i] += source.Player.Position[i]
+		}
+	}
+
+	if !t.Block {
+		return position, nil
+	}
+
+	var blockPosition [3]int
+	for i := range position {
+		// os blocos ficam centrados nas coordenadas inteiras
+		blockPosition[i] = int(math.Floor(float64(position[i]) + 0.5))
+	}
+	return blockPosition, nil
+}
+
+func (Position) Suggest(source *Source, index int) []string {
+	if source.Player == nil {
+		return nil
+	}
+
+	return []string{"~"}
+}
+
+// BlockName is a block type by its name, as in block.TypeName
+type BlockName struct{}
+
+func (BlockName) Tokens() int { return 1 }
+
+func (BlockName) Parse(source *Source, tokens []string) (interface{}, error) {
+	blockType, ok := block.TypeByName(tokens[0])
+	if !ok {
+		return nil, fmt.Errorf("unknown block %q", tokens[0])
+	}
+
+	return blockType, nil
+}
+
+func (BlockName) Suggest(source *Source, index int) []string {
+	names := []string{}
+	for _, blockType := range block.Types() {
+		names = append(names, block.TypeName(blockType))
+	}
+
+	return names
+}
+
+// PlayerName is one of the players of the source by name, or @s for the
+// player running the command. It is parsed as *player.Player.
+type PlayerName struct{}
+
+func (PlayerName) Tokens() int { return 1 }
+
+func (PlayerName) Parse(source *Source, tokens []string) (interface{}, error) {
+	return source.FindPlayer(tokens[0])
+}
+
+func (PlayerName) Suggest(source *Source, index int) []string {
+	names := []string{"@s"}
+	for _, p := range source.Players {
+		if p.Name != "" {
+			names = append(names, p.Name)
+		}
+	}
+
+	return names
+}
+
+// Choice is one of a fixed set of words, parsed as string
+type Choice struct {
+	Values []string
+}
+
+func (Choice) Tokens() int { return 1 }
+
+func (t Choice) Parse(source *Source, tokens []string) (interface{}, error) {
+	for _, value := range t.Values {
+		if value == tokens[0] {
+			return value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("expected one of %s, got %q", strings.Join(t.Values, ", "), tokens[0])
+}
+
+func (t Choice) Suggest(source *Source, index int) []string {
+	return t.Values
+}
+
+// Horários com nome, em fração do dia (0 é o nascer do sol, ver world.SetTimeOfDay)
+var timesOfDay = map[string]float64{
+	"sunrise":  0,
+	"day":      0.1,
+	"noon":     0.25,
+	"sunset":   0.5,
+	"night":    0.6,
+	"midnight": 0.75,
+}
+
+// TimeOfDay is a named time, like noon, or a fraction of the day between 0
+// (sunrise) and 1, parsed as float64
+type TimeOfDay struct{}
+
+func (TimeOfDay) Tokens() int { return 1 }
+
+func (TimeOfDay) Parse(source *Source, tokens []string) (interface{}, error) {
+	if timeOfDay, ok := timesOfDay[tokens[0]]; ok {
+		return timeOfDay, nil
+	}
+
+	return Float{0, 1}.Parse(source, tokens)
+}
+
+func (TimeOfDay) Suggest(source *Source, index int) []string {
+	names := []string{}
+	for name := range timesOfDay {
+		names = append(names, name)
+	}
+
+	return names
+}
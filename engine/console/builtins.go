@@ -0,0 +1,348 @@
+package console
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/player"
+	"github.com/reonardoleis/fcg-glcraft/world"
+)
+
+// Maior caixa que /fill muda de uma vez, em blocos
+const MaxFillVolume = 32 * 32 * 32
+
+// Nomes dos modos de jogo, na ordem de player.GameMode
+var gameModes = []string{"survival", "creative"}
+
+// Builtins returns the commands of the game: /tp, /give, /time, /gamemode,
+// /fill, /setblock, /seed and /regen
+func Builtins() []*Command {
+	return []*Command{
+		{
+			Name:        "tp",
+			Description: "leva um jogador a uma posição",
+			Permission:  PermissionCheats,
+			Args: []Arg{
+				{Name: "target", Type: PlayerName{}, Optional: true},
+				{Name: "position", Type: Position{}},
+			},
+			Run: teleport,
+		},
+		{
+			Name:        "give",
+			Description: "põe um bloco na mão de um jogador",
+			Permission:  PermissionCheats,
+			Args: []Arg{
+				{Name: "target", Type: PlayerName{}, Optional: true},
+				{Name: "block", Type: BlockName{}},
+			},
+			Run: give,
+		},
+		{
+			Name:        "time",
+			Description: "mostra ou muda a hora do dia",
+			Permission:  PermissionPlayer,
+			Subcommands: []*Command{
+				{
+					Name:        "set",
+					Description: "muda a hora do dia, por nome ou fração de 0 a 1",
+					Permission:  PermissionCheats,
+					Args:        []Arg{{Name: "time", Type: TimeOfDay{}}},
+					Run:         setTime,
+				},
+				{
+					Name:        "query",
+					Description: "mostra a hora do dia",
+					Permission:  PermissionPlayer,
+					Run:         queryTime,
+				},
+			},
+		},
+		{
+			Name:        "gamemode",
+			Description: "muda o modo de jogo de um jogador",
+			Permission:  PermissionCheats,
+			Args: []Arg{
+				{Name: "mode", Type: Choice{gameModes}},
+				{Name: "target", Type: PlayerName{}, Optional: true},
+			},
+			Run: setGameMode,
+		},
+		{
+			Name:        "fill",
+			Description: "preenche uma caixa de blocos; air os remove",
+			Permission:  PermissionCheats,
+			Args: []Arg{
+				{Name: "from", Type: Position{Block: true}},
+				{Name: "to", Type: Position{Block: true}},
+				{Name: "block", Type: BlockName{}},
+			},
+			Run: fill,
+		},
+		{
+			Name:        "setblock",
+			Description: "troca um bloco; air o remove",
+			Permission:  PermissionCheats,
+			Args: []Arg{
+				{Name: "position", Type: Position{Block: true}},
+				{Name: "block", Type: BlockName{}},
+			},
+			Run: setBlock,
+		},
+		{
+			Name:        "seed",
+			Description: "mostra a semente do mundo",
+			Permission:  PermissionPlayer,
+			Run:         seed,
+		},
+		{
+			Name:        "regen",
+			Description: "gera de novo a partir da semente, desfazendo as mudanças",
+			Permission:  PermissionOperator,
+			Subcommands: []*Command{
+				{
+					Name:        "chunk",
+					Description: "gera de novo o chunk de uma posição, ou o do jogador",
+					Permission:  PermissionOperator,
+					Args:        []Arg{{Name: "position", Type: Position{Block: true}, Optional: true}},
+					Run:         regenChunk,
+				},
+			},
+		},
+	}
+}
+
+func (c *Console) helpCommand() *Command {
+	return &Command{
+		Name:        "help",
+		Description: "lista os comandos, ou mostra como usar um deles",
+		Permission:  PermissionPlayer,
+		Args:        []Arg{{Name: "command", Type: commandName{c}, Optional: true}},
+		Run: func(ctx *Context) error {
+			if ctx.Has("command") {
+				command := ctx.Value("command").(*Command)
+				ctx.Printf("%s - %s", command.Usage(), command.Description)
+				for _, sub := range command.Subcommands {
+					if ctx.Source.Permission < sub.Permission {
+						continue
+					}
+					ctx.Printf("  %s - %s", sub.Usage(), sub.Description)
+				}
+				return nil
+			}
+
+			for _, command := range c.Commands(ctx.Source) {
+				ctx.Printf("%s - %s", command.Usage(), command.Description)
+			}
+			return nil
+		},
+	}
+}
+
+// Nome de um comando que a fonte pode usar, para /help
+type commandName struct {
+	console *Console
+}
+
+func (commandName) Tokens() int { return 1 }
+
+func (t commandName) Parse(source *Source, tokens []string) (interface{}, error) {
+	return t.console.Lookup(source, tokens[0])
+}
+
+func (t commandName) Suggest(source *Source, index int) []string {
+	names := []string{}
+	for _, command := range t.console.Commands(source) {
+		names = append(names, command.Name)
+	}
+
+	return names
+}
+
+// Os comandos que mudam o mundo precisam de um
+func worldOf(ctx *Context) (*world.World, error) {
+	if ctx.Source.World == nil {
+		return nil, fmt.Errorf("no world is loaded")
+	}
+
+	return ctx.Source.World, nil
+}
+
+func teleport(ctx *Context) error {
+	target, err := ctx.Player("target")
+	if err != nil {
+		return err
+	}
+	w, err := worldOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	position := ctx.Position("position")
+	// mesmos limites de player.HandleWorldLimits
+	if position.X() < -w.Size.X() || position.X() > w.Size.X()-1 || position.Z() < -w.Size.Z() || position.Z() > w.Size.Z()-1 {
+		return fmt.Errorf("%.1f %.1f is outside the world, which goes from %v to %v", position.X(), position.Z(), -w.Size.X(), w.Size.X()-1)
+	}
+	if position.Y() < 0 {
+		return fmt.Errorf("y can't be below 0, got %.1f", position.Y())
+	}
+
+	target.SetPosition(position.Vec4(1))
+	// o jogo procura o chunk do jogador no próximo passo
+	offset := target.GetChunkOffset()
+	w.LoadChunksAround(int(offset.X()), int(offset.Y()))
+
+	ctx.Printf("Teleported %s to %.1f %.1f %.1f", nameOf(target), position.X(), position.Y(), position.Z())
+	return nil
+}
+
+func give(ctx *Context) error {
+	target, err := ctx.Player("target")
+	if err != nil {
+		return err
+	}
+
+	blockType := ctx.Block("block")
+	if blockType == block.BlockAir {
+		return fmt.Errorf("air can't be held")
+	}
+
+	target.SelectedBlock = blockType
+	ctx.Printf("Gave %s to %s", block.TypeName(blockType), nameOf(target))
+	return nil
+}
+
+func setTime(ctx *Context) error {
+	w, err := worldOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	w.SetTimeOfDay(ctx.Float("time"))
+	ctx.Printf("Set the time to %s", formatTimeOfDay(w.TimeOfDay()))
+	return nil
+}
+
+func queryTime(ctx *Context) error {
+	w, err := worldOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx.Printf("The time is %s (day %d)", formatTimeOfDay(w.TimeOfDay()), int(w.Time/world.DayLength)+1)
+	return nil
+}
+
+// Fração do dia em horas de relógio; o dia começa às 6h, no nascer do sol
+func formatTimeOfDay(timeOfDay float64) string {
+	minutes := int(math.Mod(timeOfDay*24*60+6*60, 24*60))
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+func setGameMode(ctx *Context) error {
+	target, err := ctx.Player("target")
+	if err != nil {
+		return err
+	}
+
+	mode := ctx.String("mode")
+	for i, name := range gameModes {
+		if name == mode {
+			target.GameMode = player.GameMode(i)
+		}
+	}
+
+	ctx.Printf("Set the game mode of %s to %s", nameOf(target), mode)
+	return nil
+}
+
+func fill(ctx *Context) error {
+	w, err := worldOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	from, to := ctx.BlockPosition("from"), ctx.BlockPosition("to")
+	volume := 1
+	for i := range from {
+		// cada lado é comparado antes de multiplicar, senão coordenadas enormes
+		// estouram o volume (e a diferença é em float64 pelo mesmo motivo)
+		size := math.Abs(float64(to[i])-float64(from[i])) + 1
+		if size > float64(MaxFillVolume/volume) {
+			return fmt.Errorf("too many blocks: more than %d", MaxFillVolume)
+		}
+		volume *= int(size)
+	}
+
+	blockType := ctx.Block("block")
+	changed, err := w.Fill(from, to, blockType)
+	if err != nil {
+		return err
+	}
+
+	ctx.Printf("Filled %d blocks with %s", changed, block.TypeName(blockType))
+	return nil
+}
+
+func setBlock(ctx *Context) error {
+	w, err := worldOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	position, blockType := ctx.BlockPosition("position"), ctx.Block("block")
+	changed, err := w.SetBlock(position[0], position[1], position[2], blockType)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return fmt.Errorf("the block at %d %d %d is already %s", position[0], position[1], position[2], block.TypeName(blockType))
+	}
+
+	ctx.Printf("Set the block at %d %d %d to %s", position[0], position[1], position[2], block.TypeName(blockType))
+	return nil
+}
+
+func seed(ctx *Context) error {
+	w, err := worldOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx.Printf("Seed: %d", w.Seed)
+	return nil
+}
+
+func regenChunk(ctx *Context) error {
+	w, err := worldOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	var x, z int
+	if ctx.Has("position") {
+		position := ctx.BlockPosition("position")
+		x, z = world.ChunkOf(position[0]), world.ChunkOf(position[2])
+	} else if ctx.Source.Player != nil {
+		offset := ctx.Source.Player.GetChunkOffset()
+		x, z = int(offset.X()), int(offset.Y())
+	} else {
+		return fmt.Errorf("missing <position>")
+	}
+
+	if !w.RegenerateChunk(x, z) {
+		return fmt.Errorf("chunk %d %d is not loaded", x, z)
+	}
+
+	ctx.Printf("Regenerated chunk %d %d", x, z)
+	return nil
+}
+
+func nameOf(p *player.Player) string {
+	if p.Name == "" {
+		return "the player"
+	}
+
+	return p.Name
+}
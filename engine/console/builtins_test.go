@@ -0,0 +1,177 @@
+package console
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/world"
+	"github.com/reonardoleis/fcg-glcraft/world/chunk"
+)
+
+// Altura livre acima do terreno plano
+const air = chunk.FlatHeight + 8
+
+// Mundo plano sem janela, com os chunks em volta da origem carregados
+func newTestWorld(t *testing.T) *world.World {
+	// só os chunks vizinhos, abaixo do mínimo das configurações, para gerar menos
+	viewDistance := configs.Video.ViewDistance
+	configs.Video.ViewDistance = 0
+	t.Cleanup(func() { configs.Video.ViewDistance = viewDistance })
+
+	w := world.NewWorld("console", mgl32.Vec3{64, 32, 64}, 1, chunk.GeneratorFlat)
+	// o terreno distante só serve para desenhar na janela
+	w.LOD.Destroy()
+	w.LOD = nil
+	w.LoadChunksAround(0, 0)
+
+	return w
+}
+
+func blockTypeAt(w *world.World, x, y, z int) block.BlockType {
+	b := w.GetBlockAt(x, y, z)
+	if b == nil {
+		return block.BlockAir
+	}
+
+	return b.BlockType
+}
+
+func TestWorldCommands(t *testing.T) {
+	type check struct {
+		x, y, z int
+		want    block.BlockType
+	}
+
+	tests := []struct {
+		name    string
+		before  string // comando rodado antes, sem conferir
+		line    string
+		want    []string
+		wantErr string
+		blocks  []check
+	}{
+		{
+			name:   "fill",
+			line:   "/fill 0 40 0 2 41 2 stone",
+			want:   []string{"Filled 18 blocks with stone"},
+			blocks: []check{{0, 40, 0, block.BlockStone}, {2, 41, 2, block.BlockStone}, {3, 41, 2, block.BlockAir}, {2, 42, 2, block.BlockAir}},
+		},
+		{
+			name:   "fill com os cantos trocados",
+			line:   "/fill 2 41 2 0 40 0 glass",
+			want:   []string{"Filled 18 blocks with glass"},
+			blocks: []check{{0, 40, 0, block.BlockGlass}, {2, 41, 2, block.BlockGlass}},
+		},
+		{
+			name:   "fill só conta o que mudou",
+			before: "/fill 0 40 0 0 40 0 stone",
+			line:   "/fill 0 40 0 1 40 0 stone",
+			want:   []string{"Filled 1 blocks with stone"},
+		},
+		{
+			name:   "fill com air remove",
+			line:   "/fill 0 31 0 1 32 1 air",
+			want:   []string{"Filled 8 blocks with air"},
+			blocks: []check{{0, 32, 0, block.BlockAir}, {1, 31, 1, block.BlockAir}, {0, 30, 0, block.BlockDirt}},
+		},
+		{
+			name:   "fill entre chunks",
+			line:   "/fill -1 40 15 16 40 16 stone",
+			want:   []string{"Filled 36 blocks with stone"},
+			blocks: []check{{-1, 40, 15, block.BlockStone}, {16, 40, 16, block.BlockStone}},
+		},
+		{name: "fill grande demais", line: "/fill 0 0 0 32 32 32 stone", wantErr: "too many blocks"},
+		{
+			// (2^62 + 1) * 4 estoura e daria 4 se o volume fosse multiplicado antes
+			name:    "fill com coordenadas enormes",
+			line:    "/fill 0 0 0 4611686018427387904 3 0 stone",
+			wantErr: "too many blocks",
+		},
+		{name: "fill fora dos chunks carregados", line: "/fill 100 40 0 101 40 0 stone", wantErr: "chunk 6 0 is not loaded"},
+		{name: "fill acima do mundo", line: "/fill 0 63 0 0 64 0 stone", wantErr: "y must be between 0 and 63"},
+		{
+			name:   "setblock",
+			line:   "/setblock 5 40 -5 wood",
+			want:   []string{"Set the block at 5 40 -5 to wood"},
+			blocks: []check{{5, 40, -5, block.BlockWood}},
+		},
+		{name: "setblock do mesmo bloco", line: "/setblock 5 32 5 grass", wantErr: "the block at 5 32 5 is already grass"},
+		{name: "setblock de bloco desconhecido", line: "/setblock 5 40 5 gold", wantErr: `unknown block "gold"`},
+		{name: "tp", line: "/tp 20 40 -20", want: []string{"Teleported steve to 20.0 40.0 -20.0"}},
+		{name: "tp de outro jogador", line: "/tp alex ~ ~1 ~", want: []string{"Teleported alex to 10.4 34.0 -2.6"}},
+		{name: "tp para fora do mundo", line: "/tp 64 40 0", wantErr: "is outside the world"},
+		{name: "tp para baixo do mundo", line: "/tp 0 -1 0", wantErr: "y can't be below 0"},
+		{
+			name:   "tp carrega os chunks do destino",
+			line:   "/tp 60 40 60",
+			want:   []string{"Teleported steve to 60.0 40.0 60.0"},
+			blocks: []check{{60, chunk.FlatHeight, 60, block.BlockGrass}},
+		},
+		{
+			name:   "regen",
+			before: "/fill 0 30 0 15 40 15 air",
+			line:   "/regen chunk 5 0 5",
+			want:   []string{"Regenerated chunk 0 0"},
+			blocks: []check{{0, chunk.FlatHeight, 0, block.BlockGrass}, {15, 30, 15, block.BlockDirt}},
+		},
+		{
+			name:   "regen do chunk do jogador",
+			before: "/setblock 1 40 -1 stone",
+			line:   "/regen chunk",
+			want:   []string{"Regenerated chunk 0 -1"},
+			blocks: []check{{1, 40, -1, block.BlockAir}},
+		},
+		{name: "regen fora dos chunks carregados", line: "/regen chunk 100 0 0", wantErr: "chunk 6 0 is not loaded"},
+	}
+
+	for _, test := range tests {
+		console := NewConsole(testCommands()...)
+		source := testSource(PermissionOperator)
+		source.World = newTestWorld(t)
+
+		if test.before != "" {
+			if _, err := console.Execute(source, test.before); err != nil {
+				t.Fatalf("%s: %s: %v", test.name, test.before, err)
+			}
+		}
+
+		got, err := console.Execute(source, test.line)
+		if test.wantErr == "" && err != nil {
+			t.Errorf("%s: Execute(%q) error %v", test.name, test.line, err)
+			continue
+		}
+		if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+			t.Errorf("%s: Execute(%q) error = %v, want %q", test.name, test.line, err, test.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Execute(%q) = %q, want %q", test.name, test.line, got, test.want)
+		}
+
+		for _, check := range test.blocks {
+			if got := blockTypeAt(source.World, check.x, check.y, check.z); got != check.want {
+				t.Errorf("%s: block at %d %d %d is %s, want %s", test.name, check.x, check.y, check.z, block.TypeName(got), block.TypeName(check.want))
+			}
+		}
+	}
+}
+
+func TestTeleportMovesPlayer(t *testing.T) {
+	console := NewConsole(testCommands()...)
+	source := testSource(PermissionCheats)
+	source.World = newTestWorld(t)
+
+	if _, err := console.Execute(source, "/tp alex 1 40 -3"); err != nil {
+		t.Fatal(err)
+	}
+	if want := (mgl32.Vec4{1, 40, -3, 1}); source.Players[1].Position != want {
+		t.Errorf("alex is at %v, want %v", source.Players[1].Position, want)
+	}
+	if want := (mgl32.Vec4{10.4, 33, -2.6, 1}); source.Player.Position != want {
+		t.Errorf("/tp alex moved steve to %v", source.Player.Position)
+	}
+}
@@ -0,0 +1,448 @@
+package console
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/player"
+	"github.com/reonardoleis/fcg-glcraft/world"
+)
+
+// Permission is the level needed to run a command
+type Permission int
+
+const (
+	PermissionPlayer   Permission = iota // só consultam o jogo, como /seed
+	PermissionCheats                     // mudam o jogador ou o mundo, como /tp e /fill
+	PermissionOperator                   // descartam partes do mundo, como /regen
+)
+
+// Source is who runs a command and what it acts on. Nothing in it needs a
+// window, so the commands also run in tests and on the command line.
+type Source struct {
+	Player     *player.Player // quem executa; as coordenadas com ~ partem dele
+	Players    []*player.Player
+	World      *world.World
+	Permission Permission
+}
+
+// FindPlayer returns the player called name, or the source itself for @s
+func (s *Source) FindPlayer(name string) (*player.Player, error) {
+	if name == "@s" {
+		if s.Player == nil {
+			return nil, fmt.Errorf("@s needs a player running the command")
+		}
+		return s.Player, nil
+	}
+
+	for _, p := range s.Players {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no player named %q", name)
+}
+
+// Command is a registered command. Commands with Subcommands, like /time,
+// pick one by the next word; Run may be nil if a subcommand is required.
+type Command struct {
+	Name        string
+	Description string
+	Permission  Permission
+	Args        []Arg
+	Subcommands []*Command
+	Run         func(ctx *Context) error
+
+	parent *Command // definido por Register
+}
+
+// Usage is how the command is typed, e.g. "/tp [target] <position>"
+func (c *Command) Usage() string {
+	usage := c.usage()
+	for parent := c.parent; parent != nil; parent = parent.parent {
+		usage = parent.Name + " " + usage
+	}
+
+	return "/" + usage
+}
+
+func (c *Command) usage() string {
+	parts := []string{c.Name}
+	if c.Run == nil && len(c.Subcommands) > 0 {
+		names := []string{}
+		for _, sub := range c.Subcommands {
+			names = append(names, sub.Name)
+		}
+		parts = append(parts, strings.Join(names, "|"))
+	}
+	for _, arg := range c.Args {
+		if arg.Optional {
+			parts = append(parts, "["+arg.Name+"]")
+		} else {
+			parts = append(parts, "<"+arg.Name+">")
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (c *Command) subcommand(name string) *Command {
+	for _, sub := range c.Subcommands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+
+	return nil
+}
+
+// Arg is a typed argument of a command
+type Arg struct {
+	Name     string
+	Type     ArgType
+	Optional bool // pode faltar; os seguintes são tentados no lugar dele
+}
+
+// Context is given to a command while it runs, with its parsed arguments
+type Context struct {
+	Source  *Source
+	Command *Command
+
+	values map[string]interface{}
+	output []string
+}
+
+// Has tells if the optional argument name was given
+func (c *Context) Has(name string) bool {
+	_, ok := c.values[name]
+	return ok
+}
+
+// Value returns the parsed argument name, nil if it wasn't given
+func (c *Context) Value(name string) interface{} {
+	return c.values[name]
+}
+
+// Printf adds a line to the output of the command
+func (c *Context) Printf(format string, a ...interface{}) {
+	c.output = append(c.output, fmt.Sprintf(format, a...))
+}
+
+// Console parses and runs the registered commands and remembers the lines
+// typed in it
+type Console struct {
+	History History
+
+	commands map[string]*Command
+}
+
+// NewConsole creates a console with the given commands and /help
+func NewConsole(commands ...*Command) *Console {
+	c := &Console{commands: map[string]*Command{}}
+	c.Register(c.helpCommand())
+	for _, command := range commands {
+		c.Register(command)
+	}
+
+	return c
+}
+
+// Register adds a command, replacing one with the same name
+func (c *Console) Register(command *Command) {
+	c.commands[command.Name] = command
+	setParents(command)
+}
+
+func setParents(command *Command) {
+	for _, sub := range command.Subcommands {
+		sub.parent = command
+		setParents(sub)
+	}
+}
+
+// Commands returns the commands source can run, sorted by name
+func (c *Console) Commands(source *Source) []*Command {
+	commands := []*Command{}
+	for _, command := range c.commands {
+		if source.Permission >= command.Permission {
+			commands = append(commands, command)
+		}
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Name < commands[j].Name
+	})
+
+	return commands
+}
+
+// Lookup returns the command called name if source can run it
+func (c *Console) Lookup(source *Source, name string) (*Command, error) {
+	command, ok := c.commands[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown command /%s, see /help", name)
+	}
+	if source.Permission < command.Permission {
+		return nil, fmt.Errorf("you are not allowed to use /%s", name)
+	}
+
+	return command, nil
+}
+
+// Execute runs a line typed in the console, with or without the leading
+// slash, and returns what the command printed
+func (c *Console) Execute(source *Source, line string) ([]string, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, nil
+	}
+	c.History.Add(line)
+
+	tokens, _, err := tokenize(strings.TrimPrefix(line, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("missing command, see /help")
+	}
+
+	command, err := c.Lookup(source, tokens[0])
+	if err != nil {
+		return nil, err
+	}
+	path := tokens[0]
+	tokens = tokens[1:]
+	for len(tokens) > 0 {
+		sub := command.subcommand(tokens[0])
+		if sub == nil {
+			break
+		}
+		path += " " + sub.Name
+		if source.Permission < sub.Permission {
+			return nil, fmt.Errorf("you are not allowed to use /%s", path)
+		}
+		command, tokens = sub, tokens[1:]
+	}
+	if command.Run == nil {
+		return nil, fmt.Errorf("usage: %s", command.Usage())
+	}
+
+	ctx := &Context{Source: source, Command: command, values: map[string]interface{}{}}
+	if err := parseArgs(source, command.Args, tokens, ctx.values); err != nil {
+		return nil, fmt.Errorf("%v\nusage: %s", err, command.Usage())
+	}
+	if err := command.Run(ctx); err != nil {
+		return ctx.output, err
+	}
+
+	return ctx.output, nil
+}
+
+// Divide a linha em palavras separadas por espaços. Aspas juntam espaços em
+// uma palavra, como "dois blocos", e dentro delas \ escapa o caractere
+// seguinte. starts é onde cada palavra começa na linha; o erro diz que faltou
+// fechar as aspas, e a última palavra vai até o fim da linha.
+func tokenize(line string) (tokens []string, starts []int, err error) {
+	var word strings.Builder
+	inWord, quoted, escaped := false, false, false
+	begin := func(i int) {
+		if !inWord {
+			inWord = true
+			starts = append(starts, i)
+		}
+	}
+
+	for i, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			begin(i)
+			quoted = !quoted
+		case !quoted && unicode.IsSpace(r):
+			if inWord {
+				tokens = append(tokens, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			begin(i)
+			word.WriteRune(r)
+		}
+	}
+	if inWord {
+		tokens = append(tokens, word.String())
+	}
+	if quoted {
+		return tokens, starts, fmt.Errorf("missing closing quote")
+	}
+
+	return tokens, starts, nil
+}
+
+// Põe a palavra entre aspas se ela não puder ser lida sem elas
+func quote(word string) string {
+	if word != "" && !strings.ContainsAny(word, " \t\"\\") {
+		return word
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(word) + `"`
+}
+
+// Reads tokens into values, trying the optional arguments both given and left out
+func parseArgs(source *Source, args []Arg, tokens []string, values map[string]interface{}) error {
+	if len(args) == 0 {
+		if len(tokens) > 0 {
+			return fmt.Errorf("unexpected %q", tokens[0])
+		}
+		return nil
+	}
+
+	arg := args[0]
+	count := arg.Type.Tokens()
+	var err error
+	if len(tokens) < count {
+		err = fmt.Errorf("missing <%s>", arg.Name)
+	} else if value, parseErr := arg.Type.Parse(source, tokens[:count]); parseErr != nil {
+		err = fmt.Errorf("<%s>: %v", arg.Name, parseErr)
+	} else {
+		values[arg.Name] = value
+		if err = parseArgs(source, args[1:], tokens[count:], values); err == nil {
+			return nil
+		}
+		delete(values, arg.Name)
+	}
+
+	if arg.Optional {
+		if skipped := parseArgs(source, args[1:], tokens, values); skipped == nil || len(tokens) < count {
+			return skipped
+		}
+	}
+
+	return err
+}
+
+// Complete returns the ways to finish the last word of line, each as the
+// whole line, for the tab key
+func (c *Console) Complete(source *Source, line string) []string {
+	slash := strings.HasPrefix(line, "/")
+	body := strings.TrimPrefix(line, "/")
+
+	// a palavra completada é a última, ou uma nova depois de um espaço fora das aspas
+	words, starts, open := tokenize(body)
+	if open == nil && (body == "" || unicode.IsSpace(rune(body[len(body)-1]))) {
+		words, starts = append(words, ""), append(starts, len(body))
+	}
+	done, prefix := words[:len(words)-1], words[len(words)-1]
+	head := body[:starts[len(starts)-1]]
+	if slash {
+		head = "/" + head
+	}
+
+	var candidates []string
+	if len(done) == 0 {
+		for _, command := range c.Commands(source) {
+			candidates = append(candidates, command.Name)
+		}
+	} else if command, err := c.Lookup(source, done[0]); err == nil {
+		done = done[1:]
+		for len(done) > 0 && command.subcommand(done[0]) != nil {
+			command, done = command.subcommand(done[0]), done[1:]
+		}
+		if len(done) == 0 {
+			for _, sub := range command.Subcommands {
+				if source.Permission >= sub.Permission {
+					candidates = append(candidates, sub.Name)
+				}
+			}
+		}
+		candidates = append(candidates, completeArgs(source, command.Args, done)...)
+	}
+
+	seen := map[string]bool{}
+	lines := []string{}
+	for _, candidate := range candidates {
+		if !strings.HasPrefix(candidate, prefix) || seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+		lines = append(lines, head+quote(candidate))
+	}
+	sort.Strings(lines)
+
+	return lines
+}
+
+// Sugestões para a palavra depois de done, em qualquer argumento em que ela possa cair
+func completeArgs(source *Source, args []Arg, done []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+
+	arg := args[0]
+	count := arg.Type.Tokens()
+	var candidates []string
+	if len(done) < count {
+		candidates = arg.Type.Suggest(source, len(done))
+	} else if _, err := arg.Type.Parse(source, done[:count]); err == nil {
+		candidates = completeArgs(source, args[1:], done[count:])
+	}
+	if arg.Optional {
+		candidates = append(candidates, completeArgs(source, args[1:], done)...)
+	}
+
+	return candidates
+}
+
+// Int returns the integer argument name, 0 if it wasn't given
+func (c *Context) Int(name string) int {
+	value, _ := c.values[name].(int)
+	return value
+}
+
+// Float returns the number argument name, 0 if it wasn't given
+func (c *Context) Float(name string) float64 {
+	value, _ := c.values[name].(float64)
+	return value
+}
+
+// String returns the choice argument name, "" if it wasn't given
+func (c *Context) String(name string) string {
+	value, _ := c.values[name].(string)
+	return value
+}
+
+// Position returns the Position argument name, which must not be a block position
+func (c *Context) Position(name string) mgl32.Vec3 {
+	value, _ := c.values[name].(mgl32.Vec3)
+	return value
+}
+
+// BlockPosition returns the block Position argument name
+func (c *Context) BlockPosition(name string) [3]int {
+	value, _ := c.values[name].([3]int)
+	return value
+}
+
+// Block returns the block type argument name
+func (c *Context) Block(name string) block.BlockType {
+	value, _ := c.values[name].(block.BlockType)
+	return value
+}
+
+// Player returns the player argument name or, if it wasn't given, the
+// player running the command
+func (c *Context) Player(name string) (*player.Player, error) {
+	if value, ok := c.values[name].(*player.Player); ok {
+		return value, nil
+	}
+
+	return c.Source.FindPlayer("@s")
+}
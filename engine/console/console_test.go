@@ -0,0 +1,304 @@
+package console
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/player"
+)
+
+// Comandos de teste, um para cada tipo de argumento, além dos do jogo
+func testCommands() []*Command {
+	return append(Builtins(),
+		&Command{
+			Name:       "echo",
+			Permission: PermissionPlayer,
+			Args: []Arg{
+				{Name: "times", Type: Int{1, 3}, Optional: true},
+				{Name: "word", Type: Choice{[]string{"hello", "two words"}}},
+			},
+			Run: func(ctx *Context) error {
+				times := 1
+				if ctx.Has("times") {
+					times = ctx.Int("times")
+				}
+				ctx.Printf("%d %s", times, ctx.String("word"))
+				return nil
+			},
+		},
+		&Command{
+			Name:       "where",
+			Permission: PermissionPlayer,
+			Args:       []Arg{{Name: "position", Type: Position{Block: true}}},
+			Run: func(ctx *Context) error {
+				position := ctx.BlockPosition("position")
+				ctx.Printf("%d %d %d", position[0], position[1], position[2])
+				return nil
+			},
+		},
+		&Command{
+			Name:       "scale",
+			Permission: PermissionPlayer,
+			Args:       []Arg{{Name: "factor", Type: Float{0, 2}}},
+			Run: func(ctx *Context) error {
+				ctx.Printf("%.2f", ctx.Float("factor"))
+				return nil
+			},
+		},
+		&Command{
+			Name:       "fail",
+			Permission: PermissionPlayer,
+			Run: func(ctx *Context) error {
+				ctx.Printf("before")
+				return fmt.Errorf("failed")
+			},
+		},
+	)
+}
+
+func testSource(permission Permission) *Source {
+	steve := &player.Player{Name: "steve", Position: mgl32.Vec4{10.4, 33, -2.6, 1}}
+	alex := &player.Player{Name: "alex"}
+
+	return &Source{Player: steve, Players: []*player.Player{steve, alex}, Permission: permission}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		line       string
+		want       []string
+		wantStarts []int
+		wantErr    bool
+	}{
+		{"", nil, nil, false},
+		{"  a  b ", []string{"a", "b"}, []int{2, 5}, false},
+		{`say "two words" x`, []string{"say", "two words", "x"}, []int{0, 4, 16}, false},
+		{`"" x`, []string{"", "x"}, []int{0, 3}, false},
+		{`a"b c"d`, []string{"ab cd"}, []int{0}, false},
+		{`"escaped \" quote" "back\\slash"`, []string{`escaped " quote`, `back\slash`}, []int{0, 19}, false},
+		{`fora\ das aspas`, []string{`fora\`, "das", "aspas"}, []int{0, 6, 10}, false},
+		{"\ttab\tseparated", []string{"tab", "separated"}, []int{1, 5}, false},
+		{`open "quote here`, []string{"open", "quote here"}, []int{0, 5}, true},
+	}
+
+	for _, test := range tests {
+		got, starts, err := tokenize(test.line)
+		if (err != nil) != test.wantErr {
+			t.Errorf("tokenize(%q) error = %v, wantErr %v", test.line, err, test.wantErr)
+		}
+		if !reflect.DeepEqual(got, test.want) || !reflect.DeepEqual(starts, test.wantStarts) {
+			t.Errorf("tokenize(%q) = %q at %v, want %q at %v", test.line, got, starts, test.want, test.wantStarts)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		word, want string
+	}{
+		{"hello", "hello"},
+		{"two words", `"two words"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`back\slash`, `"back\\slash"`},
+		{"", `""`},
+	}
+
+	for _, test := range tests {
+		if got := quote(test.word); got != test.want {
+			t.Errorf("quote(%q) = %s, want %s", test.word, got, test.want)
+		}
+
+		// a palavra entre aspas é lida de volta igual
+		tokens, _, err := tokenize(quote(test.word))
+		if err != nil || len(tokens) != 1 || tokens[0] != test.word {
+			t.Errorf("tokenize(quote(%q)) = %q, %v", test.word, tokens, err)
+		}
+	}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		permission Permission
+		noPlayer   bool
+		want       []string
+		wantErr    string // trecho do erro, vazio se não houver
+	}{
+		{"sem argumentos opcionais", "/echo hello", PermissionPlayer, false, []string{"1 hello"}, ""},
+		{"sem barra", "echo 2 hello", PermissionPlayer, false, []string{"2 hello"}, ""},
+		{"espaços sobrando", "  /echo   3   hello  ", PermissionPlayer, false, []string{"3 hello"}, ""},
+		{"aspas", `/echo "two words"`, PermissionPlayer, false, []string{"1 two words"}, ""},
+		{"aspas e opcional", `/echo 3 "two words"`, PermissionPlayer, false, []string{"3 two words"}, ""},
+		{"aspas sem fechar", `/echo "two words`, PermissionPlayer, false, nil, "missing closing quote"},
+		{"escolha inválida", "/echo two words", PermissionPlayer, false, nil, "usage: /echo [times] <word>"},
+		{"inteiro fora do intervalo", "/echo 9 hello", PermissionPlayer, false, nil, "must be between 1 and 3, got 9"},
+		{"argumento sobrando", "/scale 1 2", PermissionPlayer, false, nil, `unexpected "2"`},
+		{"argumento faltando", "/echo", PermissionPlayer, false, nil, "missing <word>"},
+		{"número", "/scale 1.5", PermissionPlayer, false, []string{"1.50"}, ""},
+		{"não é número", "/scale NaN", PermissionPlayer, false, nil, "expected a number"},
+		{"número fora do intervalo", "/scale 3", PermissionPlayer, false, nil, "must be between 0 and 2"},
+		{"posição absoluta", "/where 1 2.4 -2.6", PermissionPlayer, false, []string{"1 2 -3"}, ""},
+		{"posição relativa", "/where ~ ~1 ~-0.6", PermissionPlayer, false, []string{"10 34 -3"}, ""},
+		{"posição relativa sem jogador", "/where ~ 0 0", PermissionPlayer, true, nil, "~ needs a player"},
+		{"posição incompleta", "/where 1 2", PermissionPlayer, false, nil, "missing <position>"},
+		{"coordenada inválida", "/where 1 2 x", PermissionPlayer, false, nil, `expected a coordinate, got "x"`},
+		{"comando desconhecido", "/nothing", PermissionPlayer, false, nil, "unknown command /nothing"},
+		{"sem comando", "/", PermissionPlayer, false, nil, "missing command"},
+		{"linha vazia", "   ", PermissionPlayer, false, nil, ""},
+		{"sem permissão", "/gamemode creative", PermissionPlayer, false, nil, "not allowed to use /gamemode"},
+		{"subcomando sem permissão", "/time set noon", PermissionPlayer, false, nil, "not allowed to use /time set"},
+		{"operador", "/regen chunk", PermissionCheats, false, nil, "not allowed to use /regen"},
+		{"subcomando faltando", "/time", PermissionPlayer, false, nil, "usage: /time set|query"},
+		{"jogador", "/gamemode creative alex", PermissionCheats, false, []string{"Set the game mode of alex to creative"}, ""},
+		{"jogador por @s", "/gamemode creative @s", PermissionCheats, false, []string{"Set the game mode of steve to creative"}, ""},
+		{"jogador desconhecido", "/gamemode creative bob", PermissionCheats, false, nil, `no player named "bob"`},
+		{"sem mundo", "/seed", PermissionPlayer, false, nil, "no world is loaded"},
+		{"erro do comando", "/fail", PermissionPlayer, false, []string{"before"}, "failed"},
+		{"ajuda", "/help where", PermissionPlayer, false, []string{"/where <position> - "}, ""},
+	}
+
+	for _, test := range tests {
+		console := NewConsole(testCommands()...)
+		source := testSource(test.permission)
+		if test.noPlayer {
+			source.Player = nil
+		}
+
+		got, err := console.Execute(source, test.line)
+		if test.wantErr == "" && err != nil {
+			t.Errorf("%s: Execute(%q) error %v", test.name, test.line, err)
+			continue
+		}
+		if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+			t.Errorf("%s: Execute(%q) error = %v, want %q", test.name, test.line, err, test.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Execute(%q) = %q, want %q", test.name, test.line, got, test.want)
+		}
+	}
+}
+
+func TestExecuteChangesPlayer(t *testing.T) {
+	console := NewConsole(testCommands()...)
+	source := testSource(PermissionCheats)
+
+	if _, err := console.Execute(source, "/gamemode creative"); err != nil {
+		t.Fatal(err)
+	}
+	if source.Player.GameMode != player.Creative {
+		t.Errorf("game mode %v, want creative", source.Player.GameMode)
+	}
+	if source.Players[1].GameMode != player.Survival {
+		t.Errorf("/gamemode without target changed another player")
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		line       string
+		permission Permission
+		want       []string
+	}{
+		{"/", PermissionCheats, []string{"/echo", "/fail", "/fill", "/gamemode", "/give", "/help", "/scale", "/seed", "/setblock", "/time", "/tp", "/where"}},
+		{"/", PermissionPlayer, []string{"/echo", "/fail", "/help", "/scale", "/seed", "/time", "/where"}},
+		{"/se", PermissionCheats, []string{"/seed", "/setblock"}},
+		{"se", PermissionCheats, []string{"seed", "setblock"}},
+		{"/time ", PermissionCheats, []string{"/time query", "/time set"}},
+		{"/time ", PermissionPlayer, []string{"/time query"}},
+		{"/time set no", PermissionCheats, []string{"/time set noon"}},
+		{"/echo ", PermissionPlayer, []string{`/echo "two words"`, "/echo hello"}},
+		{`/echo "tw`, PermissionPlayer, []string{`/echo "two words"`}},
+		{`/echo "two `, PermissionPlayer, []string{`/echo "two words"`}},
+		{"/echo 2 h", PermissionPlayer, []string{"/echo 2 hello"}},
+		{"/echo hello ", PermissionPlayer, nil},
+		{"/gamemode cr", PermissionCheats, []string{"/gamemode creative"}},
+		{"/gamemode creative ", PermissionCheats, []string{"/gamemode creative @s", "/gamemode creative alex", "/gamemode creative steve"}},
+		{"/where ", PermissionPlayer, []string{"/where ~"}},
+		{"/where ~ ~ ", PermissionPlayer, []string{"/where ~ ~ ~"}},
+		{"/help ti", PermissionPlayer, []string{"/help time"}},
+		{"/regen ", PermissionCheats, nil},
+		{"/nothing ", PermissionCheats, nil},
+		{"/zz", PermissionCheats, nil},
+	}
+
+	for _, test := range tests {
+		console := NewConsole(testCommands()...)
+		got := console.Complete(testSource(test.permission), test.line)
+		if len(got) == 0 && len(test.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Complete(%q) = %q, want %q", test.line, got, test.want)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	var history History
+	for _, line := range []string{"a", "b", "b", "c"} {
+		history.Add(line)
+	}
+	if !reflect.DeepEqual(history.Lines(), []string{"a", "b", "c"}) {
+		t.Fatalf("Lines() = %q, want a b c", history.Lines())
+	}
+
+	steps := []struct {
+		previous bool // Previous, senão Next
+		want     string
+		wantOk   bool
+	}{
+		{true, "c", true},
+		{true, "b", true},
+		{true, "a", true},
+		{true, "", false},
+		{false, "b", true},
+		{false, "c", true},
+		{false, "", true}, // a linha nova, vazia
+		{false, "", false},
+		{true, "c", true},
+	}
+	for i, step := range steps {
+		var line string
+		var ok bool
+		if step.previous {
+			line, ok = history.Previous()
+		} else {
+			line, ok = history.Next()
+		}
+		if line != step.want || ok != step.wantOk {
+			t.Errorf("step %d: got (%q, %v), want (%q, %v)", i, line, ok, step.want, step.wantOk)
+		}
+	}
+
+	// adicionar volta para o fim
+	history.Add("d")
+	if line, _ := history.Previous(); line != "d" {
+		t.Errorf("Previous() after Add = %q, want d", line)
+	}
+
+	var long History
+	for i := 0; i < historySize+50; i++ {
+		long.Add(fmt.Sprintf("line %d", i))
+	}
+	if lines := long.Lines(); len(lines) != historySize || lines[0] != "line 50" {
+		t.Errorf("history kept %d lines starting at %q, want %d starting at \"line 50\"", len(lines), lines[0], historySize)
+	}
+}
+
+func TestExecuteAddsToHistory(t *testing.T) {
+	console := NewConsole(testCommands()...)
+	source := testSource(PermissionPlayer)
+
+	// as linhas com erro também ficam, para serem corrigidas
+	for _, line := range []string{"/echo hello", "/echo hello", "/nothing", "  "} {
+		console.Execute(source, line)
+	}
+	if want := []string{"/echo hello", "/nothing"}; !reflect.DeepEqual(console.History.Lines(), want) {
+		t.Errorf("history %q, want %q", console.History.Lines(), want)
+	}
+}
@@ -0,0 +1,52 @@
+package console
+
+// Linhas guardadas no histórico do console
+const historySize = 100
+
+// History is the list of lines typed in the console, browsed with the arrow
+// keys from the newest to the oldest
+type History struct {
+	lines []string
+	index int // len(lines) é a linha nova, ainda não enviada
+}
+
+// Add appends a line and goes back to the end of the history. A line equal
+// to the last one isn't repeated.
+func (h *History) Add(line string) {
+	if len(h.lines) == 0 || h.lines[len(h.lines)-1] != line {
+		h.lines = append(h.lines, line)
+		if len(h.lines) > historySize {
+			h.lines = h.lines[len(h.lines)-historySize:]
+		}
+	}
+	h.index = len(h.lines)
+}
+
+// Previous returns the line before the current one. ok is false at the oldest.
+func (h *History) Previous() (line string, ok bool) {
+	if h.index == 0 {
+		return "", false
+	}
+
+	h.index--
+	return h.lines[h.index], true
+}
+
+// Next returns the line after the current one, or an empty line past the
+// newest. ok is false if already there.
+func (h *History) Next() (line string, ok bool) {
+	if h.index >= len(h.lines) {
+		return "", false
+	}
+
+	h.index++
+	if h.index == len(h.lines) {
+		return "", true
+	}
+	return h.lines[h.index], true
+}
+
+// Lines returns the history, the oldest line first
+func (h *History) Lines() []string {
+	return h.lines
+}
@@ -0,0 +1,300 @@
+package controls
+
+import "github.com/go-gl/glfw/v3.3/glfw"
+
+// Action is something the player does, bound to keys and mouse buttons
+type Action string
+
+const (
+	ActionMoveForward    Action = "move_forward"
+	ActionMoveBack       Action = "move_back"
+	ActionMoveLeft       Action = "move_left"
+	ActionMoveRight      Action = "move_right"
+	ActionJump           Action = "jump"
+	ActionSprint         Action = "sprint"
+	ActionBreak          Action = "break"
+	ActionPlace          Action = "place"
+	ActionToggleView     Action = "toggle_view"
+	ActionToggleGameMode Action = "toggle_game_mode"
+	ActionWireframe      Action = "wireframe"
+	ActionDebug          Action = "debug"
+	ActionPause          Action = "pause"
+	ActionBack           Action = "back" // fecha a tela de cima, ou pausa o jogo
+	ActionFullscreen     Action = "fullscreen"
+	ActionChat           Action = "chat"
+	ActionCommand        Action = "command" // abre o console já com a barra
+	ActionHotbar1        Action = "hotbar_1"
+	ActionHotbar2        Action = "hotbar_2"
+	ActionHotbar3        Action = "hotbar_3"
+	ActionHotbar4        Action = "hotbar_4"
+	ActionHotbar5        Action = "hotbar_5"
+	ActionHotbar6        Action = "hotbar_6"
+	ActionHotbar7        Action = "hotbar_7"
+	ActionHotbar8        Action = "hotbar_8"
+)
+
+// Actions lists every action, in the order of the controls screen
+var Actions = []Action{
+	ActionMoveForward, ActionMoveBack, ActionMoveLeft, ActionMoveRight, ActionJump, ActionSprint,
+	ActionBreak, ActionPlace, ActionToggleView, ActionToggleGameMode, ActionWireframe, ActionDebug, ActionPause, ActionBack, ActionFullscreen,
+	ActionChat, ActionCommand,
+	ActionHotbar1, ActionHotbar2, ActionHotbar3, ActionHotbar4, ActionHotbar5, ActionHotbar6, ActionHotbar7, ActionHotbar8,
+}
+
+// Slots da barra de itens, na ordem
+var HotbarActions = []Action{
+	ActionHotbar1, ActionHotbar2, ActionHotbar3, ActionHotbar4, ActionHotbar5, ActionHotbar6, ActionHotbar7, ActionHotbar8,
+}
+
+// Bindings maps each action to the chords that trigger it
+type Bindings map[Action][]Chord
+
+func DefaultBindings() Bindings {
+	return Bindings{
+		ActionMoveForward:    {{Key(glfw.KeyW)}},
+		ActionMoveBack:       {{Key(glfw.KeyS)}},
+		ActionMoveLeft:       {{Key(glfw.KeyA)}},
+		ActionMoveRight:      {{Key(glfw.KeyD)}},
+		ActionJump:           {{Key(glfw.KeySpace)}},
+		ActionSprint:         {{Key(glfw.KeyLeftShift)}},
+		ActionBreak:          {{MouseButton(glfw.MouseButtonLeft)}},
+		ActionPlace:          {{MouseButton(glfw.MouseButtonRight)}},
+		ActionToggleView:     {{Key(glfw.KeyQ)}},
+		ActionToggleGameMode: {{Key(glfw.KeyG)}},
+		ActionWireframe:      {{Key(glfw.KeyZ)}},
+		ActionDebug:          {{Key(glfw.KeyF3)}},
+		ActionPause:          {{Key(glfw.KeyTab)}},
+		ActionBack:           {{Key(glfw.KeyEscape)}},
+		ActionFullscreen:     {{Key(glfw.KeyF11)}},
+		ActionChat:           {{Key(glfw.KeyT)}},
+		ActionCommand:        {{Key(glfw.KeySlash)}},
+		ActionHotbar1:        {{Key(glfw.Key1)}},
+		ActionHotbar2:        {{Key(glfw.Key2)}},
+		ActionHotbar3:        {{Key(glfw.Key3)}},
+		ActionHotbar4:        {{Key(glfw.Key4)}},
+		ActionHotbar5:        {{Key(glfw.Key5)}},
+		ActionHotbar6:        {{Key(glfw.Key6)}},
+		ActionHotbar7:        {{Key(glfw.Key7)}},
+		ActionHotbar8:        {{Key(glfw.Key8)}},
+	}
+}
+
+// Clone copies the bindings, so they can be changed without affecting the original
+func (b Bindings) Clone() Bindings {
+	clone := make(Bindings, len(b))
+	for action, chords := range b {
+		clone[action] = append([]Chord(nil), chords...)
+	}
+
+	return clone
+}
+
+// Conflicts returns the other actions that chord already triggers, in the
+// order of Actions
+func (b Bindings) Conflicts(action Action, chord Chord) []Action {
+	var conflicts []Action
+	for _, other := range Actions {
+		if other == action {
+			continue
+		}
+		for _, bound := range b[other] {
+			if bound.Equal(chord) {
+				conflicts = append(conflicts, other)
+				break
+			}
+		}
+	}
+
+	return conflicts
+}
+
+// ActionMap turns input events into action states. The events are fed as
+// they arrive and Update, once per frame, computes which actions are held,
+// so the pressed and released edges last exactly one frame. Feeding
+// synthetic events drives it without a window.
+type ActionMap struct {
+	bindings Bindings
+	down     map[Input]bool
+	latched  map[Input]bool // pressionadas desde o último Update, mesmo se já soltas
+	held     map[Action]bool
+	last     map[Action]bool
+	toggled  map[Action]bool
+
+	capture     func(Chord)
+	captureMods Chord
+}
+
+func NewActionMap(bindings Bindings) *ActionMap {
+	return &ActionMap{
+		bindings: bindings.Clone(),
+		down:     map[Input]bool{},
+		latched:  map[Input]bool{},
+		held:     map[Action]bool{},
+		last:     map[Action]bool{},
+		toggled:  map[Action]bool{},
+	}
+}
+
+// DefaultActions is fed by the window callbacks and read through Controls
+var DefaultActions = NewActionMap(DefaultBindings())
+
+// SetBindings replaces every binding
+func (m *ActionMap) SetBindings(bindings Bindings) {
+	m.bindings = bindings.Clone()
+}
+
+// Bindings returns a copy of the current bindings
+func (m *ActionMap) Bindings() Bindings {
+	return m.bindings.Clone()
+}
+
+// Bind replaces the chords of a single action
+func (m *ActionMap) Bind(action Action, chords ...Chord) {
+	m.bindings[action] = append([]Chord(nil), chords...)
+}
+
+// Feed records a key or mouse button going down or up
+func (m *ActionMap) Feed(input Input, down bool) {
+	if m.capture != nil && m.feedCapture(input, down) {
+		return
+	}
+
+	m.down[input] = down
+	if down {
+		m.latched[input] = true
+	}
+}
+
+// Update computes the state of the actions from the inputs fed since the last frame
+func (m *ActionMap) Update() {
+	m.last, m.held = m.held, m.last
+	for action := range m.held {
+		delete(m.held, action)
+	}
+
+	for action, chords := range m.bindings {
+		for _, chord := range chords {
+			if m.chordDown(chord) && !m.shadowed(chord) {
+				m.held[action] = true
+				break
+			}
+		}
+	}
+
+	for action := range m.held {
+		if m.Pressed(action) {
+			m.toggled[action] = !m.toggled[action]
+		}
+	}
+
+	for input := range m.latched {
+		delete(m.latched, input)
+	}
+}
+
+func (m *ActionMap) chordDown(chord Chord) bool {
+	if len(chord) == 0 {
+		return false
+	}
+
+	for _, input := range chord {
+		if !m.down[input] && !m.latched[input] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// A chord is shadowed by a longer one being held that contains it, so that
+// LeftControl+S doesn't also trigger the action bound to S
+func (m *ActionMap) shadowed(chord Chord) bool {
+	for _, chords := range m.bindings {
+		for _, other := range chords {
+			if len(other) > len(chord) && other.Contains(chord) && m.chordDown(other) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Held tells if the action is active this frame
+func (m *ActionMap) Held(action Action) bool {
+	return m.held[action]
+}
+
+// Pressed tells if the action became active this frame
+func (m *ActionMap) Pressed(action Action) bool {
+	return m.held[action] && !m.last[action]
+}
+
+// Released tells if the action stopped being active this frame
+func (m *ActionMap) Released(action Action) bool {
+	return !m.held[action] && m.last[action]
+}
+
+// Toggled flips each time the action is pressed
+func (m *ActionMap) Toggled(action Action) bool {
+	return m.toggled[action]
+}
+
+// SetToggled changes the toggle state of the action, e.g. when loading a game
+func (m *ActionMap) SetToggled(action Action, toggled bool) {
+	m.toggled[action] = toggled
+}
+
+// CaptureChord calls callback with the next chord pressed, for rebinding. A
+// modifier pressed alone is only captured when released without another input.
+// A chord of ActionBack cancels the capture, calling callback with nil. The
+// captured inputs don't reach the actions.
+func (m *ActionMap) CaptureChord(callback func(Chord)) {
+	m.capture = callback
+	m.captureMods = nil
+}
+
+// CancelCapture stops waiting for a chord
+func (m *ActionMap) CancelCapture() {
+	m.capture = nil
+	m.captureMods = nil
+}
+
+// Capturing tells if a chord is being waited for
+func (m *ActionMap) Capturing() bool {
+	return m.capture != nil
+}
+
+func (m *ActionMap) feedCapture(input Input, down bool) bool {
+	if input.IsModifier() {
+		if down {
+			m.captureMods = append(m.captureMods, input)
+			return true
+		}
+		if !m.captureMods.Contains(Chord{input}) {
+			return false
+		}
+		m.finishCapture(append(Chord(nil), m.captureMods...))
+		return true
+	}
+
+	if !down {
+		return false
+	}
+
+	chord := append(append(Chord(nil), m.captureMods...), input)
+	for _, back := range m.bindings[ActionBack] {
+		if back.Equal(chord) {
+			chord = nil
+			break
+		}
+	}
+	m.finishCapture(chord)
+	return true
+}
+
+func (m *ActionMap) finishCapture(chord Chord) {
+	callback := m.capture
+	m.CancelCapture()
+	callback(chord)
+}
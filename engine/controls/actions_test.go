@@ -0,0 +1,349 @@
+package controls
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+const ActionSave Action = "save"
+
+var (
+	keyW     = Key(glfw.KeyW)
+	keyS     = Key(glfw.KeyS)
+	keyEsc   = Key(glfw.KeyEscape)
+	keyCtrl  = Key(glfw.KeyLeftControl)
+	keyShift = Key(glfw.KeyLeftShift)
+	mouse1   = MouseButton(glfw.MouseButtonLeft)
+)
+
+// Uma entrada apertada ou solta
+type feed struct {
+	input Input
+	down  bool
+}
+
+// Estado esperado de uma ação depois de um Update
+type state struct {
+	held, pressed, released bool
+}
+
+func check(t *testing.T, m *ActionMap, frame string, action Action, want state) {
+	t.Helper()
+
+	got := state{m.Held(action), m.Pressed(action), m.Released(action)}
+	if got != want {
+		t.Errorf("%s: %s = %+v, want %+v", frame, action, got, want)
+	}
+}
+
+func TestEdges(t *testing.T) {
+	m := NewActionMap(DefaultBindings())
+
+	m.Feed(keyW, true)
+	m.Update()
+	check(t, m, "press", ActionMoveForward, state{held: true, pressed: true})
+
+	m.Update()
+	check(t, m, "hold", ActionMoveForward, state{held: true})
+
+	m.Feed(keyW, false)
+	m.Update()
+	check(t, m, "release", ActionMoveForward, state{released: true})
+
+	m.Update()
+	check(t, m, "idle", ActionMoveForward, state{})
+
+	// apertada e solta entre dois quadros, a ação ainda dura um quadro
+	m.Feed(keyW, true)
+	m.Feed(keyW, false)
+	m.Update()
+	check(t, m, "tap", ActionMoveForward, state{held: true, pressed: true})
+	m.Update()
+	check(t, m, "after tap", ActionMoveForward, state{released: true})
+}
+
+func TestToggled(t *testing.T) {
+	m := NewActionMap(DefaultBindings())
+
+	want := []bool{true, true, false, false, true}
+	for i, pressed := range []bool{true, false, true, false, true} {
+		m.Feed(Key(glfw.KeyZ), pressed)
+		m.Update()
+		if m.Toggled(ActionWireframe) != want[i] {
+			t.Errorf("frame %d: Toggled = %v, want %v", i, m.Toggled(ActionWireframe), want[i])
+		}
+	}
+
+	m.SetToggled(ActionWireframe, false)
+	if m.Toggled(ActionWireframe) {
+		t.Error("SetToggled(false) kept the action toggled")
+	}
+}
+
+func TestChords(t *testing.T) {
+	bindings := DefaultBindings()
+	bindings[ActionSave] = []Chord{{keyCtrl, keyS}}
+	bindings[ActionBreak] = []Chord{{mouse1}, {keyShift, keyW}}
+
+	tests := []struct {
+		name  string
+		down  []Input
+		held  []Action
+		other []Action // não podem estar ativas
+	}{
+		{"tecla sozinha", []Input{keyS}, []Action{ActionMoveBack}, []Action{ActionSave}},
+		{"acorde", []Input{keyCtrl, keyS}, []Action{ActionSave}, []Action{ActionMoveBack}},
+		{"acorde em outra ordem", []Input{keyS, keyCtrl}, []Action{ActionSave}, []Action{ActionMoveBack}},
+		{"modificador sozinho", []Input{keyCtrl}, nil, []Action{ActionSave, ActionMoveBack}},
+		{"segundo acorde da ação", []Input{keyShift, keyW}, []Action{ActionBreak}, []Action{ActionMoveForward, ActionSprint}},
+		{"botão do mouse", []Input{mouse1}, []Action{ActionBreak}, []Action{ActionPlace}},
+		{"acordes independentes", []Input{keyW, mouse1}, []Action{ActionMoveForward, ActionBreak}, nil},
+	}
+
+	for _, test := range tests {
+		m := NewActionMap(bindings)
+		for _, input := range test.down {
+			m.Feed(input, true)
+		}
+		m.Update()
+
+		for _, action := range test.held {
+			if !m.Held(action) {
+				t.Errorf("%s: %s not held", test.name, action)
+			}
+		}
+		for _, action := range test.other {
+			if m.Held(action) {
+				t.Errorf("%s: %s held", test.name, action)
+			}
+		}
+	}
+}
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs []feed
+		want   Chord
+	}{
+		{
+			name:   "tecla",
+			inputs: []feed{{keyW, true}},
+			want:   Chord{keyW},
+		},
+		{
+			name:   "modificador e tecla",
+			inputs: []feed{{keyCtrl, true}, {keyS, true}},
+			want:   Chord{keyCtrl, keyS},
+		},
+		{
+			name:   "modificador sozinho",
+			inputs: []feed{{keyShift, true}, {keyShift, false}},
+			want:   Chord{keyShift},
+		},
+		{
+			name:   "botão do mouse",
+			inputs: []feed{{mouse1, true}},
+			want:   Chord{mouse1},
+		},
+		{
+			name:   "voltar cancela",
+			inputs: []feed{{keyEsc, true}},
+			want:   nil,
+		},
+	}
+
+	for _, test := range tests {
+		m := NewActionMap(DefaultBindings())
+
+		called := false
+		var got Chord
+		m.CaptureChord(func(chord Chord) {
+			called = true
+			got = chord
+		})
+
+		for _, event := range test.inputs {
+			m.Feed(event.input, event.down)
+		}
+		m.Update()
+
+		if !called {
+			t.Errorf("%s: callback not called", test.name)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: captured %v, want %v", test.name, got, test.want)
+		}
+		if m.Capturing() {
+			t.Errorf("%s: still capturing", test.name)
+		}
+
+		// as entradas capturadas não ativam ações, nem o voltar que cancelou
+		for _, action := range Actions {
+			if m.Held(action) {
+				t.Errorf("%s: captured input triggered %s", test.name, action)
+			}
+		}
+	}
+}
+
+func TestRebindBackChord(t *testing.T) {
+	// com voltar em outro acorde, o Esc pode ser associado a outra ação
+	bindings := DefaultBindings()
+	bindings[ActionBack] = []Chord{{keyCtrl, keyW}}
+	m := NewActionMap(bindings)
+
+	var got Chord
+	m.CaptureChord(func(chord Chord) { got = chord })
+	m.Feed(keyEsc, true)
+	if !got.Equal(Chord{keyEsc}) {
+		t.Errorf("captured %v, want Escape", got)
+	}
+
+	got = Chord{keyS}
+	m.CaptureChord(func(chord Chord) { got = chord })
+	m.Feed(keyCtrl, true)
+	m.Feed(keyW, true)
+	if got != nil {
+		t.Errorf("back chord captured %v, want the capture cancelled", got)
+	}
+}
+
+func TestCancelCapture(t *testing.T) {
+	m := NewActionMap(DefaultBindings())
+	m.CaptureChord(func(chord Chord) {
+		t.Errorf("cancelled capture received %v", chord)
+	})
+	m.CancelCapture()
+
+	m.Feed(keyW, true)
+	m.Update()
+	if !m.Held(ActionMoveForward) {
+		t.Error("input after CancelCapture didn't reach the actions")
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	bindings := DefaultBindings()
+	bindings[ActionDebug] = []Chord{{Key(glfw.KeyF3)}, {keyCtrl, keyS}}
+
+	tests := []struct {
+		name   string
+		action Action
+		chord  Chord
+		want   []Action
+	}{
+		{"tecla de outra ação", ActionJump, Chord{keyW}, []Action{ActionMoveForward}},
+		{"a própria ação", ActionMoveForward, Chord{keyW}, nil},
+		{"tecla livre", ActionJump, Chord{Key(glfw.KeyJ)}, nil},
+		{"acorde em outra ordem", ActionJump, Chord{keyS, keyCtrl}, []Action{ActionDebug}},
+		{"parte de um acorde", ActionJump, Chord{keyCtrl}, nil},
+		{"botão do mouse", ActionJump, Chord{mouse1}, []Action{ActionBreak}},
+		{"esc", ActionPause, Chord{keyEsc}, []Action{ActionBack}},
+	}
+
+	for _, test := range tests {
+		got := bindings.Conflicts(test.action, test.chord)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Conflicts(%s, %v) = %v, want %v", test.name, test.action, test.chord, got, test.want)
+		}
+	}
+}
+
+func TestDefaultBindingsHaveNoConflicts(t *testing.T) {
+	bindings := DefaultBindings()
+	for _, action := range Actions {
+		if len(bindings[action]) == 0 {
+			t.Errorf("%s has no default binding", action)
+		}
+		for _, chord := range bindings[action] {
+			if conflicts := bindings.Conflicts(action, chord); len(conflicts) > 0 {
+				t.Errorf("%s: %v also triggers %v", action, chord, conflicts)
+			}
+		}
+	}
+}
+
+func TestParseChord(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Chord
+		wantErr bool
+	}{
+		{"W", Chord{keyW}, false},
+		{"leftcontrol+s", Chord{keyCtrl, keyS}, false},
+		{" LeftShift + Mouse1 ", Chord{keyShift, mouse1}, false},
+		{"Escape", Chord{keyEsc}, false},
+		{"Hyper+S", nil, true},
+		{"", nil, true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseChord(test.text)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseChord(%q) error = %v, wantErr %v", test.text, err, test.wantErr)
+			continue
+		}
+		if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseChord(%q) = %v, want %v", test.text, got, test.want)
+		}
+	}
+}
+
+func TestBindingsJSON(t *testing.T) {
+	bindings := DefaultBindings()
+	bindings[ActionSave] = []Chord{{keyCtrl, keyS}}
+
+	data, err := json.Marshal(bindings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Bindings
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, bindings) {
+		t.Errorf("round trip changed the bindings:\n%v\n%v", decoded, bindings)
+	}
+}
+
+func TestApplyEvents(t *testing.T) {
+	// eventos sintéticos, como os da janela ou de uma gravação
+	events := []Event{
+		{Type: EventKey, Code: int(glfw.KeyW), Action: glfw.Press},
+		{Type: EventKey, Code: int(glfw.KeyW), Action: glfw.Repeat},
+		{Type: EventMouseButton, Code: int(glfw.MouseButtonLeft), Action: glfw.Press},
+	}
+	for _, event := range events {
+		Apply(event)
+	}
+	DefaultActions.Update()
+
+	controls := NewControls(nil)
+	if !controls.Pressed(ActionMoveForward) || !controls.Pressed(ActionBreak) {
+		t.Errorf("applied events didn't press the actions")
+	}
+	if keys := controls.ConsumePressedKeys(); len(keys) != 2 {
+		t.Errorf("queued keys %v, want the press and the repeat", keys)
+	}
+
+	// uma rota desligada não vê as ações
+	route := controls.Route()
+	route.SetEnabled(false)
+	if route.Held(ActionMoveForward) {
+		t.Error("disabled route sees the actions")
+	}
+
+	Apply(Event{Type: EventKey, Code: int(glfw.KeyW), Action: glfw.Release})
+	Apply(Event{Type: EventMouseButton, Code: int(glfw.MouseButtonLeft), Action: glfw.Release})
+	DefaultActions.Update()
+	if !controls.Released(ActionMoveForward) || !controls.Released(ActionBreak) {
+		t.Errorf("released events didn't release the actions")
+	}
+	Reset(0, 0)
+}
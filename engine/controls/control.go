@@ -9,9 +9,13 @@ const (
 	RMB
 )
 
+// Quantas teclas e caracteres ficam guardados enquanto ninguém os consome
+const maxQueuedInput = 64
+
 var (
-	keys         = make(map[int]bool)
-	keysToggling = make(map[int]int)
+	keys        = make(map[int]bool)
+	pressedKeys []glfw.Key // teclas pressionadas (ou repetidas) desde a última leitura
+	typedChars  []rune     // texto digitado desde a última leitura
 )
 
 var (
@@ -24,6 +28,12 @@ var (
 
 type Controls struct {
 	window *glfw.Window
+	route  *route // nil recebe sempre a entrada
+}
+
+// Estado de uma rota, compartilhado pelas cópias de um Controls
+type route struct {
+	enabled bool
 }
 
 func boolFromInt(n int) bool {
@@ -40,12 +50,67 @@ func NewControls(window *glfw.Window) Controls {
 	}
 }
 
+// Route returns controls that read the same input, but only while enabled.
+// Each scene reads the input through its own route, and only the scene on
+// top of the stack has its route enabled. Copies share the route.
+func (c Controls) Route() Controls {
+	c.route = &route{enabled: true}
+	return c
+}
+
+// SetEnabled turns the route on or off. It does nothing on unrouted controls.
+func (c Controls) SetEnabled(enabled bool) {
+	if c.route != nil {
+		c.route.enabled = enabled
+	}
+}
+
+// Enabled tells if these controls are receiving the input
+func (c Controls) Enabled() bool {
+	return c.route == nil || c.route.enabled
+}
+
+// CaptureCursor hides the cursor and locks it to the window, for mouse look,
+// or releases it to point at the interface. A captured cursor uses raw motion
+// if MouseLook asks for it and the system supports it.
+func (c Controls) CaptureCursor(captured bool) {
+	if captured {
+		c.window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+	} else {
+		c.window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+	}
+
+	if glfw.RawMouseMotionSupported() {
+		if captured && MouseLook.RawMotion {
+			c.window.SetInputMode(glfw.RawMouseMotion, glfw.True)
+		} else {
+			c.window.SetInputMode(glfw.RawMouseMotion, glfw.False)
+		}
+	}
+}
+
 func (c Controls) IsDown(key int) bool {
-	return keys[key]
+	return c.Enabled() && keys[key]
 }
 
-func (c Controls) IsToggled(key int) bool {
-	return boolFromInt(keysToggling[key])
+// Held tells if the action is active, see ActionMap
+func (c Controls) Held(action Action) bool {
+	return c.Enabled() && DefaultActions.Held(action)
+}
+
+// Pressed tells if the action became active this frame
+func (c Controls) Pressed(action Action) bool {
+	return c.Enabled() && DefaultActions.Pressed(action)
+}
+
+// Released tells if the action stopped being active this frame
+func (c Controls) Released(action Action) bool {
+	return c.Enabled() && DefaultActions.Released(action)
+}
+
+// Toggled is a state, not an event, so it is read even with the route disabled
+func (c Controls) Toggled(action Action) bool {
+	return DefaultActions.Toggled(action)
 }
 
 func (c Controls) SetKeyStatus(key int, status bool) {
@@ -56,6 +121,34 @@ func (c Controls) StartKeyHandlers() {
 	c.window.SetMouseButtonCallback(MouseButtonCallback)
 	c.window.SetCursorPosCallback(CursorPosCallback)
 	c.window.SetKeyCallback(KeyCallback)
+	c.window.SetCharCallback(CharCallback)
+}
+
+// GetCursorPosition returns the cursor position in window coordinates, origin at the top left
+func (c Controls) GetCursorPosition() (float64, float64) {
+	return c.window.GetCursorPos()
+}
+
+// ConsumePressedKeys returns the keys pressed or repeated since the last call, in order
+func (c Controls) ConsumePressedKeys() []glfw.Key {
+	if !c.Enabled() {
+		return nil
+	}
+
+	pressed := pressedKeys
+	pressedKeys = nil
+	return pressed
+}
+
+// ConsumeTypedChars returns the text typed since the last call
+func (c Controls) ConsumeTypedChars() []rune {
+	if !c.Enabled() {
+		return nil
+	}
+
+	typed := typedChars
+	typedChars = nil
+	return typed
 }
 
 func (c Controls) GetMouseDeltas() (float64, float64) {
@@ -63,7 +156,7 @@ func (c Controls) GetMouseDeltas() (float64, float64) {
 }
 
 func (c Controls) MousePositionChanged() bool {
-	return gMousePosChanged
+	return c.Enabled() && gMousePosChanged
 }
 
 func (c Controls) FinishMousePositionChanged() {
@@ -0,0 +1,91 @@
+package controls
+
+import "github.com/go-gl/glfw/v3.3/glfw"
+
+type EventType uint8
+
+const (
+	EventKey EventType = iota
+	EventMouseButton
+	EventCursor
+	EventChar
+)
+
+// Event is an input event, as received from the window. The callbacks turn
+// the GLFW events into Events and Apply them, so they can be recorded and
+// applied again later without a window.
+type Event struct {
+	Type   EventType
+	Code   int         // glfw.Key, glfw.MouseButton ou o caractere
+	Action glfw.Action // teclas e botões
+	X, Y   float64     // posição do cursor
+}
+
+// Listener, if set, receives every event from the window before it is applied
+var Listener func(event Event)
+
+func dispatch(event Event) {
+	if Listener != nil {
+		Listener(event)
+	}
+	Apply(event)
+}
+
+// Apply updates the input state with an event
+func Apply(event Event) {
+	switch event.Type {
+	case EventKey:
+		key := glfw.Key(event.Code)
+		keys[event.Code] = boolFromInt(int(event.Action))
+
+		if event.Action == glfw.Press || event.Action == glfw.Repeat {
+			if len(pressedKeys) < maxQueuedInput {
+				pressedKeys = append(pressedKeys, key)
+			}
+		}
+
+		if event.Action != glfw.Repeat {
+			DefaultActions.Feed(Key(key), event.Action == glfw.Press)
+		}
+
+	case EventMouseButton:
+		// o clique não mexe na posição do cursor, senão a câmera pularia
+		button := glfw.MouseButton(event.Code)
+		if button == glfw.MouseButtonLeft || button == glfw.MouseButtonRight {
+			keys[event.Code] = event.Action == glfw.Press
+		}
+
+		DefaultActions.Feed(MouseButton(button), event.Action == glfw.Press)
+
+	case EventCursor:
+		gCursorDeltaX = event.X - gLastCursorPosX
+		gCursorDeltaY = event.Y - gLastCursorPosY
+
+		gLastCursorPosX = event.X
+		gLastCursorPosY = event.Y
+
+		gMousePosChanged = true
+
+	case EventChar:
+		if len(typedChars) < maxQueuedInput {
+			typedChars = append(typedChars, rune(event.Code))
+		}
+	}
+}
+
+// CursorPosition returns the last cursor position received
+func CursorPosition() (float64, float64) {
+	return gLastCursorPosX, gLastCursorPosY
+}
+
+// Reset releases every input and drops the queued ones, with the cursor at
+// (x, y), e.g. before applying recorded events
+func Reset(x, y float64) {
+	keys = make(map[int]bool)
+	pressedKeys = nil
+	typedChars = nil
+	gLastCursorPosX, gLastCursorPosY = x, y
+	gCursorDeltaX, gCursorDeltaY = 0, 0
+	gSmoothDeltaX, gSmoothDeltaY = 0, 0
+	gMousePosChanged = false
+}
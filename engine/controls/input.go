@@ -0,0 +1,176 @@
+package controls
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// Input is a keyboard key or a mouse button
+type Input struct {
+	Mouse bool
+	Code  int // glfw.Key, ou glfw.MouseButton se Mouse
+}
+
+func Key(key glfw.Key) Input {
+	return Input{Code: int(key)}
+}
+
+func MouseButton(button glfw.MouseButton) Input {
+	return Input{Mouse: true, Code: int(button)}
+}
+
+// Nomes das entradas nos arquivos de configuração
+var keyNames = map[glfw.Key]string{
+	glfw.KeySpace: "Space", glfw.KeyApostrophe: "Apostrophe", glfw.KeyComma: "Comma",
+	glfw.KeyMinus: "Minus", glfw.KeyPeriod: "Period", glfw.KeySlash: "Slash",
+	glfw.Key0: "0", glfw.Key1: "1", glfw.Key2: "2", glfw.Key3: "3", glfw.Key4: "4",
+	glfw.Key5: "5", glfw.Key6: "6", glfw.Key7: "7", glfw.Key8: "8", glfw.Key9: "9",
+	glfw.KeySemicolon: "Semicolon", glfw.KeyEqual: "Equal",
+	glfw.KeyA: "A", glfw.KeyB: "B", glfw.KeyC: "C", glfw.KeyD: "D", glfw.KeyE: "E",
+	glfw.KeyF: "F", glfw.KeyG: "G", glfw.KeyH: "H", glfw.KeyI: "I", glfw.KeyJ: "J",
+	glfw.KeyK: "K", glfw.KeyL: "L", glfw.KeyM: "M", glfw.KeyN: "N", glfw.KeyO: "O",
+	glfw.KeyP: "P", glfw.KeyQ: "Q", glfw.KeyR: "R", glfw.KeyS: "S", glfw.KeyT: "T",
+	glfw.KeyU: "U", glfw.KeyV: "V", glfw.KeyW: "W", glfw.KeyX: "X", glfw.KeyY: "Y",
+	glfw.KeyZ: "Z", glfw.KeyLeftBracket: "LeftBracket", glfw.KeyBackslash: "Backslash",
+	glfw.KeyRightBracket: "RightBracket", glfw.KeyGraveAccent: "GraveAccent",
+	glfw.KeyEscape: "Escape", glfw.KeyEnter: "Enter", glfw.KeyTab: "Tab",
+	glfw.KeyBackspace: "Backspace", glfw.KeyInsert: "Insert", glfw.KeyDelete: "Delete",
+	glfw.KeyRight: "Right", glfw.KeyLeft: "Left", glfw.KeyDown: "Down", glfw.KeyUp: "Up",
+	glfw.KeyPageUp: "PageUp", glfw.KeyPageDown: "PageDown", glfw.KeyHome: "Home",
+	glfw.KeyEnd: "End", glfw.KeyCapsLock: "CapsLock",
+	glfw.KeyF1: "F1", glfw.KeyF2: "F2", glfw.KeyF3: "F3", glfw.KeyF4: "F4",
+	glfw.KeyF5: "F5", glfw.KeyF6: "F6", glfw.KeyF7: "F7", glfw.KeyF8: "F8",
+	glfw.KeyF9: "F9", glfw.KeyF10: "F10", glfw.KeyF11: "F11", glfw.KeyF12: "F12",
+	glfw.KeyKP0: "KP0", glfw.KeyKP1: "KP1", glfw.KeyKP2: "KP2", glfw.KeyKP3: "KP3",
+	glfw.KeyKP4: "KP4", glfw.KeyKP5: "KP5", glfw.KeyKP6: "KP6", glfw.KeyKP7: "KP7",
+	glfw.KeyKP8: "KP8", glfw.KeyKP9: "KP9", glfw.KeyKPEnter: "KPEnter",
+	glfw.KeyLeftShift: "LeftShift", glfw.KeyRightShift: "RightShift",
+	glfw.KeyLeftControl: "LeftControl", glfw.KeyRightControl: "RightControl",
+	glfw.KeyLeftAlt: "LeftAlt", glfw.KeyRightAlt: "RightAlt",
+	glfw.KeyLeftSuper: "LeftSuper", glfw.KeyRightSuper: "RightSuper",
+}
+
+var mouseButtonNames = map[glfw.MouseButton]string{
+	glfw.MouseButtonLeft:   "Mouse1",
+	glfw.MouseButtonRight:  "Mouse2",
+	glfw.MouseButtonMiddle: "Mouse3",
+	glfw.MouseButton4:      "Mouse4",
+	glfw.MouseButton5:      "Mouse5",
+}
+
+var inputsByName = map[string]Input{}
+
+func init() {
+	for key, name := range keyNames {
+		inputsByName[strings.ToLower(name)] = Key(key)
+	}
+	for button, name := range mouseButtonNames {
+		inputsByName[strings.ToLower(name)] = MouseButton(button)
+	}
+}
+
+func (i Input) String() string {
+	if i.Mouse {
+		if name, ok := mouseButtonNames[glfw.MouseButton(i.Code)]; ok {
+			return name
+		}
+		return fmt.Sprintf("Mouse#%d", i.Code)
+	}
+	if name, ok := keyNames[glfw.Key(i.Code)]; ok {
+		return name
+	}
+	return fmt.Sprintf("Key#%d", i.Code)
+}
+
+// ParseInput reads the name of a key or mouse button, ignoring the case
+func ParseInput(name string) (Input, error) {
+	input, ok := inputsByName[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Input{}, fmt.Errorf("controls: unknown input %q", name)
+	}
+
+	return input, nil
+}
+
+// Modifiers are only bound together with another input, unless bound alone
+func (i Input) IsModifier() bool {
+	if i.Mouse {
+		return false
+	}
+
+	switch glfw.Key(i.Code) {
+	case glfw.KeyLeftShift, glfw.KeyRightShift, glfw.KeyLeftControl, glfw.KeyRightControl,
+		glfw.KeyLeftAlt, glfw.KeyRightAlt, glfw.KeyLeftSuper, glfw.KeyRightSuper:
+		return true
+	}
+	return false
+}
+
+// Chord is a set of inputs held together, written as "LeftControl+S"
+type Chord []Input
+
+func ParseChord(text string) (Chord, error) {
+	var chord Chord
+	for _, name := range strings.Split(text, "+") {
+		input, err := ParseInput(name)
+		if err != nil {
+			return nil, err
+		}
+		chord = append(chord, input)
+	}
+
+	return chord, nil
+}
+
+func (c Chord) String() string {
+	names := make([]string, len(c))
+	for i, input := range c {
+		names[i] = input.String()
+	}
+
+	return strings.Join(names, "+")
+}
+
+// Contains tells if every input of other is part of the chord
+func (c Chord) Contains(other Chord) bool {
+	for _, input := range other {
+		found := false
+		for _, own := range c {
+			if own == input {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Equal tells if both chords have the same inputs, in any order
+func (c Chord) Equal(other Chord) bool {
+	return len(c) == len(other) && c.Contains(other) && other.Contains(c)
+}
+
+func (c Chord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+func (c *Chord) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	chord, err := ParseChord(text)
+	if err != nil {
+		return err
+	}
+	*c = chord
+	return nil
+}
@@ -6,19 +6,10 @@ import (
 
 // Keyboard key callback
 func KeyCallback(window *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-	if key == glfw.KeyEscape && action == glfw.Press {
-		window.SetShouldClose(true)
-	}
-
-	keys[int(key)] = boolFromInt(int(action))
-
-	if action == glfw.Press {
-		current := keysToggling[int(key)]
-		if current == 0 {
-			keysToggling[int(key)] = 1
-		} else {
-			keysToggling[int(key)] = 0
-		}
-	}
+	dispatch(Event{Type: EventKey, Code: int(key), Action: action})
+}
 
+// Text input callback, receives the characters already composed by the keyboard layout
+func CharCallback(window *glfw.Window, char rune) {
+	dispatch(Event{Type: EventChar, Code: int(char)})
 }
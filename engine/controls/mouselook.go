@@ -0,0 +1,54 @@
+package controls
+
+// MouseLookOptions configure how the cursor movement turns the camera
+type MouseLookOptions struct {
+	Sensitivity float64 `json:"sensitivity"` // radianos por pixel
+	InvertY     bool    `json:"invert_y"`
+	Smoothing   float64 `json:"smoothing"`  // 0 desliga, perto de 1 suaviza mais
+	RawMotion   bool    `json:"raw_motion"` // sem a aceleração do sistema, se suportado
+}
+
+func DefaultMouseLookOptions() MouseLookOptions {
+	return MouseLookOptions{Sensitivity: 0.01}
+}
+
+// MouseLook is read by the camera each tick and changed by the settings
+var MouseLook = DefaultMouseLookOptions()
+
+// Suavização máxima, para o giro nunca ficar parado esperando o mouse
+const maxSmoothing = 0.95
+
+// Movimento do cursor suavizado ao longo dos ticks
+var gSmoothDeltaX, gSmoothDeltaY float64
+
+// LookRotation returns how much the camera turns this tick, in radians, from
+// the cursor movement. It is called once per tick, since the smoothing spreads
+// the movement over the next ticks.
+func (c Controls) LookRotation() (yaw, pitch float64) {
+	if !c.Enabled() {
+		gSmoothDeltaX, gSmoothDeltaY = 0, 0
+		return 0, 0
+	}
+
+	dx, dy := 0.0, 0.0
+	if c.MousePositionChanged() {
+		dx, dy = c.GetMouseDeltas()
+	}
+
+	smoothing := MouseLook.Smoothing
+	if smoothing < 0 {
+		smoothing = 0
+	} else if smoothing > maxSmoothing {
+		smoothing = maxSmoothing
+	}
+	gSmoothDeltaX = gSmoothDeltaX*smoothing + dx*(1-smoothing)
+	gSmoothDeltaY = gSmoothDeltaY*smoothing + dy*(1-smoothing)
+
+	yaw = -MouseLook.Sensitivity * gSmoothDeltaX
+	pitch = -MouseLook.Sensitivity * gSmoothDeltaY
+	if MouseLook.InvertY {
+		pitch = -pitch
+	}
+
+	return yaw, pitch
+}
@@ -0,0 +1,96 @@
+package controls
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLookRotation(t *testing.T) {
+	type tick struct {
+		dx, dy     float64 // movimento do cursor no tick
+		unfocused  bool
+		yaw, pitch float64
+	}
+
+	tests := []struct {
+		name    string
+		options MouseLookOptions
+		ticks   []tick
+	}{
+		{
+			name:    "sem suavização",
+			options: MouseLookOptions{Sensitivity: 0.01},
+			ticks: []tick{
+				{dx: 10, dy: 5, yaw: -0.1, pitch: -0.05},
+				{},
+				{dx: -20, yaw: 0.2},
+			},
+		},
+		{
+			name:    "eixo Y invertido",
+			options: MouseLookOptions{Sensitivity: 0.01, InvertY: true},
+			ticks: []tick{
+				{dx: 10, dy: 5, yaw: -0.1, pitch: 0.05},
+				{dy: -10, pitch: -0.1},
+			},
+		},
+		{
+			name:    "suavização espalha o movimento",
+			options: MouseLookOptions{Sensitivity: 0.01, Smoothing: 0.5},
+			ticks: []tick{
+				{dx: 10, dy: 10, yaw: -0.05, pitch: -0.05},
+				{yaw: -0.025, pitch: -0.025},
+				{yaw: -0.0125, pitch: -0.0125},
+			},
+		},
+		{
+			name:    "suavização acima do máximo é limitada",
+			options: MouseLookOptions{Sensitivity: 0.01, Smoothing: 2},
+			ticks: []tick{
+				{dx: 100, yaw: -0.01 * 100 * (1 - maxSmoothing)},
+				{yaw: -0.01 * 100 * (1 - maxSmoothing) * maxSmoothing},
+			},
+		},
+		{
+			name:    "suavização negativa desliga",
+			options: MouseLookOptions{Sensitivity: 0.01, Smoothing: -1},
+			ticks: []tick{
+				{dx: 10, yaw: -0.1},
+				{},
+			},
+		},
+		{
+			name:    "sem foco não gira e esquece a suavização",
+			options: MouseLookOptions{Sensitivity: 0.01, Smoothing: 0.5},
+			ticks: []tick{
+				{dx: 10, yaw: -0.05},
+				{dx: 50, dy: 50, unfocused: true},
+				{},
+			},
+		},
+	}
+
+	defer func(options MouseLookOptions) { MouseLook = options }(MouseLook)
+	for _, test := range tests {
+		Reset(0, 0)
+		MouseLook = test.options
+		controls := NewControls(nil).Route()
+
+		x, y := 0.0, 0.0
+		for i, tick := range test.ticks {
+			controls.SetEnabled(!tick.unfocused)
+			if tick.dx != 0 || tick.dy != 0 {
+				x, y = x+tick.dx, y+tick.dy
+				Apply(Event{Type: EventCursor, X: x, Y: y})
+			}
+
+			// como a câmera, uma leitura por tick
+			yaw, pitch := controls.LookRotation()
+			controls.FinishMousePositionChanged()
+			if math.Abs(yaw-tick.yaw) > 1e-9 || math.Abs(pitch-tick.pitch) > 1e-9 {
+				t.Errorf("%s: tick %d turned (%v, %v), want (%v, %v)", test.name, i, yaw, pitch, tick.yaw, tick.pitch)
+			}
+		}
+	}
+	Reset(0, 0)
+}
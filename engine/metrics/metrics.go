@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+)
+
+// Nomes dos valores publicados pelos subsistemas
+const (
+	FrameTime        = "frame.time"         // segundos do último quadro
+	DrawCalls        = "render.draw_calls"  // por quadro
+	Triangles        = "render.triangles"   // por quadro
+	BlocksDrawn      = "world.blocks_drawn" // blocos desenhados no último tick
+	ChunksLoaded     = "world.chunks_loaded"
+	ChunksGenerated  = "world.chunks_generated" // por quadro
+	ChunksGenerating = "lod.chunks_generating"  // malhas de LOD esperando os workers
+	Particles        = "particles.alive"
+	Goroutines       = "runtime.goroutines"
+	HeapAlloc        = "runtime.heap_alloc" // bytes
+	NumGC            = "runtime.gc_count"
+	GCPauseTotal     = "runtime.gc_pause_total" // segundos
+)
+
+// Quantos quadros as séries guardam
+const HistoryLength = 240
+
+// Registry holds named values pushed by the engine subsystems. Gauges keep
+// their last value; counters are summed during a frame and read as the total
+// of the previous frame. Any goroutine may push values.
+type Registry struct {
+	mu        sync.Mutex
+	gauges    map[string]float64
+	counting  map[string]float64
+	counters  map[string]float64
+	histories map[string]*Series
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		gauges:    make(map[string]float64),
+		counting:  make(map[string]float64),
+		counters:  make(map[string]float64),
+		histories: make(map[string]*Series),
+	}
+}
+
+// Registro usado pelo motor
+var Default = NewRegistry()
+
+// Set sets a gauge
+func (r *Registry) Set(name string, value float64) {
+	r.mu.Lock()
+	r.gauges[name] = value
+	r.mu.Unlock()
+}
+
+// Add adds delta to a counter of the current frame
+func (r *Registry) Add(name string, delta float64) {
+	r.mu.Lock()
+	r.counting[name] += delta
+	r.mu.Unlock()
+}
+
+// Record sets a gauge and appends it to its history
+func (r *Registry) Record(name string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.gauges[name] = value
+	history := r.histories[name]
+	if history == nil {
+		history = &Series{}
+		r.histories[name] = history
+	}
+	history.push(value)
+}
+
+// Get returns a gauge, or the total of a counter in the last finished frame
+func (r *Registry) Get(name string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if value, ok := r.gauges[name]; ok {
+		return value
+	}
+	return r.counters[name]
+}
+
+// History returns the recorded values of name, oldest first
+func (r *Registry) History(name string) []float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if history := r.histories[name]; history != nil {
+		return history.Values()
+	}
+	return nil
+}
+
+// Names returns the name of every value, sorted
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.gauges)+len(r.counters))
+	for name := range r.gauges {
+		names = append(names, name)
+	}
+	for name := range r.counters {
+		if _, ok := r.gauges[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// EndFrame publishes the counters of the frame and starts counting the next one
+func (r *Registry) EndFrame() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name := range r.counters {
+		r.counters[name] = 0
+	}
+	for name, value := range r.counting {
+		r.counters[name] = value
+		delete(r.counting, name)
+	}
+}
+
+// Atalhos para o registro padrão
+
+func Set(name string, value float64)    { Default.Set(name, value) }
+func Add(name string, delta float64)    { Default.Add(name, delta) }
+func Record(name string, value float64) { Default.Record(name, value) }
+func Get(name string) float64           { return Default.Get(name) }
+func History(name string) []float64     { return Default.History(name) }
+func EndFrame()                         { Default.EndFrame() }
+
+// DrawCall counts a draw call of the given number of triangles
+func DrawCall(triangles int) {
+	Default.mu.Lock()
+	Default.counting[DrawCalls]++
+	Default.counting[Triangles] += float64(triangles)
+	Default.mu.Unlock()
+}
+
+// Series is a ring buffer of the last HistoryLength values
+type Series struct {
+	values [HistoryLength]float64
+	next   int
+	full   bool
+}
+
+func (h *Series) push(value float64) {
+	h.values[h.next] = value
+	h.next = (h.next + 1) % HistoryLength
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Values returns the values, oldest first
+func (h *Series) Values() []float64 {
+	if !h.full {
+		return append([]float64(nil), h.values[:h.next]...)
+	}
+
+	return append(append([]float64(nil), h.values[h.next:]...), h.values[:h.next]...)
+}
@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCounters(t *testing.T) {
+	r := NewRegistry()
+
+	// frame roda antes de EndFrame; before é o que Get lê antes dele e want depois
+	tests := []struct {
+		name   string
+		frame  func()
+		before map[string]float64
+		want   map[string]float64
+	}{
+		{
+			name:   "soma do quadro",
+			frame:  func() { r.Add("c", 2); r.Add("c", 3); r.Add("d", 1) },
+			before: map[string]float64{"c": 0, "d": 0},
+			want:   map[string]float64{"c": 5, "d": 1},
+		},
+		{
+			name:   "quadro sem contagem zera",
+			frame:  func() { r.Add("c", 4) },
+			before: map[string]float64{"c": 5, "d": 1},
+			want:   map[string]float64{"c": 4, "d": 0},
+		},
+		{
+			name:  "medidor mantém o valor",
+			frame: func() { r.Set("g", 7) },
+			want:  map[string]float64{"c": 0, "d": 0, "g": 7},
+		},
+		{
+			name:  "medidor continua nos quadros seguintes",
+			frame: func() {},
+			want:  map[string]float64{"g": 7},
+		},
+	}
+
+	for _, test := range tests {
+		test.frame()
+		for name, want := range test.before {
+			if got := r.Get(name); got != want {
+				t.Errorf("%s: %s = %v before the frame ended, want %v", test.name, name, got, want)
+			}
+		}
+		r.EndFrame()
+
+		for name, want := range test.want {
+			if got := r.Get(name); got != want {
+				t.Errorf("%s: %s = %v, want %v", test.name, name, got, want)
+			}
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		first float64
+	}{
+		{"vazia", 0, 0},
+		{"uma entrada", 1, 0},
+		{"quase cheia", HistoryLength - 1, 0},
+		{"cheia", HistoryLength, 0},
+		{"dá a volta", HistoryLength + 1, 1},
+		{"duas voltas e meia", 2*HistoryLength + HistoryLength/2, 2*HistoryLength + HistoryLength/2 - HistoryLength},
+	}
+
+	for _, test := range tests {
+		r := NewRegistry()
+		for i := 0; i < test.count; i++ {
+			r.Record("h", float64(i))
+		}
+
+		values := r.History("h")
+		wantLen := test.count
+		if wantLen > HistoryLength {
+			wantLen = HistoryLength
+		}
+		if len(values) != wantLen {
+			t.Errorf("%s: %d values, want %d", test.name, len(values), wantLen)
+			continue
+		}
+		for i, value := range values {
+			if want := test.first + float64(i); value != want {
+				t.Errorf("%s: value %d = %v, want %v (oldest first)", test.name, i, value, want)
+				break
+			}
+		}
+		if test.count > 0 {
+			if got := r.Get("h"); got != float64(test.count-1) {
+				t.Errorf("%s: gauge = %v, want the last value %v", test.name, got, test.count-1)
+			}
+		}
+	}
+
+	// a cópia devolvida não muda o histórico
+	r := NewRegistry()
+	r.Record("h", 1)
+	r.History("h")[0] = 5
+	if got := r.History("h"); got[0] != 1 {
+		t.Errorf("History returned the internal buffer: %v", got)
+	}
+	if got := r.History("missing"); got != nil {
+		t.Errorf("History of a missing name = %v, want nil", got)
+	}
+}
+
+func TestNames(t *testing.T) {
+	r := NewRegistry()
+	r.Set("world.b", 1)
+	r.Record("frame.time", 0.016)
+	r.Add("render.draw_calls", 3)
+	if got, want := r.Names(), []string{"frame.time", "world.b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("names before the frame ended = %v, want %v", got, want)
+	}
+
+	r.EndFrame()
+	// um nome que é medidor e contador aparece uma vez só
+	r.Add("world.b", 1)
+	r.EndFrame()
+	if got, want := r.Names(), []string{"frame.time", "render.draw_calls", "world.b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("names = %v, want %v", got, want)
+	}
+}
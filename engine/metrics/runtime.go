@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"runtime"
+	"time"
+)
+
+// SampleRuntime publishes the goroutine count and the garbage collector
+// statistics. runtime.ReadMemStats stops the world, so it should not be called
+// every frame.
+func SampleRuntime() {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	Set(Goroutines, float64(runtime.NumGoroutine()))
+	Set(HeapAlloc, float64(stats.HeapAlloc))
+	Set(NumGC, float64(stats.NumGC))
+	Set(GCPauseTotal, time.Duration(stats.PauseTotalNs).Seconds())
+}
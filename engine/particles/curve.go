@@ -0,0 +1,59 @@
+package particles
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// FloatKey is a value of a FloatCurve at time T, T in [0, 1] of the particle lifetime
+type FloatKey struct {
+	T     float32
+	Value float32
+}
+
+// FloatCurve is a piecewise linear curve, keys sorted by T
+type FloatCurve []FloatKey
+
+// At returns the value of the curve at t, clamped to the first and last keys
+func (c FloatCurve) At(t float32) float32 {
+	if len(c) == 0 {
+		return 1
+	}
+	if t <= c[0].T {
+		return c[0].Value
+	}
+
+	for i := 1; i < len(c); i++ {
+		if t <= c[i].T {
+			a, b := c[i-1], c[i]
+			return a.Value + (b.Value-a.Value)*(t-a.T)/(b.T-a.T)
+		}
+	}
+
+	return c[len(c)-1].Value
+}
+
+// ColorKey is a value of a ColorCurve at time T
+type ColorKey struct {
+	T     float32
+	Color mgl32.Vec4
+}
+
+// ColorCurve is a piecewise linear RGBA curve, keys sorted by T
+type ColorCurve []ColorKey
+
+// At returns the color of the curve at t, clamped to the first and last keys
+func (c ColorCurve) At(t float32) mgl32.Vec4 {
+	if len(c) == 0 {
+		return mgl32.Vec4{1, 1, 1, 1}
+	}
+	if t <= c[0].T {
+		return c[0].Color
+	}
+
+	for i := 1; i < len(c); i++ {
+		if t <= c[i].T {
+			a, b := c[i-1], c[i]
+			return a.Color.Add(b.Color.Sub(a.Color).Mul((t - a.T) / (b.T - a.T)))
+		}
+	}
+
+	return c[len(c)-1].Color
+}
@@ -0,0 +1,129 @@
+package particles
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Estilos dos efeitos do jogo
+var (
+	// Pedaços do bloco quebrado, que caem e quicam no chão
+	DebrisStyle = &Style{
+		Lifetime:     [2]float32{0.6, 1.2},
+		Spread:       3,
+		Gravity:      18,
+		Drag:         0.5,
+		Bounce:       0.3,
+		Friction:     0.6,
+		Collide:      true,
+		Size:         FloatCurve{{0, 0.14}, {0.8, 0.12}, {1, 0}},
+		Color:        ColorCurve{{0, mgl32.Vec4{1, 1, 1, 1}}, {1, mgl32.Vec4{0.8, 0.8, 0.8, 1}}},
+		TextureScale: 0.25,
+	}
+
+	// Poeira leve ao colocar ou ao um bloco cair sobre outro
+	DustStyle = &Style{
+		Lifetime:     [2]float32{0.4, 0.8},
+		Spread:       1.2,
+		Gravity:      1,
+		Drag:         2.5,
+		Size:         FloatCurve{{0, 0.08}, {1, 0.2}},
+		Color:        ColorCurve{{0, mgl32.Vec4{1, 1, 1, 0.8}}, {1, mgl32.Vec4{1, 1, 1, 0}}},
+		TextureScale: 0.125,
+	}
+
+	// Gotas de água que saltam e caem de volta
+	SplashStyle = &Style{
+		Lifetime:     [2]float32{0.5, 0.9},
+		Spread:       1.5,
+		Gravity:      14,
+		Drag:         0.3,
+		Collide:      true,
+		Size:         FloatCurve{{0, 0.1}, {1, 0.05}},
+		Color:        ColorCurve{{0, mgl32.Vec4{0.8, 0.9, 1, 0.9}}, {1, mgl32.Vec4{0.8, 0.9, 1, 0.2}}},
+		TextureScale: 0.25,
+	}
+
+	// Partículas de poeira flutuando no ar ao redor da câmera
+	AmbientStyle = &Style{
+		Lifetime: [2]float32{4, 8},
+		Spread:   0.15,
+		Gravity:  -0.02,
+		Drag:     0.1,
+		Size:     FloatCurve{{0, 0}, {0.2, 0.04}, {0.8, 0.04}, {1, 0}},
+		Color:    ColorCurve{{0, mgl32.Vec4{1, 0.97, 0.85, 0.6}}},
+	}
+)
+
+// Quantidades e alcance dos efeitos
+const (
+	BlockBreakParticles  = 24
+	BlockPlaceParticles  = 10
+	BlockLandedParticles = 12
+	SplashParticles      = 32
+	AmbientRate          = 12 // partículas por segundo
+	AmbientRadius        = 12
+)
+
+// BlockBreak bursts debris textured with the broken block's texture from the
+// block centered at position
+func BlockBreak(position mgl32.Vec3, texture uint32) *Emitter {
+	return &Emitter{
+		Style:     DebrisStyle,
+		Position:  position,
+		Extent:    mgl32.Vec3{0.35, 0.35, 0.35},
+		Direction: mgl32.Vec3{0, 2, 0},
+		Burst:     BlockBreakParticles,
+		Texture:   texture,
+	}
+}
+
+// BlockPlace puffs dust around the block placed at position
+func BlockPlace(position mgl32.Vec3, texture uint32) *Emitter {
+	return &Emitter{
+		Style:    DustStyle,
+		Position: position,
+		Extent:   mgl32.Vec3{0.6, 0.6, 0.6},
+		Burst:    BlockPlaceParticles,
+		Texture:  texture,
+	}
+}
+
+// BlockLanded puffs dust under the falling block that landed at position
+func BlockLanded(position mgl32.Vec3, texture uint32) *Emitter {
+	return &Emitter{
+		Style:     DustStyle,
+		Position:  position.Sub(mgl32.Vec3{0, 0.45, 0}),
+		Extent:    mgl32.Vec3{0.55, 0.05, 0.55},
+		Direction: mgl32.Vec3{0, 0.5, 0},
+		Burst:     BlockLandedParticles,
+		Texture:   texture,
+	}
+}
+
+// WaterSplash throws droplets up from the water surface at position. speed is
+// how fast the player entered the water and scales the splash.
+func WaterSplash(position mgl32.Vec3, speed float32, texture uint32) *Emitter {
+	strength := 1 + speed/10
+	if strength > 2 {
+		strength = 2
+	}
+
+	return &Emitter{
+		Style:     SplashStyle,
+		Position:  position,
+		Extent:    mgl32.Vec3{0.4, 0, 0.4},
+		Direction: mgl32.Vec3{0, 4 * strength, 0},
+		Burst:     int(SplashParticles * strength),
+		Texture:   texture,
+	}
+}
+
+// Ambience emits dust motes forever around center; move its Position with
+// the camera
+func Ambience(center mgl32.Vec3) *Emitter {
+	return &Emitter{
+		Style:    AmbientStyle,
+		Position: center,
+		Extent:   mgl32.Vec3{AmbientRadius, AmbientRadius / 2, AmbientRadius},
+		Rate:     AmbientRate,
+		Duration: -1,
+	}
+}
@@ -0,0 +1,141 @@
+package particles
+
+import (
+	"sort"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/engine/metrics"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
+)
+
+// Floats por instância: centro e tamanho, cor, deslocamento e escala da textura
+const instanceFloats = 12
+
+// Renderer draws particles as camera facing billboards, one instanced draw
+// call per texture
+type Renderer struct {
+	Program *shaders.Program
+
+	vao         uint32
+	quadVBO     uint32
+	instanceVBO uint32
+	capacity    int
+	data        []float32
+	order       []int
+}
+
+// NewRenderer creates the billboard buffers. It must be called after the
+// OpenGL context is created.
+func NewRenderer(program *shaders.Program) *Renderer {
+	r := &Renderer{Program: program}
+
+	gl.GenVertexArrays(1, &r.vao)
+	gl.BindVertexArray(r.vao)
+
+	// quadrado unitário desenhado como triangle strip
+	corners := []float32{-0.5, -0.5, 0.5, -0.5, -0.5, 0.5, 0.5, 0.5}
+	gl.GenBuffers(1, &r.quadVBO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, r.quadVBO)
+	gl.BufferData(gl.ARRAY_BUFFER, len(corners)*4, gl.Ptr(corners), gl.STATIC_DRAW)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, 0, nil)
+	gl.EnableVertexAttribArray(0)
+
+	// um atributo por vec4 da instância, avançando uma vez por partícula
+	gl.GenBuffers(1, &r.instanceVBO)
+	for i := uint32(0); i < instanceFloats/4; i++ {
+		gl.EnableVertexAttribArray(1 + i)
+		gl.VertexAttribDivisor(1+i, 1)
+	}
+	r.pointInstances(0)
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindVertexArray(0)
+
+	return r
+}
+
+// Render draws particles sorted back to front within each texture. brightness
+// scales their color, e.g. darker at night.
+func (r *Renderer) Render(particles []Particle, cameraPosition mgl32.Vec3, view, projection mgl32.Mat4, brightness float32) {
+	if len(particles) == 0 {
+		return
+	}
+
+	r.order = r.order[:0]
+	for i := range particles {
+		r.order = append(r.order, i)
+	}
+	sort.Slice(r.order, func(a, b int) bool {
+		pa, pb := &particles[r.order[a]], &particles[r.order[b]]
+		if pa.Texture != pb.Texture {
+			return pa.Texture < pb.Texture
+		}
+		return pa.Position.Sub(cameraPosition).LenSqr() > pb.Position.Sub(cameraPosition).LenSqr()
+	})
+
+	r.data = r.data[:0]
+	for _, i := range r.order {
+		p := &particles[i]
+		r.data = append(r.data,
+			p.Position.X(), p.Position.Y(), p.Position.Z(), p.Size,
+			p.Color.X(), p.Color.Y(), p.Color.Z(), p.Color.W(),
+			p.UV.X(), p.UV.Y(), p.UV.Z(), p.UV.W(),
+		)
+	}
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, r.instanceVBO)
+	if len(particles) > r.capacity {
+		r.capacity = len(particles)
+		gl.BufferData(gl.ARRAY_BUFFER, len(r.data)*4, gl.Ptr(r.data), gl.STREAM_DRAW)
+	} else {
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(r.data)*4, gl.Ptr(r.data))
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+
+	r.Program.Use()
+	r.Program.SetMat4("view", view)
+	r.Program.SetMat4("projection", projection)
+	r.Program.SetFloat("brightness", brightness)
+	r.Program.SetInt("particle_texture", 0)
+
+	// transparentes: testam mas não escrevem profundidade
+	gl.DepthMask(false)
+	gl.BindVertexArray(r.vao)
+	gl.ActiveTexture(gl.TEXTURE0)
+
+	for start := 0; start < len(r.order); {
+		texture := particles[r.order[start]].Texture
+		end := start + 1
+		for end < len(r.order) && particles[r.order[end]].Texture == texture {
+			end++
+		}
+
+		gl.BindTexture(gl.TEXTURE_2D, texture)
+		r.Program.SetBool("textured", texture != 0)
+		r.pointInstances(start)
+		gl.DrawArraysInstanced(gl.TRIANGLE_STRIP, 0, 4, int32(end-start))
+		metrics.DrawCall(2 * (end - start))
+
+		start = end
+	}
+
+	gl.BindVertexArray(0)
+	gl.DepthMask(true)
+}
+
+// Aponta os atributos de instância para a partícula first. Sem glDrawArraysInstancedBaseInstance
+// (OpenGL 4.2), é assim que cada grupo de textura começa no seu trecho do buffer.
+func (r *Renderer) pointInstances(first int) {
+	gl.BindBuffer(gl.ARRAY_BUFFER, r.instanceVBO)
+	for i := uint32(0); i < instanceFloats/4; i++ {
+		gl.VertexAttribPointer(1+i, 4, gl.FLOAT, false, instanceFloats*4, gl.PtrOffset((first*instanceFloats+int(i)*4)*4))
+	}
+}
+
+// Destroy frees the buffers
+func (r *Renderer) Destroy() {
+	gl.DeleteBuffers(1, &r.quadVBO)
+	gl.DeleteBuffers(1, &r.instanceVBO)
+	gl.DeleteVertexArrays(1, &r.vao)
+}
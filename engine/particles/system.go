@@ -0,0 +1,254 @@
+package particles
+
+// Sistema de partículas simulado na CPU. Nada aqui usa OpenGL, então a
+// simulação roda sem contexto (e.g. em testes); o desenho fica em renderer.go.
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SolidFunc tells whether the voxel at (x, y, z) blocks particles. Blocks are
+// centered at integer coordinates.
+type SolidFunc func(x, y, z int) bool
+
+// Style is the behavior shared by the particles of an emitter
+type Style struct {
+	Lifetime     [2]float32 // mínimo e máximo, em segundos
+	Spread       float32    // velocidade aleatória somada à direção do emissor
+	Gravity      float32    // aceleração para baixo
+	Drag         float32    // fração da velocidade perdida por segundo
+	Bounce       float32    // fração da velocidade mantida ao colidir
+	Friction     float32    // fração da velocidade horizontal mantida ao colidir no chão
+	Collide      bool       // colide com os blocos do mundo
+	Size         FloatCurve // tamanho ao longo da vida
+	Color        ColorCurve // cor (multiplica a textura) ao longo da vida
+	TextureScale float32    // fração da textura amostrada por partícula, 0 não usa textura
+}
+
+// Particle is a single simulated particle
+type Particle struct {
+	Position mgl32.Vec3
+	Velocity mgl32.Vec3
+	Age      float32
+	Lifetime float32
+	Size     float32
+	Color    mgl32.Vec4
+	Texture  uint32     // textura do bloco, 0 se não houver
+	UV       mgl32.Vec4 // deslocamento (xy) e escala (zw) das coordenadas de textura
+	Resting  bool       // parada no chão
+
+	style *Style
+}
+
+// Emitter spawns particles of a Style. It emits Burst particles on its first
+// update and then Rate particles per second for Duration seconds (forever if
+// Duration is negative).
+type Emitter struct {
+	Style     *Style
+	Position  mgl32.Vec3
+	Extent    mgl32.Vec3 // meia-caixa onde as partículas nascem
+	Direction mgl32.Vec3 // velocidade inicial média
+	Burst     int
+	Rate      float32
+	Duration  float32
+	Texture   uint32
+	Stopped   bool
+
+	elapsed     float32
+	accumulator float32
+	burstDone   bool
+}
+
+// Done tells whether the emitter will not spawn particles anymore
+func (e *Emitter) Done() bool {
+	return e.Stopped || (e.burstDone && e.Duration >= 0 && e.elapsed >= e.Duration)
+}
+
+// System owns a fixed pool of particles and the emitters feeding it. Emit may
+// be called from any goroutine; Update and Particles from the render loop.
+type System struct {
+	Solid SolidFunc
+
+	pool     []Particle
+	alive    int
+	emitters []*Emitter
+	incoming []*Emitter
+	random   *rand.Rand
+	lock     sync.Mutex
+}
+
+// NewSystem creates a system with room for maxParticles. Particles spawned
+// while the pool is full are dropped.
+func NewSystem(maxParticles int, seed int64, solid SolidFunc) *System {
+	return &System{
+		Solid:  solid,
+		pool:   make([]Particle, maxParticles),
+		random: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Emit adds an emitter to the system, returning it so it can be moved or stopped
+func (s *System) Emit(emitter *Emitter) *Emitter {
+	s.lock.Lock()
+	s.incoming = append(s.incoming, emitter)
+	s.lock.Unlock()
+
+	return emitter
+}
+
+// Particles returns the alive particles. The slice is reused by the next Update.
+func (s *System) Particles() []Particle {
+	return s.pool[:s.alive]
+}
+
+// Len returns how many particles are alive
+func (s *System) Len() int {
+	return s.alive
+}
+
+// Update runs the emitters and advances the particles by deltaTime seconds
+func (s *System) Update(deltaTime float32) {
+	s.lock.Lock()
+	s.emitters = append(s.emitters, s.incoming...)
+	s.incoming = s.incoming[:0]
+	s.lock.Unlock()
+
+	active := s.emitters[:0]
+	for _, emitter := range s.emitters {
+		s.runEmitter(emitter, deltaTime)
+		if !emitter.Done() {
+			active = append(active, emitter)
+		}
+	}
+	for i := len(active); i < len(s.emitters); i++ {
+		s.emitters[i] = nil
+	}
+	s.emitters = active
+
+	for i := 0; i < s.alive; {
+		p := &s.pool[i]
+		p.Age += deltaTime
+		if p.Age >= p.Lifetime {
+			// a última partícula viva ocupa o lugar da que morreu
+			s.alive--
+			s.pool[i] = s.pool[s.alive]
+			continue
+		}
+
+		s.step(p, deltaTime)
+		i++
+	}
+}
+
+func (s *System) runEmitter(e *Emitter, deltaTime float32) {
+	if e.Stopped {
+		return
+	}
+
+	if !e.burstDone {
+		for i := 0; i < e.Burst; i++ {
+			s.spawn(e)
+		}
+		e.burstDone = true
+	}
+
+	if e.Duration >= 0 && e.elapsed >= e.Duration {
+		return
+	}
+	e.elapsed += deltaTime
+
+	e.accumulator += e.Rate * deltaTime
+	for e.accumulator >= 1 {
+		s.spawn(e)
+		e.accumulator--
+	}
+}
+
+func (s *System) spawn(e *Emitter) {
+	if s.alive == len(s.pool) {
+		return
+	}
+
+	style := e.Style
+	p := &s.pool[s.alive]
+	s.alive++
+
+	*p = Particle{
+		Position: e.Position.Add(mgl32.Vec3{
+			e.Extent.X() * s.signed(),
+			e.Extent.Y() * s.signed(),
+			e.Extent.Z() * s.signed(),
+		}),
+		Velocity: e.Direction.Add(s.inSphere().Mul(style.Spread)),
+		Lifetime: style.Lifetime[0] + (style.Lifetime[1]-style.Lifetime[0])*s.random.Float32(),
+		Size:     style.Size.At(0),
+		Color:    style.Color.At(0),
+		style:    style,
+	}
+
+	if style.TextureScale > 0 && e.Texture != 0 {
+		p.Texture = e.Texture
+		free := 1 - style.TextureScale
+		p.UV = mgl32.Vec4{free * s.random.Float32(), free * s.random.Float32(), style.TextureScale, style.TextureScale}
+	}
+}
+
+func (s *System) step(p *Particle, deltaTime float32) {
+	style := p.style
+
+	p.Velocity[1] -= style.Gravity * deltaTime
+	drag := 1 - style.Drag*deltaTime
+	if drag < 0 {
+		drag = 0
+	}
+	p.Velocity = p.Velocity.Mul(drag)
+
+	if !style.Collide || s.Solid == nil {
+		p.Position = p.Position.Add(p.Velocity.Mul(deltaTime))
+	} else {
+		// um eixo por vez, para deslizar ao longo das faces dos blocos
+		p.Resting = false
+		for axis := 0; axis < 3; axis++ {
+			next := p.Position
+			next[axis] += p.Velocity[axis] * deltaTime
+			if !s.Solid(round(next[0]), round(next[1]), round(next[2])) {
+				p.Position = next
+				continue
+			}
+
+			if axis == 1 && p.Velocity[1] < 0 {
+				p.Resting = true
+				p.Velocity[0] *= style.Friction
+				p.Velocity[2] *= style.Friction
+			}
+			p.Velocity[axis] *= -style.Bounce
+		}
+	}
+
+	t := p.Age / p.Lifetime
+	p.Size = style.Size.At(t)
+	p.Color = style.Color.At(t)
+}
+
+// Valor aleatório em [-1, 1)
+func (s *System) signed() float32 {
+	return 2*s.random.Float32() - 1
+}
+
+// Ponto aleatório dentro da esfera de raio 1
+func (s *System) inSphere() mgl32.Vec3 {
+	for {
+		v := mgl32.Vec3{s.signed(), s.signed(), s.signed()}
+		if v.LenSqr() <= 1 {
+			return v
+		}
+	}
+}
+
+func round(v float32) int {
+	return int(math.Round(float64(v)))
+}
@@ -0,0 +1,338 @@
+package particles
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const epsilon = 1e-4
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < epsilon
+}
+
+// Estilo sem aleatoriedade: sem espalhamento, vida fixa
+func still(lifetime float32) *Style {
+	return &Style{Lifetime: [2]float32{lifetime, lifetime}}
+}
+
+func TestFloatCurve(t *testing.T) {
+	curve := FloatCurve{{0, 0}, {0.5, 1}, {1, 0.5}}
+
+	tests := []struct {
+		name  string
+		curve FloatCurve
+		t     float32
+		want  float32
+	}{
+		{"vazia", nil, 0.3, 1},
+		{"antes do início", FloatCurve{{0.2, 3}, {1, 4}}, 0, 3},
+		{"no primeiro ponto", curve, 0, 0},
+		{"subindo", curve, 0.25, 0.5},
+		{"no meio", curve, 0.5, 1},
+		{"descendo", curve, 0.75, 0.75},
+		{"no fim", curve, 1, 0.5},
+		{"depois do fim", curve, 2, 0.5},
+		{"um ponto", FloatCurve{{0.5, 7}}, 0.9, 7},
+	}
+
+	for _, test := range tests {
+		if got := test.curve.At(test.t); !near(got, test.want) {
+			t.Errorf("%s: At(%v) = %v, want %v", test.name, test.t, got, test.want)
+		}
+	}
+}
+
+func TestColorCurve(t *testing.T) {
+	curve := ColorCurve{{0, mgl32.Vec4{1, 0, 0, 1}}, {1, mgl32.Vec4{0, 0, 1, 0}}}
+
+	tests := []struct {
+		name  string
+		curve ColorCurve
+		t     float32
+		want  mgl32.Vec4
+	}{
+		{"vazia", nil, 0.5, mgl32.Vec4{1, 1, 1, 1}},
+		{"início", curve, 0, mgl32.Vec4{1, 0, 0, 1}},
+		{"meio", curve, 0.5, mgl32.Vec4{0.5, 0, 0.5, 0.5}},
+		{"fim", curve, 1, mgl32.Vec4{0, 0, 1, 0}},
+		{"depois do fim", curve, 3, mgl32.Vec4{0, 0, 1, 0}},
+	}
+
+	for _, test := range tests {
+		if got := test.curve.At(test.t); !got.ApproxEqualThreshold(test.want, epsilon) {
+			t.Errorf("%s: At(%v) = %v, want %v", test.name, test.t, got, test.want)
+		}
+	}
+}
+
+func TestPoolLimit(t *testing.T) {
+	system := NewSystem(5, 1, nil)
+	system.Emit(&Emitter{Style: still(10), Burst: 8})
+	system.Update(0.1)
+
+	if system.Len() != 5 {
+		t.Fatalf("Len() = %d, want 5 (pool cheio)", system.Len())
+	}
+
+	// com o pool cheio as novas partículas são descartadas
+	system.Emit(&Emitter{Style: still(10), Burst: 3})
+	system.Update(0.1)
+	if system.Len() != 5 {
+		t.Fatalf("Len() = %d after second burst, want 5", system.Len())
+	}
+}
+
+func TestEmitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		emitter  Emitter
+		updates  int
+		want     []int // partículas vivas depois de cada atualização
+		wantDone bool
+	}{
+		{
+			name:     "burst",
+			emitter:  Emitter{Burst: 6},
+			updates:  3,
+			want:     []int{6, 6, 6},
+			wantDone: true,
+		},
+		{
+			name:     "taxa por um segundo",
+			emitter:  Emitter{Rate: 4, Duration: 1},
+			updates:  6,
+			want:     []int{1, 2, 3, 4, 4, 4},
+			wantDone: true,
+		},
+		{
+			name:    "burst e taxa sem fim",
+			emitter: Emitter{Burst: 2, Rate: 4, Duration: -1},
+			updates: 4,
+			want:    []int{3, 4, 5, 6},
+		},
+		{
+			name:     "parado",
+			emitter:  Emitter{Burst: 5, Rate: 4, Duration: -1, Stopped: true},
+			updates:  2,
+			want:     []int{0, 0},
+			wantDone: true,
+		},
+	}
+
+	for _, test := range tests {
+		system := NewSystem(100, 1, nil)
+		emitter := test.emitter
+		emitter.Style = still(100)
+		system.Emit(&emitter)
+
+		for i := 0; i < test.updates; i++ {
+			system.Update(0.25)
+			if system.Len() != test.want[i] {
+				t.Errorf("%s: update %d: Len() = %d, want %d", test.name, i, system.Len(), test.want[i])
+			}
+		}
+		if emitter.Done() != test.wantDone {
+			t.Errorf("%s: Done() = %v, want %v", test.name, emitter.Done(), test.wantDone)
+		}
+	}
+}
+
+func TestLifetime(t *testing.T) {
+	system := NewSystem(10, 1, nil)
+	system.Emit(&Emitter{Style: still(1), Burst: 3})
+
+	// as partículas envelhecem já na atualização em que nascem
+	for i := 1; i <= 3; i++ {
+		system.Update(0.25)
+		if system.Len() != 3 {
+			t.Fatalf("update %d: Len() = %d, want 3", i, system.Len())
+		}
+	}
+
+	system.Update(0.25)
+	if system.Len() != 0 {
+		t.Fatalf("Len() = %d after the lifetime, want 0", system.Len())
+	}
+}
+
+func TestLifetimeRange(t *testing.T) {
+	system := NewSystem(200, 7, nil)
+	system.Emit(&Emitter{Style: &Style{Lifetime: [2]float32{0.5, 2}}, Burst: 200})
+	system.Update(0)
+
+	for _, p := range system.Particles() {
+		if p.Lifetime < 0.5 || p.Lifetime > 2 {
+			t.Fatalf("lifetime %v outside [0.5, 2]", p.Lifetime)
+		}
+	}
+}
+
+func TestGravityAndDrag(t *testing.T) {
+	tests := []struct {
+		name      string
+		style     Style
+		direction mgl32.Vec3
+		want      func(n int, dt float32) mgl32.Vec3 // posição depois de n passos
+	}{
+		{
+			name:  "gravidade",
+			style: Style{Gravity: 10},
+			want: func(n int, dt float32) mgl32.Vec3 {
+				// Euler semi-implícito: a velocidade muda antes da posição
+				return mgl32.Vec3{0, -10 * dt * dt * float32(n*(n+1)) / 2, 0}
+			},
+		},
+		{
+			name:      "velocidade constante",
+			direction: mgl32.Vec3{2, 0, -1},
+			want: func(n int, dt float32) mgl32.Vec3 {
+				return mgl32.Vec3{2, 0, -1}.Mul(float32(n) * dt)
+			},
+		},
+		{
+			name:      "arrasto total",
+			style:     Style{Drag: 20},
+			direction: mgl32.Vec3{5, 5, 5},
+			want: func(n int, dt float32) mgl32.Vec3 {
+				return mgl32.Vec3{}
+			},
+		},
+	}
+
+	const dt = 0.1
+	for _, test := range tests {
+		style := test.style
+		style.Lifetime = [2]float32{100, 100}
+
+		system := NewSystem(1, 1, nil)
+		system.Emit(&Emitter{Style: &style, Direction: test.direction, Burst: 1})
+
+		for n := 1; n <= 5; n++ {
+			system.Update(dt)
+			got := system.Particles()[0].Position
+			if want := test.want(n, dt); !got.ApproxEqualThreshold(want, epsilon) {
+				t.Errorf("%s: step %d: position %v, want %v", test.name, n, got, want)
+			}
+		}
+	}
+}
+
+func TestCollision(t *testing.T) {
+	// chão de blocos em y <= 0, com a face de cima em y = 0.5
+	floor := func(x, y, z int) bool {
+		return y <= 0
+	}
+
+	style := &Style{
+		Lifetime: [2]float32{100, 100},
+		Gravity:  10,
+		Friction: 0.5,
+		Collide:  true,
+	}
+	system := NewSystem(1, 1, floor)
+	system.Emit(&Emitter{Style: style, Position: mgl32.Vec3{0, 3, 0}, Direction: mgl32.Vec3{1, 0, 0}, Burst: 1})
+
+	rested := false
+	for i := 0; i < 200; i++ {
+		system.Update(0.05)
+		p := system.Particles()[0]
+		if p.Position.Y() < 0.5 {
+			t.Fatalf("step %d: particle entered the floor at %v", i, p.Position)
+		}
+		if p.Resting {
+			rested = true
+		}
+	}
+
+	p := system.Particles()[0]
+	if !rested || !p.Resting {
+		t.Fatalf("particle never rested on the floor: %+v", p)
+	}
+	if p.Velocity.Y() > 0 || math.Abs(float64(p.Velocity.X())) > epsilon {
+		t.Errorf("velocity %v, want stopped by the friction", p.Velocity)
+	}
+
+	// sem colisão, a mesma partícula atravessa o chão
+	free := *style
+	free.Collide = false
+	system = NewSystem(1, 1, floor)
+	system.Emit(&Emitter{Style: &free, Position: mgl32.Vec3{0, 3, 0}, Burst: 1})
+	for i := 0; i < 40; i++ {
+		system.Update(0.05)
+	}
+	if y := system.Particles()[0].Position.Y(); y >= 0.5 {
+		t.Errorf("particle without collision stopped at y = %v", y)
+	}
+}
+
+func TestCurvesOverLifetime(t *testing.T) {
+	style := &Style{
+		Lifetime: [2]float32{1, 1},
+		Size:     FloatCurve{{0, 0}, {1, 2}},
+		Color:    ColorCurve{{0, mgl32.Vec4{1, 1, 1, 1}}, {1, mgl32.Vec4{1, 1, 1, 0}}},
+	}
+	system := NewSystem(1, 1, nil)
+	system.Emit(&Emitter{Style: style, Burst: 1})
+
+	for _, age := range []float32{0.25, 0.5, 0.75} {
+		system.Update(0.25)
+		p := system.Particles()[0]
+		if !near(p.Size, 2*age) {
+			t.Errorf("age %v: size %v, want %v", age, p.Size, 2*age)
+		}
+		if !near(p.Color.W(), 1-age) {
+			t.Errorf("age %v: alpha %v, want %v", age, p.Color.W(), 1-age)
+		}
+	}
+}
+
+func TestTextureUV(t *testing.T) {
+	style := &Style{Lifetime: [2]float32{1, 1}, TextureScale: 0.25}
+
+	system := NewSystem(50, 3, nil)
+	system.Emit(&Emitter{Style: style, Burst: 25, Texture: 9})
+	system.Emit(&Emitter{Style: style, Burst: 25})
+	system.Update(0)
+
+	textured := 0
+	for _, p := range system.Particles() {
+		if p.Texture == 0 {
+			if p.UV != (mgl32.Vec4{}) {
+				t.Errorf("particle without texture has UV %v", p.UV)
+			}
+			continue
+		}
+
+		textured++
+		if p.UV.Z() != 0.25 || p.UV.W() != 0.25 || p.UV.X() < 0 || p.UV.X() > 0.75 || p.UV.Y() < 0 || p.UV.Y() > 0.75 {
+			t.Errorf("UV %v outside the texture", p.UV)
+		}
+	}
+	if textured != 25 {
+		t.Errorf("%d textured particles, want 25", textured)
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	run := func() []Particle {
+		system := NewSystem(64, 42, nil)
+		system.Emit(BlockBreak(mgl32.Vec3{1, 2, 3}, 5))
+		for i := 0; i < 10; i++ {
+			system.Update(1.0 / 60)
+		}
+		return append([]Particle(nil), system.Particles()...)
+	}
+
+	a, b := run(), run()
+	if len(a) != len(b) || len(a) == 0 {
+		t.Fatalf("runs with %d and %d particles", len(a), len(b))
+	}
+	for i := range a {
+		if a[i].Position != b[i].Position || a[i].Velocity != b[i].Velocity {
+			t.Fatalf("particle %d differs: %+v != %+v", i, a[i], b[i])
+		}
+	}
+}
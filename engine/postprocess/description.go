@@ -0,0 +1,151 @@
+package postprocess
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Stage is one full screen pass of the pipeline
+type Stage string
+
+const (
+	StageFog        Stage = "fog"        // névoa a partir do buffer de profundidade
+	StageUnderwater Stage = "underwater" // tinta azulada com a câmera dentro da água
+	StageToneMap    Stage = "tonemap"    // HDR para [0, 1] (ACES)
+	StageGamma      Stage = "gamma"      // linear para sRGB
+	StageFXAA       Stage = "fxaa"       // anti-aliasing, depois da correção gamma
+)
+
+var knownStages = []Stage{StageFog, StageUnderwater, StageToneMap, StageGamma, StageFXAA}
+
+// FogMode is how fog grows with the distance to the camera
+type FogMode string
+
+const (
+	FogNone        FogMode = "none"
+	FogLinear      FogMode = "linear"      // de Start até End
+	FogExponential FogMode = "exponential" // 1 - exp(-(distância * Density)²)
+)
+
+// Valores de "fog_mode" nos shaders, na ordem dos defines gerados
+var fogModes = []FogMode{FogNone, FogLinear, FogExponential}
+
+// FogSettings is the fog of one biome. Colors are sRGB.
+type FogSettings struct {
+	Mode    FogMode     `json:"mode"`
+	Color   *[3]float32 `json:"color,omitempty"` // nil usa a cor do horizonte do céu
+	Start   float32     `json:"start"`
+	End     float32     `json:"end"`
+	Density float32     `json:"density"`
+	FogSky  bool        `json:"fog_sky"` // cobre também o céu, e.g. embaixo d'água
+}
+
+// Biomas com configuração de névoa própria. O mundo ainda não tem biomas, então
+// só DefaultBiome e UnderwaterBiome são usados.
+const (
+	DefaultBiome    = "default"
+	UnderwaterBiome = "underwater"
+)
+
+// Description configures the pipeline: which stages run, in order, and their settings
+type Description struct {
+	HDR            bool                   `json:"hdr"` // alvo de ponto flutuante para a cena
+	Stages         []Stage                `json:"stages"`
+	Fog            map[string]FogSettings `json:"fog"` // por bioma
+	UnderwaterTint [3]float32             `json:"underwater_tint"`
+	Exposure       float32                `json:"exposure"`
+	Gamma          float32                `json:"gamma"`
+}
+
+// DefaultDescription is used when there is no pipeline file
+func DefaultDescription() Description {
+	return Description{
+		HDR:    true,
+		Stages: []Stage{StageFog, StageUnderwater, StageToneMap, StageGamma, StageFXAA},
+		Fog: map[string]FogSettings{
+			DefaultBiome:    {Mode: FogLinear, Start: 256, End: 486},
+			UnderwaterBiome: {Mode: FogExponential, Color: &[3]float32{0.1, 0.25, 0.5}, Density: 0.12, FogSky: true},
+		},
+		UnderwaterTint: [3]float32{0.55, 0.75, 1.0},
+		Exposure:       1.0,
+		Gamma:          2.2,
+	}
+}
+
+// LoadDescription reads a JSON pipeline description. Missing fields keep the
+// values of DefaultDescription.
+func LoadDescription(path string) (Description, error) {
+	description := DefaultDescription()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return description, err
+	}
+
+	if err := json.Unmarshal(data, &description); err != nil {
+		return DefaultDescription(), fmt.Errorf("postprocess: %v: %v", path, err)
+	}
+
+	if err := description.Validate(); err != nil {
+		return DefaultDescription(), fmt.Errorf("postprocess: %v: %v", path, err)
+	}
+
+	return description, nil
+}
+
+// Validate checks the stage names and settings
+func (d Description) Validate() error {
+	for _, stage := range d.Stages {
+		if stageIndex(stage) < 0 {
+			return fmt.Errorf("unknown stage %q", stage)
+		}
+	}
+
+	for biome, fog := range d.Fog {
+		if fogModeIndex(fog.Mode) < 0 {
+			return fmt.Errorf("fog of %q: unknown mode %q", biome, fog.Mode)
+		}
+		if fog.Mode == FogLinear && fog.End <= fog.Start {
+			return fmt.Errorf("fog of %q: end must be greater than start", biome)
+		}
+	}
+
+	if d.Gamma <= 0 {
+		return fmt.Errorf("gamma must be positive")
+	}
+
+	return nil
+}
+
+// FogFor returns the fog of a biome, falling back to DefaultBiome
+func (d Description) FogFor(biome string) FogSettings {
+	if fog, ok := d.Fog[biome]; ok {
+		return fog
+	}
+	if fog, ok := d.Fog[DefaultBiome]; ok {
+		return fog
+	}
+
+	return FogSettings{Mode: FogNone}
+}
+
+func stageIndex(stage Stage) int {
+	for i, known := range knownStages {
+		if known == stage {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func fogModeIndex(mode FogMode) int {
+	for i, known := range fogModes {
+		if known == mode {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,166 @@
+package postprocess
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(d *Description)
+		wantErr string
+	}{
+		{name: "padrão", change: func(d *Description) {}},
+		{name: "sem estágios", change: func(d *Description) { d.Stages = nil }},
+		{name: "estágio repetido", change: func(d *Description) { d.Stages = []Stage{StageGamma, StageGamma} }},
+		{name: "sem névoa", change: func(d *Description) { d.Fog = nil }},
+		{name: "estágio desconhecido", change: func(d *Description) { d.Stages = append(d.Stages, "bloom") }, wantErr: `unknown stage "bloom"`},
+		{name: "estágio em maiúsculas", change: func(d *Description) { d.Stages = []Stage{"FXAA"} }, wantErr: `unknown stage "FXAA"`},
+		{
+			name:    "modo desconhecido",
+			change:  func(d *Description) { d.Fog["desert"] = FogSettings{Mode: "cubic"} },
+			wantErr: `fog of "desert": unknown mode "cubic"`,
+		},
+		{
+			name:    "modo vazio",
+			change:  func(d *Description) { d.Fog["desert"] = FogSettings{} },
+			wantErr: `unknown mode ""`,
+		},
+		{
+			name:    "linear com fim antes do início",
+			change:  func(d *Description) { d.Fog[DefaultBiome] = FogSettings{Mode: FogLinear, Start: 100, End: 50} },
+			wantErr: "end must be greater than start",
+		},
+		{
+			name:    "linear com fim igual ao início",
+			change:  func(d *Description) { d.Fog[DefaultBiome] = FogSettings{Mode: FogLinear, Start: 100, End: 100} },
+			wantErr: "end must be greater than start",
+		},
+		{
+			name:   "exponencial ignora início e fim",
+			change: func(d *Description) { d.Fog[DefaultBiome] = FogSettings{Mode: FogExponential, Start: 100, End: 50} },
+		},
+		{name: "gamma zero", change: func(d *Description) { d.Gamma = 0 }, wantErr: "gamma must be positive"},
+	}
+
+	for _, test := range tests {
+		description := DefaultDescription()
+		test.change(&description)
+		err := description.Validate()
+		if test.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("%s: error = %v, want one about %q", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestFogFor(t *testing.T) {
+	defaults := DefaultDescription()
+	desert := FogSettings{Mode: FogExponential, Density: 0.02}
+
+	tests := []struct {
+		name  string
+		fog   map[string]FogSettings
+		biome string
+		want  FogSettings
+	}{
+		{"bioma padrão", defaults.Fog, DefaultBiome, defaults.Fog[DefaultBiome]},
+		{"embaixo d'água", defaults.Fog, UnderwaterBiome, defaults.Fog[UnderwaterBiome]},
+		{"bioma sem névoa própria usa a padrão", defaults.Fog, "desert", defaults.Fog[DefaultBiome]},
+		{"bioma com névoa própria", map[string]FogSettings{DefaultBiome: defaults.Fog[DefaultBiome], "desert": desert}, "desert", desert},
+		{"sem a padrão", map[string]FogSettings{"desert": desert}, "forest", FogSettings{Mode: FogNone}},
+		{"sem névoa nenhuma", nil, DefaultBiome, FogSettings{Mode: FogNone}},
+	}
+
+	for _, test := range tests {
+		description := Description{Fog: test.fog}
+		if got := description.FogFor(test.biome); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: fog = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestLoadDescription(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string // sem arquivo se vazio
+		want    func(d *Description)
+		wantErr string
+	}{
+		{
+			name:    "sem arquivo",
+			wantErr: "no such file",
+			want:    func(d *Description) {},
+		},
+		{
+			name: "campos faltando ficam com o padrão",
+			file: `{"stages": ["gamma"], "exposure": 2}`,
+			want: func(d *Description) { d.Stages, d.Exposure = []Stage{StageGamma}, 2 },
+		},
+		{
+			name: "névoa de um bioma novo mantém as outras",
+			file: `{"fog": {"desert": {"mode": "exponential", "density": 0.02}}}`,
+			want: func(d *Description) { d.Fog["desert"] = FogSettings{Mode: FogExponential, Density: 0.02} },
+		},
+		{
+			name: "névoa de um bioma é trocada inteira",
+			file: `{"fog": {"default": {"mode": "linear", "start": 10, "end": 20}}}`,
+			want: func(d *Description) { d.Fog[DefaultBiome] = FogSettings{Mode: FogLinear, Start: 10, End: 20} },
+		},
+		{
+			name:    "estágio desconhecido",
+			file:    `{"stages": ["fog", "bloom"]}`,
+			wantErr: `unknown stage "bloom"`,
+			want:    func(d *Description) {},
+		},
+		{
+			name:    "névoa linear invertida",
+			file:    `{"fog": {"cave": {"mode": "linear", "start": 20, "end": 10}}}`,
+			wantErr: "end must be greater than start",
+			want:    func(d *Description) {},
+		},
+		{
+			name:    "JSON inválido",
+			file:    `{"stages": `,
+			wantErr: "unexpected end",
+			want:    func(d *Description) {},
+		},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "postprocess.json")
+		if test.file != "" {
+			if err := os.WriteFile(path, []byte(test.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got, err := LoadDescription(path)
+		if test.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("%s: error = %v, want one about %q", test.name, err, test.wantErr)
+		}
+
+		want := DefaultDescription()
+		test.want(&want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: description = %+v, want %+v", test.name, got, want)
+		}
+	}
+
+	// play.go usa a descrição padrão sem avisar quando não há arquivo
+	if _, err := LoadDescription(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,355 @@
+package postprocess
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
+	"github.com/reonardoleis/fcg-glcraft/geometry"
+	math2 "github.com/reonardoleis/fcg-glcraft/math"
+)
+
+// Um framebuffer com textura de cor e, opcionalmente, de profundidade
+type target struct {
+	framebuffer  uint32
+	colorTexture uint32
+	depthTexture uint32
+}
+
+func newTarget(width, height int32, hdr, withDepth bool) (target, error) {
+	var t target
+
+	internalFormat, pixelType := int32(gl.RGBA8), uint32(gl.UNSIGNED_BYTE)
+	if hdr {
+		internalFormat, pixelType = gl.RGBA16F, gl.FLOAT
+	}
+
+	gl.GenFramebuffers(1, &t.framebuffer)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, t.framebuffer)
+
+	gl.GenTextures(1, &t.colorTexture)
+	gl.BindTexture(gl.TEXTURE_2D, t.colorTexture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, internalFormat, width, height, 0, gl.RGBA, pixelType, nil)
+	// filtro linear: o FXAA amostra entre os pixels
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, t.colorTexture, 0)
+
+	if withDepth {
+		gl.GenTextures(1, &t.depthTexture)
+		gl.BindTexture(gl.TEXTURE_2D, t.depthTexture)
+		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.DEPTH_COMPONENT24, width, height, 0, gl.DEPTH_COMPONENT, gl.FLOAT, nil)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.TEXTURE_2D, t.depthTexture, 0)
+	}
+
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		t.destroy()
+		return target{}, fmt.Errorf("postprocess: incomplete framebuffer (status 0x%x)", status)
+	}
+
+	return t, nil
+}
+
+func (t *target) destroy() {
+	gl.DeleteFramebuffers(1, &t.framebuffer)
+	gl.DeleteTextures(1, &t.colorTexture)
+	if t.depthTexture != 0 {
+		gl.DeleteTextures(1, &t.depthTexture)
+	}
+	*t = target{}
+}
+
+// Framebuffer multiamostrado onde a cena é desenhada com MSAA. Ele não pode
+// ser lido pelos estágios, então é resolvido no alvo da cena antes deles.
+type multisampled struct {
+	framebuffer uint32
+	color       uint32
+	depth       uint32
+}
+
+func newMultisampled(width, height, samples int32, hdr bool) (multisampled, error) {
+	var m multisampled
+
+	// o formato tem que ser o mesmo do alvo da cena para o blit resolver
+	internalFormat := uint32(gl.RGBA8)
+	if hdr {
+		internalFormat = gl.RGBA16F
+	}
+
+	gl.GenFramebuffers(1, &m.framebuffer)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, m.framebuffer)
+
+	gl.GenRenderbuffers(1, &m.color)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, m.color)
+	gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, samples, internalFormat, width, height)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.RENDERBUFFER, m.color)
+
+	gl.GenRenderbuffers(1, &m.depth)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, m.depth)
+	gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, samples, gl.DEPTH_COMPONENT24, width, height)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.RENDERBUFFER, m.depth)
+
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		m.destroy()
+		return multisampled{}, fmt.Errorf("postprocess: incomplete multisampled framebuffer (status 0x%x)", status)
+	}
+
+	return m, nil
+}
+
+func (m *multisampled) destroy() {
+	if m.framebuffer == 0 {
+		return
+	}
+
+	gl.DeleteFramebuffers(1, &m.framebuffer)
+	gl.DeleteRenderbuffers(1, &m.color)
+	gl.DeleteRenderbuffers(1, &m.depth)
+	*m = multisampled{}
+}
+
+// Pipeline renders the scene to an offscreen target and then runs the stages
+// of its Description, each reading the output of the previous one, the last
+// one writing to the screen
+type Pipeline struct {
+	Description Description
+	Width       int32
+	Height      int32
+	FogColor    mgl32.Vec3 // cor do horizonte (sRGB), usada pela névoa sem cor própria
+	Biome       string
+	Underwater  bool
+	Samples     int32 // amostras de MSAA da cena, 0 desliga
+
+	scene        target
+	multisampled multisampled // só com Samples
+	pingPong     [2]target
+	programs     map[Stage]*shaders.Program
+	quad         *geometry.Mesh
+}
+
+// NewPipeline creates the targets and compiles one variant of the
+// "postprocess" program per stage. The scene is anti-aliased with samples
+// MSAA samples, fewer if the driver doesn't support as many; the window
+// should have none, since the stages draw to it without depth. It must be
+// called after the OpenGL context is created.
+func NewPipeline(registry *shaders.Registry, description Description, width, height int32, samples int) (*Pipeline, error) {
+	if err := description.Validate(); err != nil {
+		return nil, fmt.Errorf("postprocess: %v", err)
+	}
+
+	var maxSamples int32
+	gl.GetIntegerv(gl.MAX_SAMPLES, &maxSamples)
+	if int32(samples) > maxSamples {
+		samples = int(maxSamples)
+	}
+	if samples < 2 {
+		samples = 0
+	}
+
+	p := &Pipeline{
+		Description: description,
+		Width:       width,
+		Height:      height,
+		Samples:     int32(samples),
+		Biome:       DefaultBiome,
+		programs:    map[Stage]*shaders.Program{},
+		quad:        geometry.NewMesh(geometry.BuildFace(), nil),
+	}
+
+	if err := p.createTargets(); err != nil {
+		p.quad.Destroy()
+		return nil, err
+	}
+
+	for _, stage := range description.Stages {
+		program, err := registry.LoadVariant("postprocess", StageVariant(stage))
+		if err != nil {
+			p.Destroy()
+			return nil, err
+		}
+		p.programs[stage] = program
+	}
+
+	return p, nil
+}
+
+func (p *Pipeline) createTargets() error {
+	var err error
+	if p.scene, err = newTarget(p.Width, p.Height, p.Description.HDR, true); err != nil {
+		return err
+	}
+	if p.Samples > 0 {
+		if p.multisampled, err = newMultisampled(p.Width, p.Height, p.Samples, p.Description.HDR); err != nil {
+			p.destroyTargets()
+			return err
+		}
+	}
+	for i := range p.pingPong {
+		if p.pingPong[i], err = newTarget(p.Width, p.Height, p.Description.HDR, false); err != nil {
+			p.destroyTargets()
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Pipeline) destroyTargets() {
+	p.scene.destroy()
+	p.multisampled.destroy()
+	for i := range p.pingPong {
+		p.pingPong[i].destroy()
+	}
+}
+
+// Resize creates the targets again with the new size of the window
+func (p *Pipeline) Resize(width, height int32) error {
+	if width == p.Width && height == p.Height {
+		return nil
+	}
+
+	p.destroyTargets()
+	p.Width, p.Height = width, height
+	return p.createTargets()
+}
+
+// StageVariant returns the shader variant of a stage: "STAGE_<NAME>" plus the fog mode constants
+func StageVariant(stage Stage) shaders.Variant {
+	defines := []shaders.Define{{Name: "STAGE_" + strings.ToUpper(string(stage)), Value: "1"}}
+	for i, mode := range fogModes {
+		defines = append(defines, shaders.Define{Name: "FOG_" + strings.ToUpper(string(mode)), Value: fmt.Sprint(i)})
+	}
+
+	return shaders.Variant{Name: string(stage), Defines: defines}
+}
+
+// Begin redirects rendering to the offscreen scene target, the multisampled
+// one with MSAA
+func (p *Pipeline) Begin() {
+	framebuffer := p.scene.framebuffer
+	if p.Samples > 0 {
+		framebuffer = p.multisampled.framebuffer
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, framebuffer)
+	gl.Viewport(0, 0, p.Width, p.Height)
+}
+
+// End resolves the MSAA samples, runs the stages and writes the result to the
+// screen. projection is the camera projection, used to turn depths back into
+// distances.
+func (p *Pipeline) End(projection mgl32.Mat4) {
+	if p.Samples > 0 {
+		// a profundidade também é resolvida, para a névoa
+		gl.BindFramebuffer(gl.READ_FRAMEBUFFER, p.multisampled.framebuffer)
+		gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, p.scene.framebuffer)
+		gl.BlitFramebuffer(0, 0, p.Width, p.Height, 0, 0, p.Width, p.Height, gl.COLOR_BUFFER_BIT|gl.DEPTH_BUFFER_BIT, gl.NEAREST)
+		gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	}
+
+	stages := []Stage{}
+	for _, stage := range p.Description.Stages {
+		if stage == StageUnderwater && !p.Underwater {
+			continue
+		}
+		stages = append(stages, stage)
+	}
+
+	if len(stages) == 0 {
+		gl.BindFramebuffer(gl.READ_FRAMEBUFFER, p.scene.framebuffer)
+		gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, 0)
+		gl.BlitFramebuffer(0, 0, p.Width, p.Height, 0, 0, p.Width, p.Height, gl.COLOR_BUFFER_BIT, gl.NEAREST)
+		gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+		return
+	}
+
+	gl.Disable(gl.DEPTH_TEST)
+	gl.DepthMask(false)
+
+	input := p.scene.colorTexture
+	for i, stage := range stages {
+		output := uint32(0)
+		if i < len(stages)-1 {
+			output = p.pingPong[i%2].framebuffer
+		}
+		gl.BindFramebuffer(gl.FRAMEBUFFER, output)
+
+		gl.ActiveTexture(gl.TEXTURE0)
+		gl.BindTexture(gl.TEXTURE_2D, input)
+		gl.ActiveTexture(gl.TEXTURE1)
+		gl.BindTexture(gl.TEXTURE_2D, p.scene.depthTexture)
+		gl.ActiveTexture(gl.TEXTURE0)
+
+		program := p.programs[stage]
+		p.setUniforms(program, projection)
+		p.quad.DrawWithProgram(program, math2.Matrix_Scale(2, 2, 1))
+
+		input = p.pingPong[i%2].colorTexture
+	}
+
+	gl.DepthMask(true)
+	gl.Enable(gl.DEPTH_TEST)
+}
+
+func (p *Pipeline) setUniforms(program *shaders.Program, projection mgl32.Mat4) {
+	program.SetInt("color_texture", 0)
+	program.SetInt("depth_texture", 1)
+	program.SetVec2("texel_size", mgl32.Vec2{1 / float32(p.Width), 1 / float32(p.Height)})
+	program.SetMat4("inverse_projection", projection.Inv())
+
+	biome := p.Biome
+	if p.Underwater {
+		biome = UnderwaterBiome
+	}
+	fog := p.Description.FogFor(biome)
+	fogColor := p.FogColor
+	if fog.Color != nil {
+		fogColor = mgl32.Vec3(*fog.Color)
+	}
+	fogSky := float32(0)
+	if fog.FogSky {
+		fogSky = 1
+	}
+
+	// a névoa é aplicada antes da correção gamma, então a cor vai para o espaço linear
+	program.SetVec3("fog_color", toLinear(fogColor, p.Description.Gamma))
+	program.SetInt("fog_mode", int32(fogModeIndex(fog.Mode)))
+	program.SetFloat("fog_start", fog.Start)
+	program.SetFloat("fog_end", fog.End)
+	program.SetFloat("fog_density", fog.Density)
+	program.SetFloat("fog_sky", fogSky)
+
+	program.SetVec3("underwater_tint", toLinear(mgl32.Vec3(p.Description.UnderwaterTint), p.Description.Gamma))
+	program.SetFloat("exposure", p.Description.Exposure)
+	program.SetFloat("gamma", p.Description.Gamma)
+}
+
+// Destroy frees the targets
+func (p *Pipeline) Destroy() {
+	p.destroyTargets()
+	p.quad.Destroy()
+}
+
+func toLinear(color mgl32.Vec3, gamma float32) mgl32.Vec3 {
+	for i := range color {
+		color[i] = float32(math.Pow(float64(color[i]), float64(gamma)))
+	}
+
+	return color
+}
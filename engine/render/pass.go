@@ -0,0 +1,64 @@
+package render
+
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
+)
+
+// Pass is a group of objects drawn with the same blending and depth state.
+// Passes are drawn in order.
+type Pass int
+
+const (
+	PassOpaque      Pass = iota // sem blending, escreve profundidade
+	PassCutout                  // como o opaco, mas descarta fragmentos transparentes (folhas)
+	PassTranslucent             // blending, sem escrita de profundidade, de trás para frente (água, vidro)
+	NumPasses
+)
+
+// Fragmentos com alpha menor que isso são descartados no PassCutout
+const AlphaCutoff float32 = 0.5
+
+var passNames = []string{
+	PassOpaque:      "opaque",
+	PassCutout:      "cutout",
+	PassTranslucent: "translucent",
+}
+
+func (p Pass) String() string {
+	if p < 0 || p >= NumPasses {
+		return "unknown"
+	}
+
+	return passNames[p]
+}
+
+// Begin sets the OpenGL state of the pass
+func (p Pass) Begin() {
+	alphaCutoff := float32(0)
+
+	switch p {
+	case PassOpaque:
+		gl.Disable(gl.BLEND)
+		gl.DepthMask(true)
+	case PassCutout:
+		gl.Disable(gl.BLEND)
+		gl.DepthMask(true)
+		alphaCutoff = AlphaCutoff
+	case PassTranslucent:
+		gl.Enable(gl.BLEND)
+		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+		// objetos translúcidos não escondem o que está atrás deles
+		gl.DepthMask(false)
+	}
+
+	shaders.Get("standard").SetFloat("alpha_cutoff", alphaCutoff)
+}
+
+// End restores the default state set up in main
+func End() {
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.DepthMask(true)
+	shaders.Get("standard").SetFloat("alpha_cutoff", 0)
+}
@@ -0,0 +1,116 @@
+package replay
+
+import (
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/controls"
+	"github.com/reonardoleis/fcg-glcraft/engine/scene"
+	math2 "github.com/reonardoleis/fcg-glcraft/math"
+	"github.com/reonardoleis/fcg-glcraft/player"
+	"github.com/reonardoleis/fcg-glcraft/world/saves"
+)
+
+// Driver feeds a recording back to the controls in place of the window, one
+// tick at a time
+type Driver struct {
+	recording *Recording
+	game      *scene.GameScene
+	tick      int
+	entry     int
+}
+
+// NewDriver puts the input back as it was when the recording started. The
+// game must have been created from the recorded world.
+func NewDriver(recording *Recording, game *scene.GameScene) *Driver {
+	header := recording.Header
+	controls.Reset(header.Cursor[0], header.Cursor[1])
+	controls.MouseLook = header.MouseLook
+	if header.ViewDistance > 0 {
+		configs.Video.ViewDistance = header.ViewDistance
+	}
+	controls.DefaultActions = controls.NewActionMap(header.Bindings)
+	for _, action := range header.Toggled {
+		controls.DefaultActions.SetToggled(action, true)
+	}
+
+	game.World.Deterministic = true
+
+	return &Driver{recording: recording, game: game}
+}
+
+// Timestep is the fixed step the recording was made with
+func (d *Driver) Timestep() float64 {
+	if d.recording.Header.Timestep <= 0 {
+		return configs.FixedTimestep
+	}
+
+	return d.recording.Header.Timestep
+}
+
+// Next applies the events of the next tick and tells if the game should be
+// stepped in it. ok is false when the recording is over.
+func (d *Driver) Next() (stepped, ok bool) {
+	if d.tick >= len(d.recording.Stepped) {
+		return false, false
+	}
+
+	for ; d.entry < len(d.recording.Entries) && d.recording.Entries[d.entry].Tick <= d.tick; d.entry++ {
+		entry := d.recording.Entries[d.entry]
+		switch entry.Kind {
+		case EntryEvent:
+			controls.Apply(entry.Event)
+		case EntryFocus:
+			d.game.ControlHandler.SetEnabled(entry.Focused)
+			if entry.Focused {
+				d.game.ControlHandler.FinishMousePositionChanged()
+			}
+			d.game.Player.GameMode = player.GameMode(entry.GameMode)
+		}
+	}
+
+	stepped = d.recording.Stepped[d.tick]
+	d.tick++
+	return stepped, true
+}
+
+// Ticks returns how many ticks were replayed
+func (d *Driver) Ticks() int {
+	return d.tick
+}
+
+// RunHeadless replays the recording without a window and returns the final
+// state of the game
+func RunHeadless(recording *Recording) State {
+	game := newHeadlessGame(recording.Header.World)
+	driver := NewDriver(recording, game)
+	timestep := driver.Timestep()
+	for {
+		stepped, ok := driver.Next()
+		if !ok {
+			break
+		}
+
+		controls.DefaultActions.Update()
+		if stepped {
+			math2.DeltaTime = timestep
+			game.Step(timestep)
+		}
+	}
+
+	return StateOf(game, driver.Ticks())
+}
+
+// Cria o jogo de um mundo sem janela
+func newHeadlessGame(info saves.WorldInfo) *scene.GameScene {
+	game := scene.NewGame(info, controls.NewControls(nil).Route())
+	// sem janela não há terreno distante para desenhar
+	game.World.LOD.Destroy()
+	game.World.LOD = nil
+
+	return game
+}
+
+// Verify replays the recording without a window and checks that it ends in
+// the recorded state, for regression tests
+func Verify(recording *Recording) error {
+	return recording.Final.Compare(RunHeadless(recording))
+}
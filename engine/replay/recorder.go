@@ -0,0 +1,173 @@
+package replay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/controls"
+	"github.com/reonardoleis/fcg-glcraft/engine/scene"
+	"github.com/reonardoleis/fcg-glcraft/player"
+	"github.com/reonardoleis/fcg-glcraft/world/saves"
+)
+
+// Recorder writes every input event of a game, and the ticks they happened
+// before, to a replay file. The game loop calls Sync whenever the game may
+// have gained or lost the focus and Tick after each fixed step.
+type Recorder struct {
+	file   *os.File
+	gzip   *gzip.Writer
+	writer *bufio.Writer
+	game   *scene.GameScene
+
+	ticks      int
+	run        uint64 // ticks ainda não escritos, todos simulados ou todos pausados
+	runStepped bool
+	synced     bool
+	focused    bool
+	gameMode   player.GameMode
+	started    time.Time
+}
+
+// NewRecorder starts recording the game, which must have just been created
+// from info. The world is switched to deterministic updates.
+func NewRecorder(path string, game *scene.GameScene, info saves.WorldInfo, timestep float64) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	header := Header{
+		World:        info,
+		Timestep:     timestep,
+		MouseLook:    controls.MouseLook,
+		ViewDistance: configs.Video.ViewDistance,
+		Bindings:     controls.DefaultActions.Bindings(),
+		Started:      time.Now(),
+	}
+	header.Cursor[0], header.Cursor[1] = controls.CursorPosition()
+	for _, action := range controls.Actions {
+		if controls.DefaultActions.Toggled(action) {
+			header.Toggled = append(header.Toggled, action)
+		}
+	}
+
+	encoded, err := json.Marshal(header)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	r := &Recorder{file: file, game: game, started: header.Started}
+	r.gzip = gzip.NewWriter(file)
+	r.writer = bufio.NewWriter(r.gzip)
+	r.writer.WriteString(magic)
+	r.writer.WriteByte(version)
+	writeUvarint(r.writer, uint64(len(encoded)))
+	r.writer.Write(encoded)
+
+	game.World.Deterministic = true
+	controls.Listener = r.event
+	r.Sync()
+
+	return r, nil
+}
+
+// Game returns the game being recorded
+func (r *Recorder) Game() *scene.GameScene {
+	return r.game
+}
+
+func (r *Recorder) event(event controls.Event) {
+	r.flushRun()
+	r.writer.WriteByte(byte(recordEvent))
+	writeEvent(r.writer, event)
+}
+
+// Sync records whether the game has the focus, and its game mode, if they
+// changed. Pausing changes what the game reads from the input.
+func (r *Recorder) Sync() {
+	focused := r.game.ControlHandler.Enabled()
+	gameMode := r.game.Player.GameMode
+	if r.synced && focused == r.focused && gameMode == r.gameMode {
+		return
+	}
+	r.synced, r.focused, r.gameMode = true, focused, gameMode
+
+	r.flushRun()
+	r.writer.WriteByte(byte(recordFocus))
+	if focused {
+		r.writer.WriteByte(1)
+	} else {
+		r.writer.WriteByte(0)
+	}
+	writeVarint(r.writer, int64(gameMode))
+}
+
+// Tick records a fixed step of the loop, in which the game was simulated or not
+func (r *Recorder) Tick(stepped bool) {
+	if r.run > 0 && stepped != r.runStepped {
+		r.flushRun()
+	}
+	r.run++
+	r.runStepped = stepped
+	r.ticks++
+}
+
+// Frame records when a frame was drawn
+func (r *Recorder) Frame() {
+	r.flushRun()
+	r.writer.WriteByte(byte(recordFrame))
+	binary.Write(r.writer, binary.LittleEndian, time.Since(r.started).Seconds())
+}
+
+func (r *Recorder) flushRun() {
+	if r.run == 0 {
+		return
+	}
+
+	if r.runStepped {
+		r.writer.WriteByte(byte(recordSteps))
+	} else {
+		r.writer.WriteByte(byte(recordIdle))
+	}
+	writeUvarint(r.writer, r.run)
+	r.run = 0
+}
+
+// Close writes the final state of the game, which replays are checked
+// against, and stops listening to the input
+func (r *Recorder) Close() error {
+	controls.Listener = nil
+
+	r.flushRun()
+	r.writer.WriteByte(byte(recordEnd))
+	writeState(r.writer, StateOf(r.game, r.ticks))
+
+	if err := r.writer.Flush(); err != nil {
+		r.file.Close()
+		return err
+	}
+	if err := r.gzip.Close(); err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
+
+// StateOf returns the state of the game after the given number of ticks
+func StateOf(game *scene.GameScene, ticks int) State {
+	position := game.Player.Position
+	return State{
+		Ticks:       ticks,
+		Position:    [3]float32{position.X(), position.Y(), position.Z()},
+		CameraTheta: game.MainCamera.CameraTheta,
+		CameraPhi:   game.MainCamera.CameraPhi,
+		GameMode:    int(game.Player.GameMode),
+		WorldHash:   game.World.Hash(),
+	}
+}
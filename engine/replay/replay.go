@@ -0,0 +1,333 @@
+// Package replay records the input of a game session and plays it back. The
+// simulation runs in fixed steps and the world is generated from its seed, so
+// feeding the same events before the same steps reproduces the same player
+// trajectory, with or without a window.
+package replay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/reonardoleis/fcg-glcraft/engine/controls"
+	"github.com/reonardoleis/fcg-glcraft/world/saves"
+)
+
+// Começo de todo arquivo de replay, seguido da versão do formato
+const (
+	magic   = "GLCR"
+	version = 3
+)
+
+// Versão mais antiga que ainda é lida; a 2 não guarda o hash do mundo
+const oldestVersion = 2
+
+var ErrInvalidFile = errors.New("replay: not a replay file")
+
+// Header is what the game looked like when the recording started
+type Header struct {
+	World        saves.WorldInfo           `json:"world"`
+	Timestep     float64                   `json:"timestep"`
+	MouseLook    controls.MouseLookOptions `json:"mouse_look"`
+	ViewDistance float32                   `json:"view_distance"` // decide quais chunks são gerados e atualizados
+	Bindings     controls.Bindings         `json:"bindings"`
+	Toggled      []controls.Action         `json:"toggled"` // ações alternadas no início
+	Cursor       [2]float64                `json:"cursor"`
+	Started      time.Time                 `json:"started"`
+}
+
+// State is what a replay is checked against when it ends
+type State struct {
+	Ticks       int
+	Position    [3]float32
+	CameraTheta float64
+	CameraPhi   float64
+	GameMode    int
+	WorldHash   uint64 // veja World.Hash, 0 se a gravação não o tem
+}
+
+// Tolerance is how far, in blocks, a replayed position may be from the recorded one
+const Tolerance = 1e-3
+
+// Compare returns an error describing how other differs from s
+func (s State) Compare(other State) error {
+	if s.Ticks != other.Ticks {
+		return fmt.Errorf("replay: %d ticks, expected %d", other.Ticks, s.Ticks)
+	}
+	for i := range s.Position {
+		if math.Abs(float64(s.Position[i]-other.Position[i])) > Tolerance {
+			return fmt.Errorf("replay: player at %v, expected %v", other.Position, s.Position)
+		}
+	}
+	if math.Abs(s.CameraTheta-other.CameraTheta) > Tolerance || math.Abs(s.CameraPhi-other.CameraPhi) > Tolerance {
+		return fmt.Errorf("replay: camera at (%.4f, %.4f), expected (%.4f, %.4f)", other.CameraTheta, other.CameraPhi, s.CameraTheta, s.CameraPhi)
+	}
+	if s.GameMode != other.GameMode {
+		return fmt.Errorf("replay: game mode %d, expected %d", other.GameMode, s.GameMode)
+	}
+	if s.WorldHash != 0 && s.WorldHash != other.WorldHash {
+		return fmt.Errorf("replay: world hash %016x, expected %016x", other.WorldHash, s.WorldHash)
+	}
+
+	return nil
+}
+
+type EntryKind uint8
+
+const (
+	EntryEvent EntryKind = iota // evento de entrada
+	EntryFocus                  // o jogo ganhou ou perdeu o foco (pausa)
+)
+
+// Entry is something that happened before a tick was simulated
+type Entry struct {
+	Tick     int
+	Kind     EntryKind
+	Event    controls.Event
+	Focused  bool
+	GameMode int
+}
+
+// Frame is when a frame was drawn, in seconds since the recording started
+type Frame struct {
+	Tick int
+	Time float64
+}
+
+// Recording is a whole replay file in memory
+type Recording struct {
+	Header  Header
+	Entries []Entry
+	Frames  []Frame
+	Stepped []bool // se o jogo foi simulado em cada tick, ou se estava pausado
+	Final   State
+
+	version int // do arquivo lido
+}
+
+// Duration is the time between the first and the last frame recorded
+func (r *Recording) Duration() time.Duration {
+	if len(r.Frames) == 0 {
+		return 0
+	}
+
+	return time.Duration(r.Frames[len(r.Frames)-1].Time * float64(time.Second))
+}
+
+// Registros do arquivo, depois do cabeçalho
+type recordKind uint8
+
+const (
+	recordEvent recordKind = iota // tipo do evento e seus dados
+	recordFocus                   // byte com foco, varint com o modo de jogo
+	recordSteps                   // uvarint de ticks em que o jogo foi simulado
+	recordIdle                    // uvarint de ticks em que o jogo estava pausado
+	recordFrame                   // float64 com o tempo do quadro
+	recordEnd                     // estado final
+)
+
+// Load reads a replay file written by a Recorder
+func Load(path string) (*Recording, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	compressed, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, ErrInvalidFile
+	}
+	reader := bufio.NewReader(compressed)
+
+	start := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(reader, start); err != nil || string(start[:len(magic)]) != magic {
+		return nil, ErrInvalidFile
+	}
+	fileVersion := int(start[len(magic)])
+	if fileVersion < oldestVersion || fileVersion > version {
+		return nil, fmt.Errorf("replay: unsupported version %d", fileVersion)
+	}
+
+	recording := &Recording{version: fileVersion}
+	headerLength, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, ErrInvalidFile
+	}
+	header := make([]byte, headerLength)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, ErrInvalidFile
+	}
+	if err := json.Unmarshal(header, &recording.Header); err != nil {
+		return nil, err
+	}
+
+	if err := recording.read(reader); err != nil {
+		return nil, fmt.Errorf("replay: %s is truncated: %w", path, err)
+	}
+
+	return recording, nil
+}
+
+func (r *Recording) read(reader *bufio.Reader) error {
+	for {
+		kind, err := reader.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		tick := len(r.Stepped)
+		switch recordKind(kind) {
+		case recordEvent:
+			event, err := readEvent(reader)
+			if err != nil {
+				return err
+			}
+			r.Entries = append(r.Entries, Entry{Tick: tick, Kind: EntryEvent, Event: event})
+
+		case recordFocus:
+			focused, err := reader.ReadByte()
+			if err != nil {
+				return err
+			}
+			gameMode, err := binary.ReadVarint(reader)
+			if err != nil {
+				return err
+			}
+			r.Entries = append(r.Entries, Entry{Tick: tick, Kind: EntryFocus, Focused: focused == 1, GameMode: int(gameMode)})
+
+		case recordSteps, recordIdle:
+			count, err := binary.ReadUvarint(reader)
+			if err != nil {
+				return err
+			}
+			for i := uint64(0); i < count; i++ {
+				r.Stepped = append(r.Stepped, recordKind(kind) == recordSteps)
+			}
+
+		case recordFrame:
+			var seconds float64
+			if err := binary.Read(reader, binary.LittleEndian, &seconds); err != nil {
+				return err
+			}
+			r.Frames = append(r.Frames, Frame{Tick: tick, Time: seconds})
+
+		case recordEnd:
+			return readState(reader, &r.Final, r.version)
+
+		default:
+			return fmt.Errorf("unknown record %d", kind)
+		}
+	}
+}
+
+func writeEvent(writer *bufio.Writer, event controls.Event) {
+	writer.WriteByte(byte(event.Type))
+	switch event.Type {
+	case controls.EventKey, controls.EventMouseButton:
+		writeVarint(writer, int64(event.Code))
+		writer.WriteByte(byte(event.Action))
+	case controls.EventCursor:
+		binary.Write(writer, binary.LittleEndian, [2]float64{event.X, event.Y})
+	case controls.EventChar:
+		writeVarint(writer, int64(event.Code))
+	}
+}
+
+func readEvent(reader *bufio.Reader) (controls.Event, error) {
+	eventType, err := reader.ReadByte()
+	if err != nil {
+		return controls.Event{}, err
+	}
+
+	event := controls.Event{Type: controls.EventType(eventType)}
+	readCode := func() {
+		if err == nil {
+			var code int64
+			code, err = binary.ReadVarint(reader)
+			event.Code = int(code)
+		}
+	}
+	readAction := func() {
+		if err == nil {
+			var action byte
+			action, err = reader.ReadByte()
+			event.Action = glfw.Action(action)
+		}
+	}
+	readPosition := func() {
+		if err == nil {
+			var position [2]float64
+			err = binary.Read(reader, binary.LittleEndian, &position)
+			event.X, event.Y = position[0], position[1]
+		}
+	}
+
+	switch event.Type {
+	case controls.EventKey, controls.EventMouseButton:
+		readCode()
+		readAction()
+	case controls.EventCursor:
+		readPosition()
+	case controls.EventChar:
+		readCode()
+	default:
+		return event, fmt.Errorf("unknown event %d", eventType)
+	}
+
+	return event, err
+}
+
+func writeState(writer *bufio.Writer, state State) {
+	writeVarint(writer, int64(state.Ticks))
+	binary.Write(writer, binary.LittleEndian, state.Position)
+	binary.Write(writer, binary.LittleEndian, [2]float64{state.CameraTheta, state.CameraPhi})
+	writeVarint(writer, int64(state.GameMode))
+	binary.Write(writer, binary.LittleEndian, state.WorldHash)
+}
+
+func readState(reader *bufio.Reader, state *State, fileVersion int) error {
+	ticks, err := binary.ReadVarint(reader)
+	if err != nil {
+		return err
+	}
+	state.Ticks = int(ticks)
+
+	if err := binary.Read(reader, binary.LittleEndian, &state.Position); err != nil {
+		return err
+	}
+	var camera [2]float64
+	if err := binary.Read(reader, binary.LittleEndian, &camera); err != nil {
+		return err
+	}
+	state.CameraTheta, state.CameraPhi = camera[0], camera[1]
+
+	gameMode, err := binary.ReadVarint(reader)
+	if err != nil {
+		return err
+	}
+	state.GameMode = int(gameMode)
+
+	if fileVersion < 3 {
+		return nil
+	}
+	return binary.Read(reader, binary.LittleEndian, &state.WorldHash)
+}
+
+func writeVarint(writer *bufio.Writer, value int64) {
+	var buffer [binary.MaxVarintLen64]byte
+	writer.Write(buffer[:binary.PutVarint(buffer[:], value)])
+}
+
+func writeUvarint(writer *bufio.Writer, value uint64) {
+	var buffer [binary.MaxVarintLen64]byte
+	writer.Write(buffer[:binary.PutUvarint(buffer[:], value)])
+}
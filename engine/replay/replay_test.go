@@ -0,0 +1,149 @@
+package replay
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/controls"
+	"github.com/reonardoleis/fcg-glcraft/engine/scene"
+	math2 "github.com/reonardoleis/fcg-glcraft/math"
+	"github.com/reonardoleis/fcg-glcraft/world/chunk"
+)
+
+var update = flag.Bool("update", false, "record the fixtures in testdata again")
+
+// Gravação curta em um mundo plano: anda, pula, vira a câmera para baixo e
+// quebra o bloco sob o jogador
+const (
+	fixture      = "testdata/walk.glcr"
+	fixtureTicks = 240
+)
+
+func fixtureEvents(tick int) []controls.Event {
+	key := func(key glfw.Key, action glfw.Action) controls.Event {
+		return controls.Event{Type: controls.EventKey, Code: int(key), Action: action}
+	}
+	mouse := func(action glfw.Action) controls.Event {
+		return controls.Event{Type: controls.EventMouseButton, Code: int(glfw.MouseButtonLeft), Action: action}
+	}
+
+	switch {
+	case tick == 10:
+		return []controls.Event{key(glfw.KeyW, glfw.Press)}
+	case tick == 40:
+		return []controls.Event{key(glfw.KeySpace, glfw.Press)}
+	case tick == 45:
+		return []controls.Event{key(glfw.KeySpace, glfw.Release)}
+	case tick == 90:
+		return []controls.Event{key(glfw.KeyW, glfw.Release)}
+	case tick > 90 && tick <= 120:
+		// gira e olha para baixo
+		return []controls.Event{{Type: controls.EventCursor, X: float64(tick-90) * 4, Y: float64(tick-90) * 8}}
+	case tick == 130:
+		return []controls.Event{mouse(glfw.Press)}
+	case tick == 200:
+		return []controls.Event{mouse(glfw.Release)}
+	}
+
+	return nil
+}
+
+// Grava o fixture jogando os eventos como se viessem da janela
+func recordFixture(t *testing.T, path string) {
+	info := scene.NewWorldInfo("replay", 42, chunk.GeneratorFlat, configs.DefaultWorld(), time.Unix(0, 0))
+	info.PlayerPosition = [3]float32{0.5, float32(chunk.FlatHeight) + 2, 0.5}
+
+	controls.Reset(0, 0)
+	controls.DefaultActions = controls.NewActionMap(controls.DefaultBindings())
+
+	game := newHeadlessGame(info)
+	recorder, err := NewRecorder(path, game, info, configs.FixedTimestep)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for tick := 0; tick < fixtureTicks; tick++ {
+		for _, event := range fixtureEvents(tick) {
+			controls.Listener(event)
+			controls.Apply(event)
+		}
+
+		controls.DefaultActions.Update()
+		math2.DeltaTime = configs.FixedTimestep
+		game.Step(configs.FixedTimestep)
+		recorder.Tick(true)
+		recorder.Sync()
+	}
+
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplayFixture(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generates a whole world")
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(fixture), 0755); err != nil {
+			t.Fatal(err)
+		}
+		recordFixture(t, fixture)
+	}
+
+	recording, err := Load(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recording.Final.Ticks != fixtureTicks || len(recording.Stepped) != fixtureTicks {
+		t.Fatalf("fixture has %d ticks (%d stepped), want %d", recording.Final.Ticks, len(recording.Stepped), fixtureTicks)
+	}
+	if recording.Final.WorldHash == 0 {
+		t.Fatal("fixture has no world hash")
+	}
+
+	if err := Verify(recording); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	recorded := State{Ticks: 10, Position: [3]float32{1, 2, 3}, CameraTheta: 0.5, CameraPhi: -0.2, GameMode: 1, WorldHash: 0xabc}
+
+	tests := []struct {
+		name    string
+		change  func(s *State)
+		wantErr bool
+	}{
+		{"igual", func(s *State) {}, false},
+		{"dentro da tolerância", func(s *State) { s.Position[1] += Tolerance / 2 }, false},
+		{"ticks", func(s *State) { s.Ticks++ }, true},
+		{"posição", func(s *State) { s.Position[0] += 2 * Tolerance }, true},
+		{"câmera", func(s *State) { s.CameraPhi += 0.1 }, true},
+		{"modo de jogo", func(s *State) { s.GameMode = 0 }, true},
+		{"mundo", func(s *State) { s.WorldHash = 0xabd }, true},
+	}
+
+	for _, test := range tests {
+		replayed := recorded
+		test.change(&replayed)
+		if err := recorded.Compare(replayed); (err != nil) != test.wantErr {
+			t.Errorf("%s: Compare() = %v, wantErr %v", test.name, err, test.wantErr)
+		}
+	}
+
+	// gravações sem o hash do mundo não o conferem
+	old := recorded
+	old.WorldHash = 0
+	replayed := recorded
+	replayed.WorldHash = 0x123
+	if err := old.Compare(replayed); err != nil {
+		t.Errorf("recording without world hash: %v", err)
+	}
+}
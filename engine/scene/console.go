@@ -0,0 +1,180 @@
+package scene
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/engine/ui"
+)
+
+// Linhas guardadas e mostradas no console
+const (
+	consoleLogSize     = 100
+	consoleVisibleLogs = 10
+	consoleWidth       = 640
+)
+
+// ConsoleScreen is the chat shown over the game: lines starting with / run a
+// command of the game, the others are chat messages. Up and down go through
+// the history, Tab completes and Enter on an empty line closes it.
+type ConsoleScreen struct {
+	Context *ui.Context
+
+	game        *GameScene
+	field       *commandField
+	log         *ui.Label
+	suggestions *ui.Label
+	lines       []string
+	onClose     func()
+
+	completions []string // opções do último Tab, percorridas a cada Tab
+	completion  int
+}
+
+func NewConsoleScreen(font *ui.Font, screen ui.Screen, game *GameScene, onClose func()) *ConsoleScreen {
+	ctx := ui.NewContext(font, screen)
+	c := &ConsoleScreen{Context: ctx, game: game, onClose: onClose}
+
+	c.field = &commandField{TextField: ui.NewTextField(ui.BottomLeft, mgl32.Vec2{10, -10}, mgl32.Vec2{consoleWidth, buttonHeight}, "", c.submit), onKey: c.handleKey}
+	c.field.MaxLength = 256
+	c.log = ui.NewLabel(ui.BottomLeft, mgl32.Vec2{8, -8}, "", 2)
+	c.suggestions = ui.NewLabel(ui.BottomLeft, mgl32.Vec2{10, -(buttonHeight + 14)}, "", 1)
+	c.suggestions.Color = ui.MutedTextColor
+
+	history := ui.NewPanel(ui.BottomLeft, mgl32.Vec2{10, -(buttonHeight + 30)}, mgl32.Vec2{consoleWidth, float32(consoleVisibleLogs*font.LineHeight*2 + 16)}, ui.PanelColor, c.log)
+	ctx.Root.Add(
+		history,
+		c.suggestions,
+		ui.NewLabel(ui.TopLeft, mgl32.Vec2{10, 10}, "Enter: enviar   Tab: completar   Cima/Baixo: histórico   Enter vazio: fechar", 1),
+		c.field,
+	)
+
+	return c
+}
+
+// Refresh focuses the text field, starting with text, e.g. "/" for a command
+func (c *ConsoleScreen) Refresh(text string) {
+	c.field.SetText(text)
+	c.Context.Focus(c.field)
+	c.completions = nil
+	c.suggestions.Text = ""
+}
+
+// Print adds lines to the log of the console
+func (c *ConsoleScreen) Print(lines ...string) {
+	for _, line := range lines {
+		c.lines = append(c.lines, strings.Split(line, "\n")...)
+	}
+	if len(c.lines) > consoleLogSize {
+		c.lines = c.lines[len(c.lines)-consoleLogSize:]
+	}
+
+	visible := c.lines
+	if len(visible) > consoleVisibleLogs {
+		visible = visible[len(visible)-consoleVisibleLogs:]
+	}
+	c.log.Text = strings.Join(visible, "\n")
+}
+
+func (c *ConsoleScreen) submit(text string) {
+	text = strings.TrimSpace(text)
+	c.field.SetText("")
+	c.completions = nil
+	c.suggestions.Text = ""
+	if text == "" {
+		c.onClose()
+		return
+	}
+
+	if !strings.HasPrefix(text, "/") {
+		c.game.Commands.History.Add(text)
+		c.Print(fmt.Sprintf("<%s> %s", c.game.Player.Name, text))
+		return
+	}
+
+	c.Print("> " + text)
+	output, err := c.game.Commands.Execute(c.game.CommandSource(), text)
+	c.Print(output...)
+	if err != nil {
+		c.Print(err.Error())
+	}
+}
+
+// Teclas do console que o campo de texto não usa
+func (c *ConsoleScreen) handleKey(key ui.Key) bool {
+	switch key {
+	case ui.KeyUp:
+		if line, ok := c.game.Commands.History.Previous(); ok {
+			c.field.SetText(line)
+		}
+	case ui.KeyDown:
+		if line, ok := c.game.Commands.History.Next(); ok {
+			c.field.SetText(line)
+		}
+	case ui.KeyTab:
+		c.complete()
+		return true
+	default:
+		return false
+	}
+
+	c.completions = nil
+	return true
+}
+
+// Completa a última palavra; Tab de novo passa para a próxima opção
+func (c *ConsoleScreen) complete() {
+	if len(c.completions) > 0 && c.field.Text == c.completions[c.completion] {
+		c.completion = (c.completion + 1) % len(c.completions)
+		c.field.SetText(c.completions[c.completion])
+		return
+	}
+
+	text := c.field.Text
+	if text == "" {
+		text = "/"
+	}
+	c.completions = c.game.Commands.Complete(c.game.CommandSource(), text)
+	c.completion = 0
+	switch len(c.completions) {
+	case 0:
+		c.suggestions.Text = ""
+	case 1:
+		c.field.SetText(c.completions[0] + " ")
+		c.completions = nil
+		c.suggestions.Text = ""
+	default:
+		c.field.SetText(c.completions[0])
+		words := []string{}
+		for _, completion := range c.completions {
+			fields := strings.Fields(completion)
+			words = append(words, fields[len(fields)-1])
+		}
+		c.suggestions.Text = strings.Join(words, "  ")
+	}
+}
+
+// Campo de texto que passa ao console as teclas que não edita o texto
+type commandField struct {
+	*ui.TextField
+	onKey func(key ui.Key) bool
+}
+
+func (f *commandField) HandleEvent(ctx *ui.Context, event ui.Event) bool {
+	switch event.Type {
+	case ui.MouseDown:
+		// o foco fica no campo inteiro, não só no TextField de dentro
+		if f.Bounds().Contains(event.X, event.Y) {
+			ctx.Focus(f)
+			return true
+		}
+		return false
+	case ui.KeyPress:
+		if f.onKey(event.Key) {
+			return true
+		}
+	}
+
+	return f.TextField.HandleEvent(ctx, event)
+}
@@ -0,0 +1,186 @@
+package scene
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/metrics"
+	"github.com/reonardoleis/fcg-glcraft/engine/ui"
+)
+
+// De quanto em quanto tempo as estatísticas do runtime são lidas (ReadMemStats para o mundo)
+const runtimeSampleInterval = 0.5
+
+var faceNames = []string{"north", "south", "east", "west", "up", "down"}
+
+// DebugOverlay shows the engine statistics over the game, like the F3 screen
+// of Minecraft. The values come from the metrics registry and from the scene.
+type DebugOverlay struct {
+	Context *ui.Context
+	Batch   *ui.Batch
+
+	general            *ui.Label
+	target             *ui.Label
+	frameTime          *ui.Graph
+	sinceRuntimeSample float64
+}
+
+func NewDebugOverlay(batch *ui.Batch, font *ui.Font, screen ui.Screen) *DebugOverlay {
+	d := &DebugOverlay{
+		Context:            ui.NewContext(font, screen),
+		Batch:              batch,
+		general:            ui.NewLabel(ui.TopLeft, mgl32.Vec2{8, 8}, "", 1),
+		target:             ui.NewLabel(ui.TopRight, mgl32.Vec2{-8, 8}, "", 1),
+		frameTime:          ui.NewGraph(ui.BottomLeft, mgl32.Vec2{8, -8}, mgl32.Vec2{metrics.HistoryLength, 60}, metrics.HistoryLength, 50),
+		sinceRuntimeSample: runtimeSampleInterval,
+	}
+	d.frameTime.Marks = []float64{1000.0 / 60, 1000.0 / 30}
+
+	d.Context.Root.Add(d.general, d.target, d.frameTime)
+
+	return d
+}
+
+// Update refreshes the text from the metrics and the state of the game scene
+func (d *DebugOverlay) Update(s *GameScene, deltaTime float64) {
+	d.sinceRuntimeSample += deltaTime
+	if d.sinceRuntimeSample >= runtimeSampleInterval {
+		d.sinceRuntimeSample = 0
+		metrics.SampleRuntime()
+	}
+
+	frameTimes := metrics.History(metrics.FrameTime)
+	d.frameTime.Values = d.frameTime.Values[:0]
+	for _, frameTime := range frameTimes {
+		d.frameTime.Values = append(d.frameTime.Values, frameTime*1000)
+	}
+
+	d.general.Text = d.generalText(s, frameTimes)
+	d.target.Text = d.targetText(s)
+}
+
+func (d *DebugOverlay) generalText(s *GameScene, frameTimes []float64) string {
+	var text strings.Builder
+
+	frameTime := metrics.Get(metrics.FrameTime)
+	fps := 0.0
+	if frameTime > 0 {
+		fps = 1 / frameTime
+	}
+	worst := 0.0
+	for _, t := range frameTimes {
+		worst = math.Max(worst, t)
+	}
+	fmt.Fprintf(&text, "fcg-glcraft %.0f fps (%.1f ms, worst %.1f ms)\n", fps, frameTime*1000, worst*1000)
+
+	x, y, z := s.Player.GetRealPosition()
+	bx, by, bz := s.Player.GetRoundedPosition()
+	chunkOffset := s.Player.GetChunkOffset()
+	fmt.Fprintf(&text, "XYZ: %.3f / %.3f / %.3f\n", x, y, z)
+	fmt.Fprintf(&text, "Block: %d %d %d\n", bx, by, bz)
+	fmt.Fprintf(&text, "Chunk: %.0f %.0f (local %d %d)\n", chunkOffset.X(), chunkOffset.Y(), mod(bx, configs.ChunkSize), mod(bz, configs.ChunkSize))
+
+	view := s.MainCamera.ViewVector
+	yaw := mgl32.RadToDeg(float32(math.Atan2(float64(view.Z()), float64(view.X()))))
+	pitch := mgl32.RadToDeg(float32(math.Asin(float64(mgl32.Clamp(view.Y(), -1, 1)))))
+	fmt.Fprintf(&text, "Facing: %s (yaw %.1f, pitch %.1f)\n", facing(view.Vec3()), yaw, pitch)
+
+	biome := "?"
+	if currentChunk := s.World.GetChunk(bx, bz); currentChunk != nil {
+		biome = fmt.Sprint(currentChunk.BiomeType)
+	}
+	fog := "default"
+	if s.PostProcess != nil && s.PostProcess.Underwater {
+		fog = "underwater"
+	}
+	fmt.Fprintf(&text, "Biome: %s, fog: %s\n", biome, fog)
+
+	skyLight := float32(1)
+	if s.Sky != nil {
+		skyLight = 1 - s.Sky.Night
+	}
+	hours := s.World.TimeOfDay() * 24
+	fmt.Fprintf(&text, "Sky light: %.2f, day time: %02d:%02d\n", skyLight, int(hours+6)%24, int(math.Mod(hours, 1)*60))
+
+	fmt.Fprintf(&text, "\nChunks: %.0f loaded, %.0f generated this frame, %.0f LOD building\n",
+		metrics.Get(metrics.ChunksLoaded), metrics.Get(metrics.ChunksGenerated), metrics.Get(metrics.ChunksGenerating))
+	fmt.Fprintf(&text, "Blocks drawn: %.0f\n", metrics.Get(metrics.BlocksDrawn))
+	fmt.Fprintf(&text, "Draw calls: %.0f, triangles: %.0f\n", metrics.Get(metrics.DrawCalls), metrics.Get(metrics.Triangles))
+	fmt.Fprintf(&text, "Particles: %.0f\n", metrics.Get(metrics.Particles))
+
+	fmt.Fprintf(&text, "\nGoroutines: %.0f\n", metrics.Get(metrics.Goroutines))
+	fmt.Fprintf(&text, "Heap: %.1f MiB, GC: %.0f (%.1f ms paused)\n",
+		metrics.Get(metrics.HeapAlloc)/(1<<20), metrics.Get(metrics.NumGC), metrics.Get(metrics.GCPauseTotal)*1000)
+
+	return text.String()
+}
+
+func (d *DebugOverlay) targetText(s *GameScene) string {
+	hitAt := s.Player.HitAt
+	if hitAt == nil {
+		return ""
+	}
+
+	x, y, z := int(hitAt.X()), int(hitAt.Y()), int(hitAt.Z())
+	target := s.World.GetBlockAt(x, y, z)
+	if target == nil {
+		return ""
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Targeted block: %d %d %d\n", x, y, z)
+	fmt.Fprintf(&text, "Type: %s\n", block.TypeName(target.BlockType))
+	fmt.Fprintf(&text, "WaterForce: %d, water above: %v\n", target.WaterForce, target.HasWaterAbove)
+	fmt.Fprintf(&text, "Falling: %v, breakable: %v\n", target.IsFalling, target.IsBreakable)
+	fmt.Fprintf(&text, "Transparent: %v, hardness: %.2f s\n", target.Transparent, block.Hardness(target.BlockType))
+
+	text.WriteString("\nNeighbours:\n")
+	for face, normal := range block.FaceNormals {
+		neighbourType := "air"
+		if neighbour := s.World.GetBlockAt(x+normal[0], y+normal[1], z+normal[2]); neighbour != nil {
+			neighbourType = block.TypeName(neighbour.BlockType)
+		}
+
+		lighting := "unlit"
+		if target.HasLighting {
+			fl := target.Lighting[face]
+			lighting = fmt.Sprintf("light %.2f ao %.2f", average(fl.Light), average(fl.AO))
+		}
+		fmt.Fprintf(&text, "%s: %s, %s\n", faceNames[face], neighbourType, lighting)
+	}
+
+	return text.String()
+}
+
+// Draw draws the overlay over the screen
+func (d *DebugOverlay) Draw() {
+	d.Context.Draw(d.Batch)
+}
+
+// Direção dominante da visão no plano horizontal, com os nomes das faces dos blocos
+func facing(view mgl32.Vec3) string {
+	if math.Abs(float64(view.X())) >= math.Abs(float64(view.Z())) {
+		if view.X() >= 0 {
+			return "north (+x)"
+		}
+		return "south (-x)"
+	}
+
+	if view.Z() >= 0 {
+		return "east (+z)"
+	}
+	return "west (-z)"
+}
+
+func average(values [4]float32) float32 {
+	return (values[0] + values[1] + values[2] + values[3]) / 4
+}
+
+// Resto da divisão sempre positivo, para coordenadas negativas
+func mod(a, b int) int {
+	return ((a % b) + b) % b
+}
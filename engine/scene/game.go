@@ -0,0 +1,298 @@
+package scene
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/camera"
+	"github.com/reonardoleis/fcg-glcraft/collisions"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/console"
+	"github.com/reonardoleis/fcg-glcraft/engine/controls"
+	"github.com/reonardoleis/fcg-glcraft/engine/metrics"
+	"github.com/reonardoleis/fcg-glcraft/engine/particles"
+	"github.com/reonardoleis/fcg-glcraft/engine/postprocess"
+	"github.com/reonardoleis/fcg-glcraft/engine/selection"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
+	"github.com/reonardoleis/fcg-glcraft/engine/shadows"
+	"github.com/reonardoleis/fcg-glcraft/engine/sky"
+	"github.com/reonardoleis/fcg-glcraft/geometry"
+	math2 "github.com/reonardoleis/fcg-glcraft/math"
+	"github.com/reonardoleis/fcg-glcraft/player"
+	"github.com/reonardoleis/fcg-glcraft/world"
+	"github.com/reonardoleis/fcg-glcraft/world/saves"
+)
+
+// GameScene simulates and draws a world, with its player, sky and effects
+type GameScene struct {
+	Layer
+	World          *world.World
+	MainCamera     *camera.Camera
+	Player         *player.Player
+	ControlHandler *controls.Controls
+	Objs           []*geometry.GeometryInformation
+	Shadows        *shadows.CascadedShadowMap // criado em OnEnter
+	Sky            *sky.Sky                   // criado em OnEnter
+	PostProcess    *postprocess.Pipeline      // nil desenha direto na tela
+	Particles      *particles.Renderer        // criado em OnEnter
+	Selection      *selection.Overlay         // criado em OnEnter
+	HUD            *HUD                       // desenhado sobre o jogo, se houver
+	Debug          *DebugOverlay              // alternado com F3
+	Commands       *console.Console           // comandos digitados no console
+	ambience       *particles.Emitter
+}
+
+func NewGameScene(world *world.World, mainCamera *camera.Camera, player *player.Player, controlHandler controls.Controls, objs []*geometry.GeometryInformation) *GameScene {
+	return &GameScene{
+		Layer:          Layer{Name: SceneGame},
+		World:          world,
+		MainCamera:     mainCamera,
+		Player:         player,
+		ControlHandler: &controlHandler,
+		Objs:           objs,
+		Commands:       console.NewConsole(console.Builtins()...),
+	}
+}
+
+// CommandSource is who runs the commands typed in the console: the player,
+// who is the operator of the worlds played alone
+func (s *GameScene) CommandSource() *console.Source {
+	return &console.Source{
+		Player:     s.Player,
+		Players:    []*player.Player{s.Player},
+		World:      s.World,
+		Permission: console.PermissionOperator,
+	}
+}
+
+// NewGame creates the game scene of a saved world. Nothing is created on the
+// GPU until the scene is pushed, so replays also use it without a window.
+func NewGame(info saves.WorldInfo, controlHandler controls.Controls) *GameScene {
+	configs.World = info.Config
+
+	mainCamera := camera.NewCamera(mgl32.Vec4{0.0, 0.0, 0.0, 1.0}, controlHandler, math.Pi/3, camera.FirstPersonCamera)
+	position := mgl32.Vec4{info.PlayerPosition[0], info.PlayerPosition[1], info.PlayerPosition[2], 1.0}
+	gamePlayer := player.NewPlayer(position, controlHandler, 5, 2.0, info.Config.JumpHeight, info.Config.JumpForce, 2)
+	gamePlayer.GameMode = player.GameMode(info.GameMode)
+	gamePlayer.BeFollowedByCamera(mainCamera)
+
+	gameWorld := world.NewWorld(info.Name, mgl32.Vec3(info.Config.Size), info.Seed, info.Generator)
+	gameWorld.Time = info.Time
+	gameWorld.GenerateWorld()
+
+	return NewGameScene(gameWorld, mainCamera, &gamePlayer, controlHandler, nil)
+}
+
+// Creates the GPU resources of the scene
+func (s *GameScene) OnEnter() {
+	s.Shadows = shadows.NewFromConfig()
+	s.Sky = sky.NewSky(shaders.Get("sky"), s.World.Seed)
+	s.Particles = particles.NewRenderer(shaders.Get("particle"))
+	s.Selection = selection.NewOverlay(shaders.Get("standard"), s.World.Seed)
+}
+
+// Frees what the scene created on the GPU and stops its world. Shared
+// resources, like the post-processing pipeline and the UI batch, are kept.
+func (s *GameScene) OnExit() {
+	s.Shadows.Destroy()
+	s.Sky.Destroy()
+	s.Particles.Destroy()
+	s.Selection.Destroy()
+	s.World.Destroy()
+}
+
+// Only the focused game reads the input and captures the cursor
+func (s *GameScene) SetFocused(focused bool) {
+	s.ControlHandler.SetEnabled(focused)
+	if focused {
+		s.ControlHandler.CaptureCursor(true)
+		// descarta o movimento do mouse feito enquanto o jogo estava coberto
+		s.ControlHandler.FinishMousePositionChanged()
+	}
+}
+
+// Updates the world, the player and the effects of the scene
+func (s *GameScene) Update(deltaTime float64) {
+	cx, cz := s.Player.GetChunkOffset().Elem()
+
+	s.Step(deltaTime)
+	s.Sky.Update(s.World.SunDirection(), deltaTime)
+
+	// handle wireframe mode
+	if s.ControlHandler.Toggled(controls.ActionWireframe) {
+		block.BlockEdgesOnly = true
+	} else {
+		block.BlockEdgesOnly = false
+	}
+
+	// keeps the ambient dust around the camera
+	eye := s.MainCamera.Position.Vec3()
+	if s.ambience == nil {
+		s.ambience = s.World.Particles.Emit(particles.Ambience(eye))
+	}
+	s.ambience.Position = eye
+	s.World.Particles.Update(float32(deltaTime))
+	metrics.Set(metrics.Particles, float64(s.World.Particles.Len()))
+
+	if s.HUD != nil {
+		s.HUD.Update(s.Player)
+	}
+	if s.Debug != nil && s.ControlHandler.Toggled(controls.ActionDebug) {
+		s.Debug.Update(s, deltaTime)
+	}
+
+	s.World.SwapPopulatedBlocks()
+	s.World.RequestPopulatedBlocks(cx, cz, int(configs.Video.ViewDistance))
+}
+
+// Step simulates the world and the player, without the effects that are only
+// drawn. It doesn't touch the GPU, so replays run it without a window.
+func (s *GameScene) Step(deltaTime float64) {
+	cx, cz := s.Player.GetChunkOffset().Elem()
+
+	currentChunk := s.World.Chunks[int(cx)][int(cz)]
+	if currentChunk == nil {
+		// o jogador foi para longe dos chunks carregados, e.g. por um teleporte
+		s.World.LoadChunksAround(int(cx), int(cz))
+		currentChunk = s.World.Chunks[int(cx)][int(cz)]
+		if currentChunk == nil {
+			return
+		}
+	}
+
+	s.World.AdvanceTime(deltaTime)
+
+	// verify if player changed chunk, or if more chunks are now in view
+	if currentChunk.ID != s.Player.LastChunk || s.World.ViewDistanceChanged() {
+		s.World.HandleChunkChange(int(currentChunk.Offset[0]), int(currentChunk.Offset[1]))
+		s.World.SetPopulatedBlocks(currentChunk.Offset[0], currentChunk.Offset[1], int(configs.Video.ViewDistance))
+	}
+
+	roundedPlayerX, roundedPlayerY, roundedPlayerZ := s.Player.GetRoundedPosition()
+
+	backOfPlayer, frontOfPlayer := s.Player.GetFrontAndBackDirections()
+
+	// animate the .obj objects near the player
+	for _, obj := range s.Objs {
+		if obj.Animating {
+			if obj.T >= 3 {
+				obj.Tdir = -1
+			}
+			if obj.T <= 0 {
+				if obj.BCurve.ControlPoints != nil {
+					c := obj.BCurve.T(obj.T)
+					obj.Position = mgl32.Vec3{obj.Position[0] + c.X(), obj.Position[1], obj.Position[2] + c.Z()}
+				}
+				obj.Tdir = 1
+				curve := math2.NewBezierCurve()
+				curve.GenerateRandomPoints()
+				obj.BCurve = curve
+			}
+
+			obj.T = obj.T + (obj.Tdir * 1 * float32(deltaTime))
+		}
+
+		sphereCollider := collisions.SphereCollider{
+			Center: obj.Position,
+			Radius: 5,
+		}
+
+		obj.Animating = sphereCollider.CollidesWith(s.Player.Position.Vec3())
+	}
+
+	s.World.Update(mgl32.Vec3{float32(roundedPlayerX), float32(roundedPlayerY), float32(roundedPlayerZ)}, backOfPlayer, frontOfPlayer, currentChunk)
+	s.Player.Update(s.World, currentChunk)
+
+	s.ControlHandler.FinishMousePositionChanged()
+
+	s.World.SwapFutureChunks()
+}
+
+// Draws the world and the overlays of the game on the screen
+func (s *GameScene) Render() {
+	s.renderShadows()
+
+	if s.PostProcess != nil {
+		s.PostProcess.Begin()
+	}
+
+	horizon := s.Sky.Palette.Horizon
+	gl.ClearColor(horizon.X(), horizon.Y(), horizon.Z(), 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	s.Sky.Render(s.MainCamera.Position.Vec3(), s.MainCamera.ViewMatrix(), s.MainCamera.ProjectionMatrix())
+
+	shaders.Get("standard").Use()
+	s.MainCamera.Handle()
+
+	// draw all .obj objects
+	for _, obj := range s.Objs {
+		if obj.Animating && obj.BCurve.ControlPoints != nil {
+			obj.DrawAt(nil, obj.BCurve.T(obj.T))
+		} else {
+			obj.Draw(nil)
+		}
+	}
+
+	s.World.Draw()
+	s.Player.Draw()
+	s.renderSelection()
+	s.renderParticles()
+
+	s.finishPostProcess()
+	geometry.DrawCrosshair()
+	if s.HUD != nil {
+		s.HUD.Draw()
+	}
+	if s.Debug != nil && s.ControlHandler.Toggled(controls.ActionDebug) {
+		s.Debug.Draw()
+	}
+}
+
+// Renders the sun shadow maps and binds them to the standard shader
+func (s *GameScene) renderShadows() {
+	sun := s.World.SunDirection()
+	s.Shadows.Update(s.MainCamera.ViewMatrix(), s.MainCamera.Fov, s.MainCamera.Aspect(), s.MainCamera.Near, sun)
+	s.Shadows.Render(shaders.Get("shadow"), s.World.DrawShadowCasters)
+	s.Shadows.Bind(shaders.Get("standard"), sun)
+}
+
+// Outlines the block the player is aiming at, with cracks while mining in survival
+func (s *GameScene) renderSelection() {
+	hitAt := s.Player.HitAt
+	if hitAt == nil {
+		return
+	}
+
+	progress := float32(0)
+	if s.Player.GameMode == player.Survival {
+		progress = s.Player.Mining.Progress
+	}
+	s.Selection.Draw(s.World.GetBlockAt(int(hitAt.X()), int(hitAt.Y()), int(hitAt.Z())), progress)
+}
+
+// Draws the world particles
+func (s *GameScene) renderParticles() {
+	eye := s.MainCamera.Position.Vec3()
+	s.Particles.Render(s.World.Particles.Particles(), eye, s.MainCamera.ViewMatrix(), s.MainCamera.ProjectionMatrix(), 1-0.75*s.Sky.Night)
+}
+
+// Runs the post-processing stages, with the fog of the horizon color and the
+// underwater settings when the camera is inside water
+func (s *GameScene) finishPostProcess() {
+	if s.PostProcess == nil {
+		return
+	}
+
+	eye := s.MainCamera.Position
+	x, y, z := int(math.Round(float64(eye.X()))), int(math.Round(float64(eye.Y()))), int(math.Round(float64(eye.Z())))
+	eyeBlock := s.World.GetBlockAt(x, y, z)
+
+	s.PostProcess.FogColor = s.Sky.Palette.Horizon
+	s.PostProcess.Underwater = eyeBlock != nil && eyeBlock.BlockType == block.BlockWater
+	s.PostProcess.Biome = postprocess.DefaultBiome
+	s.PostProcess.End(s.MainCamera.ProjectionMatrix())
+}
@@ -0,0 +1,108 @@
+package scene
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/ui"
+	"github.com/reonardoleis/fcg-glcraft/player"
+)
+
+const (
+	hotbarSlotSize = 48
+	hotbarPadding  = 4
+	heartSize      = 16
+)
+
+var (
+	heartColor      = mgl32.Vec4{0.85, 0.1, 0.1, 1}
+	halfHeartColor  = mgl32.Vec4{0.5, 0.06, 0.06, 1}
+	emptyHeartColor = mgl32.Vec4{0.2, 0.05, 0.05, 0.8}
+)
+
+// HUD is the interface drawn over the game: the hotbar with the block types
+// the player can place and the player health
+type HUD struct {
+	Context *ui.Context
+	Batch   *ui.Batch
+
+	blockTypes []block.BlockType
+	slots      []*ui.Panel
+	hearts     []*ui.Image
+	mode       *ui.Label
+}
+
+// NewHUD builds the widgets of the HUD. The block textures must already be loaded.
+func NewHUD(batch *ui.Batch, font *ui.Font, screen ui.Screen) *HUD {
+	h := &HUD{
+		Context:    ui.NewContext(font, screen),
+		Batch:      batch,
+		blockTypes: block.GetBlockTypes(),
+	}
+
+	slotCount := float32(len(h.blockTypes))
+	hotbarWidth := slotCount*(hotbarSlotSize+hotbarPadding) + hotbarPadding
+	hotbar := ui.NewPanel(ui.BottomCenter, mgl32.Vec2{0, -8}, mgl32.Vec2{hotbarWidth, hotbarSlotSize + 2*hotbarPadding}, ui.PanelColor)
+
+	for i, blockType := range h.blockTypes {
+		icon := &ui.Texture{ID: block.IconTexture(blockType), Kind: ui.TextureSRGB}
+		slot := ui.NewPanel(ui.CenterLeft, mgl32.Vec2{hotbarPadding + float32(i)*(hotbarSlotSize+hotbarPadding), 0}, mgl32.Vec2{hotbarSlotSize, hotbarSlotSize}, ui.ControlColor,
+			ui.NewImage(ui.Center, mgl32.Vec2{}, mgl32.Vec2{hotbarSlotSize - 12, hotbarSlotSize - 12}, icon, ui.White),
+			ui.NewLabel(ui.TopLeft, mgl32.Vec2{3, 2}, fmt.Sprint(i+1), 1),
+		)
+		h.slots = append(h.slots, slot)
+		hotbar.Add(slot)
+	}
+
+	heartCount := (configs.MaxHealth + 1) / 2
+	health := ui.NewPanel(ui.BottomCenter, mgl32.Vec2{0, -(hotbarSlotSize + 2*hotbarPadding + 14)}, mgl32.Vec2{hotbarWidth, heartSize}, mgl32.Vec4{})
+	for i := 0; i < heartCount; i++ {
+		heart := ui.NewImage(ui.CenterLeft, mgl32.Vec2{float32(i) * (heartSize + 2), 0}, mgl32.Vec2{heartSize, heartSize}, nil, heartColor)
+		h.hearts = append(h.hearts, heart)
+		health.Add(heart)
+	}
+
+	h.mode = ui.NewLabel(ui.CenterRight, mgl32.Vec2{}, "", 1)
+	health.Add(h.mode)
+
+	h.Context.Root.Add(hotbar, health)
+
+	return h
+}
+
+// Update shows the state of p: the selected block, its health and game mode
+func (h *HUD) Update(p *player.Player) {
+	for i, slot := range h.slots {
+		if h.blockTypes[i] == p.SelectedBlock {
+			slot.Border = ui.FocusColor
+		} else {
+			slot.Border = mgl32.Vec4{}
+		}
+	}
+
+	// cada coração vale 2 pontos de vida
+	for i, heart := range h.hearts {
+		points := p.Health - 2*i
+		switch {
+		case points >= 2:
+			heart.Color = heartColor
+		case points == 1:
+			heart.Color = halfHeartColor
+		default:
+			heart.Color = emptyHeartColor
+		}
+		heart.Hidden = p.GameMode == player.Creative
+	}
+
+	h.mode.Text = "Survival"
+	if p.GameMode == player.Creative {
+		h.mode.Text = "Creative"
+	}
+}
+
+// Draw draws the HUD over the screen
+func (h *HUD) Draw() {
+	h.Context.Draw(h.Batch)
+}
@@ -0,0 +1,55 @@
+package scene
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/engine/ui"
+	"github.com/reonardoleis/fcg-glcraft/player"
+)
+
+var gameModeNames = []string{"Survival", "Creative"}
+
+// PauseMenu is shown while a game scene is paused: it resumes the game, opens
+// the settings, quits to the title screen and changes the time of day and the
+// game mode
+type PauseMenu struct {
+	Context *ui.Context
+
+	game      *GameScene
+	timeOfDay *ui.Slider
+	gameModes *ui.List
+}
+
+func NewPauseMenu(font *ui.Font, screen ui.Screen, game *GameScene, onResume, onSettings, onQuit func()) *PauseMenu {
+	ctx := ui.NewContext(font, screen)
+
+	menu := ui.NewPanel(ui.Center, mgl32.Vec2{}, mgl32.Vec2{buttonWidth + 40, 470}, ui.PanelColor)
+	menu.Add(
+		ui.NewLabel(ui.TopCenter, mgl32.Vec2{0, 16}, "Jogo pausado", 3),
+		ui.NewButton(ui.TopCenter, mgl32.Vec2{0, 70}, mgl32.Vec2{buttonWidth, buttonHeight}, "Voltar ao jogo", onResume),
+		ui.NewButton(ui.TopCenter, mgl32.Vec2{0, 120}, mgl32.Vec2{buttonWidth, buttonHeight}, "Configurações", onSettings),
+	)
+
+	timeOfDay := ui.NewSlider(ui.TopCenter, mgl32.Vec2{0, 190}, mgl32.Vec2{buttonWidth, buttonHeight}, "Hora do dia", 0, 1, float32(game.World.TimeOfDay()), func(value float32) {
+		game.World.SetTimeOfDay(float64(value))
+	})
+
+	gameModes := ui.NewList(ui.TopCenter, mgl32.Vec2{0, 240}, mgl32.Vec2{buttonWidth, 80}, gameModeNames, func(index int) {
+		game.Player.GameMode = player.GameMode(index)
+	})
+
+	menu.Add(timeOfDay, gameModes,
+		ui.NewButton(ui.BottomCenter, mgl32.Vec2{0, -20}, mgl32.Vec2{buttonWidth, buttonHeight}, "Salvar e sair", onQuit),
+	)
+	ctx.Root.Add(menu)
+
+	m := &PauseMenu{Context: ctx, game: game, timeOfDay: timeOfDay, gameModes: gameModes}
+	m.Refresh()
+
+	return m
+}
+
+// Refresh shows the current state of the game, which changes while the menu is closed
+func (m *PauseMenu) Refresh() {
+	m.timeOfDay.Value = float32(m.game.World.TimeOfDay())
+	m.gameModes.Selected = int(m.game.Player.GameMode)
+}
@@ -0,0 +1,618 @@
+package scene
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/controls"
+	"github.com/reonardoleis/fcg-glcraft/engine/settings"
+	"github.com/reonardoleis/fcg-glcraft/engine/ui"
+	"github.com/reonardoleis/fcg-glcraft/engine/window"
+	"github.com/reonardoleis/fcg-glcraft/world"
+	"github.com/reonardoleis/fcg-glcraft/world/chunk"
+	"github.com/reonardoleis/fcg-glcraft/world/saves"
+)
+
+// Nomes das cenas no SceneManager
+const (
+	SceneTitle       = "title"
+	SceneWorlds      = "worlds"
+	SceneCreateWorld = "create_world"
+	SceneSettings    = "settings"
+	SceneControls    = "controls"
+	ScenePause       = "pause"
+	SceneConsole     = "console"
+	SceneGame        = "game"
+)
+
+const buttonWidth, buttonHeight = 320, 40
+
+// Onde um mundo novo coloca o jogador, que cai até o chão
+var spawnPosition = [3]float32{0, 128, 0}
+
+// Menus builds the menu screens and moves between them and the game: the
+// title screen, the world list, the world creation, the settings and the
+// pause menu. The screens are pushed over each other in the SceneManager, and
+// going back pops them.
+type Menus struct {
+	Manager      *SceneManager
+	Window       *window.Window
+	Controls     controls.Controls
+	Font         *ui.Font
+	Batch        *ui.Batch
+	Screen       ui.Screen
+	SavesDir     string
+	Settings     *settings.Settings
+	SettingsPath string
+	WorldConfig  configs.WorldConfig // parâmetros dos mundos criados
+	// NewGame creates the game scene of a world
+	NewGame func(info saves.WorldInfo) *GameScene
+
+	screens     map[string]*UIScene
+	game        *GameScene
+	gameInfo    saves.WorldInfo
+	worlds      []saves.WorldInfo
+	worldList   *ui.List
+	worldStatus *ui.Label
+	console     *ConsoleScreen
+}
+
+// Start builds the menu screens and shows the title screen
+func (m *Menus) Start() {
+	m.screens = map[string]*UIScene{}
+	m.addScreen(SceneTitle, m.titleScreen(), nil).Back = m.quit
+	m.addScreen(SceneWorlds, m.worldsScreen(), m.refreshWorlds)
+	create, resetCreate := m.createWorldScreen()
+	m.addScreen(SceneCreateWorld, create, resetCreate)
+	settingsScreen, closeSettings := m.settingsScreen()
+	m.addScreen(SceneSettings, settingsScreen, nil).Back = closeSettings
+	controlsScreen, refreshControls, closeControls := m.controlsScreen()
+	m.addScreen(SceneControls, controlsScreen, refreshControls).Back = closeControls
+	m.applySettings()
+
+	m.Manager.Push(m.screens[SceneTitle])
+}
+
+// Creates a scene showing ctx, refreshed each time it is pushed
+func (m *Menus) addScreen(name string, ctx *ui.Context, refresh func()) *UIScene {
+	screen := NewUIScene(name, m.Controls, ctx, m.Batch)
+	screen.Refresh = refresh
+	m.screens[name] = screen
+
+	return screen
+}
+
+// Opens the screen called name over the current one
+func (m *Menus) show(name string) {
+	m.Manager.Push(m.screens[name])
+}
+
+func (m *Menus) titleScreen() *ui.Context {
+	ctx := ui.NewContext(m.Font, m.Screen)
+
+	ctx.Root.Add(
+		ui.NewLabel(ui.TopCenter, mgl32.Vec2{0, m.Screen.Height / 5}, "fcg-glcraft", 6),
+		ui.NewButton(ui.Center, mgl32.Vec2{0, 0}, mgl32.Vec2{buttonWidth, buttonHeight}, "Jogar", func() {
+			m.show(SceneWorlds)
+		}),
+		ui.NewButton(ui.Center, mgl32.Vec2{0, 50}, mgl32.Vec2{buttonWidth, buttonHeight}, "Configurações", func() {
+			m.show(SceneSettings)
+		}),
+		ui.NewButton(ui.Center, mgl32.Vec2{0, 100}, mgl32.Vec2{buttonWidth, buttonHeight}, "Sair", m.quit),
+	)
+
+	return ctx
+}
+
+// Fecha o jogo, só pela tela de título
+func (m *Menus) quit() {
+	m.Window.SetShouldClose(true)
+}
+
+func (m *Menus) worldsScreen() *ui.Context {
+	ctx := ui.NewContext(m.Font, m.Screen)
+
+	m.worldList = ui.NewList(ui.Center, mgl32.Vec2{0, -40}, mgl32.Vec2{2*buttonWidth + 10, 300}, nil, nil)
+	m.worldStatus = ui.NewLabel(ui.Center, mgl32.Vec2{0, 130}, "", 1)
+	m.worldStatus.Color = ui.MutedTextColor
+
+	ctx.Root.Add(
+		ui.NewLabel(ui.TopCenter, mgl32.Vec2{0, 40}, "Mundos", 3),
+		m.worldList,
+		m.worldStatus,
+		ui.NewButton(ui.Center, mgl32.Vec2{-(buttonWidth + 10) / 2, 170}, mgl32.Vec2{buttonWidth, buttonHeight}, "Jogar", func() {
+			if selected := m.selectedWorld(); selected != nil {
+				m.Play(*selected)
+			}
+		}),
+		ui.NewButton(ui.Center, mgl32.Vec2{(buttonWidth + 10) / 2, 170}, mgl32.Vec2{buttonWidth, buttonHeight}, "Criar mundo", func() {
+			m.show(SceneCreateWorld)
+		}),
+		ui.NewButton(ui.Center, mgl32.Vec2{-(buttonWidth + 10) / 2, 220}, mgl32.Vec2{buttonWidth, buttonHeight}, "Excluir", func() {
+			if selected := m.selectedWorld(); selected != nil {
+				if err := saves.Delete(m.SavesDir, selected.Name); err != nil {
+					m.worldStatus.Text = err.Error()
+					return
+				}
+				m.refreshWorlds()
+			}
+		}),
+		ui.NewButton(ui.Center, mgl32.Vec2{(buttonWidth + 10) / 2, 220}, mgl32.Vec2{buttonWidth, buttonHeight}, "Voltar", m.Manager.Pop),
+	)
+
+	return ctx
+}
+
+// Lê de novo os mundos salvos para a lista
+func (m *Menus) refreshWorlds() {
+	worlds, err := saves.List(m.SavesDir)
+	m.worlds = worlds
+	m.worldStatus.Text = ""
+	if err != nil {
+		m.worldStatus.Text = err.Error()
+	} else if len(worlds) == 0 {
+		m.worldStatus.Text = "Nenhum mundo salvo"
+	}
+
+	m.worldList.Items = m.worldList.Items[:0]
+	for _, info := range worlds {
+		m.worldList.Items = append(m.worldList.Items, fmt.Sprintf("%s (%s, %s)", info.Name, info.Generator, info.LastPlayed.Format("02/01/2006 15:04")))
+	}
+	m.worldList.Selected = -1
+	if len(worlds) > 0 {
+		m.worldList.Selected = 0
+	}
+}
+
+func (m *Menus) selectedWorld() *saves.WorldInfo {
+	if m.worldList.Selected < 0 || m.worldList.Selected >= len(m.worlds) {
+		return nil
+	}
+
+	return &m.worlds[m.worldList.Selected]
+}
+
+func (m *Menus) createWorldScreen() (*ui.Context, func()) {
+	ctx := ui.NewContext(m.Font, m.Screen)
+
+	name := ui.NewTextField(ui.Center, mgl32.Vec2{0, -110}, mgl32.Vec2{buttonWidth, buttonHeight}, "Nome do mundo", nil)
+	seed := ui.NewTextField(ui.Center, mgl32.Vec2{0, -60}, mgl32.Vec2{buttonWidth, buttonHeight}, "Semente (aleatória se vazia)", nil)
+	seed.MaxLength = 64
+	generator := ui.NewList(ui.Center, mgl32.Vec2{0, 10}, mgl32.Vec2{buttonWidth, 80}, chunk.Generators, nil)
+	status := ui.NewLabel(ui.Center, mgl32.Vec2{0, 70}, "", 1)
+	status.Color = ui.MutedTextColor
+
+	create := func(string) {
+		worldName := strings.TrimSpace(name.Text)
+		if saves.Exists(m.SavesDir, worldName) {
+			status.Text = "Já existe um mundo com esse nome"
+			return
+		}
+
+		now := time.Now()
+		info := NewWorldInfo(worldName, ParseSeed(seed.Text, now), chunk.Generators[generator.Selected], m.WorldConfig, now)
+		if err := saves.Save(m.SavesDir, info); err != nil {
+			if errors.Is(err, saves.ErrInvalidName) {
+				status.Text = "Nome inválido"
+			} else {
+				status.Text = err.Error()
+			}
+			return
+		}
+
+		m.Play(info)
+	}
+	name.OnSubmit = create
+	seed.OnSubmit = create
+
+	ctx.Root.Add(
+		ui.NewLabel(ui.TopCenter, mgl32.Vec2{0, 40}, "Novo mundo", 3),
+		name, seed, generator, status,
+		ui.NewButton(ui.Center, mgl32.Vec2{0, 120}, mgl32.Vec2{buttonWidth, buttonHeight}, "Criar", func() { create("") }),
+		ui.NewButton(ui.Center, mgl32.Vec2{0, 170}, mgl32.Vec2{buttonWidth, buttonHeight}, "Cancelar", m.Manager.Pop),
+	)
+
+	reset := func() {
+		name.Text, seed.Text, status.Text = "", "", ""
+		generator.Selected = 0
+		ctx.Focus(name)
+	}
+
+	return ctx, reset
+}
+
+// NewWorldInfo describes a world that was never played, with the player at the
+// spawn in the morning
+func NewWorldInfo(name string, seed int64, generator string, config configs.WorldConfig, now time.Time) saves.WorldInfo {
+	return saves.WorldInfo{
+		Name:           name,
+		Seed:           seed,
+		Generator:      generator,
+		Config:         config,
+		Time:           world.StartTimeOfDay * world.DayLength,
+		PlayerPosition: spawnPosition,
+		Created:        now,
+	}
+}
+
+// ParseSeed turns the seed typed by the player into a number. Text that is not
+// a number is hashed, and an empty seed is random.
+func ParseSeed(text string, now time.Time) int64 {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return now.UnixNano()
+	}
+	if seed, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return seed
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(text))
+	return int64(hash.Sum64())
+}
+
+func (m *Menus) settingsScreen() (*ui.Context, func()) {
+	ctx := ui.NewContext(m.Font, m.Screen)
+
+	fov := ui.NewSlider(ui.Center, mgl32.Vec2{0, -150}, mgl32.Vec2{buttonWidth, buttonHeight}, "", 30, 110, m.Settings.FieldOfView, nil)
+	fov.Step = 1
+	fov.OnChange = func(value float32) {
+		m.Settings.FieldOfView = value
+		fov.Label = fmt.Sprintf("FOV: %.0f", value)
+		m.applySettings()
+	}
+	fov.Label = fmt.Sprintf("FOV: %.0f", fov.Value)
+
+	// o terreno simplificado começa onde os chunks completos terminam
+	maxViewDistance := float32(configs.MaxViewDistance)
+	if lod := float32(m.Settings.Video.LODViewDistance); lod < maxViewDistance {
+		maxViewDistance = lod
+	}
+	viewDistance := ui.NewSlider(ui.Center, mgl32.Vec2{0, -200}, mgl32.Vec2{buttonWidth, buttonHeight}, "", 1, maxViewDistance, m.Settings.Video.ViewDistance, nil)
+	viewDistance.Step = 1
+	viewDistance.OnChange = func(value float32) {
+		m.Settings.Video.ViewDistance = value
+		viewDistance.Label = fmt.Sprintf("Distância de visão: %.0f", value)
+		m.applySettings()
+	}
+	viewDistance.Label = fmt.Sprintf("Distância de visão: %.0f", viewDistance.Value)
+
+	sensitivity := ui.NewSlider(ui.Center, mgl32.Vec2{0, -100}, mgl32.Vec2{buttonWidth, buttonHeight}, "", 0.1, 3, float32(m.Settings.MouseSensitivity*100), nil)
+	sensitivity.Step = 0.1
+	sensitivity.OnChange = func(value float32) {
+		m.Settings.MouseSensitivity = float64(value) / 100
+		sensitivity.Label = fmt.Sprintf("Sensibilidade: %.1f", value)
+		m.applySettings()
+	}
+	sensitivity.Label = fmt.Sprintf("Sensibilidade: %.1f", sensitivity.Value)
+
+	smoothing := ui.NewSlider(ui.Center, mgl32.Vec2{0, -50}, mgl32.Vec2{buttonWidth, buttonHeight}, "", 0, 0.95, float32(m.Settings.MouseSmoothing), nil)
+	smoothing.Step = 0.05
+	smoothing.OnChange = func(value float32) {
+		m.Settings.MouseSmoothing = float64(value)
+		smoothing.Label = fmt.Sprintf("Suavização: %.0f%%", value*100)
+		m.applySettings()
+	}
+	smoothing.Label = fmt.Sprintf("Suavização: %.0f%%", smoothing.Value*100)
+
+	invertY := ui.NewButton(ui.Center, mgl32.Vec2{0, 0}, mgl32.Vec2{buttonWidth, buttonHeight}, "", nil)
+	invertY.OnClick = func() {
+		m.Settings.InvertMouseY = !m.Settings.InvertMouseY
+		invertY.Text = "Inverter eixo Y: " + onOff(m.Settings.InvertMouseY)
+		m.applySettings()
+	}
+	invertY.Text = "Inverter eixo Y: " + onOff(m.Settings.InvertMouseY)
+
+	rawMotion := ui.NewButton(ui.Center, mgl32.Vec2{0, 50}, mgl32.Vec2{buttonWidth, buttonHeight}, "", nil)
+	rawMotion.OnClick = func() {
+		m.Settings.RawMouseMotion = !m.Settings.RawMouseMotion
+		rawMotion.Text = "Movimento bruto: " + onOff(m.Settings.RawMouseMotion)
+		m.applySettings()
+	}
+	rawMotion.Text = "Movimento bruto: " + onOff(m.Settings.RawMouseMotion)
+
+	// tela cheia e vsync dividem uma linha
+	halfWidth := float32(buttonWidth-10) / 2
+	fullscreen := ui.NewButton(ui.Center, mgl32.Vec2{-(halfWidth + 10) / 2, 100}, mgl32.Vec2{halfWidth, buttonHeight}, "", nil)
+	fullscreen.OnClick = func() {
+		m.Settings.Video.Fullscreen = !m.Settings.Video.Fullscreen
+		fullscreen.Text = "Tela cheia: " + onOff(m.Settings.Video.Fullscreen)
+		m.applySettings()
+	}
+	fullscreen.Text = "Tela cheia: " + onOff(m.Settings.Video.Fullscreen)
+
+	vsync := ui.NewButton(ui.Center, mgl32.Vec2{(halfWidth + 10) / 2, 100}, mgl32.Vec2{halfWidth, buttonHeight}, "", nil)
+	vsync.OnClick = func() {
+		m.Settings.Video.VSync = !m.Settings.Video.VSync
+		vsync.Text = "VSync: " + onOff(m.Settings.Video.VSync)
+		m.applySettings()
+	}
+	vsync.Text = "VSync: " + onOff(m.Settings.Video.VSync)
+
+	back := func() {
+		if err := m.Settings.Save(m.SettingsPath); err != nil {
+			log.Println(err)
+		}
+		m.Manager.Pop()
+	}
+
+	ctx.Root.Add(
+		ui.NewLabel(ui.TopCenter, mgl32.Vec2{0, 40}, "Configurações", 3),
+		viewDistance, fov, sensitivity, smoothing, invertY, rawMotion, fullscreen, vsync,
+		ui.NewButton(ui.Center, mgl32.Vec2{0, 160}, mgl32.Vec2{buttonWidth, buttonHeight}, "Controles", func() {
+			m.show(SceneControls)
+		}),
+		ui.NewButton(ui.Center, mgl32.Vec2{0, 210}, mgl32.Vec2{buttonWidth, buttonHeight}, "Voltar", back),
+	)
+
+	return ctx, back
+}
+
+func onOff(on bool) string {
+	if on {
+		return "Sim"
+	}
+	return "Não"
+}
+
+// Nomes das ações na tela de controles
+var actionNames = map[controls.Action]string{
+	controls.ActionMoveForward:    "Andar para frente",
+	controls.ActionMoveBack:       "Andar para trás",
+	controls.ActionMoveLeft:       "Andar para a esquerda",
+	controls.ActionMoveRight:      "Andar para a direita",
+	controls.ActionJump:           "Pular",
+	controls.ActionSprint:         "Correr",
+	controls.ActionBreak:          "Quebrar bloco",
+	controls.ActionPlace:          "Colocar bloco",
+	controls.ActionToggleView:     "Terceira pessoa",
+	controls.ActionToggleGameMode: "Alternar modo de jogo",
+	controls.ActionWireframe:      "Modo wireframe",
+	controls.ActionDebug:          "Informações de depuração",
+	controls.ActionPause:          "Pausar",
+	controls.ActionBack:           "Voltar",
+	controls.ActionFullscreen:     "Tela cheia",
+	controls.ActionChat:           "Abrir o chat",
+	controls.ActionCommand:        "Digitar um comando",
+	controls.ActionHotbar1:        "Bloco 1",
+	controls.ActionHotbar2:        "Bloco 2",
+	controls.ActionHotbar3:        "Bloco 3",
+	controls.ActionHotbar4:        "Bloco 4",
+	controls.ActionHotbar5:        "Bloco 5",
+	controls.ActionHotbar6:        "Bloco 6",
+	controls.ActionHotbar7:        "Bloco 7",
+	controls.ActionHotbar8:        "Bloco 8",
+}
+
+// Lists the actions with their bindings. Changing one waits for the next
+// chord pressed, which replaces the bindings of the action; the back action
+// cancels it. A chord already used by other actions is kept, with a warning.
+func (m *Menus) controlsScreen() (*ui.Context, func(), func()) {
+	ctx := ui.NewContext(m.Font, m.Screen)
+
+	list := ui.NewList(ui.Center, mgl32.Vec2{0, -40}, mgl32.Vec2{2*buttonWidth + 10, 360}, nil, nil)
+	status := ui.NewLabel(ui.Center, mgl32.Vec2{0, 160}, "", 1)
+	status.Color = ui.MutedTextColor
+
+	refresh := func() {
+		list.Items = list.Items[:0]
+		for _, action := range controls.Actions {
+			chords := make([]string, len(m.Settings.Bindings[action]))
+			for i, chord := range m.Settings.Bindings[action] {
+				chords[i] = chord.String()
+			}
+			list.Items = append(list.Items, fmt.Sprintf("%s: %s", actionNames[action], strings.Join(chords, ", ")))
+		}
+		if list.Selected < 0 {
+			list.Selected = 0
+		}
+	}
+
+	rebind := func() {
+		if list.Selected < 0 || list.Selected >= len(controls.Actions) {
+			return
+		}
+
+		action := controls.Actions[list.Selected]
+		status.Text = fmt.Sprintf("Pressione a nova tecla para \"%s\"", actionNames[action])
+		controls.DefaultActions.CaptureChord(func(chord controls.Chord) {
+			status.Text = ""
+			if chord == nil {
+				return
+			}
+
+			if conflicts := m.Settings.Bindings.Conflicts(action, chord); len(conflicts) > 0 {
+				names := make([]string, len(conflicts))
+				for i, conflict := range conflicts {
+					names[i] = actionNames[conflict]
+				}
+				status.Text = fmt.Sprintf("%s também é usado por: %s", chord, strings.Join(names, ", "))
+			}
+			m.Settings.Bindings[action] = []controls.Chord{chord}
+			m.applySettings()
+			refresh()
+		})
+	}
+
+	back := func() {
+		controls.DefaultActions.CancelCapture()
+		if err := m.Settings.Save(m.SettingsPath); err != nil {
+			log.Println(err)
+		}
+		m.Manager.Pop()
+	}
+
+	ctx.Root.Add(
+		ui.NewLabel(ui.TopCenter, mgl32.Vec2{0, 40}, "Controles", 3),
+		list, status,
+		ui.NewButton(ui.Center, mgl32.Vec2{-(buttonWidth + 10) / 2, 200}, mgl32.Vec2{buttonWidth, buttonHeight}, "Alterar", rebind),
+		ui.NewButton(ui.Center, mgl32.Vec2{(buttonWidth + 10) / 2, 200}, mgl32.Vec2{buttonWidth, buttonHeight}, "Restaurar padrão", func() {
+			controls.DefaultActions.CancelCapture()
+			m.Settings.Bindings = controls.DefaultBindings()
+			status.Text = ""
+			m.applySettings()
+			refresh()
+		}),
+		ui.NewButton(ui.Center, mgl32.Vec2{0, 250}, mgl32.Vec2{buttonWidth, buttonHeight}, "Voltar", back),
+	)
+
+	reset := func() {
+		status.Text = ""
+		refresh()
+	}
+
+	return ctx, reset, back
+}
+
+// Aplica as configurações ao jogo em andamento
+func (m *Menus) applySettings() {
+	controls.MouseLook = m.Settings.MouseLook()
+	controls.DefaultActions.SetBindings(m.Settings.Bindings)
+	configs.Video = m.Settings.Video
+	if m.Window != nil {
+		m.Window.SetFullscreen(m.Settings.Video.Fullscreen)
+		m.Window.SetVSync(m.Settings.Video.VSync)
+	}
+	if m.game != nil {
+		m.game.MainCamera.Fov = mgl32.DegToRad(m.Settings.FieldOfView)
+		m.game.MainCamera.Far = float32(configs.Video.LODViewDistance * configs.ChunkSize)
+	}
+}
+
+// ToggleFullscreen switches the window between fullscreen and windowed and
+// saves the choice in the settings
+func (m *Menus) ToggleFullscreen() {
+	m.Settings.Video.Fullscreen = !m.Settings.Video.Fullscreen
+	m.applySettings()
+	if err := m.Settings.Save(m.SettingsPath); err != nil {
+		log.Println(err)
+	}
+}
+
+// Resize lays the menus out again in screen, after the window changes
+func (m *Menus) Resize(screen ui.Screen) {
+	m.Screen = screen
+	for _, scene := range m.screens {
+		scene.Context.Resize(screen)
+	}
+}
+
+// Play starts the game in a saved world, closing the menus
+func (m *Menus) Play(info saves.WorldInfo) {
+	m.Manager.Clear()
+
+	info.LastPlayed = time.Now()
+	m.gameInfo = info
+	m.game = m.NewGame(info)
+	m.game.Player.Name = m.Settings.PlayerName
+	m.applySettings()
+
+	// a pausa congela o jogo, mas o deixa visível por trás do menu
+	pause := NewPauseMenu(m.Font, m.Screen, m.game,
+		m.Manager.Pop,
+		func() { m.show(SceneSettings) },
+		m.QuitToTitle,
+	)
+	pause.Context.Root.Color = mgl32.Vec4{0, 0, 0, 0.4}
+	m.addScreen(ScenePause, pause.Context, pause.Refresh).RenderThrough = true
+
+	// o console é aberto por OpenConsole, que o preenche
+	m.console = NewConsoleScreen(m.Font, m.Screen, m.game, m.Manager.Pop)
+	m.addScreen(SceneConsole, m.console.Context, nil).RenderThrough = true
+
+	m.Manager.Push(m.game)
+	m.SaveGame()
+}
+
+// Game returns the game being played, or nil in the menus
+func (m *Menus) Game() *GameScene {
+	return m.game
+}
+
+// GameInfo returns the world of the game being played, as it was when it started
+func (m *Menus) GameInfo() saves.WorldInfo {
+	return m.gameInfo
+}
+
+// TogglePause pauses the game, or resumes it from the pause menu
+func (m *Menus) TogglePause() {
+	top := m.Manager.Top()
+	if top == nil {
+		return
+	}
+
+	switch top.GetLayer().Name {
+	case SceneGame:
+		m.show(ScenePause)
+	case ScenePause:
+		m.Manager.Pop()
+	}
+}
+
+// Back handles the back action on the scene on top: the game opens the pause
+// menu, the title screen quits and the other screens close, as their "Voltar"
+// button does. A rebinding in progress is cancelled by the ActionMap first.
+func (m *Menus) Back() {
+	top := m.Manager.Top()
+	if top == nil {
+		return
+	}
+
+	if top.GetLayer().Name == SceneGame {
+		m.show(ScenePause)
+		return
+	}
+	if screen, ok := top.(*UIScene); ok && screen.Back != nil {
+		screen.Back()
+		return
+	}
+	m.Manager.Pop()
+}
+
+// OpenConsole shows the console over the game, starting with text, e.g. "/"
+// to type a command. It does nothing if the game isn't on top.
+func (m *Menus) OpenConsole(text string) {
+	top := m.Manager.Top()
+	if top == nil || top.GetLayer().Name != SceneGame {
+		return
+	}
+
+	m.console.Refresh(text)
+	m.show(SceneConsole)
+}
+
+// SaveGame writes the state of the current game to its world folder
+func (m *Menus) SaveGame() {
+	if m.game == nil {
+		return
+	}
+
+	position := m.game.Player.Position
+	m.gameInfo.PlayerPosition = [3]float32{position.X(), position.Y(), position.Z()}
+	m.gameInfo.Time = m.game.World.Time
+	m.gameInfo.GameMode = int(m.game.Player.GameMode)
+	m.gameInfo.LastPlayed = time.Now()
+
+	if err := saves.Save(m.SavesDir, m.gameInfo); err != nil {
+		log.Println(err)
+	}
+}
+
+// QuitToTitle saves and closes the game, which frees it when popped, and shows
+// the title screen
+func (m *Menus) QuitToTitle() {
+	m.SaveGame()
+	m.Manager.Clear()
+	m.Manager.Push(m.screens[SceneTitle])
+	m.game = nil
+	m.console = nil
+	delete(m.screens, ScenePause)
+	delete(m.screens, SceneConsole)
+}
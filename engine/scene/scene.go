@@ -1,177 +1,166 @@
 package scene
 
-import (
-	"fmt"
-
-	"github.com/go-gl/gl/v3.3-core/gl"
-
-	"github.com/go-gl/glfw/v3.3/glfw"
-	"github.com/go-gl/mathgl/mgl32"
-	"github.com/reonardoleis/fcg-glcraft/block"
-	"github.com/reonardoleis/fcg-glcraft/camera"
-	"github.com/reonardoleis/fcg-glcraft/collisions"
-	"github.com/reonardoleis/fcg-glcraft/engine/controls"
-	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
-	"github.com/reonardoleis/fcg-glcraft/geometry"
-	math2 "github.com/reonardoleis/fcg-glcraft/math"
-	"github.com/reonardoleis/fcg-glcraft/player"
-	"github.com/reonardoleis/fcg-glcraft/world"
-	"github.com/reonardoleis/fcg-glcraft/world/chunk"
-)
-
-type SceneType = uint
-
-const (
-	GameScene = iota
-	UIScene
-)
-
-type SceneManager struct {
-	Scenes      []*Scene
-	ActiveScene int
-}
-
-func NewSceneManager() SceneManager {
-	return SceneManager{
-		Scenes:      []*Scene{},
-		ActiveScene: -1,
-	}
+// Scene is one screen of the game. Scenes are stacked in a SceneManager: the
+// scene on top receives the input, and the scenes below it are updated and
+// drawn only while every scene above lets them through.
+type Scene interface {
+	GetLayer() *Layer
+	// OnEnter is called when the scene is pushed, before its first update
+	OnEnter()
+	// OnExit is called when the scene is popped, to free what it created
+	OnExit()
+	// SetFocused tells if the scene is on top of the stack, receiving the input
+	SetFocused(focused bool)
+	// Update simulates the scene by deltaTime seconds
+	Update(deltaTime float64)
+	// Render draws the scene, over the scenes below it if they are drawn
+	Render()
 }
 
-func (sm *SceneManager) AddScene(scene *Scene) {
-	sm.Scenes = append(sm.Scenes, scene)
+// Layer holds what the SceneManager needs to know about every scene
+type Layer struct {
+	Name          string
+	UpdateThrough bool // as cenas abaixo continuam sendo simuladas
+	RenderThrough bool // as cenas abaixo são desenhadas antes desta
 }
 
-func (sm *SceneManager) SetActiveScene(sceneIndex int) {
-	sm.ActiveScene = sceneIndex
+func (l *Layer) GetLayer() *Layer {
+	return l
 }
 
-func (sm *SceneManager) HandleActiveScene(window glfw.Window) {
-	sm.Scenes[sm.ActiveScene].Update(window)
+// SceneManager keeps the scenes in a stack. Changes to the stack made while
+// the scenes are updated (e.g. a button that opens a menu) are applied after
+// the update, so a scene is never popped in the middle of its own Update.
+type SceneManager struct {
+	stack   []Scene
+	pending []func()
+	busy    bool
 }
 
-type Scene struct {
-	World          *world.World
-	MainCamera     *camera.Camera
-	Type           SceneType
-	Player         *player.Player
-	ControlHandler *controls.Controls
-	Objs           []*geometry.GeometryInformation
+func NewSceneManager() SceneManager {
+	return SceneManager{}
 }
 
-func NewScene(world *world.World, mainCamera *camera.Camera, player *player.Player, controlHandler controls.Controls, sceneType SceneType, objs []*geometry.GeometryInformation) *Scene {
-	return &Scene{
-		World:          world,
-		MainCamera:     mainCamera,
-		Player:         player,
-		ControlHandler: &controlHandler,
-		Type:           sceneType,
-		Objs:           objs,
+// Top returns the scene on top of the stack, or nil if it is empty
+func (sm *SceneManager) Top() Scene {
+	if len(sm.stack) == 0 {
+		return nil
 	}
+
+	return sm.stack[len(sm.stack)-1]
 }
 
-// Updates the scene each frame, will handle world and player updates
-func (s *Scene) Update(window glfw.Window) {
+// Len returns how many scenes are stacked
+func (sm *SceneManager) Len() int {
+	return len(sm.stack)
+}
 
-	cx, cz := s.Player.GetChunkOffset().Elem()
+// Find returns the stacked scene called name, or nil
+func (sm *SceneManager) Find(name string) Scene {
+	for _, scene := range sm.stack {
+		if scene.GetLayer().Name == name {
+			return scene
+		}
+	}
 
-	currentChunk := s.World.Chunks[int(cx)][int(cz)]
+	return nil
+}
 
-	if currentChunk.GetBlockInformationAt(int(s.Player.Position.X()), int(s.Player.Position.Y()), int(s.Player.Position.Z())) == chunk.BlockInformationCave {
-		gl.ClearColor(0.47, 0.65, 1.0, 1.0)
-	} else {
-		gl.ClearColor(0.47, 0.65, 1.0, 1.0)
-	}
+// Push puts scene on top of the stack, over the current scene
+func (sm *SceneManager) Push(scene Scene) {
+	sm.apply(func() {
+		if top := sm.Top(); top != nil {
+			top.SetFocused(false)
+		}
 
-	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+		sm.stack = append(sm.stack, scene)
+		scene.OnEnter()
+		scene.SetFocused(true)
+	})
+}
 
-	gl.UseProgram(shaders.ShaderProgramCrosshair)
+// Pop removes the scene on top of the stack, giving the focus back to the one below
+func (sm *SceneManager) Pop() {
+	sm.apply(func() {
+		sm.pop()
+		if top := sm.Top(); top != nil {
+			top.SetFocused(true)
+		}
+	})
+}
 
-	geometry.DrawCrosshair()
+// Replace swaps the scene on top of the stack for scene. The scene below
+// doesn't get the focus in between.
+func (sm *SceneManager) Replace(scene Scene) {
+	sm.apply(func() {
+		sm.pop()
+		sm.stack = append(sm.stack, scene)
+		scene.OnEnter()
+		scene.SetFocused(true)
+	})
+}
 
-	gl.UseProgram(shaders.ShaderProgramDefault)
+// Clear pops every scene, from the top down
+func (sm *SceneManager) Clear() {
+	sm.apply(func() {
+		for len(sm.stack) > 0 {
+			sm.pop()
+		}
+	})
+}
 
-	// verify if player changed chunk
-	if currentChunk.ID != s.Player.LastChunk {
-		s.World.FutureChunks = s.World.Chunks
-		s.World.HandleChunkChange(int(currentChunk.Offset[0]), int(currentChunk.Offset[1]))
-		s.World.SetPopulatedBlocks(currentChunk.Offset[0], currentChunk.Offset[1])
+func (sm *SceneManager) pop() {
+	top := sm.Top()
+	if top == nil {
+		return
 	}
 
-	roundedPlayerX, roundedPlayerY, roundedPlayerZ := s.Player.GetRoundedPosition()
-	realPlayerX, realPlayerY, realPlayerZ := s.Player.GetRealPosition()
-	//playerY := float64(s.Player.Position.Y())
+	top.SetFocused(false)
+	sm.stack[len(sm.stack)-1] = nil
+	sm.stack = sm.stack[:len(sm.stack)-1]
+	top.OnExit()
+}
 
-	// handle wireframe mode
-	if s.ControlHandler.IsToggled(int(glfw.KeyZ)) {
-		block.BlockEdgesOnly = true
-	} else {
-		block.BlockEdgesOnly = false
+// Roda a mudança agora, ou depois da atualização em andamento
+func (sm *SceneManager) apply(change func()) {
+	if sm.busy {
+		sm.pending = append(sm.pending, change)
+		return
 	}
 
-	backOfPlayer, frontOfPlayer := s.Player.GetFrontAndBackDirections()
-	gl.BindVertexArray(1)
-
-	// draw all .obj objects
-	for _, obj := range s.Objs {
-
-		if obj.Animating {
-			if obj.T >= 3 {
-				obj.Tdir = -1
-			}
-			if obj.T <= 0 {
-				if obj.BCurve.ControlPoints != nil {
-					c := obj.BCurve.T(obj.T)
-					obj.Position = mgl32.Vec3{obj.Position[0] + c.X(), obj.Position[1], obj.Position[2] + c.Z()}
-				}
-				obj.Tdir = 1
-				curve := math2.NewBezierCurve()
-				curve.GenerateRandomPoints()
-				obj.BCurve = curve
-			}
-
-			obj.T = obj.T + (obj.Tdir * 1 * float32(math2.DeltaTime))
-			c := obj.BCurve.T(obj.T)
-
-			obj.DrawAt(nil, 1, c)
-		} else {
-			obj.Draw(nil, 1)
-		}
-
-		sphereCollider := collisions.SphereCollider{
-			Center: obj.Position,
-			Radius: 5,
-		}
+	change()
+}
 
-		if sphereCollider.CollidesWith(s.Player.Position.Vec3()) {
-			obj.Animating = true
-		} else {
-			obj.Animating = false
+// Update updates the scene on top and the scenes below it that it lets through
+func (sm *SceneManager) Update(deltaTime float64) {
+	sm.busy = true
+	for i := len(sm.stack) - 1; i >= 0; i-- {
+		sm.stack[i].Update(deltaTime)
+		if !sm.stack[i].GetLayer().UpdateThrough {
+			break
 		}
+	}
+	sm.busy = false
 
+	for len(sm.pending) > 0 {
+		change := sm.pending[0]
+		sm.pending = sm.pending[1:]
+		change()
 	}
-	gl.BindVertexArray(0)
-	s.World.Update(mgl32.Vec3{float32(roundedPlayerX), float32(roundedPlayerY), float32(roundedPlayerZ)}, backOfPlayer, frontOfPlayer, currentChunk)
-	s.Player.Update(s.World, s.World.Chunks[int(cx)][int(cz)])
-
-	/*window.SetTitle(fmt.Sprintf("FPS: %v - X: %v - Y: %v - Z: %v - wsX: %v - wsZ: %v", 1/math2.DeltaTime,
-	roundedPlayerX, playerY, roundedPlayerZ, s.World.Size.X(), s.World.Size.Z()))*/
-	gl.BindVertexArray(0)
-	window.SetTitle(fmt.Sprintf("FPS: %v - X: %v - Y: %v - Z: %v - wsX: %v - wsZ: %v", 1/math2.DeltaTime,
-		realPlayerX, realPlayerY, realPlayerZ, s.World.Size.X(), s.World.Size.Z()))
-
-	s.ControlHandler.FinishMousePositionChanged()
-	window.SwapBuffers()
-	glfw.PollEvents()
-
-	if s.World.ShouldUpdateChunks {
-		s.World.Chunks = s.World.FutureChunks
-		s.World.ShouldUpdateChunks = false
+}
+
+// Render draws the visible scenes, from the bottom up
+func (sm *SceneManager) Render() {
+	if len(sm.stack) == 0 {
+		return
 	}
 
-	if s.World.NextPopulatedBlocksReady {
-		s.World.PopulatedBlocks = s.World.NextPopulatedBlocks
+	bottom := len(sm.stack) - 1
+	for bottom > 0 && sm.stack[bottom].GetLayer().RenderThrough {
+		bottom--
 	}
 
-	go s.World.SetPopulatedBlocks(cx, cz)
+	for i := bottom; i < len(sm.stack); i++ {
+		sm.stack[i].Render()
+	}
 }
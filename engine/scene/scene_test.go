@@ -0,0 +1,185 @@
+package scene
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// Cena de teste que anota cada chamada em log
+type fakeScene struct {
+	Layer
+	log      *[]string
+	onUpdate func()
+}
+
+func (f *fakeScene) record(event string) {
+	*f.log = append(*f.log, f.Name+" "+event)
+}
+
+func (f *fakeScene) OnEnter() { f.record("enter") }
+func (f *fakeScene) OnExit()  { f.record("exit") }
+func (f *fakeScene) SetFocused(focused bool) {
+	f.record(fmt.Sprint("focused ", focused))
+}
+func (f *fakeScene) Render() { f.record("render") }
+func (f *fakeScene) Update(deltaTime float64) {
+	f.record("update")
+	if f.onUpdate != nil {
+		f.onUpdate()
+	}
+}
+
+type newScene func(name string, updateThrough, renderThrough bool) *fakeScene
+
+func TestSceneManager(t *testing.T) {
+	tests := []struct {
+		name  string
+		run   func(sm *SceneManager, scene newScene, log *[]string)
+		want  []string
+		stack []string
+	}{
+		{
+			name: "push passa o foco",
+			run: func(sm *SceneManager, scene newScene, log *[]string) {
+				sm.Push(scene("game", false, false))
+				sm.Push(scene("menu", false, false))
+			},
+			want: []string{
+				"game enter", "game focused true",
+				"game focused false", "menu enter", "menu focused true",
+			},
+			stack: []string{"game", "menu"},
+		},
+		{
+			name: "pop devolve o foco",
+			run: func(sm *SceneManager, scene newScene, log *[]string) {
+				sm.Push(scene("game", false, false))
+				sm.Push(scene("menu", false, false))
+				sm.Pop()
+			},
+			want: []string{
+				"game enter", "game focused true",
+				"game focused false", "menu enter", "menu focused true",
+				"menu focused false", "menu exit", "game focused true",
+			},
+			stack: []string{"game"},
+		},
+		{
+			name: "replace não dá o foco à cena de baixo",
+			run: func(sm *SceneManager, scene newScene, log *[]string) {
+				sm.Push(scene("game", false, false))
+				sm.Push(scene("menu", false, false))
+				sm.Replace(scene("options", false, false))
+			},
+			want: []string{
+				"game enter", "game focused true",
+				"game focused false", "menu enter", "menu focused true",
+				"menu focused false", "menu exit", "options enter", "options focused true",
+			},
+			stack: []string{"game", "options"},
+		},
+		{
+			name: "clear tira de cima para baixo",
+			run: func(sm *SceneManager, scene newScene, log *[]string) {
+				sm.Push(scene("game", false, false))
+				sm.Push(scene("menu", false, false))
+				*log = nil
+				sm.Clear()
+			},
+			want:  []string{"menu focused false", "menu exit", "game focused false", "game exit"},
+			stack: []string{},
+		},
+		{
+			name: "mudanças durante a atualização esperam o fim dela",
+			run: func(sm *SceneManager, scene newScene, log *[]string) {
+				game := scene("game", false, false)
+				sm.Push(game)
+				*log = nil
+				game.onUpdate = func() {
+					game.onUpdate = nil
+					sm.Pop()
+					sm.Push(scene("menu", false, false))
+					game.record("still on top " + fmt.Sprint(sm.Top() == game))
+				}
+				sm.Update(1)
+			},
+			want: []string{
+				"game update", "game still on top true",
+				"game focused false", "game exit", "menu enter", "menu focused true",
+			},
+			stack: []string{"menu"},
+		},
+		{
+			name: "update para na cena que não deixa passar",
+			run: func(sm *SceneManager, scene newScene, log *[]string) {
+				sm.Push(scene("world", false, false))
+				sm.Push(scene("game", false, false))
+				sm.Push(scene("hud", true, false))
+				sm.Push(scene("chat", true, false))
+				*log = nil
+				sm.Update(1)
+			},
+			want:  []string{"chat update", "hud update", "game update"},
+			stack: []string{"world", "game", "hud", "chat"},
+		},
+		{
+			name: "render desenha de baixo para cima a partir da cena opaca",
+			run: func(sm *SceneManager, scene newScene, log *[]string) {
+				sm.Push(scene("title", false, false))
+				sm.Push(scene("game", false, false))
+				sm.Push(scene("pause", false, true))
+				sm.Push(scene("options", false, true))
+				*log = nil
+				sm.Render()
+			},
+			want:  []string{"game render", "pause render", "options render"},
+			stack: []string{"title", "game", "pause", "options"},
+		},
+		{
+			name: "render com todas deixando passar",
+			run: func(sm *SceneManager, scene newScene, log *[]string) {
+				sm.Push(scene("a", false, true))
+				sm.Push(scene("b", false, true))
+				*log = nil
+				sm.Render()
+				sm.Update(1)
+			},
+			want:  []string{"a render", "b render", "b update"},
+			stack: []string{"a", "b"},
+		},
+	}
+
+	for _, test := range tests {
+		var log []string
+		sm := NewSceneManager()
+		test.run(&sm, func(name string, updateThrough, renderThrough bool) *fakeScene {
+			return &fakeScene{
+				Layer: Layer{Name: name, UpdateThrough: updateThrough, RenderThrough: renderThrough},
+				log:   &log,
+			}
+		}, &log)
+
+		if !reflect.DeepEqual(log, test.want) {
+			t.Errorf("%s: calls = %q, want %q", test.name, log, test.want)
+		}
+
+		stack := []string{}
+		for _, scene := range sm.stack {
+			stack = append(stack, scene.GetLayer().Name)
+		}
+		if !reflect.DeepEqual(stack, test.stack) {
+			t.Errorf("%s: stack = %v, want %v", test.name, stack, test.stack)
+		}
+	}
+}
+
+func TestSceneManagerEmpty(t *testing.T) {
+	sm := NewSceneManager()
+	sm.Pop()
+	sm.Update(1)
+	sm.Render()
+	if sm.Top() != nil || sm.Len() != 0 || sm.Find("game") != nil {
+		t.Errorf("empty manager has top %v and %d scenes", sm.Top(), sm.Len())
+	}
+}
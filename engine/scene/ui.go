@@ -0,0 +1,62 @@
+package scene
+
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/reonardoleis/fcg-glcraft/engine/controls"
+	"github.com/reonardoleis/fcg-glcraft/engine/ui"
+)
+
+// UIScene only shows an interface, e.g. a menu. With RenderThrough it is drawn
+// over the scene below it instead of clearing the screen.
+type UIScene struct {
+	Layer
+	Context        *ui.Context
+	Batch          *ui.Batch
+	ControlHandler controls.Controls
+	Refresh        func() // chamado ao entrar, para mostrar o estado atual
+	Back           func() // chamado pela ação de voltar, nil fecha a tela
+}
+
+// NewUIScene creates a scene that reads the input through its own route of controlHandler
+func NewUIScene(name string, controlHandler controls.Controls, context *ui.Context, batch *ui.Batch) *UIScene {
+	return &UIScene{
+		Layer:          Layer{Name: name},
+		Context:        context,
+		Batch:          batch,
+		ControlHandler: controlHandler.Route(),
+	}
+}
+
+func (s *UIScene) OnEnter() {
+	if s.Refresh != nil {
+		s.Refresh()
+	}
+}
+
+func (s *UIScene) OnExit() {}
+
+// Only the focused interface reads the input, with the cursor released. The
+// input queued while another scene was on top is dropped, so keys pressed in
+// the game don't reach the text fields.
+func (s *UIScene) SetFocused(focused bool) {
+	s.ControlHandler.SetEnabled(focused)
+	if focused {
+		s.ControlHandler.CaptureCursor(false)
+		s.ControlHandler.ConsumePressedKeys()
+		s.ControlHandler.ConsumeTypedChars()
+	}
+}
+
+func (s *UIScene) Update(deltaTime float64) {
+	s.Context.Update(s.ControlHandler)
+	s.ControlHandler.FinishMousePositionChanged()
+}
+
+func (s *UIScene) Render() {
+	if !s.RenderThrough {
+		gl.ClearColor(0.08, 0.08, 0.1, 1.0)
+		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	}
+
+	s.Context.Draw(s.Batch)
+}
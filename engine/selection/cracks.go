@@ -0,0 +1,73 @@
+package selection
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Configuração das rachaduras
+const (
+	CrackStages      = 10
+	CrackTextureSize = 16
+	crackBranches    = 6
+)
+
+// CrackStage returns the crack texture to show for a mining progress in
+// [0, 1], or -1 when nothing has been mined yet
+func CrackStage(progress float32) int {
+	if progress <= 0 {
+		return -1
+	}
+
+	stage := int(progress * CrackStages)
+	if stage >= CrackStages {
+		stage = CrackStages - 1
+	}
+
+	return stage
+}
+
+// GenerateCracks returns one size x size mask per stage, 255 where there is
+// a crack. The cracks are random walks growing from the center, and every
+// stage contains the cracks of the previous ones.
+func GenerateCracks(size, stages int, seed int64) [][]uint8 {
+	random := rand.New(rand.NewSource(seed))
+	steps := size / 2 // do centro até perto da borda
+
+	// passos de cada ramo, na ordem em que crescem
+	type point struct{ x, y int }
+	branches := make([][]point, crackBranches)
+	for b := range branches {
+		x, y := float64(size)/2, float64(size)/2
+		angle := 2 * math.Pi * (float64(b) + random.Float64()) / crackBranches
+		for i := 0; i < steps; i++ {
+			angle += (random.Float64() - 0.5) * 1.2
+			x += math.Cos(angle)
+			y += math.Sin(angle)
+			px, py := int(x), int(y)
+			if px < 0 || py < 0 || px >= size || py >= size {
+				break
+			}
+			branches[b] = append(branches[b], point{px, py})
+		}
+	}
+
+	masks := make([][]uint8, stages)
+	for stage := range masks {
+		mask := make([]uint8, size*size)
+		if stage > 0 {
+			copy(mask, masks[stage-1])
+		}
+
+		// arredondado para cima, para que o primeiro estágio já tenha rachaduras
+		grown := (steps*(stage+1) + stages - 1) / stages
+		for _, branch := range branches {
+			for i := 0; i < grown && i < len(branch); i++ {
+				mask[branch[i].y*size+branch[i].x] = 255
+			}
+		}
+		masks[stage] = mask
+	}
+
+	return masks
+}
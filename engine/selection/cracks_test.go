@@ -0,0 +1,88 @@
+package selection
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCrackStage(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress float32
+		want     int
+	}{
+		{"nada minerado", 0, -1},
+		{"negativo", -0.5, -1},
+		{"começo", 0.01, 0},
+		{"antes do segundo", 0.099, 0},
+		{"segundo", 0.1, 1},
+		{"metade", 0.5, 5},
+		{"quase quebrado", 0.99, CrackStages - 1},
+		{"quebrado", 1, CrackStages - 1},
+		{"além do fim", 1.5, CrackStages - 1},
+	}
+
+	for _, test := range tests {
+		if got := CrackStage(test.progress); got != test.want {
+			t.Errorf("%s: stage of %v = %d, want %d", test.name, test.progress, got, test.want)
+		}
+	}
+}
+
+func TestGenerateCracks(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		stages int
+	}{
+		{"textura do jogo", CrackTextureSize, CrackStages},
+		{"poucos estágios", 16, 3},
+		{"mais estágios que passos", 8, 10},
+		{"textura grande", 64, 5},
+	}
+
+	for _, test := range tests {
+		masks := GenerateCracks(test.size, test.stages, 1)
+		if len(masks) != test.stages {
+			t.Errorf("%s: %d masks, want %d", test.name, len(masks), test.stages)
+			continue
+		}
+
+		previous := 0
+		for stage, mask := range masks {
+			if len(mask) != test.size*test.size {
+				t.Errorf("%s: mask %d has %d pixels, want %d", test.name, stage, len(mask), test.size*test.size)
+				break
+			}
+
+			cracked := 0
+			for i, value := range mask {
+				if value != 0 && value != 255 {
+					t.Errorf("%s: mask %d has value %d", test.name, stage, value)
+				}
+				if value == 255 {
+					cracked++
+				}
+				// cada estágio tem as rachaduras do anterior
+				if stage > 0 && masks[stage-1][i] == 255 && value != 255 {
+					t.Errorf("%s: stage %d lost the crack at pixel %d", test.name, stage, i)
+					break
+				}
+			}
+			if cracked == 0 {
+				t.Errorf("%s: stage %d has no cracks", test.name, stage)
+			}
+			if cracked < previous {
+				t.Errorf("%s: stage %d has fewer cracks than the previous one", test.name, stage)
+			}
+			previous = cracked
+		}
+
+		if !reflect.DeepEqual(masks, GenerateCracks(test.size, test.stages, 1)) {
+			t.Errorf("%s: the same seed gave different cracks", test.name)
+		}
+		if reflect.DeepEqual(masks, GenerateCracks(test.size, test.stages, 2)) {
+			t.Errorf("%s: different seeds gave the same cracks", test.name)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package selection
+
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
+	"github.com/reonardoleis/fcg-glcraft/geometry"
+	math2 "github.com/reonardoleis/fcg-glcraft/math"
+)
+
+// Um pouco maior que o bloco, para as linhas não brigarem com as faces
+const outlineScale float32 = 1.004
+
+// Overlay draws the outline of the targeted block and the cracks of the
+// block being mined
+type Overlay struct {
+	outline *geometry.Mesh
+	cracks  []*geometry.Material
+	stages  []uint32
+}
+
+// NewOverlay uploads the outline mesh and the crack textures. It must be
+// called after the OpenGL context is created.
+func NewOverlay(program *shaders.Program, seed int64) *Overlay {
+	o := &Overlay{
+		outline: geometry.NewMesh(geometry.BuildCubeEdges(0, 0, 0, outlineScale), geometry.NewMaterial(program, shaders.ObjectSelection)),
+	}
+
+	masks := GenerateCracks(CrackTextureSize, CrackStages, seed)
+	o.stages = make([]uint32, len(masks))
+	gl.GenTextures(int32(len(masks)), &o.stages[0])
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	for i, mask := range masks {
+		gl.BindTexture(gl.TEXTURE_2D, o.stages[i])
+		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.R8, CrackTextureSize, CrackTextureSize, 0, gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(mask))
+		// texels nítidos, como as texturas dos blocos
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+		o.cracks = append(o.cracks, geometry.NewMaterial(program, shaders.ObjectCracks, o.stages[i]))
+	}
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	return o
+}
+
+// Draw outlines target, if any, and covers its visible faces with the cracks
+// of the mining progress, in [0, 1]
+func (o *Overlay) Draw(target *block.Block, progress float32) {
+	if target == nil {
+		return
+	}
+
+	gl.DepthMask(false)
+
+	o.outline.Draw(math2.Matrix_Translate(target.Position.X(), target.Position.Y(), target.Position.Z()))
+
+	if stage := CrackStage(progress); stage >= 0 {
+		// desenhadas sobre as faces do bloco, puxadas para a frente na profundidade
+		gl.Enable(gl.POLYGON_OFFSET_FILL)
+		gl.PolygonOffset(-1, -1)
+		geometry.SetFaceLighting(block.FullyLit.AO, block.FullyLit.Light)
+
+		for face := range target.Neighbors {
+			if target.Neighbors[face] == 1 {
+				continue
+			}
+			geometry.FaceMesh.DrawWithMaterial(o.cracks[stage], target.FaceMatrix(face))
+		}
+
+		gl.Disable(gl.POLYGON_OFFSET_FILL)
+	}
+
+	gl.DepthMask(true)
+}
+
+// Destroy frees the outline mesh and the crack textures
+func (o *Overlay) Destroy() {
+	o.outline.Destroy()
+	gl.DeleteTextures(int32(len(o.stages)), &o.stages[0])
+}
@@ -0,0 +1,132 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/controls"
+)
+
+// Arquivo padrão das configurações, relativo ao diretório do jogo
+const DefaultPath = "settings.json"
+
+// Versão do formato do arquivo. Arquivos sem versão são anteriores a Video e
+// a recebem com os valores padrão.
+const SchemaVersion = 1
+
+// Settings are the engine options, video and input, that the player changes in
+// the settings screen. The options of each world are saved with it instead.
+type Settings struct {
+	Version          int                 `json:"version"`
+	PlayerName       string              `json:"player_name"`
+	FieldOfView      float32             `json:"field_of_view"`     // graus
+	MouseSensitivity float64             `json:"mouse_sensitivity"` // radianos por pixel
+	InvertMouseY     bool                `json:"invert_mouse_y"`
+	MouseSmoothing   float64             `json:"mouse_smoothing"`  // 0 a 0.95
+	RawMouseMotion   bool                `json:"raw_mouse_motion"` // quando o sistema suporta
+	Video            configs.VideoConfig `json:"video"`
+	Bindings         controls.Bindings   `json:"bindings"` // ação -> teclas, como "LeftControl+S"
+}
+
+func Default() Settings {
+	return Settings{
+		Version:          SchemaVersion,
+		PlayerName:       "Player",
+		FieldOfView:      60,
+		MouseSensitivity: 0.01,
+		Video:            configs.DefaultVideo(),
+		Bindings:         controls.DefaultBindings(),
+	}
+}
+
+// Validate tells which option, if any, is out of its range
+func (s Settings) Validate() error {
+	switch {
+	case s.PlayerName == "" || len(s.PlayerName) > 16 || strings.ContainsAny(s.PlayerName, " \t@~/"):
+		return fmt.Errorf("settings: player_name must have 1 to 16 characters, without spaces, @, ~ or /, got %q", s.PlayerName)
+	case s.FieldOfView < 30 || s.FieldOfView > 110:
+		return fmt.Errorf("settings: field_of_view must be between 30 and 110, got %v", s.FieldOfView)
+	case s.MouseSensitivity <= 0:
+		return fmt.Errorf("settings: mouse_sensitivity must be positive, got %v", s.MouseSensitivity)
+	case s.MouseSmoothing < 0 || s.MouseSmoothing > 0.95:
+		return fmt.Errorf("settings: mouse_smoothing must be between 0 and 0.95, got %v", s.MouseSmoothing)
+	}
+	if err := s.Video.Validate(); err != nil {
+		return fmt.Errorf("settings: video.%w", err)
+	}
+
+	return nil
+}
+
+// Set changes one option by its name in the file, e.g. "video.view_distance",
+// as given on the command line
+func (s *Settings) Set(key, value string) error {
+	// o mapa de teclas é decodificado no lugar, então a cópia tem o seu próprio
+	changed := *s
+	changed.Bindings = s.Bindings.Clone()
+	if err := configs.Set(&changed, key, value); err != nil {
+		return fmt.Errorf("settings: %w", err)
+	}
+	if err := changed.Validate(); err != nil {
+		return err
+	}
+
+	*s = changed
+	return nil
+}
+
+// MouseLook returns the mouse options, as used by the cameras
+func (s Settings) MouseLook() controls.MouseLookOptions {
+	return controls.MouseLookOptions{
+		Sensitivity: s.MouseSensitivity,
+		InvertY:     s.InvertMouseY,
+		Smoothing:   s.MouseSmoothing,
+		RawMotion:   s.RawMouseMotion,
+	}
+}
+
+// Load reads the settings from path. A missing file gives the defaults, and
+// options missing from the file keep their default value. Files of a newer
+// version or with invalid options give the defaults and an error.
+func Load(path string) (Settings, error) {
+	settings := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return settings, nil
+		}
+		return settings, err
+	}
+
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return Default(), err
+	}
+	if settings.Version > SchemaVersion {
+		return Default(), fmt.Errorf("settings: %s is from a newer version of the game (%d > %d)", path, settings.Version, SchemaVersion)
+	}
+	if settings.Bindings == nil {
+		settings.Bindings = controls.DefaultBindings()
+	}
+	if err := settings.Validate(); err != nil {
+		return Default(), err
+	}
+	settings.Version = SchemaVersion
+
+	return settings, nil
+}
+
+// Save writes the settings to path
+func (s Settings) Save(path string) error {
+	s.Version = SchemaVersion
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,178 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/reonardoleis/fcg-glcraft/engine/controls"
+)
+
+func chord(t *testing.T, text string) controls.Chord {
+	t.Helper()
+	chord, err := controls.ParseChord(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return chord
+}
+
+func TestLoad(t *testing.T) {
+	defaults := Default()
+	customVideo := defaults.Video
+	customVideo.ViewDistance = 3
+	customVideo.MSAA = 8
+
+	tests := []struct {
+		name    string
+		file    string // sem arquivo se vazio
+		want    func(s *Settings)
+		wantErr string
+	}{
+		{name: "sem arquivo", want: func(s *Settings) {}},
+		{
+			name: "campos faltando ficam com o padrão",
+			file: `{"version": 1, "player_name": "Steve"}`,
+			want: func(s *Settings) { s.PlayerName = "Steve" },
+		},
+		{
+			name: "arquivo sem versão recebe o vídeo padrão",
+			file: `{"player_name": "Alex", "field_of_view": 90}`,
+			want: func(s *Settings) { s.PlayerName, s.FieldOfView = "Alex", 90 },
+		},
+		{
+			name: "opção aninhada parcial",
+			file: `{"version": 1, "video": {"view_distance": 3, "msaa": 8}}`,
+			want: func(s *Settings) { s.Video = customVideo },
+		},
+		{
+			name: "teclas faltando ficam com o padrão",
+			file: `{"version": 1, "bindings": {"jump": ["LeftControl+J"]}}`,
+			want: func(s *Settings) {
+				s.Bindings[controls.ActionJump] = []controls.Chord{chord(t, "LeftControl+J")}
+			},
+		},
+		{
+			name:    "versão mais nova",
+			file:    `{"version": 2, "player_name": "Steve"}`,
+			wantErr: "newer version",
+		},
+		{
+			name:    "opção inválida",
+			file:    `{"version": 1, "field_of_view": 200}`,
+			wantErr: "field_of_view",
+		},
+		{
+			name:    "opção de vídeo inválida",
+			file:    `{"version": 1, "video": {"msaa": 3}}`,
+			wantErr: "video.msaa",
+		},
+		{
+			name:    "JSON inválido",
+			file:    `{"version": 1,`,
+			wantErr: "unexpected end",
+		},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "settings.json")
+		if test.file != "" {
+			if err := os.WriteFile(path, []byte(test.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got, err := Load(path)
+		want := Default()
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%s: error = %v, want one about %q", test.name, err, test.wantErr)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+			test.want(&want)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: settings = %+v, want %+v", test.name, got, want)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    func(s *Settings)
+		wantErr string
+	}{
+		{name: "número", key: "field_of_view", value: "90", want: func(s *Settings) { s.FieldOfView = 90 }},
+		{name: "texto sem aspas", key: "player_name", value: "Steve", want: func(s *Settings) { s.PlayerName = "Steve" }},
+		{name: "texto com aspas", key: "player_name", value: `"Alex"`, want: func(s *Settings) { s.PlayerName = "Alex" }},
+		{name: "booleano", key: "invert_mouse_y", value: "true", want: func(s *Settings) { s.InvertMouseY = true }},
+		{name: "chave aninhada", key: "video.view_distance", value: "4", want: func(s *Settings) { s.Video.ViewDistance = 4 }},
+		{
+			name:  "tecla",
+			key:   "bindings.jump",
+			value: `["J"]`,
+			want: func(s *Settings) {
+				s.Bindings[controls.ActionJump] = []controls.Chord{chord(t, "J")}
+			},
+		},
+		{name: "chave desconhecida", key: "volume", value: "1", wantErr: `unknown option "volume"`},
+		{name: "chave aninhada desconhecida", key: "video.gamma", value: "1", wantErr: `unknown option "video.gamma"`},
+		{name: "dentro de um valor simples", key: "field_of_view.x", value: "1", wantErr: "unknown option"},
+		{name: "tipo errado", key: "field_of_view", value: "wide", wantErr: "field_of_view"},
+		{name: "fora do intervalo", key: "field_of_view", value: "200", wantErr: "field_of_view must be"},
+		{name: "vídeo fora do intervalo", key: "video.msaa", value: "3", wantErr: "video.msaa must be"},
+		{name: "tecla inválida", key: "bindings.jump", value: `["NoSuchKey"]`, wantErr: "bindings.jump"},
+	}
+
+	for _, test := range tests {
+		settings := Default()
+		err := settings.Set(test.key, test.value)
+
+		// um valor inválido não muda nada
+		want := Default()
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%s: error = %v, want one about %q", test.name, err, test.wantErr)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+			test.want(&want)
+		}
+
+		if !reflect.DeepEqual(settings, want) {
+			t.Errorf("%s: settings = %+v, want %+v", test.name, settings, want)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	settings := Default()
+	settings.Version = 0
+	settings.PlayerName = "Steve"
+	settings.Video.Fullscreen = true
+	if err := settings.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.Version = SchemaVersion
+	if !reflect.DeepEqual(loaded, settings) {
+		t.Errorf("loaded %+v, want %+v", loaded, settings)
+	}
+}
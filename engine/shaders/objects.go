@@ -0,0 +1,55 @@
+package shaders
+
+import "fmt"
+
+// ObjectID is the value of the "object_id" uniform, used by the fragment
+// shader to pick how an object is shaded
+type ObjectID int32
+
+const (
+	ObjectBlock ObjectID = iota
+	ObjectOBJ
+	ObjectPlayerArm
+	ObjectPlayerBody
+	ObjectTerrainLOD
+	ObjectSkyDome
+	ObjectSun
+	ObjectMoon
+	ObjectClouds
+	ObjectSelection
+	ObjectCracks
+)
+
+// Nomes dos defines gerados para os shaders, na ordem dos ObjectIDs
+var objectIDNames = []string{
+	ObjectBlock:      "BLOCK",
+	ObjectOBJ:        "OBJ",
+	ObjectPlayerArm:  "PLAYER_ARM",
+	ObjectPlayerBody: "PLAYER_BODY",
+	ObjectTerrainLOD: "TERRAIN_LOD",
+	ObjectSkyDome:    "SKY_DOME",
+	ObjectSun:        "SUN",
+	ObjectMoon:       "MOON",
+	ObjectClouds:     "CLOUDS",
+	ObjectSelection:  "SELECTION",
+	ObjectCracks:     "CRACKS",
+}
+
+func (id ObjectID) String() string {
+	if id < 0 || int(id) >= len(objectIDNames) {
+		return "UNKNOWN"
+	}
+
+	return objectIDNames[id]
+}
+
+// ObjectIDDefines returns a "#define" for every ObjectID, so shaders never
+// hardcode the numeric values
+func ObjectIDDefines() []Define {
+	defines := make([]Define, len(objectIDNames))
+	for id, name := range objectIDNames {
+		defines[id] = Define{Name: name, Value: fmt.Sprint(id)}
+	}
+
+	return defines
+}
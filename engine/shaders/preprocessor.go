@@ -0,0 +1,215 @@
+package shaders
+
+// Pré-processador dos arquivos .glsl. Trata "#include", injeta "#define"s
+// vindos do Go logo após o "#version" e guarda, para cada linha gerada, o
+// arquivo e a linha de origem, usados para traduzir os erros do compilador.
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Define is a "#define Name Value" injected in the shader source
+type Define struct {
+	Name  string
+	Value string
+}
+
+// Variant is a permutation of a program, compiled with extra defines
+type Variant struct {
+	Name    string
+	Defines []Define
+}
+
+// Key identifies the variant of a program, independently of the defines order
+func (v Variant) Key() string {
+	defines := make([]string, len(v.Defines))
+	for i, define := range v.Defines {
+		defines[i] = define.Name + "=" + define.Value
+	}
+	sort.Strings(defines)
+
+	return strings.Join(defines, ";")
+}
+
+// SourceLocation is the file and line (starting at 1) where a generated line came from
+type SourceLocation struct {
+	File string
+	Line int
+}
+
+func (l SourceLocation) String() string {
+	return fmt.Sprintf("%v:%v", l.File, l.Line)
+}
+
+// ProcessedSource is a shader source after preprocessing
+type ProcessedSource struct {
+	File  string
+	Code  string
+	Lines []SourceLocation // Lines[i] é a origem da linha i+1 de Code
+	Files []string         // todos os arquivos lidos, o principal primeiro
+}
+
+// Origin returns where the given line (starting at 1) of the generated code came from
+func (s ProcessedSource) Origin(line int) (SourceLocation, bool) {
+	if line < 1 || line > len(s.Lines) {
+		return SourceLocation{}, false
+	}
+
+	return s.Lines[line-1], true
+}
+
+// Formatos de erro mais comuns dos drivers:
+//
+//	0(12) : error C0000: ...        (NVIDIA)
+//	0:12(5): error: ...             (Mesa)
+//	ERROR: 0:12: ...                (AMD, Intel)
+var errorLinePattern = regexp.MustCompile(`^(ERROR: |WARNING: )?\d+(?::(\d+)|\((\d+)\))`)
+
+// MapError rewrites the line references of a compiler log to the original files
+func (s ProcessedSource) MapError(log string) string {
+	lines := strings.Split(strings.TrimRight(log, "\x00\n"), "\n")
+	for i, line := range lines {
+		match := errorLinePattern.FindStringSubmatchIndex(line)
+		if match == nil {
+			continue
+		}
+
+		// grupo 2 (formato "0:N") ou grupo 3 (formato "0(N)")
+		start, end := match[4], match[5]
+		if start < 0 {
+			start, end = match[6], match[7]
+		}
+
+		number, err := strconv.Atoi(line[start:end])
+		if err != nil {
+			continue
+		}
+
+		origin, ok := s.Origin(number)
+		if !ok {
+			continue
+		}
+
+		prefix := ""
+		if match[2] >= 0 {
+			prefix = line[match[2]:match[3]]
+		}
+		lines[i] = prefix + origin.String() + line[match[1]:]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Preprocessor expands the shader files of a directory
+type Preprocessor struct {
+	Dir      string
+	ReadFile func(path string) ([]byte, error) // os.ReadFile por padrão, pode ser trocado em testes
+}
+
+func NewPreprocessor(dir string) *Preprocessor {
+	return &Preprocessor{Dir: dir, ReadFile: os.ReadFile}
+}
+
+var includePattern = regexp.MustCompile(`^\s*#\s*include\s+"([^"]+)"\s*(//.*)?$`)
+var versionPattern = regexp.MustCompile(`^\s*#\s*version\b`)
+
+// Process reads file (relative to Dir), expands its includes and adds the
+// defines right after the "#version" directive. Each file is included at most
+// once, so includes may depend on each other freely.
+func (p *Preprocessor) Process(file string, defines []Define) (ProcessedSource, error) {
+	source := ProcessedSource{File: file}
+	included := map[string]bool{}
+	versionSeen := false
+	var builder strings.Builder
+
+	emit := func(code string, origin SourceLocation) {
+		builder.WriteString(code)
+		builder.WriteByte('\n')
+		source.Lines = append(source.Lines, origin)
+	}
+
+	emitDefines := func() {
+		for i, define := range defines {
+			emit(fmt.Sprintf("#define %v %v", define.Name, define.Value), SourceLocation{File: "<defines>", Line: i + 1})
+		}
+	}
+
+	var expand func(file string, stack []string, from SourceLocation) error
+	expand = func(file string, stack []string, from SourceLocation) error {
+		for _, parent := range stack {
+			if parent == file {
+				return fmt.Errorf("%v: include cycle: %v -> %v", from, strings.Join(stack, " -> "), file)
+			}
+		}
+		if included[file] {
+			return nil
+		}
+		included[file] = true
+
+		data, err := p.ReadFile(filepath.Join(p.Dir, file))
+		if err != nil {
+			if len(stack) > 0 {
+				return fmt.Errorf("%v: %v", from, err)
+			}
+			return err
+		}
+		source.Files = append(source.Files, file)
+
+		lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+		if len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+
+		for i, line := range lines {
+			origin := SourceLocation{File: file, Line: i + 1}
+
+			if match := includePattern.FindStringSubmatch(line); match != nil {
+				// o caminho é relativo ao arquivo que faz o include
+				path := filepath.ToSlash(filepath.Join(filepath.Dir(file), match[1]))
+				if err := expand(path, append(stack, file), origin); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if versionPattern.MatchString(line) {
+				if len(stack) > 0 {
+					// só o arquivo principal pode declarar a versão
+					emit("", origin)
+					continue
+				}
+				emit(line, origin)
+				emitDefines()
+				versionSeen = true
+				continue
+			}
+
+			emit(line, origin)
+		}
+
+		return nil
+	}
+
+	if err := expand(filepath.ToSlash(file), nil, SourceLocation{}); err != nil {
+		return ProcessedSource{}, err
+	}
+
+	if !versionSeen && len(defines) > 0 {
+		// sem "#version", os defines vão no início do código
+		code, lines := builder.String(), source.Lines
+		builder.Reset()
+		source.Lines = nil
+		emitDefines()
+		builder.WriteString(code)
+		source.Lines = append(source.Lines, lines...)
+	}
+
+	source.Code = builder.String()
+	return source, nil
+}
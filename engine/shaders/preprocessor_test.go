@@ -0,0 +1,199 @@
+package shaders
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Pré-processador que lê os arquivos de um mapa em vez do disco
+func memoryPreprocessor(files map[string]string) *Preprocessor {
+	p := NewPreprocessor("shaders")
+	p.ReadFile = func(path string) ([]byte, error) {
+		code, ok := files[filepath.ToSlash(path)]
+		if !ok {
+			return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+		}
+		return []byte(code), nil
+	}
+
+	return p
+}
+
+func TestProcessIncludes(t *testing.T) {
+	p := memoryPreprocessor(map[string]string{
+		"shaders/main.glsl":       "#version 330 core\n#include \"lib/light.glsl\"\n#include \"lib/common.glsl\"\nvoid main() {}\n",
+		"shaders/lib/light.glsl":  "#include \"common.glsl\" // usa PI\nfloat light;\n",
+		"shaders/lib/common.glsl": "#version 330 core\nconst float PI = 3.14;\n",
+		"shaders/lib/unused.glsl": "never included\n",
+	})
+
+	source, err := p.Process("main.glsl", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// common.glsl entra uma vez só, e sem o #version
+	want := "#version 330 core\n\nconst float PI = 3.14;\nfloat light;\nvoid main() {}\n"
+	if source.Code != want {
+		t.Errorf("Code =\n%q\nwant\n%q", source.Code, want)
+	}
+
+	wantFiles := []string{"main.glsl", "lib/light.glsl", "lib/common.glsl"}
+	if strings.Join(source.Files, ",") != strings.Join(wantFiles, ",") {
+		t.Errorf("Files = %v, want %v", source.Files, wantFiles)
+	}
+
+	wantLines := []SourceLocation{
+		{"main.glsl", 1}, {"lib/common.glsl", 1}, {"lib/common.glsl", 2}, {"lib/light.glsl", 2}, {"main.glsl", 4},
+	}
+	if len(source.Lines) != len(wantLines) {
+		t.Fatalf("Lines = %v, want %v", source.Lines, wantLines)
+	}
+	for i, want := range wantLines {
+		if source.Lines[i] != want {
+			t.Errorf("Lines[%d] = %v, want %v", i, source.Lines[i], want)
+		}
+	}
+}
+
+func TestProcessDefines(t *testing.T) {
+	defines := []Define{{"BLOCK", "0"}, {"SHADOWS", "1"}}
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{"after version", "// topo\n#version 330 core\nvoid main() {}\n", "// topo\n#version 330 core\n#define BLOCK 0\n#define SHADOWS 1\nvoid main() {}\n"},
+		{"without version", "void main() {}\n", "#define BLOCK 0\n#define SHADOWS 1\nvoid main() {}\n"},
+		{"spaced version", "  #  version 330\n", "  #  version 330\n#define BLOCK 0\n#define SHADOWS 1\n"},
+	}
+	for _, test := range tests {
+		p := memoryPreprocessor(map[string]string{"shaders/main.glsl": test.code})
+		source, err := p.Process("main.glsl", defines)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if source.Code != test.want {
+			t.Errorf("%s: Code =\n%q\nwant\n%q", test.name, source.Code, test.want)
+		}
+
+		// cada linha gerada tem uma origem, e a dos defines é <defines>
+		if strings.Count(source.Code, "\n") != len(source.Lines) {
+			t.Errorf("%s: %d lines of code but %d origins", test.name, strings.Count(source.Code, "\n"), len(source.Lines))
+		}
+		for i, line := range strings.Split(strings.TrimSuffix(source.Code, "\n"), "\n") {
+			if strings.HasPrefix(line, "#define") != (source.Lines[i].File == "<defines>") {
+				t.Errorf("%s: line %q comes from %v", test.name, line, source.Lines[i])
+			}
+		}
+	}
+}
+
+func TestProcessErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"missing main", map[string]string{}, "main.glsl"},
+		{"missing include", map[string]string{"shaders/main.glsl": "\n#include \"gone.glsl\"\n"}, "main.glsl:2"},
+		{"cycle", map[string]string{
+			"shaders/main.glsl": "#include \"a.glsl\"\n",
+			"shaders/a.glsl":    "#include \"b.glsl\"\n",
+			"shaders/b.glsl":    "#include \"a.glsl\"\n",
+		}, "include cycle: main.glsl -> a.glsl -> b.glsl -> a.glsl"},
+	}
+	for _, test := range tests {
+		_, err := memoryPreprocessor(test.files).Process("main.glsl", nil)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: err = %v, want it to mention %q", test.name, err, test.want)
+		}
+	}
+}
+
+func TestMapError(t *testing.T) {
+	p := memoryPreprocessor(map[string]string{
+		"shaders/main.glsl":  "#version 330 core\n#include \"light.glsl\"\nvoid main() {}\n",
+		"shaders/light.glsl": "float light;\nfloat shadow;\n",
+	})
+	source, err := p.Process("main.glsl", []Define{{"BLOCK", "0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// linhas geradas: 1 version, 2 define, 3-4 light.glsl, 5 main
+	tests := []struct {
+		log, want string
+	}{
+		{"0(4) : error C0000: syntax error", "light.glsl:2 : error C0000: syntax error"},
+		{"0:5(3): error: undeclared", "main.glsl:3(3): error: undeclared"},
+		{"ERROR: 0:3: 'light' : redefinition", "ERROR: light.glsl:1: 'light' : redefinition"},
+		{"0:99(1): error: past the end", "0:99(1): error: past the end"},
+		{"some other message", "some other message"},
+	}
+	for _, test := range tests {
+		if got := source.MapError(test.log + "\x00"); got != test.want {
+			t.Errorf("MapError(%q) = %q, want %q", test.log, got, test.want)
+		}
+	}
+}
+
+func TestVariantKey(t *testing.T) {
+	a := Variant{Name: "a", Defines: []Define{{"X", "1"}, {"Y", "2"}}}
+	b := Variant{Name: "b", Defines: []Define{{"Y", "2"}, {"X", "1"}}}
+	if a.Key() != b.Key() {
+		t.Errorf("keys differ with the order of the defines: %q, %q", a.Key(), b.Key())
+	}
+
+	if got := programKey("standard", Variant{}); got != "standard" {
+		t.Errorf("programKey without variant = %q, want standard", got)
+	}
+	if programKey("standard", a) == programKey("standard", Variant{Defines: []Define{{"X", "2"}, {"Y", "2"}}}) {
+		t.Errorf("variants with different values share a key")
+	}
+}
+
+func TestObjectIDDefines(t *testing.T) {
+	defines := ObjectIDDefines()
+	if len(defines) != int(ObjectCracks)+1 {
+		t.Fatalf("got %d defines, want one per ObjectID", len(defines))
+	}
+	for id := ObjectBlock; id <= ObjectCracks; id++ {
+		want := Define{Name: id.String(), Value: fmt.Sprint(int(id))}
+		if defines[id] != want {
+			t.Errorf("define of %v = %v, want %v", int(id), defines[id], want)
+		}
+	}
+}
+
+func TestRegistryPoll(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{"a_shader_vertex.glsl", "common.glsl"} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte("void main() {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewRegistry(dir)
+	files := []string{"a_shader_vertex.glsl", "common.glsl"}
+	r.programs["a"] = &registeredProgram{program: &Program{Name: "a"}, name: "a", files: files, modTime: r.lastModified(files)}
+
+	r.Poll()
+	if r.changed["a"] {
+		t.Fatalf("program marked as changed before any file changed")
+	}
+
+	// mudar um include também recarrega o programa
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, "common.glsl"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	r.Poll()
+	if !r.changed["a"] {
+		t.Errorf("program not marked after an included file changed")
+	}
+}
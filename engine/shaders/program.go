@@ -0,0 +1,76 @@
+package shaders
+
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Program is a linked shader program owned by a Registry. The OpenGL ID may
+// change when the program is hot reloaded, so callers should keep the
+// *Program and not the ID.
+type Program struct {
+	Name     string
+	ID       uint32
+	uniforms map[string]int32
+}
+
+func (p *Program) Use() {
+	gl.UseProgram(p.ID)
+}
+
+// UniformLocation returns the location of an uniform, looking it up only once
+// per compiled program. Missing uniforms return -1, which gl ignores.
+func (p *Program) UniformLocation(name string) int32 {
+	location, ok := p.uniforms[name]
+	if !ok {
+		location = gl.GetUniformLocation(p.ID, gl.Str(name+"\x00"))
+		p.uniforms[name] = location
+	}
+
+	return location
+}
+
+// Setters de uniforms. Usam glProgramUniform, então o programa não precisa
+// estar em uso.
+
+func (p *Program) SetInt(name string, value int32) {
+	gl.ProgramUniform1i(p.ID, p.UniformLocation(name), value)
+}
+
+func (p *Program) SetBool(name string, value bool) {
+	if value {
+		p.SetInt(name, 1)
+	} else {
+		p.SetInt(name, 0)
+	}
+}
+
+func (p *Program) SetFloat(name string, value float32) {
+	gl.ProgramUniform1f(p.ID, p.UniformLocation(name), value)
+}
+
+func (p *Program) SetVec2(name string, value mgl32.Vec2) {
+	gl.ProgramUniform2f(p.ID, p.UniformLocation(name), value[0], value[1])
+}
+
+func (p *Program) SetVec3(name string, value mgl32.Vec3) {
+	gl.ProgramUniform3f(p.ID, p.UniformLocation(name), value[0], value[1], value[2])
+}
+
+func (p *Program) SetVec4(name string, value mgl32.Vec4) {
+	gl.ProgramUniform4f(p.ID, p.UniformLocation(name), value[0], value[1], value[2], value[3])
+}
+
+func (p *Program) SetMat4(name string, value mgl32.Mat4) {
+	gl.ProgramUniformMatrix4fv(p.ID, p.UniformLocation(name), 1, false, &value[0])
+}
+
+// swaps the compiled program, deleting the previous one
+func (p *Program) swap(id uint32) {
+	if p.ID != 0 {
+		gl.DeleteProgram(p.ID)
+	}
+
+	p.ID = id
+	p.uniforms = map[string]int32{}
+}
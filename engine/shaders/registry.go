@@ -0,0 +1,216 @@
+package shaders
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Registry compiles named programs from "<name>_shader_vertex.glsl" and
+// "<name>_shader_fragment.glsl" files in Dir. Every OpenGL call happens in
+// Load and ReloadChanged, which must run on the thread owning the context;
+// Watch only polls the files.
+type Registry struct {
+	Dir          string
+	Defines      []Define // injetados em todos os programas, depois dos defines da variante
+	preprocessor *Preprocessor
+	programs     map[string]*registeredProgram
+	changed      map[string]bool
+	mutex        sync.Mutex
+	stop         chan struct{}
+}
+
+type registeredProgram struct {
+	program *Program
+	name    string
+	variant Variant
+	files   []string // arquivos lidos, incluindo os includes
+	modTime time.Time
+}
+
+func NewRegistry(dir string) *Registry {
+	return &Registry{
+		Dir:          dir,
+		Defines:      ObjectIDDefines(),
+		preprocessor: NewPreprocessor(dir),
+		programs:     map[string]*registeredProgram{},
+		changed:      map[string]bool{},
+	}
+}
+
+// Chave de um programa no registry: o nome, seguido dos defines da variante
+func programKey(name string, variant Variant) string {
+	if key := variant.Key(); key != "" {
+		return name + "#" + key
+	}
+
+	return name
+}
+
+// Load compiles the program with the given name, or returns it if it was already loaded
+func (r *Registry) Load(name string) (*Program, error) {
+	return r.LoadVariant(name, Variant{})
+}
+
+// LoadVariant compiles a permutation of a program with the variant defines
+func (r *Registry) LoadVariant(name string, variant Variant) (*Program, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := programKey(name, variant)
+	if registered, ok := r.programs[key]; ok {
+		return registered.program, nil
+	}
+
+	registered := &registeredProgram{
+		program: &Program{Name: name},
+		name:    name,
+		variant: variant,
+	}
+	if variant.Name != "" {
+		registered.program.Name = name + ":" + variant.Name
+	}
+
+	id, err := r.compile(registered)
+	if err != nil {
+		return nil, err
+	}
+
+	registered.program.swap(id)
+	registered.modTime = r.lastModified(registered.files)
+	r.programs[key] = registered
+
+	return registered.program, nil
+}
+
+// Get returns a loaded program, or nil if it was never loaded
+func (r *Registry) Get(name string) *Program {
+	return r.GetVariant(name, Variant{})
+}
+
+func (r *Registry) GetVariant(name string, variant Variant) *Program {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if registered, ok := r.programs[programKey(name, variant)]; ok {
+		return registered.program
+	}
+
+	return nil
+}
+
+func (r *Registry) compile(registered *registeredProgram) (uint32, error) {
+	defines := append(append([]Define{}, registered.variant.Defines...), r.Defines...)
+
+	vertexShaderSource, err := r.preprocessor.Process(vertexShaderFile(registered.name), defines)
+	if err != nil {
+		return 0, fmt.Errorf("shader %v: %v", registered.program.Name, err)
+	}
+
+	fragmentShaderSource, err := r.preprocessor.Process(fragmentShaderFile(registered.name), defines)
+	if err != nil {
+		return 0, fmt.Errorf("shader %v: %v", registered.program.Name, err)
+	}
+
+	registered.files = append(append([]string{}, vertexShaderSource.Files...), fragmentShaderSource.Files...)
+
+	id, err := linkProgram(vertexShaderSource, fragmentShaderSource)
+	if err != nil {
+		return 0, fmt.Errorf("shader %v: %v", registered.program.Name, err)
+	}
+
+	return id, nil
+}
+
+// Latest modification time between the files of a program
+func (r *Registry) lastModified(files []string) time.Time {
+	var latest time.Time
+	for _, file := range files {
+		info, err := os.Stat(filepath.Join(r.Dir, file))
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest
+}
+
+// Poll marks the programs whose files (or included files) changed since they were compiled
+func (r *Registry) Poll() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for key, registered := range r.programs {
+		modTime := r.lastModified(registered.files)
+		if modTime.After(registered.modTime) {
+			registered.modTime = modTime
+			r.changed[key] = true
+		}
+	}
+}
+
+// Watch polls the shader files every interval until StopWatching is called
+func (r *Registry) Watch(interval time.Duration) {
+	r.mutex.Lock()
+	if r.stop != nil {
+		r.mutex.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	r.stop = stop
+	r.mutex.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.Poll()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+func (r *Registry) StopWatching() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+}
+
+// ReloadChanged recompiles the programs marked by Poll. A program that fails
+// to compile keeps running the previous version and the error is returned.
+func (r *Registry) ReloadChanged() []error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	errs := []error{}
+	for key := range r.changed {
+		delete(r.changed, key)
+
+		registered := r.programs[key]
+		id, err := r.compile(registered)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		registered.program.swap(id)
+		log.Printf("shader %v reloaded", registered.program.Name)
+	}
+
+	return errs
+}
@@ -2,105 +2,30 @@ package shaders
 
 import (
 	"fmt"
-	"log"
-	"os"
 	"strings"
 
 	"github.com/go-gl/gl/v3.3-core/gl"
 )
 
-var (
-	ShaderProgramDefault   uint32
-	ShaderProgramCrosshair uint32
-	FragmentShader         string
-	VertexShader           string
-)
-
-// Loads a fragment shader
-func LoadFragmentShader(name string) (string, error) {
-	fragmentShaderFile := fmt.Sprintf(fmt.Sprintf("./%v_shader_fragment.glsl", name))
+// Programs is the registry used by the game, shaders are loaded from the working directory
+var Programs = NewRegistry(".")
 
-	fragmentShader, err := os.ReadFile(fragmentShaderFile)
-	if err != nil {
-		log.Println(err)
-		return "", err
-	}
-
-	return string(fragmentShader) + "\x00", nil
+// Get returns a program of the default registry
+func Get(name string) *Program {
+	return Programs.Get(name)
 }
 
-// Loads a vertex shader
-func LoadVertexShader(name string) (string, error) {
-	vertexShaderFile := fmt.Sprintf(fmt.Sprintf("./%v_shader_vertex.glsl", name))
-
-	vertexShader, err := os.ReadFile(vertexShaderFile)
-	if err != nil {
-		log.Println(err)
-		return "", err
-	}
-
-	return string(vertexShader) + "\x00", nil
+// Nomes dos arquivos de um shader, relativos ao diretório do Registry
+func vertexShaderFile(name string) string {
+	return fmt.Sprintf("%v_shader_vertex.glsl", name)
 }
 
-// compile standard shaders
-func InitStandardShaderPrograms(vertex, frag string) (uint32, error) {
-	vertexShaderSource, err := LoadVertexShader(vertex)
-	if err != nil {
-		return 0, err
-	}
-
-	fragmentShaderSource, err := LoadFragmentShader(frag)
-	if err != nil {
-		return 0, err
-	}
-
-	vertexShader, err := compileShader(vertexShaderSource, gl.VERTEX_SHADER)
-	if err != nil {
-		return 0, err
-	}
-
-	fragmentShader, err := compileShader(fragmentShaderSource, gl.FRAGMENT_SHADER)
-	if err != nil {
-		return 0, err
-	}
-
-	program := gl.CreateProgram()
-
-	gl.AttachShader(program, vertexShader)
-	gl.AttachShader(program, fragmentShader)
-	gl.LinkProgram(program)
-
-	var status int32
-	gl.GetProgramiv(program, gl.LINK_STATUS, &status)
-	if status == gl.FALSE {
-		var logLength int32
-		gl.GetProgramiv(program, gl.INFO_LOG_LENGTH, &logLength)
-
-		log := strings.Repeat("\x00", int(logLength+1))
-		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
-
-		return 0, fmt.Errorf("failed to link program: %v", log)
-	}
-
-	gl.DeleteShader(vertexShader)
-	gl.DeleteShader(fragmentShader)
-
-	ShaderProgramDefault = program
-	return program, nil
+func fragmentShaderFile(name string) string {
+	return fmt.Sprintf("%v_shader_fragment.glsl", name)
 }
 
-// compile shader
-func InitShaderProgram(name string) (uint32, error) {
-	vertexShaderSource, err := LoadVertexShader(name)
-	if err != nil {
-		return 0, err
-	}
-
-	fragmentShaderSource, err := LoadFragmentShader(name)
-	if err != nil {
-		return 0, err
-	}
-
+// compiles and links a program from its vertex and fragment shader sources
+func linkProgram(vertexShaderSource, fragmentShaderSource ProcessedSource) (uint32, error) {
 	vertexShader, err := compileShader(vertexShaderSource, gl.VERTEX_SHADER)
 	if err != nil {
 		return 0, err
@@ -108,6 +33,7 @@ func InitShaderProgram(name string) (uint32, error) {
 
 	fragmentShader, err := compileShader(fragmentShaderSource, gl.FRAGMENT_SHADER)
 	if err != nil {
+		gl.DeleteShader(vertexShader)
 		return 0, err
 	}
 
@@ -117,53 +43,9 @@ func InitShaderProgram(name string) (uint32, error) {
 	gl.AttachShader(program, fragmentShader)
 	gl.LinkProgram(program)
 
-	var status int32
-	gl.GetProgramiv(program, gl.LINK_STATUS, &status)
-	if status == gl.FALSE {
-		var logLength int32
-		gl.GetProgramiv(program, gl.INFO_LOG_LENGTH, &logLength)
-
-		log := strings.Repeat("\x00", int(logLength+1))
-		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
-
-		return 0, fmt.Errorf("failed to link program: %v", log)
-	}
-
 	gl.DeleteShader(vertexShader)
 	gl.DeleteShader(fragmentShader)
 
-	ShaderProgramDefault = program
-	return program, nil
-}
-
-// compile alternative shader program
-func InitShaderProgram2(name string) (uint32, error) {
-	vertexShaderSource, err := LoadVertexShader(name)
-	if err != nil {
-		return 0, err
-	}
-
-	fragmentShaderSource, err := LoadFragmentShader(name)
-	if err != nil {
-		return 0, err
-	}
-
-	vertexShader, err := compileShader(vertexShaderSource, gl.VERTEX_SHADER)
-	if err != nil {
-		return 0, err
-	}
-
-	fragmentShader, err := compileShader(fragmentShaderSource, gl.FRAGMENT_SHADER)
-	if err != nil {
-		return 0, err
-	}
-
-	program := gl.CreateProgram()
-
-	gl.AttachShader(program, vertexShader)
-	gl.AttachShader(program, fragmentShader)
-	gl.LinkProgram(program)
-
 	var status int32
 	gl.GetProgramiv(program, gl.LINK_STATUS, &status)
 	if status == gl.FALSE {
@@ -172,22 +54,19 @@ func InitShaderProgram2(name string) (uint32, error) {
 
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
+		gl.DeleteProgram(program)
 
 		return 0, fmt.Errorf("failed to link program: %v", log)
 	}
 
-	gl.DeleteShader(vertexShader)
-	gl.DeleteShader(fragmentShader)
-
-	ShaderProgramCrosshair = program
 	return program, nil
 }
 
 // compiles the shader with gl calls
-func compileShader(source string, shaderType uint32) (uint32, error) {
+func compileShader(source ProcessedSource, shaderType uint32) (uint32, error) {
 	shader := gl.CreateShader(shaderType)
 
-	csources, free := gl.Strs(source)
+	csources, free := gl.Strs(source.Code + "\x00")
 	gl.ShaderSource(shader, 1, csources, nil)
 	free()
 	gl.CompileShader(shader)
@@ -200,8 +79,9 @@ func compileShader(source string, shaderType uint32) (uint32, error) {
 
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
+		gl.DeleteShader(shader)
 
-		return 0, fmt.Errorf("failed to compile %v: %v", source, log)
+		return 0, fmt.Errorf("failed to compile %v:\n%v", source.File, source.MapError(log))
 	}
 
 	return shader, nil
@@ -0,0 +1,154 @@
+package shadows
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
+	math2 "github.com/reonardoleis/fcg-glcraft/math"
+)
+
+// MaxCascades is the size of the cascade uniform arrays of the shaders
+const MaxCascades = 4
+
+// TextureUnit is the texture unit the shadow map is bound to, above the units
+// used by materials
+const TextureUnit = 7
+
+// Quanto a caixa de cada cascata se estende na direção do sol, para incluir
+// objetos fora da fatia que projetam sombra dentro dela
+const depthMargin float32 = 64
+
+// Defines returns the "#define"s used by the shaders that sample the shadow map
+func Defines() []shaders.Define {
+	return []shaders.Define{
+		{Name: "MAX_SHADOW_CASCADES", Value: fmt.Sprint(MaxCascades)},
+		{Name: "SHADOW_TEXTURE_UNIT", Value: fmt.Sprint(TextureUnit)},
+	}
+}
+
+// CascadedShadowMap renders the scene depth from the sun once per cascade.
+// Each cascade covers a slice of the camera frustum, closer slices being
+// smaller so shadows near the camera get more texels. Depths are stored in
+// the layers of a single texture array.
+type CascadedShadowMap struct {
+	Cascades      int
+	Resolution    int32
+	Bias          float32
+	Splits        []float32    // distâncias da câmera onde cada cascata termina
+	LightMatrices []mgl32.Mat4 // projeção * view do sol, por cascata
+	Enabled       bool         // falso quando o sol está abaixo do horizonte
+
+	framebuffer  uint32
+	depthTexture uint32
+}
+
+// NewCascadedShadowMap creates the depth texture array and framebuffer. It
+// must be called after the OpenGL context is created.
+func NewCascadedShadowMap(cascades int, resolution int32, bias float32) *CascadedShadowMap {
+	if cascades < 1 {
+		cascades = 1
+	}
+	if cascades > MaxCascades {
+		cascades = MaxCascades
+	}
+
+	s := &CascadedShadowMap{
+		Cascades:      cascades,
+		Resolution:    resolution,
+		Bias:          bias,
+		LightMatrices: make([]mgl32.Mat4, cascades),
+	}
+
+	gl.GenTextures(1, &s.depthTexture)
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, s.depthTexture)
+	gl.TexImage3D(gl.TEXTURE_2D_ARRAY, 0, gl.DEPTH_COMPONENT32F, resolution, resolution, int32(cascades), 0, gl.DEPTH_COMPONENT, gl.FLOAT, nil)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	// fora do mapa não há sombra
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_BORDER)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_BORDER)
+	border := []float32{1, 1, 1, 1}
+	gl.TexParameterfv(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_BORDER_COLOR, &border[0])
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, 0)
+
+	gl.GenFramebuffers(1, &s.framebuffer)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, s.framebuffer)
+	gl.FramebufferTextureLayer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, s.depthTexture, 0, 0)
+	gl.DrawBuffer(gl.NONE)
+	gl.ReadBuffer(gl.NONE)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	return s
+}
+
+// NewFromConfig creates a shadow map with the settings of the configs package
+func NewFromConfig() *CascadedShadowMap {
+	return NewCascadedShadowMap(configs.Video.ShadowCascades, configs.Video.ShadowMapResolution, configs.Video.ShadowBias)
+}
+
+// Update fits the cascades to the camera frustum, up to configs.Video.ShadowDistance,
+// for a sun in sunDirection (pointing from the ground to the sun)
+func (s *CascadedShadowMap) Update(view mgl32.Mat4, fov, aspect, near float32, sunDirection mgl32.Vec3) {
+	s.Enabled = sunDirection.Y() > 0
+	s.Splits = math2.CascadeSplits(near, configs.Video.ShadowDistance, s.Cascades, configs.Video.ShadowSplitLambda)[1:]
+
+	lightDirection := sunDirection.Mul(-1)
+	from := near
+	for cascade, to := range s.Splits {
+		corners := math2.FrustumSliceCorners(view, fov, aspect, from, to)
+		s.LightMatrices[cascade] = math2.LightMatrix(lightDirection, corners, int(s.Resolution), depthMargin)
+		from = to
+	}
+}
+
+// Render draws the depth of every cascade. drawCasters must draw the objects
+// that cast shadows with the given program, which already has "light_space"
+// set; it only needs to set "model".
+func (s *CascadedShadowMap) Render(program *shaders.Program, drawCasters func(program *shaders.Program)) {
+	if !s.Enabled {
+		return
+	}
+
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, s.framebuffer)
+	gl.Viewport(0, 0, s.Resolution, s.Resolution)
+	program.Use()
+
+	for cascade := 0; cascade < s.Cascades; cascade++ {
+		gl.FramebufferTextureLayer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, s.depthTexture, 0, int32(cascade))
+		gl.Clear(gl.DEPTH_BUFFER_BIT)
+		program.SetMat4("light_space", s.LightMatrices[cascade])
+		drawCasters(program)
+	}
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+}
+
+// Bind makes the shadow map available to a program that samples it
+func (s *CascadedShadowMap) Bind(program *shaders.Program, sunDirection mgl32.Vec3) {
+	gl.ActiveTexture(gl.TEXTURE0 + TextureUnit)
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, s.depthTexture)
+	gl.ActiveTexture(gl.TEXTURE0)
+
+	program.SetInt("shadow_map", TextureUnit)
+	program.SetBool("shadows_enabled", s.Enabled)
+	program.SetInt("shadow_cascades", int32(s.Cascades))
+	program.SetFloat("shadow_bias", s.Bias)
+	program.SetVec4("sun_direction", sunDirection.Normalize().Vec4(0))
+	for cascade := 0; cascade < s.Cascades; cascade++ {
+		program.SetFloat(fmt.Sprintf("shadow_splits[%d]", cascade), s.Splits[cascade])
+		program.SetMat4(fmt.Sprintf("light_space[%d]", cascade), s.LightMatrices[cascade])
+	}
+}
+
+// Destroy frees the depth texture and the framebuffer
+func (s *CascadedShadowMap) Destroy() {
+	gl.DeleteFramebuffers(1, &s.framebuffer)
+	gl.DeleteTextures(1, &s.depthTexture)
+}
@@ -0,0 +1,53 @@
+package sky
+
+import (
+	"math/rand"
+
+	"github.com/tbogdala/noisey"
+)
+
+// GenerateClouds returns a size x size cloud opacity map (one byte per texel)
+// from fractal noise. coverage in [0, 1] is the fraction of the sky covered.
+// The map tiles seamlessly, so the cloud layer can repeat and scroll forever.
+func GenerateClouds(size int, seed int64, coverage float64) []uint8 {
+	source := noisey.NewOpenSimplexGenerator(rand.New(rand.NewSource(seed)))
+	fbm := noisey.NewFBMGenerator2D(&source, 4, 0.5, 2.0, 4.0/float64(size))
+
+	clouds := make([]uint8, size*size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			clouds[y*size+x] = uint8(cloudDensity(&fbm, x, y, size, coverage) * 255)
+		}
+	}
+
+	return clouds
+}
+
+// Densidade em [0, 1] da nuvem no texel (x, y). Mistura quatro amostras
+// deslocadas de um período, então x = size dá o mesmo que x = 0, e o mesmo
+// em y, e a textura repete sem emendas.
+func cloudDensity(fbm *noisey.FBMGenerator2D, x, y, size int, coverage float64) float64 {
+	const amplitude = 1 + 0.5 + 0.25 + 0.125 // soma das amplitudes das oitavas
+
+	u := float64(x) / float64(size)
+	v := float64(y) / float64(size)
+	fx, fy := float64(x), float64(y)
+	period := float64(size)
+	value := fbm.Get2D(fx, fy)*(1-u)*(1-v) +
+		fbm.Get2D(fx-period, fy)*u*(1-v) +
+		fbm.Get2D(fx, fy-period)*(1-u)*v +
+		fbm.Get2D(fx-period, fy-period)*u*v
+	value /= amplitude
+
+	// value está em [-1, 1]; o limiar define a cobertura
+	density := (value+1)/2 - (1 - coverage)
+	if density <= 0 {
+		return 0
+	}
+	density = density / coverage * 2.5
+	if density > 1 {
+		density = 1
+	}
+
+	return density
+}
@@ -0,0 +1,65 @@
+package sky
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Palette holds the colors of the sky at some moment of the day
+type Palette struct {
+	Zenith  mgl32.Vec3 // cor no topo do céu
+	Horizon mgl32.Vec3 // cor no horizonte, também usada pela névoa
+}
+
+// Cores do céu pela altura do sol (componente y da direção do sol), em ordem crescente
+var paletteKeys = []struct {
+	sunHeight float32
+	palette   Palette
+}{
+	{-0.3, Palette{Zenith: mgl32.Vec3{0.01, 0.01, 0.05}, Horizon: mgl32.Vec3{0.04, 0.05, 0.12}}}, // noite
+	{0.0, Palette{Zenith: mgl32.Vec3{0.22, 0.3, 0.55}, Horizon: mgl32.Vec3{0.95, 0.55, 0.3}}},    // nascer/pôr do sol
+	{0.3, Palette{Zenith: mgl32.Vec3{0.3, 0.55, 0.95}, Horizon: mgl32.Vec3{0.47, 0.65, 1.0}}},    // dia
+}
+
+// PaletteAt interpolates the sky colors for a sun at the given height, from
+// -1 (below the ground) to 1 (overhead)
+func PaletteAt(sunHeight float32) Palette {
+	if sunHeight <= paletteKeys[0].sunHeight {
+		return paletteKeys[0].palette
+	}
+
+	for i := 1; i < len(paletteKeys); i++ {
+		to := paletteKeys[i]
+		if sunHeight > to.sunHeight {
+			continue
+		}
+
+		from := paletteKeys[i-1]
+		t := (sunHeight - from.sunHeight) / (to.sunHeight - from.sunHeight)
+		return Palette{
+			Zenith:  lerp(from.palette.Zenith, to.palette.Zenith, t),
+			Horizon: lerp(from.palette.Horizon, to.palette.Horizon, t),
+		}
+	}
+
+	return paletteKeys[len(paletteKeys)-1].palette
+}
+
+// NightFactor is 0 during the day and 1 at night, fading around sunset
+func NightFactor(sunHeight float32) float32 {
+	return clamp(-sunHeight*4, 0, 1)
+}
+
+func lerp(a, b mgl32.Vec3, t float32) mgl32.Vec3 {
+	return a.Add(b.Sub(a).Mul(t))
+}
+
+func clamp(value, min, max float32) float32 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+
+	return value
+}
@@ -0,0 +1,135 @@
+package sky
+
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
+	"github.com/reonardoleis/fcg-glcraft/geometry"
+	math2 "github.com/reonardoleis/fcg-glcraft/math"
+)
+
+// Configuração do céu
+const (
+	CelestialDistance float32 = 100 // distância da câmera aos billboards do sol e da lua
+	SunSize           float32 = 16
+	MoonSize          float32 = 10
+	CloudHeight       float32 = 96
+	CloudRadius       float32 = 400 // raio da camada de nuvens ao redor da câmera
+	CloudScale        float32 = 256 // unidades do mundo por repetição da textura de nuvens
+	CloudTextureSize  int     = 256
+	CloudCoverage     float64 = 0.45
+)
+
+// Velocidade do vento que move as nuvens, em unidades do mundo por segundo (x, z)
+var WindVelocity = mgl32.Vec2{1.5, 0.6}
+
+// Sky draws the sky gradient with stars, the sun and moon billboards and a
+// scrolling cloud layer, all behind the world
+type Sky struct {
+	Program      *shaders.Program
+	Palette      Palette
+	SunDirection mgl32.Vec3
+	Night        float32
+
+	quad         *geometry.Mesh
+	dome         *geometry.Material
+	sun          *geometry.Material
+	moon         *geometry.Material
+	clouds       *geometry.Material
+	cloudTexture uint32
+	cloudOffset  mgl32.Vec2
+}
+
+// NewSky uploads the sky meshes and the cloud texture generated from seed. It
+// must be called after the OpenGL context is created.
+func NewSky(program *shaders.Program, seed int64) *Sky {
+	s := &Sky{
+		Program: program,
+		quad:    geometry.NewMesh(geometry.BuildFace(), nil),
+	}
+
+	cloudMap := GenerateClouds(CloudTextureSize, seed, CloudCoverage)
+	gl.GenTextures(1, &s.cloudTexture)
+	gl.BindTexture(gl.TEXTURE_2D, s.cloudTexture)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.R8, int32(CloudTextureSize), int32(CloudTextureSize), 0, gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(cloudMap))
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.REPEAT)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.REPEAT)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	s.dome = geometry.NewMaterial(program, shaders.ObjectSkyDome)
+	s.sun = geometry.NewMaterial(program, shaders.ObjectSun)
+	s.moon = geometry.NewMaterial(program, shaders.ObjectMoon)
+	s.clouds = geometry.NewMaterial(program, shaders.ObjectClouds, s.cloudTexture)
+
+	return s
+}
+
+// Update sets the sky for the current sun direction and scrolls the clouds
+func (s *Sky) Update(sunDirection mgl32.Vec3, deltaTime float64) {
+	s.SunDirection = sunDirection.Normalize()
+	s.Palette = PaletteAt(s.SunDirection.Y())
+	s.Night = NightFactor(s.SunDirection.Y())
+
+	s.cloudOffset = s.cloudOffset.Add(WindVelocity.Mul(float32(deltaTime)))
+	// a textura repete, então o deslocamento pode voltar sem saltos
+	for axis := 0; axis < 2; axis++ {
+		if s.cloudOffset[axis] > CloudScale {
+			s.cloudOffset[axis] -= CloudScale
+		}
+	}
+}
+
+// Render draws the sky. It must be called right after clearing the screen,
+// the world is drawn over it.
+func (s *Sky) Render(cameraPosition mgl32.Vec3, view, projection mgl32.Mat4) {
+	s.Program.SetMat4("view", view)
+	s.Program.SetMat4("projection", projection)
+	s.Program.SetVec3("sky_zenith", s.Palette.Zenith)
+	s.Program.SetVec3("sky_horizon", s.Palette.Horizon)
+	s.Program.SetVec4("sun_direction", s.SunDirection.Vec4(0))
+	s.Program.SetFloat("night_factor", s.Night)
+	s.Program.SetVec2("cloud_offset", s.cloudOffset)
+	s.Program.SetFloat("cloud_scale", CloudScale)
+	s.Program.SetFloat("cloud_radius", CloudRadius)
+	s.Program.SetVec4("camera_position", cameraPosition.Vec4(1))
+
+	// tudo no céu fica "no infinito": sem teste nem escrita de profundidade
+	gl.Disable(gl.DEPTH_TEST)
+	gl.DepthMask(false)
+
+	s.quad.DrawWithMaterial(s.dome, math2.Matrix_Scale(2, 2, 1))
+	s.quad.DrawWithMaterial(s.sun, billboard(cameraPosition, s.SunDirection, SunSize))
+	s.quad.DrawWithMaterial(s.moon, billboard(cameraPosition, s.SunDirection.Mul(-1), MoonSize))
+
+	clouds := math2.Matrix_Translate(cameraPosition.X(), CloudHeight, cameraPosition.Z()).
+		Mul4(math2.Matrix_Rotate_X(mgl32.DegToRad(90))).
+		Mul4(math2.Matrix_Scale(2*CloudRadius, 2*CloudRadius, 1))
+	s.quad.DrawWithMaterial(s.clouds, clouds)
+
+	gl.DepthMask(true)
+	gl.Enable(gl.DEPTH_TEST)
+}
+
+// Quadrado de lado size na direção direction a partir da câmera, virado para ela
+func billboard(cameraPosition, direction mgl32.Vec3, size float32) mgl32.Mat4 {
+	up := mgl32.Vec3{0, 1, 0}
+	if direction.Cross(up).Len() < 0.01 {
+		up = mgl32.Vec3{1, 0, 0}
+	}
+	right := up.Cross(direction).Normalize()
+	up = direction.Cross(right)
+	position := cameraPosition.Add(direction.Mul(CelestialDistance))
+
+	return mgl32.Mat4FromCols(right.Vec4(0), up.Vec4(0), direction.Vec4(0), position.Vec4(1)).
+		Mul4(math2.Matrix_Scale(size, size, 1))
+}
+
+// Destroy frees the sky mesh and the cloud texture
+func (s *Sky) Destroy() {
+	s.quad.Destroy()
+	gl.DeleteTextures(1, &s.cloudTexture)
+}
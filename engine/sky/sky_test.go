@@ -0,0 +1,123 @@
+package sky
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/tbogdala/noisey"
+)
+
+func TestPaletteAt(t *testing.T) {
+	night, sunset, day := paletteKeys[0].palette, paletteKeys[1].palette, paletteKeys[2].palette
+
+	tests := []struct {
+		name      string
+		sunHeight float32
+		want      Palette
+	}{
+		{"sol embaixo da terra", -1, night},
+		{"noite", -0.3, night},
+		{"pôr do sol", 0, sunset},
+		{"dia", 0.3, day},
+		{"meio-dia", 1, day},
+		{"entre noite e pôr do sol", -0.15, Palette{lerp(night.Zenith, sunset.Zenith, 0.5), lerp(night.Horizon, sunset.Horizon, 0.5)}},
+		{"entre pôr do sol e dia", 0.075, Palette{lerp(sunset.Zenith, day.Zenith, 0.25), lerp(sunset.Horizon, day.Horizon, 0.25)}},
+	}
+
+	for _, test := range tests {
+		got := PaletteAt(test.sunHeight)
+		if !got.Zenith.ApproxEqual(test.want.Zenith) || !got.Horizon.ApproxEqual(test.want.Horizon) {
+			t.Errorf("%s: palette at %v = %v, want %v", test.name, test.sunHeight, got, test.want)
+		}
+	}
+
+	// as cores mudam sem saltos
+	previous := PaletteAt(-1)
+	for height := float32(-1); height <= 1; height += 0.01 {
+		current := PaletteAt(height)
+		if current.Zenith.Sub(previous.Zenith).Len() > 0.05 || current.Horizon.Sub(previous.Horizon).Len() > 0.05 {
+			t.Errorf("palette jumps at %v: %v -> %v", height, previous, current)
+		}
+		previous = current
+	}
+}
+
+func TestNightFactor(t *testing.T) {
+	tests := []struct {
+		name      string
+		sunHeight float32
+		want      float32
+	}{
+		{"meio-dia", 1, 0},
+		{"pôr do sol", 0, 0},
+		{"crepúsculo", -0.125, 0.5},
+		{"noite", -0.25, 1},
+		{"meia-noite", -1, 1},
+	}
+
+	for _, test := range tests {
+		if got := NightFactor(test.sunHeight); mgl32.Abs(got-test.want) > 1e-6 {
+			t.Errorf("%s: night factor at %v = %v, want %v", test.name, test.sunHeight, got, test.want)
+		}
+	}
+}
+
+func TestCloudsTile(t *testing.T) {
+	const size = 64
+	source := noisey.NewOpenSimplexGenerator(rand.New(rand.NewSource(3)))
+	fbm := noisey.NewFBMGenerator2D(&source, 4, 0.5, 2.0, 4.0/size)
+
+	// o texel logo depois da borda é o da borda oposta
+	for i := 0; i <= size; i++ {
+		if a, b := cloudDensity(&fbm, size, i, size, 0.5), cloudDensity(&fbm, 0, i, size, 0.5); math.Abs(a-b) > 1e-9 {
+			t.Errorf("column %d: x = size gives %v, x = 0 gives %v", i, a, b)
+		}
+		if a, b := cloudDensity(&fbm, i, size, size, 0.5), cloudDensity(&fbm, i, 0, size, 0.5); math.Abs(a-b) > 1e-9 {
+			t.Errorf("row %d: y = size gives %v, y = 0 gives %v", i, a, b)
+		}
+	}
+
+	// na textura, as bordas opostas variam tanto quanto os texels vizinhos
+	// perto delas
+	clouds := GenerateClouds(size, 3, 0.5)
+	var inside, across float64
+	for i := 0; i < size; i++ {
+		inside += math.Abs(float64(clouds[i*size+1]) - float64(clouds[i*size]))
+		across += math.Abs(float64(clouds[i*size]) - float64(clouds[i*size+size-1]))
+		inside += math.Abs(float64(clouds[size+i]) - float64(clouds[i]))
+		across += math.Abs(float64(clouds[i]) - float64(clouds[(size-1)*size+i]))
+	}
+	if across > 2*inside+size {
+		t.Errorf("opposite edges differ by %v in total, neighbor texels by %v", across, inside)
+	}
+}
+
+func TestCloudsCoverage(t *testing.T) {
+	tests := []struct {
+		name     string
+		coverage float64
+	}{
+		{"pouco", 0.2},
+		{"metade", 0.5},
+		{"muito", 0.8},
+	}
+
+	previous := -1.0
+	for _, test := range tests {
+		clouds := GenerateClouds(64, 7, test.coverage)
+		covered := 0
+		for _, value := range clouds {
+			if value > 0 {
+				covered++
+			}
+		}
+
+		fraction := float64(covered) / float64(len(clouds))
+		if fraction <= previous {
+			t.Errorf("%s: covers %v of the sky, not more than the lower coverage (%v)", test.name, fraction, previous)
+		}
+		previous = fraction
+	}
+}
@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/engine/metrics"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
+	math2 "github.com/reonardoleis/fcg-glcraft/math"
+)
+
+// TextureKind tells the UI shader how to read a texture
+type TextureKind int32
+
+const (
+	TextureColor TextureKind = iota // RGBA comum
+	TextureSRGB                     // RGBA em sRGB, como as texturas dos blocos
+	TextureMask                     // só o canal vermelho, usado como alfa (fontes)
+)
+
+// Texture is an OpenGL texture drawn by the batch
+type Texture struct {
+	ID   uint32
+	Kind TextureKind
+}
+
+// Posição, coordenada de textura e cor de cada vértice
+const (
+	vertexFloats = 8
+	maxQuads     = 2048
+)
+
+// Batch draws textured and colored quads in screen pixels, grouping
+// consecutive quads with the same texture in a single draw call
+type Batch struct {
+	Program *shaders.Program
+
+	vao      uint32
+	vbo      uint32
+	vertices []float32
+	texture  Texture
+	white    Texture
+	fonts    map[*Font]Texture
+	screen   Screen
+}
+
+// NewBatch creates the vertex buffer of the batch. It must be called after
+// the OpenGL context is created.
+func NewBatch(program *shaders.Program) *Batch {
+	b := &Batch{
+		Program:  program,
+		vertices: make([]float32, 0, maxQuads*6*vertexFloats),
+		fonts:    make(map[*Font]Texture),
+	}
+
+	gl.GenVertexArrays(1, &b.vao)
+	gl.BindVertexArray(b.vao)
+	gl.GenBuffers(1, &b.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, cap(b.vertices)*4, nil, gl.DYNAMIC_DRAW)
+
+	stride := int32(vertexFloats * 4)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, stride, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, stride, gl.PtrOffset(2*4))
+	gl.EnableVertexAttribArray(1)
+	gl.VertexAttribPointer(2, 4, gl.FLOAT, false, stride, gl.PtrOffset(4*4))
+	gl.EnableVertexAttribArray(2)
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindVertexArray(0)
+
+	// quads sem textura usam um texel branco
+	b.white = Texture{Kind: TextureColor}
+	white := []uint8{255, 255, 255, 255}
+	gl.GenTextures(1, &b.white.ID)
+	gl.BindTexture(gl.TEXTURE_2D, b.white.ID)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, 1, 1, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(white))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	return b
+}
+
+// Begin starts drawing on screen, over whatever was drawn before
+func (b *Batch) Begin(screen Screen) {
+	b.screen = screen
+	b.vertices = b.vertices[:0]
+	b.texture = b.white
+
+	b.Program.Use()
+	b.Program.SetMat4("projection", math2.Matrix_Orthographic(0, screen.Width, screen.Height, 0, 1, -1))
+	b.Program.SetInt("ui_texture", 0)
+
+	gl.Disable(gl.DEPTH_TEST)
+	gl.DepthMask(false)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+}
+
+// End draws the pending quads and restores the 3D state
+func (b *Batch) End() {
+	b.flush()
+	b.SetClip(nil)
+	gl.DepthMask(true)
+	gl.Enable(gl.DEPTH_TEST)
+}
+
+// SetClip restricts drawing to clip, or removes the restriction if nil
+func (b *Batch) SetClip(clip *Rect) {
+	b.flush()
+	if clip == nil {
+		gl.Disable(gl.SCISSOR_TEST)
+		return
+	}
+
+	// o scissor do OpenGL é em pixels e tem origem embaixo
+	scale := b.screen.Scale
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(int32(clip.X*scale), int32((b.screen.Height-clip.Y-clip.H)*scale), int32(clip.W*scale), int32(clip.H*scale))
+}
+
+// Fill draws a solid rectangle
+func (b *Batch) Fill(r Rect, color mgl32.Vec4) {
+	b.Image(r, Rect{0, 0, 1, 1}, b.white, color)
+}
+
+// Border draws the outline of a rectangle, width pixels thick
+func (b *Batch) Border(r Rect, width float32, color mgl32.Vec4) {
+	b.Fill(Rect{r.X, r.Y, r.W, width}, color)
+	b.Fill(Rect{r.X, r.Y + r.H - width, r.W, width}, color)
+	b.Fill(Rect{r.X, r.Y + width, width, r.H - 2*width}, color)
+	b.Fill(Rect{r.X + r.W - width, r.Y + width, width, r.H - 2*width}, color)
+}
+
+// Image draws the uv part (in [0, 1] texture coordinates, origin at the top
+// left) of texture on r, multiplied by color
+func (b *Batch) Image(r Rect, uv Rect, texture Texture, color mgl32.Vec4) {
+	if texture != b.texture || len(b.vertices)+6*vertexFloats > cap(b.vertices) {
+		b.flush()
+		b.texture = texture
+	}
+
+	x0, y0, x1, y1 := r.X, r.Y, r.X+r.W, r.Y+r.H
+	u0, v0, u1, v1 := uv.X, uv.Y, uv.X+uv.W, uv.Y+uv.H
+	red, green, blue, alpha := color.Elem()
+	b.vertices = append(b.vertices,
+		x0, y0, u0, v0, red, green, blue, alpha,
+		x0, y1, u0, v1, red, green, blue, alpha,
+		x1, y1, u1, v1, red, green, blue, alpha,
+		x1, y1, u1, v1, red, green, blue, alpha,
+		x1, y0, u1, v0, red, green, blue, alpha,
+		x0, y0, u0, v0, red, green, blue, alpha,
+	)
+}
+
+// Text draws text with its top left at (x, y), scaled by scale
+func (b *Batch) Text(font *Font, text string, x, y, scale float32, color mgl32.Vec4) {
+	atlas := b.fontTexture(font)
+	size := font.Atlas.Bounds().Size()
+	atlasW, atlasH := float32(size.X), float32(size.Y)
+
+	font.Layout(text, func(r rune, glyph Glyph, gx, gy int) {
+		if glyph.W == 0 || glyph.H == 0 {
+			return
+		}
+		b.Image(
+			Rect{x + float32(gx)*scale, y + float32(gy)*scale, float32(glyph.W) * scale, float32(glyph.H) * scale},
+			Rect{float32(glyph.X) / atlasW, float32(glyph.Y) / atlasH, float32(glyph.W) / atlasW, float32(glyph.H) / atlasH},
+			atlas, color,
+		)
+	})
+}
+
+// Envia o atlas da fonte na primeira vez que ela é desenhada
+func (b *Batch) fontTexture(font *Font) Texture {
+	if texture, ok := b.fonts[font]; ok {
+		return texture
+	}
+
+	size := font.Atlas.Bounds().Size()
+	texture := Texture{Kind: TextureMask}
+	gl.GenTextures(1, &texture.ID)
+	gl.BindTexture(gl.TEXTURE_2D, texture.ID)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.R8, int32(size.X), int32(size.Y), 0, gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(font.Atlas.Pix))
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	// bitmap: sem filtragem, os pixels da fonte ficam nítidos
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	b.fonts[font] = texture
+	return texture
+}
+
+func (b *Batch) flush() {
+	if len(b.vertices) == 0 {
+		return
+	}
+
+	b.Program.Use()
+	b.Program.SetInt("texture_kind", int32(b.texture.Kind))
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, b.texture.ID)
+
+	gl.BindVertexArray(b.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(b.vertices)*4, gl.Ptr(b.vertices))
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(b.vertices)/vertexFloats))
+	metrics.DrawCall(len(b.vertices) / vertexFloats / 3)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindVertexArray(0)
+
+	b.vertices = b.vertices[:0]
+}
+
+// Destroy frees the buffers and the uploaded textures
+func (b *Batch) Destroy() {
+	gl.DeleteBuffers(1, &b.vbo)
+	gl.DeleteVertexArrays(1, &b.vao)
+	gl.DeleteTextures(1, &b.white.ID)
+	for font, texture := range b.fonts {
+		gl.DeleteTextures(1, &texture.ID)
+		delete(b.fonts, font)
+	}
+}
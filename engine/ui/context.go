@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/engine/controls"
+)
+
+// Context is the root of an interface: it owns the widget tree, routes the
+// input to it and tracks which widget has the keyboard focus and which one
+// is being dragged
+type Context struct {
+	Root   *Panel
+	Font   *Font
+	Screen Screen
+
+	focused   Focusable
+	captured  Widget
+	mouseX    float32
+	mouseY    float32
+	mouseDown bool
+}
+
+func NewContext(font *Font, screen Screen) *Context {
+	return &Context{
+		Root:   NewPanel(TopLeft, mgl32.Vec2{}, mgl32.Vec2{screen.Width, screen.Height}, mgl32.Vec4{}),
+		Font:   font,
+		Screen: screen,
+	}
+}
+
+// Resize changes the screen the interface is laid out in
+func (c *Context) Resize(screen Screen) {
+	c.Screen = screen
+	c.Root.Size = mgl32.Vec2{screen.Width, screen.Height}
+}
+
+// Focus gives the keyboard focus to widget, or removes it if nil
+func (c *Context) Focus(widget Focusable) {
+	if c.focused == widget {
+		return
+	}
+	if c.focused != nil {
+		c.focused.SetFocused(false)
+	}
+
+	c.focused = widget
+	if widget != nil {
+		widget.SetFocused(true)
+	}
+}
+
+// Focused returns the widget with the keyboard focus, if any
+func (c *Context) Focused() Focusable {
+	return c.focused
+}
+
+// Capture sends the mouse events to widget until the button is released,
+// even outside of it, e.g. while dragging a slider
+func (c *Context) Capture(widget Widget) {
+	c.captured = widget
+}
+
+// Dispatch routes an event: mouse events to the widget under the cursor (or
+// the captured one) and keyboard events to the focused widget. It returns
+// true when a widget used the event.
+func (c *Context) Dispatch(event Event) bool {
+	c.Layout()
+
+	switch event.Type {
+	case MouseMove, MouseUp:
+		handled := false
+		if c.captured != nil {
+			handled = c.captured.HandleEvent(c, event)
+			if event.Type == MouseUp {
+				c.captured = nil
+			}
+		}
+		if event.Type == MouseMove {
+			// os outros widgets ainda atualizam o hover
+			handled = c.Root.HandleEvent(c, event) || handled
+		}
+		return handled
+
+	case MouseDown:
+		// clicar fora do widget focado tira o foco dele
+		if c.focused != nil && !c.focused.Bounds().Contains(event.X, event.Y) {
+			c.Focus(nil)
+		}
+		return c.Root.HandleEvent(c, event)
+
+	default:
+		if c.focused == nil {
+			return false
+		}
+		return c.focused.HandleEvent(c, event)
+	}
+}
+
+// Update reads the mouse and keyboard from controls and dispatches the events
+func (c *Context) Update(controlHandler controls.Controls) {
+	x, y := c.Screen.cursor(controlHandler.GetCursorPosition())
+	if x != c.mouseX || y != c.mouseY {
+		c.mouseX, c.mouseY = x, y
+		c.Dispatch(Event{Type: MouseMove, X: c.mouseX, Y: c.mouseY})
+	}
+
+	down := controlHandler.IsDown(int(glfw.MouseButtonLeft))
+	if down != c.mouseDown {
+		c.mouseDown = down
+		eventType := MouseUp
+		if down {
+			eventType = MouseDown
+		}
+		c.Dispatch(Event{Type: eventType, X: c.mouseX, Y: c.mouseY})
+	}
+
+	for _, key := range controlHandler.ConsumePressedKeys() {
+		if uiKey := keyFromGLFW(key); uiKey != KeyUnknown {
+			c.Dispatch(Event{Type: KeyPress, Key: uiKey})
+		}
+	}
+	for _, char := range controlHandler.ConsumeTypedChars() {
+		c.Dispatch(Event{Type: CharTyped, Char: char})
+	}
+}
+
+// Layout places every widget on the screen
+func (c *Context) Layout() {
+	c.Root.Layout(c, Rect{0, 0, c.Screen.Width, c.Screen.Height})
+}
+
+// Draw lays out and draws the interface over the screen
+func (c *Context) Draw(batch *Batch) {
+	c.Layout()
+
+	batch.Begin(c.Screen)
+	c.Root.Draw(c, batch)
+	batch.End()
+}
+
+func keyFromGLFW(key glfw.Key) Key {
+	switch key {
+	case glfw.KeyBackspace:
+		return KeyBackspace
+	case glfw.KeyDelete:
+		return KeyDelete
+	case glfw.KeyEnter, glfw.KeyKPEnter:
+		return KeyEnter
+	case glfw.KeyLeft:
+		return KeyLeft
+	case glfw.KeyRight:
+		return KeyRight
+	case glfw.KeyUp:
+		return KeyUp
+	case glfw.KeyDown:
+		return KeyDown
+	case glfw.KeyHome:
+		return KeyHome
+	case glfw.KeyEnd:
+		return KeyEnd
+	case glfw.KeyTab:
+		return KeyTab
+	}
+
+	return KeyUnknown
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Largura do atlas e espaço entre os glifos, em pixels
+const (
+	atlasWidth   = 256
+	glyphPadding = 1
+)
+
+// Glyph is where a character is in the atlas and how it is placed on a line
+type Glyph struct {
+	X, Y, W, H int // retângulo no atlas
+	OffsetX    int // do ponto de origem até o canto superior esquerdo
+	OffsetY    int // negativo acima da linha de base
+	Advance    int
+}
+
+// Font is a bitmap font: an alpha atlas with every glyph, their metrics and
+// the kerning between pairs of characters. It is built on the CPU; the batch
+// uploads the atlas the first time the font is drawn.
+type Font struct {
+	Atlas      *image.Alpha
+	Glyphs     map[rune]Glyph
+	Ascent     int
+	LineHeight int
+	Kerning    map[[2]rune]int
+}
+
+// NewBitmapFont rasterizes runes of face into an atlas, keeping the kerning
+// the face reports between them
+func NewBitmapFont(face font.Face, runes []rune) *Font {
+	metrics := face.Metrics()
+	f := &Font{
+		Glyphs:     make(map[rune]Glyph),
+		Ascent:     metrics.Ascent.Ceil(),
+		LineHeight: metrics.Height.Ceil(),
+		Kerning:    make(map[[2]rune]int),
+	}
+
+	type rasterized struct {
+		r       rune
+		bounds  image.Rectangle
+		mask    image.Image
+		maskAt  image.Point
+		advance int
+	}
+	glyphs := []rasterized{}
+	for _, r := range runes {
+		bounds, mask, maskAt, advance, ok := face.Glyph(fixed.P(0, 0), r)
+		if !ok {
+			continue
+		}
+		glyphs = append(glyphs, rasterized{r, bounds, mask, maskAt, advance.Round()})
+	}
+
+	// empacota os glifos em linhas
+	x, y, rowHeight := glyphPadding, glyphPadding, 0
+	for _, g := range glyphs {
+		w, h := g.bounds.Dx(), g.bounds.Dy()
+		if x+w+glyphPadding > atlasWidth {
+			x, y = glyphPadding, y+rowHeight+glyphPadding
+			rowHeight = 0
+		}
+		f.Glyphs[g.r] = Glyph{X: x, Y: y, W: w, H: h, OffsetX: g.bounds.Min.X, OffsetY: g.bounds.Min.Y, Advance: g.advance}
+		x += w + glyphPadding
+		if h > rowHeight {
+			rowHeight = h
+		}
+	}
+
+	f.Atlas = image.NewAlpha(image.Rect(0, 0, atlasWidth, nextPowerOfTwo(y+rowHeight+glyphPadding)))
+	for _, g := range glyphs {
+		glyph := f.Glyphs[g.r]
+		target := image.Rect(glyph.X, glyph.Y, glyph.X+glyph.W, glyph.Y+glyph.H)
+		draw.Draw(f.Atlas, target, g.mask, g.maskAt, draw.Src)
+	}
+
+	for a := range f.Glyphs {
+		for b := range f.Glyphs {
+			if kern := face.Kern(a, b).Round(); kern != 0 {
+				f.Kerning[[2]rune{a, b}] = kern
+			}
+		}
+	}
+
+	return f
+}
+
+// Ajustes para a fonte 7x13, que não tem kerning próprio: aproxima pares com
+// muito espaço vazio entre si
+var defaultKerning = map[[2]rune]int{
+	{'A', 'V'}: -1, {'V', 'A'}: -1, {'A', 'T'}: -1, {'T', 'A'}: -1,
+	{'A', 'Y'}: -1, {'Y', 'A'}: -1, {'L', 'T'}: -1, {'L', 'V'}: -1,
+	{'L', 'Y'}: -1, {'T', 'o'}: -1, {'T', 'e'}: -1, {'T', 'a'}: -1,
+	{'r', '.'}: -1, {'r', ','}: -1, {'F', '.'}: -1, {'P', '.'}: -1,
+}
+
+// DefaultFont is the 7x13 bitmap font with ASCII and Latin-1 characters
+func DefaultFont() *Font {
+	runes := []rune{}
+	for r := rune(0x20); r < 0x7f; r++ {
+		runes = append(runes, r)
+	}
+	for r := rune(0xa1); r <= 0xff; r++ {
+		runes = append(runes, r)
+	}
+
+	f := NewBitmapFont(basicfont.Face7x13, runes)
+	for pair, kern := range defaultKerning {
+		f.Kerning[pair] = kern
+	}
+
+	return f
+}
+
+// Kern returns the horizontal adjustment between two consecutive characters
+func (f *Font) Kern(previous, next rune) int {
+	return f.Kerning[[2]rune{previous, next}]
+}
+
+// Layout calls place for every character of text with the position, relative
+// to the top left of the text, where the glyph must be drawn. Lines are
+// separated by '\n' and unknown characters are skipped.
+func (f *Font) Layout(text string, place func(r rune, glyph Glyph, x, y int)) {
+	penX, baseline := 0, f.Ascent
+	previous := rune(-1)
+
+	for _, r := range text {
+		if r == '\n' {
+			penX, baseline = 0, baseline+f.LineHeight
+			previous = -1
+			continue
+		}
+
+		glyph, ok := f.Glyphs[r]
+		if !ok {
+			continue
+		}
+
+		if previous >= 0 {
+			penX += f.Kern(previous, r)
+		}
+		if place != nil {
+			place(r, glyph, penX+glyph.OffsetX, baseline+glyph.OffsetY)
+		}
+		penX += glyph.Advance
+		previous = r
+	}
+}
+
+// Measure returns the size of text in pixels
+func (f *Font) Measure(text string) (width, height int) {
+	lines, lineWidth := 1, 0
+	previous := rune(-1)
+
+	for _, r := range text {
+		if r == '\n' {
+			lines++
+			lineWidth, previous = 0, -1
+			continue
+		}
+
+		glyph, ok := f.Glyphs[r]
+		if !ok {
+			continue
+		}
+		if previous >= 0 {
+			lineWidth += f.Kern(previous, r)
+		}
+		lineWidth += glyph.Advance
+		previous = r
+
+		if lineWidth > width {
+			width = lineWidth
+		}
+	}
+
+	return width, lines * f.LineHeight
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p *= 2
+	}
+
+	return p
+}
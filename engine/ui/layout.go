@@ -0,0 +1,52 @@
+package ui
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Rect is a rectangle in screen pixels, origin at the top left and y down
+type Rect struct {
+	X, Y, W, H float32
+}
+
+// Contains tells whether the point (x, y) is inside the rectangle
+func (r Rect) Contains(x, y float32) bool {
+	return x >= r.X && x < r.X+r.W && y >= r.Y && y < r.Y+r.H
+}
+
+// Inset shrinks the rectangle by amount on every side
+func (r Rect) Inset(amount float32) Rect {
+	return Rect{r.X + amount, r.Y + amount, r.W - 2*amount, r.H - 2*amount}
+}
+
+// Anchor is the point of the parent a widget is positioned from. The same
+// point of the widget is placed there, so a BottomCenter widget sits at the
+// bottom of its parent, horizontally centered.
+type Anchor int
+
+const (
+	TopLeft Anchor = iota
+	TopCenter
+	TopRight
+	CenterLeft
+	Center
+	CenterRight
+	BottomLeft
+	BottomCenter
+	BottomRight
+)
+
+// Fração da largura e da altura correspondente a cada âncora
+func (a Anchor) factors() (float32, float32) {
+	return float32(int(a)%3) * 0.5, float32(int(a)/3) * 0.5
+}
+
+// Place returns the rectangle of size anchored in parent, moved by offset
+func Place(parent Rect, anchor Anchor, offset, size mgl32.Vec2) Rect {
+	fx, fy := anchor.factors()
+
+	return Rect{
+		X: parent.X + parent.W*fx - size.X()*fx + offset.X(),
+		Y: parent.Y + parent.H*fy - size.Y()*fy + offset.Y(),
+		W: size.X(),
+		H: size.Y(),
+	}
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestPlace(t *testing.T) {
+	parent := Rect{10, 20, 200, 100}
+	size := mgl32.Vec2{40, 10}
+
+	tests := []struct {
+		anchor Anchor
+		offset mgl32.Vec2
+		want   Rect
+	}{
+		{TopLeft, mgl32.Vec2{}, Rect{10, 20, 40, 10}},
+		{TopCenter, mgl32.Vec2{}, Rect{90, 20, 40, 10}},
+		{TopRight, mgl32.Vec2{}, Rect{170, 20, 40, 10}},
+		{CenterLeft, mgl32.Vec2{}, Rect{10, 65, 40, 10}},
+		{Center, mgl32.Vec2{}, Rect{90, 65, 40, 10}},
+		{CenterRight, mgl32.Vec2{}, Rect{170, 65, 40, 10}},
+		{BottomLeft, mgl32.Vec2{}, Rect{10, 110, 40, 10}},
+		{BottomCenter, mgl32.Vec2{}, Rect{90, 110, 40, 10}},
+		{BottomRight, mgl32.Vec2{}, Rect{170, 110, 40, 10}},
+
+		// o deslocamento é somado depois da âncora, nos dois sentidos
+		{TopLeft, mgl32.Vec2{5, 6}, Rect{15, 26, 40, 10}},
+		{BottomRight, mgl32.Vec2{-5, -6}, Rect{165, 104, 40, 10}},
+		{Center, mgl32.Vec2{0, -20}, Rect{90, 45, 40, 10}},
+	}
+
+	for _, test := range tests {
+		if got := Place(parent, test.anchor, test.offset, size); got != test.want {
+			t.Errorf("Place(anchor %d, offset %v) = %v, want %v", test.anchor, test.offset, got, test.want)
+		}
+	}
+}
+
+func TestPlaceInsideParent(t *testing.T) {
+	// sem deslocamento, qualquer âncora mantém o filho dentro do pai
+	parent := Rect{0, 0, 320, 180}
+	size := mgl32.Vec2{64, 32}
+
+	for anchor := TopLeft; anchor <= BottomRight; anchor++ {
+		r := Place(parent, anchor, mgl32.Vec2{}, size)
+		if r.X < parent.X || r.Y < parent.Y || r.X+r.W > parent.X+parent.W || r.Y+r.H > parent.Y+parent.H {
+			t.Errorf("anchor %d: %v outside %v", anchor, r, parent)
+		}
+	}
+}
+
+func TestRect(t *testing.T) {
+	r := Rect{10, 20, 30, 40}
+
+	tests := []struct {
+		x, y float32
+		want bool
+	}{
+		{10, 20, true},
+		{25, 40, true},
+		{39.9, 59.9, true},
+		{40, 30, false}, // a borda direita e a de baixo ficam de fora
+		{20, 60, false},
+		{9.9, 30, false},
+		{20, 19.9, false},
+	}
+
+	for _, test := range tests {
+		if got := r.Contains(test.x, test.y); got != test.want {
+			t.Errorf("%v.Contains(%v, %v) = %v, want %v", r, test.x, test.y, got, test.want)
+		}
+	}
+
+	if got, want := r.Inset(5), (Rect{15, 25, 20, 30}); got != want {
+		t.Errorf("Inset(5) = %v, want %v", got, want)
+	}
+	if got, want := r.Inset(-2), (Rect{8, 18, 34, 44}); got != want {
+		t.Errorf("Inset(-2) = %v, want %v", got, want)
+	}
+}
+
+func TestScreen(t *testing.T) {
+	tests := []struct {
+		name                  string
+		width, height         int
+		scale, cursorScale    float32
+		wantWidth, wantHeight float32
+		cursor                [2]float64
+		wantCursor            [2]float32
+	}{
+		{"sem escala", 1280, 720, 1, 1, 1280, 720, [2]float64{100, 50}, [2]float32{100, 50}},
+		{"escala inválida", 1280, 720, 0, -1, 1280, 720, [2]float64{100, 50}, [2]float32{100, 50}},
+		{"interface 2x", 2560, 1440, 2, 1, 1280, 720, [2]float64{200, 100}, [2]float32{100, 50}},
+		// monitor retina: o cursor vem em pontos, o framebuffer em pixels
+		{"retina", 2560, 1440, 2, 2, 1280, 720, [2]float64{100, 50}, [2]float32{100, 50}},
+		{"retina sem escala", 2560, 1440, 1, 2, 2560, 1440, [2]float64{100, 50}, [2]float32{200, 100}},
+		{"escala fracionária", 1920, 1080, 1.5, 1, 1280, 720, [2]float64{300, 150}, [2]float32{200, 100}},
+	}
+
+	for _, test := range tests {
+		screen := NewScreen(test.width, test.height, test.scale, test.cursorScale)
+		if screen.Width != test.wantWidth || screen.Height != test.wantHeight {
+			t.Errorf("%s: size %vx%v, want %vx%v", test.name, screen.Width, screen.Height, test.wantWidth, test.wantHeight)
+		}
+
+		x, y := screen.cursor(test.cursor[0], test.cursor[1])
+		if x != test.wantCursor[0] || y != test.wantCursor[1] {
+			t.Errorf("%s: cursor(%v) = (%v, %v), want %v", test.name, test.cursor, x, y, test.wantCursor)
+		}
+	}
+}
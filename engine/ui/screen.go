@@ -0,0 +1,36 @@
+package ui
+
+// Screen is the area the interface is laid out in. Its units are pixels of a
+// monitor without scaling, so the interface keeps its size on high-DPI
+// monitors.
+type Screen struct {
+	Width       float32
+	Height      float32
+	Scale       float32 // pixels do framebuffer por unidade
+	CursorScale float32 // pixels do framebuffer por coordenada do cursor
+}
+
+// NewScreen returns the screen of a framebuffer of width x height pixels
+// shown at scale. cursorScale converts the cursor coordinates of the window
+// to framebuffer pixels.
+func NewScreen(width, height int, scale, cursorScale float32) Screen {
+	if scale <= 0 {
+		scale = 1
+	}
+	if cursorScale <= 0 {
+		cursorScale = 1
+	}
+
+	return Screen{
+		Width:       float32(width) / scale,
+		Height:      float32(height) / scale,
+		Scale:       scale,
+		CursorScale: cursorScale,
+	}
+}
+
+// Converte uma posição do cursor na janela para as unidades da tela
+func (s Screen) cursor(x, y float64) (float32, float32) {
+	factor := s.CursorScale / s.Scale
+	return float32(x) * factor, float32(y) * factor
+}
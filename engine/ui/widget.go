@@ -0,0 +1,97 @@
+package ui
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// EventType is the kind of an input Event
+type EventType int
+
+const (
+	MouseMove EventType = iota
+	MouseDown
+	MouseUp
+	KeyPress // tecla pressionada ou repetida, em Key
+	CharTyped
+)
+
+// Teclas que os widgets entendem, independentes da biblioteca de janelas
+type Key int
+
+const (
+	KeyUnknown Key = iota
+	KeyBackspace
+	KeyDelete
+	KeyEnter
+	KeyLeft
+	KeyRight
+	KeyUp
+	KeyDown
+	KeyHome
+	KeyEnd
+	KeyTab
+)
+
+// Event is an input event, in screen pixels
+type Event struct {
+	Type EventType
+	X, Y float32
+	Key  Key
+	Char rune
+}
+
+// Widget is an element of the interface. Widgets are retained: they are
+// created once, laid out inside their parent and drawn every frame.
+type Widget interface {
+	// Layout computes the widget rectangle inside parent
+	Layout(ctx *Context, parent Rect)
+	Bounds() Rect
+	Draw(ctx *Context, batch *Batch)
+	// HandleEvent returns true when the event was used by the widget
+	HandleEvent(ctx *Context, event Event) bool
+	Visible() bool
+}
+
+// Focusable widgets receive the keyboard events while focused
+type Focusable interface {
+	Widget
+	SetFocused(focused bool)
+}
+
+// Base holds the placement shared by every widget
+type Base struct {
+	Anchor Anchor
+	Offset mgl32.Vec2
+	Size   mgl32.Vec2
+	Hidden bool
+
+	rect Rect
+}
+
+func (b *Base) Layout(ctx *Context, parent Rect) {
+	b.rect = Place(parent, b.Anchor, b.Offset, b.Size)
+}
+
+func (b *Base) Bounds() Rect {
+	return b.rect
+}
+
+func (b *Base) Visible() bool {
+	return !b.Hidden
+}
+
+func (b *Base) HandleEvent(ctx *Context, event Event) bool {
+	return false
+}
+
+// Cores padrão dos widgets
+var (
+	TextColor      = mgl32.Vec4{1, 1, 1, 1}
+	MutedTextColor = mgl32.Vec4{0.7, 0.7, 0.7, 1}
+	PanelColor     = mgl32.Vec4{0, 0, 0, 0.55}
+	ControlColor   = mgl32.Vec4{0.25, 0.25, 0.25, 0.9}
+	HoverColor     = mgl32.Vec4{0.35, 0.35, 0.35, 0.9}
+	PressedColor   = mgl32.Vec4{0.15, 0.15, 0.15, 0.9}
+	AccentColor    = mgl32.Vec4{0.45, 0.7, 0.3, 1}
+	FocusColor     = mgl32.Vec4{1, 1, 1, 0.9}
+	SelectionColor = mgl32.Vec4{0.45, 0.7, 0.3, 0.6}
+	White          = mgl32.Vec4{1, 1, 1, 1}
+)
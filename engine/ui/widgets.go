@@ -0,0 +1,492 @@
+package ui
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Panel is a rectangle holding other widgets, laid out inside it
+type Panel struct {
+	Base
+	Color    mgl32.Vec4 // fundo, transparente se zero
+	Border   mgl32.Vec4
+	Children []Widget
+}
+
+func NewPanel(anchor Anchor, offset, size mgl32.Vec2, color mgl32.Vec4, children ...Widget) *Panel {
+	return &Panel{Base: Base{Anchor: anchor, Offset: offset, Size: size}, Color: color, Children: children}
+}
+
+// Add appends children to the panel
+func (p *Panel) Add(children ...Widget) {
+	p.Children = append(p.Children, children...)
+}
+
+func (p *Panel) Layout(ctx *Context, parent Rect) {
+	p.Base.Layout(ctx, parent)
+	for _, child := range p.Children {
+		child.Layout(ctx, p.rect)
+	}
+}
+
+func (p *Panel) Draw(ctx *Context, batch *Batch) {
+	if p.Color.W() > 0 {
+		batch.Fill(p.rect, p.Color)
+	}
+	if p.Border.W() > 0 {
+		batch.Border(p.rect, 2, p.Border)
+	}
+	for _, child := range p.Children {
+		if child.Visible() {
+			child.Draw(ctx, batch)
+		}
+	}
+}
+
+func (p *Panel) HandleEvent(ctx *Context, event Event) bool {
+	// os filhos desenhados por último ficam por cima, então recebem primeiro
+	for i := len(p.Children) - 1; i >= 0; i-- {
+		if p.Children[i].Visible() && p.Children[i].HandleEvent(ctx, event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Label draws a line (or lines) of text. Its size follows the text.
+type Label struct {
+	Base
+	Text  string
+	Scale float32
+	Color mgl32.Vec4
+}
+
+func NewLabel(anchor Anchor, offset mgl32.Vec2, text string, scale float32) *Label {
+	return &Label{Base: Base{Anchor: anchor, Offset: offset}, Text: text, Scale: scale, Color: TextColor}
+}
+
+func (l *Label) Layout(ctx *Context, parent Rect) {
+	w, h := ctx.Font.Measure(l.Text)
+	l.Size = mgl32.Vec2{float32(w) * l.Scale, float32(h) * l.Scale}
+	l.Base.Layout(ctx, parent)
+}
+
+func (l *Label) Draw(ctx *Context, batch *Batch) {
+	batch.Text(ctx.Font, l.Text, l.rect.X, l.rect.Y, l.Scale, l.Color)
+}
+
+// Image draws a texture, or a solid color without one
+type Image struct {
+	Base
+	Texture *Texture
+	UV      Rect
+	Color   mgl32.Vec4
+}
+
+func NewImage(anchor Anchor, offset, size mgl32.Vec2, texture *Texture, color mgl32.Vec4) *Image {
+	return &Image{Base: Base{Anchor: anchor, Offset: offset, Size: size}, Texture: texture, UV: Rect{0, 0, 1, 1}, Color: color}
+}
+
+func (i *Image) Draw(ctx *Context, batch *Batch) {
+	if i.Texture == nil {
+		batch.Fill(i.rect, i.Color)
+		return
+	}
+
+	batch.Image(i.rect, i.UV, *i.Texture, i.Color)
+}
+
+// Button runs OnClick when pressed and released over it
+type Button struct {
+	Base
+	Text    string
+	OnClick func()
+
+	hovered bool
+	pressed bool
+}
+
+func NewButton(anchor Anchor, offset, size mgl32.Vec2, text string, onClick func()) *Button {
+	return &Button{Base: Base{Anchor: anchor, Offset: offset, Size: size}, Text: text, OnClick: onClick}
+}
+
+func (b *Button) Draw(ctx *Context, batch *Batch) {
+	color := ControlColor
+	if b.pressed {
+		color = PressedColor
+	} else if b.hovered {
+		color = HoverColor
+	}
+	batch.Fill(b.rect, color)
+	if b.hovered {
+		batch.Border(b.rect, 2, FocusColor)
+	}
+
+	drawCentered(ctx, batch, b.Text, b.rect, 2, TextColor)
+}
+
+func (b *Button) HandleEvent(ctx *Context, event Event) bool {
+	inside := b.rect.Contains(event.X, event.Y)
+
+	switch event.Type {
+	case MouseMove:
+		b.hovered = inside
+		return false
+	case MouseDown:
+		if inside {
+			b.pressed = true
+			ctx.Capture(b)
+			return true
+		}
+	case MouseUp:
+		if b.pressed {
+			b.pressed = false
+			if inside && b.OnClick != nil {
+				b.OnClick()
+			}
+			return true
+		}
+	}
+
+	return false
+}
+
+// Slider picks a value between Min and Max by dragging its handle
+type Slider struct {
+	Base
+	Label    string
+	Min, Max float32
+	Value    float32
+	Step     float32 // 0 é contínuo
+	OnChange func(value float32)
+
+	dragging bool
+}
+
+func NewSlider(anchor Anchor, offset, size mgl32.Vec2, label string, min, max, value float32, onChange func(float32)) *Slider {
+	return &Slider{Base: Base{Anchor: anchor, Offset: offset, Size: size}, Label: label, Min: min, Max: max, Value: value, OnChange: onChange}
+}
+
+// Fraction returns the value as a fraction of the range
+func (s *Slider) Fraction() float32 {
+	if s.Max == s.Min {
+		return 0
+	}
+
+	return (s.Value - s.Min) / (s.Max - s.Min)
+}
+
+// SetFraction sets the value from a fraction of the range, snapped to Step
+func (s *Slider) SetFraction(fraction float32) {
+	if fraction < 0 {
+		fraction = 0
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+
+	value := s.Min + fraction*(s.Max-s.Min)
+	if s.Step > 0 {
+		steps := int((value-s.Min)/s.Step + 0.5)
+		value = s.Min + float32(steps)*s.Step
+	}
+
+	if value != s.Value {
+		s.Value = value
+		if s.OnChange != nil {
+			s.OnChange(value)
+		}
+	}
+}
+
+func (s *Slider) Draw(ctx *Context, batch *Batch) {
+	batch.Fill(s.rect, ControlColor)
+	filled := s.rect
+	filled.W *= s.Fraction()
+	batch.Fill(filled, AccentColor)
+
+	handle := Rect{s.rect.X + s.rect.W*s.Fraction() - 3, s.rect.Y, 6, s.rect.H}
+	batch.Fill(handle, FocusColor)
+
+	drawCentered(ctx, batch, s.Label, s.rect, 2, TextColor)
+}
+
+func (s *Slider) HandleEvent(ctx *Context, event Event) bool {
+	switch event.Type {
+	case MouseDown:
+		if s.rect.Contains(event.X, event.Y) {
+			s.dragging = true
+			ctx.Capture(s)
+			s.SetFraction((event.X - s.rect.X) / s.rect.W)
+			return true
+		}
+	case MouseMove:
+		if s.dragging {
+			s.SetFraction((event.X - s.rect.X) / s.rect.W)
+			return true
+		}
+	case MouseUp:
+		if s.dragging {
+			s.dragging = false
+			return true
+		}
+	}
+
+	return false
+}
+
+// TextField edits a single line of text while focused
+type TextField struct {
+	Base
+	Text        string
+	Placeholder string
+	MaxLength   int
+	OnSubmit    func(text string)
+
+	cursor  int // em runas
+	focused bool
+}
+
+func NewTextField(anchor Anchor, offset, size mgl32.Vec2, placeholder string, onSubmit func(string)) *TextField {
+	return &TextField{Base: Base{Anchor: anchor, Offset: offset, Size: size}, Placeholder: placeholder, MaxLength: 32, OnSubmit: onSubmit}
+}
+
+func (t *TextField) SetFocused(focused bool) {
+	t.focused = focused
+	t.cursor = len([]rune(t.Text))
+}
+
+// SetText replaces the text, with the cursor at its end
+func (t *TextField) SetText(text string) {
+	t.Text = text
+	t.cursor = len([]rune(text))
+}
+
+func (t *TextField) Draw(ctx *Context, batch *Batch) {
+	batch.Fill(t.rect, PressedColor)
+	if t.focused {
+		batch.Border(t.rect, 2, FocusColor)
+	}
+
+	const scale = 2
+	textY := t.rect.Y + (t.rect.H-float32(ctx.Font.LineHeight)*scale)/2
+	if t.Text == "" && !t.focused {
+		batch.Text(ctx.Font, t.Placeholder, t.rect.X+8, textY, scale, MutedTextColor)
+		return
+	}
+	batch.Text(ctx.Font, t.Text, t.rect.X+8, textY, scale, TextColor)
+
+	if t.focused {
+		w, _ := ctx.Font.Measure(string([]rune(t.Text)[:t.cursor]))
+		batch.Fill(Rect{t.rect.X + 8 + float32(w)*scale, textY, 2, float32(ctx.Font.LineHeight) * scale}, FocusColor)
+	}
+}
+
+func (t *TextField) HandleEvent(ctx *Context, event Event) bool {
+	switch event.Type {
+	case MouseDown:
+		if t.rect.Contains(event.X, event.Y) {
+			ctx.Focus(t)
+			return true
+		}
+	case CharTyped:
+		return t.Insert(event.Char)
+	case KeyPress:
+		return t.Edit(event.Key)
+	}
+
+	return false
+}
+
+// Insert types a character at the cursor
+func (t *TextField) Insert(char rune) bool {
+	text := []rune(t.Text)
+	if t.MaxLength > 0 && len(text) >= t.MaxLength {
+		return true
+	}
+
+	text = append(text[:t.cursor], append([]rune{char}, text[t.cursor:]...)...)
+	t.Text = string(text)
+	t.cursor++
+	return true
+}
+
+// Edit applies an editing key
+func (t *TextField) Edit(key Key) bool {
+	text := []rune(t.Text)
+
+	switch key {
+	case KeyBackspace:
+		if t.cursor > 0 {
+			text = append(text[:t.cursor-1], text[t.cursor:]...)
+			t.cursor--
+		}
+	case KeyDelete:
+		if t.cursor < len(text) {
+			text = append(text[:t.cursor], text[t.cursor+1:]...)
+		}
+	case KeyLeft:
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case KeyRight:
+		if t.cursor < len(text) {
+			t.cursor++
+		}
+	case KeyHome:
+		t.cursor = 0
+	case KeyEnd:
+		t.cursor = len(text)
+	case KeyEnter:
+		if t.OnSubmit != nil {
+			t.OnSubmit(t.Text)
+		}
+	default:
+		return false
+	}
+
+	t.Text = string(text)
+	return true
+}
+
+// List shows Items in rows, scrolling to keep the selected one visible
+type List struct {
+	Base
+	Items    []string
+	Selected int // -1 sem seleção
+	OnSelect func(index int)
+
+	scroll  int
+	focused bool
+}
+
+func NewList(anchor Anchor, offset, size mgl32.Vec2, items []string, onSelect func(int)) *List {
+	return &List{Base: Base{Anchor: anchor, Offset: offset, Size: size}, Items: items, Selected: -1, OnSelect: onSelect}
+}
+
+const listRowScale = 2
+
+func (l *List) rowHeight(ctx *Context) float32 {
+	return float32(ctx.Font.LineHeight)*listRowScale + 6
+}
+
+func (l *List) visibleRows(ctx *Context) int {
+	return int(l.rect.H / l.rowHeight(ctx))
+}
+
+func (l *List) SetFocused(focused bool) {
+	l.focused = focused
+}
+
+// Select selects an item, scrolling to it
+func (l *List) Select(ctx *Context, index int) {
+	if index < 0 || index >= len(l.Items) {
+		return
+	}
+
+	l.Selected = index
+	if index < l.scroll {
+		l.scroll = index
+	}
+	if rows := l.visibleRows(ctx); rows > 0 && index >= l.scroll+rows {
+		l.scroll = index - rows + 1
+	}
+	if l.OnSelect != nil {
+		l.OnSelect(index)
+	}
+}
+
+func (l *List) Draw(ctx *Context, batch *Batch) {
+	batch.Fill(l.rect, PressedColor)
+	if l.focused {
+		batch.Border(l.rect, 2, FocusColor)
+	}
+
+	batch.SetClip(&l.rect)
+	row := l.rowHeight(ctx)
+	for i := l.scroll; i < len(l.Items); i++ {
+		y := l.rect.Y + float32(i-l.scroll)*row
+		if y > l.rect.Y+l.rect.H {
+			break
+		}
+		if i == l.Selected {
+			batch.Fill(Rect{l.rect.X, y, l.rect.W, row}, SelectionColor)
+		}
+		batch.Text(ctx.Font, l.Items[i], l.rect.X+8, y+3, listRowScale, TextColor)
+	}
+	batch.SetClip(nil)
+}
+
+func (l *List) HandleEvent(ctx *Context, event Event) bool {
+	switch event.Type {
+	case MouseDown:
+		if l.rect.Contains(event.X, event.Y) {
+			ctx.Focus(l)
+			l.Select(ctx, l.scroll+int((event.Y-l.rect.Y)/l.rowHeight(ctx)))
+			return true
+		}
+	case KeyPress:
+		switch event.Key {
+		case KeyUp:
+			l.Select(ctx, l.Selected-1)
+			return true
+		case KeyDown:
+			l.Select(ctx, l.Selected+1)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Graph draws Values as vertical bars, oldest on the left, scaled so that Max
+// fills its height. Marks are drawn as horizontal lines, e.g. frame budgets.
+type Graph struct {
+	Base
+	Values   []float64
+	Capacity int // largura das barras é W/Capacity, para o gráfico não esticar enquanto enche
+	Max      float64
+	Marks    []float64
+	Color    mgl32.Vec4
+}
+
+func NewGraph(anchor Anchor, offset, size mgl32.Vec2, capacity int, max float64) *Graph {
+	return &Graph{Base: Base{Anchor: anchor, Offset: offset, Size: size}, Capacity: capacity, Max: max, Color: AccentColor}
+}
+
+func (g *Graph) Draw(ctx *Context, batch *Batch) {
+	batch.Fill(g.rect, PanelColor)
+	if g.Max <= 0 {
+		return
+	}
+
+	bars := g.Capacity
+	if len(g.Values) > bars {
+		bars = len(g.Values)
+	}
+	barWidth := g.rect.W / float32(bars)
+
+	bottom := g.rect.Y + g.rect.H
+	for i, value := range g.Values {
+		fraction := float32(value / g.Max)
+		if fraction > 1 {
+			fraction = 1
+		}
+		height := g.rect.H * fraction
+		batch.Fill(Rect{g.rect.X + float32(i)*barWidth, bottom - height, barWidth, height}, g.Color)
+	}
+
+	for _, mark := range g.Marks {
+		if mark > g.Max {
+			continue
+		}
+		y := bottom - g.rect.H*float32(mark/g.Max)
+		batch.Fill(Rect{g.rect.X, y, g.rect.W, 1}, FocusColor)
+	}
+}
+
+// Escreve text centralizado em r
+func drawCentered(ctx *Context, batch *Batch, text string, r Rect, scale float32, color mgl32.Vec4) {
+	w, h := ctx.Font.Measure(text)
+	batch.Text(ctx.Font, text, r.X+(r.W-float32(w)*scale)/2, r.Y+(r.H-float32(h)*scale)/2, scale, color)
+}
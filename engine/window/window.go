@@ -1,33 +1,237 @@
 package window
 
 import (
+	"fmt"
 	"log"
 
+	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 )
 
 var (
+	// Largura / altura do framebuffer, lida pelas câmeras para a projeção
 	ScreenRatio float32 = 1.0
 )
 
-// Creates the windows
-func NewWindow(title string, width, height int) (*glfw.Window, error) {
-	err := glfw.Init()
-	if err != nil {
-		log.Println(StrNewWindowFail, err)
-		return nil, err
+// Options are how the window is created. Fullscreen and VSync can also change
+// afterwards; Samples only when the window is created again.
+type Options struct {
+	Title      string
+	Width      int // tamanho em janela; 0 em tela cheia usa o do monitor
+	Height     int
+	Fullscreen bool
+	VSync      bool
+	Samples    int // amostras de MSAA, 0 desliga
+}
+
+// Window is the game window and its OpenGL context. It keeps the viewport and
+// ScreenRatio in sync with the framebuffer and tells the rest of the engine
+// when its size or its scale change.
+type Window struct {
+	*glfw.Window
+
+	Width   int     // framebuffer, em pixels
+	Height  int     // framebuffer, em pixels
+	Scale   float32 // escala de conteúdo do monitor (DPI), 1 em monitores comuns
+	Samples int     // amostras de MSAA que o sistema aceitou
+
+	fullscreen bool
+	vsync      bool
+	windowed   [4]int // posição e tamanho da janela antes da tela cheia
+	onResize   []func(w *Window)
+}
+
+// NewWindow creates the window and makes its context current. When the system
+// doesn't accept the MSAA samples, it tries again with fewer down to none.
+func NewWindow(options Options) (*Window, error) {
+	if err := glfw.Init(); err != nil {
+		return nil, fmt.Errorf("%s: %w", StrNewWindowFail, err)
 	}
 
-	ScreenRatio = float32(width) / float32(height)
-	glfw.WindowHint(glfw.Resizable, glfw.False)
+	width, height := options.Width, options.Height
+	var monitor *glfw.Monitor
+	if options.Fullscreen {
+		monitor = glfw.GetPrimaryMonitor()
+		if mode := monitor.GetVideoMode(); width <= 0 || height <= 0 {
+			width, height = mode.Width, mode.Height
+		}
+	}
+	if width <= 0 || height <= 0 {
+		glfw.Terminate()
+		return nil, fmt.Errorf("%s: invalid size %dx%d", StrNewWindowFail, width, height)
+	}
+
+	glfw.WindowHint(glfw.Resizable, glfw.True)
+	glfw.WindowHint(glfw.ScaleToMonitor, glfw.True)
+	glfw.WindowHint(glfw.CocoaRetinaFramebuffer, glfw.True)
 	glfw.WindowHint(glfw.ContextVersionMajor, 4)
 	glfw.WindowHint(glfw.ContextVersionMinor, 1)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-	glfw.WindowHint(glfw.Samples, 32)
 
-	window, _ := glfw.CreateWindow(width, height, title, nil, nil)
-	window.MakeContextCurrent()
+	samples := options.Samples
+	var handle *glfw.Window
+	var err error
+	for {
+		glfw.WindowHint(glfw.Samples, samples)
+		handle, err = glfw.CreateWindow(width, height, options.Title, monitor, nil)
+		if err == nil || samples == 0 {
+			break
+		}
+
+		log.Printf("window: %dx MSAA not available (%v), trying %dx", samples, err, samples/2)
+		samples /= 2
+		if samples < 2 {
+			samples = 0
+		}
+	}
+	if err != nil {
+		glfw.Terminate()
+		return nil, fmt.Errorf("%s: %w", StrNewWindowFail, err)
+	}
+	handle.MakeContextCurrent()
+
+	w := &Window{
+		Window:     handle,
+		Samples:    samples,
+		fullscreen: options.Fullscreen,
+		windowed:   [4]int{0, 0, options.Width, options.Height},
+	}
+	if options.Fullscreen {
+		// ao sair da tela cheia a janela volta centralizada, com 2/3 do
+		// monitor se não foi dado um tamanho
+		mode := monitor.GetVideoMode()
+		if options.Width <= 0 || options.Height <= 0 {
+			w.windowed[2], w.windowed[3] = mode.Width*2/3, mode.Height*2/3
+		}
+		w.windowed[0], w.windowed[1] = (mode.Width-w.windowed[2])/2, (mode.Height-w.windowed[3])/2
+	}
+	w.setVSync(options.VSync)
+
+	w.Width, w.Height = handle.GetFramebufferSize()
+	w.setScale(handle.GetContentScale())
+	if !w.Minimized() {
+		ScreenRatio = float32(w.Width) / float32(w.Height)
+	}
+
+	handle.SetFramebufferSizeCallback(func(_ *glfw.Window, width, height int) {
+		w.Width, w.Height = width, height
+		w.resized()
+	})
+	handle.SetContentScaleCallback(func(_ *glfw.Window, x, y float32) {
+		w.setScale(x, y)
+		w.resized()
+	})
+
+	return w, nil
+}
+
+// OnResize calls handler whenever the framebuffer size or the content scale
+// change, after the viewport is updated. It isn't called while minimized.
+func (w *Window) OnResize(handler func(w *Window)) {
+	w.onResize = append(w.onResize, handler)
+}
+
+func (w *Window) resized() {
+	// minimizada no Windows o framebuffer tem tamanho 0
+	if w.Minimized() {
+		return
+	}
+
+	ScreenRatio = float32(w.Width) / float32(w.Height)
+	gl.Viewport(0, 0, int32(w.Width), int32(w.Height))
+	for _, handler := range w.onResize {
+		handler(w)
+	}
+}
+
+// A escala é a mesma nos dois eixos nos sistemas suportados pelo GLFW
+func (w *Window) setScale(x, _ float32) {
+	w.Scale = x
+	if w.Scale <= 0 {
+		w.Scale = 1
+	}
+}
+
+// Minimized tells if the framebuffer is empty, when nothing should be drawn
+func (w *Window) Minimized() bool {
+	return w.Width <= 0 || w.Height <= 0
+}
+
+// CursorScale is the ratio between framebuffer pixels and the cursor
+// coordinates, which differ on systems that scale the window themselves
+func (w *Window) CursorScale() float32 {
+	windowWidth, _ := w.GetSize()
+	if windowWidth <= 0 {
+		return 1
+	}
 
-	return window, nil
+	return float32(w.Width) / float32(windowWidth)
+}
+
+// Fullscreen tells if the window takes a whole monitor
+func (w *Window) Fullscreen() bool {
+	return w.fullscreen
+}
+
+// SetFullscreen moves the window to the monitor it is on, at the resolution
+// of the monitor, or back to where it was before
+func (w *Window) SetFullscreen(fullscreen bool) {
+	if fullscreen == w.fullscreen {
+		return
+	}
+	w.fullscreen = fullscreen
+
+	if fullscreen {
+		w.windowed[0], w.windowed[1] = w.GetPos()
+		w.windowed[2], w.windowed[3] = w.GetSize()
+
+		monitor := w.currentMonitor()
+		mode := monitor.GetVideoMode()
+		w.SetMonitor(monitor, 0, 0, mode.Width, mode.Height, mode.RefreshRate)
+	} else {
+		w.SetMonitor(nil, w.windowed[0], w.windowed[1], w.windowed[2], w.windowed[3], 0)
+	}
+
+	// o modo de tela cheia pode trocar o intervalo de troca de buffers
+	w.setVSync(w.vsync)
+}
+
+// Monitor que contém o centro da janela, o principal se nenhum contiver
+func (w *Window) currentMonitor() *glfw.Monitor {
+	x, y := w.GetPos()
+	width, height := w.GetSize()
+	centerX, centerY := x+width/2, y+height/2
+
+	for _, monitor := range glfw.GetMonitors() {
+		monitorX, monitorY := monitor.GetPos()
+		mode := monitor.GetVideoMode()
+		if centerX >= monitorX && centerX < monitorX+mode.Width && centerY >= monitorY && centerY < monitorY+mode.Height {
+			return monitor
+		}
+	}
+
+	return glfw.GetPrimaryMonitor()
+}
+
+// ToggleFullscreen switches between fullscreen and windowed
+func (w *Window) ToggleFullscreen() {
+	w.SetFullscreen(!w.fullscreen)
+}
+
+// SetVSync waits for the monitor refresh before showing each frame, if the
+// driver allows it
+func (w *Window) SetVSync(vsync bool) {
+	if vsync != w.vsync {
+		w.setVSync(vsync)
+	}
+}
+
+func (w *Window) setVSync(vsync bool) {
+	w.vsync = vsync
+	if vsync {
+		glfw.SwapInterval(1)
+	} else {
+		glfw.SwapInterval(0)
+	}
 }
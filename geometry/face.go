@@ -2,590 +2,78 @@ package geometry
 
 import (
 	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
 )
 
+// Malhas compartilhadas por todos os blocos e pela interface, criadas em InitCommonMeshes
 var (
-	CommonFaceGeometry     GeometryInformation
-	CommonFaceGeometry2    GeometryInformation
-	CommonFaceEdgeGeometry GeometryInformation
+	FaceMesh        *Mesh
+	FlippedFaceMesh *Mesh // FaceMesh dividida pela outra diagonal
+	FaceEdgesMesh   *Mesh
+	CrosshairMesh   *Mesh
 )
 
-var (
-	Faces  = []GeometryInformation{}
-	Colors = [][]float32{
-		{
-
-			0.0, 0.1, 0.0, 1.0,
-			0.0, 1.0, 0.0, 1.0,
-			0.0, 1.0, 0.0, 1.0,
-			0.0, 0.1, 0.0, 1.0,
-		},
-		{
-
-			0.5, 0.35, 0.15, 1.0,
-			0.1, 0.1, 0.1, 1.0,
-			0.1, 0.1, 0.1, 1.0,
-			0.5, 0.35, 0.15, 1.0,
-		},
-		{
-			0.3, 0.15, 0.05, 1.0,
-			0.1, 0.1, 0.1, 1.0,
-			0.1, 0.1, 0.1, 1.0,
-			0.3, 0.15, 0.05, 1.0,
-		},
-		{
-
-			0.0, 0.1, 0.05, 1.0,
-			0.0, 1.0, 0.1, 1.0,
-			0.0, 1.0, 0.1, 1.0,
-			0.0, 0.1, 0.05, 1.0,
-		},
-		{
-			0.95, 0.92, 0.55, 1.0,
-			0.1, 0.1, 0.1, 1.0,
-			0.1, 0.1, 0.1, 1.0,
-			0.95, 0.92, 0.55, 1.0,
-		},
-	}
-)
-
-// Draws a face on origin, block type parameter is unused at this moment
-func BuildFace(blockType int) {
-	// Primeiro, definimos os atributos de cada vértice.
-
-	// A posição de cada vértice é definida por coeficientes em um sistema de
-	// coordenadas local de cada modelo geométrico. Note o uso de coordenadas
-	// homogêneas.  Veja as seguintes referências:
-	//
-	//  - slides 35-48 do documento Aula_08_Sistemas_de_Coordenadas.pdf;
-	//  - slides 184-190 do documento Aula_08_Sistemas_de_Coordenadas.pdf;
-	//
-	// Este vetor "model_coefficients" define a GEOMETRIA (veja slides 64-71 do documento Aula_04_Modelagem_Geometrica_3D.pdf).
-	//
-	model_coefficients := []float32{
-		// Vértices de um cubo
-		//    X      Y     Z     W
-		-0.5, 0.5, 0.0, 1.0, // 0
-		0.5, 0.5, 0.0, 1.0, // 1
-		0.5, -0.5, 0.0, 1.0, // 2
-		-0.5, -0.5, 0.0, 1.0, // 3
-	}
-
-	// Criamos o identificador (ID) de um Vertex Buffer Object (VBO).  Um VBO é
-	// um buffer de memória que irá conter os valores de um certo atributo de
-	// um conjunto de vértices; por exemplo: posição, cor, normais, coordenadas
-	// de textura.  Neste exemplo utilizaremos vários VBOs, um para cada tipo de atributo.
-	// Agora criamos um VBO para armazenarmos um atributo: posição.
-	var VBO_model_coefficients_id uint32
-	gl.GenBuffers(1, &VBO_model_coefficients_id)
-
-	// Criamos o identificador (ID) de um Vertex Array Object (VAO).  Um VAO
-	// contém a definição de vários atributos de um certo conjunto de vértices;
-	// isto é, um VAO irá conter ponteiros para vários VBOs.
-	var vertex_array_object_id uint32
-	gl.GenVertexArrays(1, &vertex_array_object_id)
-
-	// "Ligamos" o VAO ("bind"). Informamos que iremos atualizar o VAO cujo ID
-	// está contido na variável "vertex_array_object_id".
-	gl.BindVertexArray(vertex_array_object_id)
-
-	// "Ligamos" o VBO ("bind"). Informamos que o VBO cujo ID está contido na
-	// variável VBO_model_coefficients_id será modificado a seguir. A
-	// constante "gl.ARRAY_BUFFER" informa que esse buffer é de fato um VBO, e
-	// irá conter atributos de vértices.
-	gl.BindBuffer(gl.ARRAY_BUFFER, VBO_model_coefficients_id)
-
-	// Alocamos memória para o VBO "ligado" acima. Como queremos armazenar
-	// nesse VBO todos os valores contidos no array "model_coefficients", pedimos
-	// para alocar um número de bytes exatamente igual ao tamanho ("size")
-	// desse array. A constante "gl.STATIC_DRAW" dá uma dica para o driver da
-	// GPU sobre como utilizaremos os dados do VBO. Neste caso, estamos dizendo
-	// que não pretendemos alterar tais dados (são estáticos: "STATIC"), e
-	// também dizemos que tais dados serão utilizados para renderizar ou
-	// desenhar ("DRAW").  Pense que:
-	//
-	//            glBufferData()  ==  malloc() do C  ==  new do C++.
-	//
-
-	gl.BufferData(gl.ARRAY_BUFFER, len(model_coefficients)*4, nil, gl.STATIC_DRAW)
-
-	// Finalmente, copiamos os valores do array model_coefficients para dentro do
-	// VBO "ligado" acima.  Pense que:
-	//
-	//            glBufferSubData()  ==  memcpy() do C.
-	//
-	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(model_coefficients)*4, gl.Ptr(model_coefficients))
-
-	// Precisamos então informar um índice de "local" ("location"), o qual será
-	// utilizado no shader "shader_vertex.glsl" para acessar os valores
-	// armazenados no VBO "ligado" acima. Também, informamos a dimensão (número de
-	// coeficientes) destes atributos. Como em nosso caso são pontos em coordenadas
-	// homogêneas, temos quatro coeficientes por vértice (X,Y,Z,W). Isso define
-	// um tipo de dado chamado de "vec4" em "shader_vertex.glsl": um vetor com
-	// quatro coeficientes. Finalmente, informamos que os dados estão em ponto
-	// flutuante com 32 bits (gl.FLOAT).
-	// Esta função também informa que o VBO "ligado" acima em glBindBuffer()
-	// está dentro do VAO "ligado" acima por glBindVertexArray().
-	// Veja https://www.khronos.org/opengl/wiki/Vertex_Specification#Vertex_Buffer_Object
-	location := uint32(0)            // "(location = 0)" em "shader_vertex.glsl"
-	number_of_dimensions := int32(4) // vec4 em "shader_vertex.glsl"
-	gl.VertexAttribPointer(location, number_of_dimensions, gl.FLOAT, false, 0, nil)
-
-	// "Ativamos" os atributos. Informamos que os atributos com índice de local
-	// definido acima, na variável "location", deve ser utilizado durante o
-	// rendering.
-	gl.EnableVertexAttribArray(location)
-
-	// "Desligamos" o VBO, evitando assim que operações posteriores venham a
-	// alterar o mesmo. Isso evita bugs.
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
-
-	// Agora repetimos todos os passos acima para atribuir um novo atributo a
-	// cada vértice: uma cor (veja slides 107-110 do documento Aula_03_Rendering_Pipeline_Grafico.pdf e slide 72 do documento Aula_04_Modelagem_Geometrica_3D.pdf).
-	// Tal cor é definida como coeficientes RGBA: Red, Green, Blue, Alpha;
-	// isto é: Vermelho, Verde, Azul, Alpha (valor de transparência).
-	// Conversaremos sobre sistemas de cores nas aulas de Modelos de Iluminação.
-
-	// Vamos então definir polígonos utilizando os vértices do array
-	// model_coefficients.
-	//
-	// Para referência sobre os modos de renderização, veja slides 124-130 do documento Aula_04_Modelagem_Geometrica_3D.pdf.
-	//
-	// Este vetor "indices" define a TOPOLOGIA (veja slides 64-71 do documento Aula_04_Modelagem_Geometrica_3D.pdf).
-	//
-	indices := []uint32{
-		3, 2, 1, 1, 0, 3,
-	}
-
-	// Criamos um primeiro objeto virtual (SceneObject) que se refere às faces
-	// coloridas do cubo.
-
-	// Adicionamos o objeto criado acima na nossa cena virtual (g_VirtualScene).
-
-	// Criamos um buffer OpenGL para armazenar os índices acima
-	var indices_id uint32
-	gl.GenBuffers(1, &indices_id)
-
-	// "Ligamos" o buffer. Note que o tipo agora é gl.ELEMENT_ARRAY_BUFFER.
-	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, indices_id)
-
-	// Alocamos memória para o buffer.
-	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, nil, gl.STATIC_DRAW)
-
-	// Copiamos os valores do array indices[] para dentro do buffer.
-	gl.BufferSubData(gl.ELEMENT_ARRAY_BUFFER, 0, len(indices)*4, gl.Ptr(indices))
-
-	// NÃO faça a chamada abaixo! Diferente de um VBO (gl.ARRAY_BUFFER), um
-	// array de índices (gl.ELEMENT_ARRAY_BUFFER) não pode ser "desligado",
-	// caso contrário o VAO irá perder a informação sobre os índices.
-	//
-	// glBindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0); // XXX Errado!
-	//
-
-	// "Desligamos" o VAO, evitando assim que operações posteriores venham a
-	// alterar o mesmo. Isso evita bugs.
-	gl.BindVertexArray(0)
-
-	// Retornamos o ID do VAO. Isso é tudo que será necessário para renderizar
-	// os triângulos definidos acima. Veja a chamada glDrawElements() em main().
-
-	geom := GeometryInformation{}
-	geom.FirstIndex = gl.PtrOffset(0) // Primeiro índice está em indices[0]
-	geom.NumIndices = len(indices)    // Último índice está em indices[35]; total de 36 índices.
-	geom.RenderingMode = gl.TRIANGLES // Índices correspondem ao tipo de rasterização gl.TRIANGLES.
-	geom.Vertexes = model_coefficients
-	geom.VaoID = vertex_array_object_id
-
-	Faces = append(Faces, geom)
-
+// Quadrado unitário no plano z = 0, centrado na origem
+var faceVertices = []float32{
+	-0.5, 0.5, 0.0, 1.0, // 0
+	0.5, 0.5, 0.0, 1.0, // 1
+	0.5, -0.5, 0.0, 1.0, // 2
+	-0.5, -0.5, 0.0, 1.0, // 3
 }
 
-func BuildFace3(blockType int) {
-	// Primeiro, definimos os atributos de cada vértice.
-
-	// A posição de cada vértice é definida por coeficientes em um sistema de
-	// coordenadas local de cada modelo geométrico. Note o uso de coordenadas
-	// homogêneas.  Veja as seguintes referências:
-	//
-	//  - slides 35-48 do documento Aula_08_Sistemas_de_Coordenadas.pdf;
-	//  - slides 184-190 do documento Aula_08_Sistemas_de_Coordenadas.pdf;
-	//
-	// Este vetor "model_coefficients" define a GEOMETRIA (veja slides 64-71 do documento Aula_04_Modelagem_Geometrica_3D.pdf).
-	//
-	model_coefficients := []float32{
-		// Vértices de um cubo
-		//    X      Y     Z     W
-		-0.5, 0.5, 0.0, 1.0, // 0
-		0.5, 0.5, 0.0, 1.0, // 1
-		0.5, -0.5, 0.0, 1.0, // 2
-		-0.5, -0.5, 0.0, 1.0, // 3
-	}
-
-	// Criamos o identificador (ID) de um Vertex Buffer Object (VBO).  Um VBO é
-	// um buffer de memória que irá conter os valores de um certo atributo de
-	// um conjunto de vértices; por exemplo: posição, cor, normais, coordenadas
-	// de textura.  Neste exemplo utilizaremos vários VBOs, um para cada tipo de atributo.
-	// Agora criamos um VBO para armazenarmos um atributo: posição.
-	var VBO_model_coefficients_id uint32
-	gl.GenBuffers(1, &VBO_model_coefficients_id)
-
-	// Criamos o identificador (ID) de um Vertex Array Object (VAO).  Um VAO
-	// contém a definição de vários atributos de um certo conjunto de vértices;
-	// isto é, um VAO irá conter ponteiros para vários VBOs.
-	var vertex_array_object_id uint32
-	gl.GenVertexArrays(1, &vertex_array_object_id)
-
-	// "Ligamos" o VAO ("bind"). Informamos que iremos atualizar o VAO cujo ID
-	// está contido na variável "vertex_array_object_id".
-	gl.BindVertexArray(vertex_array_object_id)
-
-	// "Ligamos" o VBO ("bind"). Informamos que o VBO cujo ID está contido na
-	// variável VBO_model_coefficients_id será modificado a seguir. A
-	// constante "gl.ARRAY_BUFFER" informa que esse buffer é de fato um VBO, e
-	// irá conter atributos de vértices.
-	gl.BindBuffer(gl.ARRAY_BUFFER, VBO_model_coefficients_id)
-
-	// Alocamos memória para o VBO "ligado" acima. Como queremos armazenar
-	// nesse VBO todos os valores contidos no array "model_coefficients", pedimos
-	// para alocar um número de bytes exatamente igual ao tamanho ("size")
-	// desse array. A constante "gl.STATIC_DRAW" dá uma dica para o driver da
-	// GPU sobre como utilizaremos os dados do VBO. Neste caso, estamos dizendo
-	// que não pretendemos alterar tais dados (são estáticos: "STATIC"), e
-	// também dizemos que tais dados serão utilizados para renderizar ou
-	// desenhar ("DRAW").  Pense que:
-	//
-	//            glBufferData()  ==  malloc() do C  ==  new do C++.
-	//
-
-	gl.BufferData(gl.ARRAY_BUFFER, len(model_coefficients)*4, nil, gl.STATIC_DRAW)
-
-	// Finalmente, copiamos os valores do array model_coefficients para dentro do
-	// VBO "ligado" acima.  Pense que:
-	//
-	//            glBufferSubData()  ==  memcpy() do C.
-	//
-	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(model_coefficients)*4, gl.Ptr(model_coefficients))
-
-	// Precisamos então informar um índice de "local" ("location"), o qual será
-	// utilizado no shader "shader_vertex.glsl" para acessar os valores
-	// armazenados no VBO "ligado" acima. Também, informamos a dimensão (número de
-	// coeficientes) destes atributos. Como em nosso caso são pontos em coordenadas
-	// homogêneas, temos quatro coeficientes por vértice (X,Y,Z,W). Isso define
-	// um tipo de dado chamado de "vec4" em "shader_vertex.glsl": um vetor com
-	// quatro coeficientes. Finalmente, informamos que os dados estão em ponto
-	// flutuante com 32 bits (gl.FLOAT).
-	// Esta função também informa que o VBO "ligado" acima em glBindBuffer()
-	// está dentro do VAO "ligado" acima por glBindVertexArray().
-	// Veja https://www.khronos.org/opengl/wiki/Vertex_Specification#Vertex_Buffer_Object
-	location := uint32(0)            // "(location = 0)" em "shader_vertex.glsl"
-	number_of_dimensions := int32(4) // vec4 em "shader_vertex.glsl"
-	gl.VertexAttribPointer(location, number_of_dimensions, gl.FLOAT, false, 0, nil)
-
-	// "Ativamos" os atributos. Informamos que os atributos com índice de local
-	// definido acima, na variável "location", deve ser utilizado durante o
-	// rendering.
-	gl.EnableVertexAttribArray(location)
-
-	// "Desligamos" o VBO, evitando assim que operações posteriores venham a
-	// alterar o mesmo. Isso evita bugs.
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
-
-	// Agora repetimos todos os passos acima para atribuir um novo atributo a
-	// cada vértice: uma cor (veja slides 107-110 do documento Aula_03_Rendering_Pipeline_Grafico.pdf e slide 72 do documento Aula_04_Modelagem_Geometrica_3D.pdf).
-	// Tal cor é definida como coeficientes RGBA: Red, Green, Blue, Alpha;
-	// isto é: Vermelho, Verde, Azul, Alpha (valor de transparência).
-	// Conversaremos sobre sistemas de cores nas aulas de Modelos de Iluminação.
-	color_coefficients := []float32{
-
-		0.0, 0.1, 0.0, 1.0,
-		0.0, 1.0, 0.0, 1.0,
-		0.0, 1.0, 0.0, 1.0,
-		0.0, 0.1, 0.0, 1.0,
-	}
-	if blockType == 0 {
-		color_coefficients = []float32{
-
-			0.0, 0.0, 0.1, 1.0,
-			0.0, 0.0, 1.0, 1.0,
-			0.0, 0.0, 1.0, 1.0,
-			0.0, 0.0, 0.1, 1.0,
-		}
-	}
-
-	var VBO_color_coefficients_id uint32
-	gl.GenBuffers(1, &VBO_color_coefficients_id)
-	gl.BindBuffer(gl.ARRAY_BUFFER, VBO_color_coefficients_id)
-	gl.BufferData(gl.ARRAY_BUFFER, len(color_coefficients)*4, nil, gl.STATIC_DRAW)
-	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(color_coefficients)*4, gl.Ptr(color_coefficients))
-	location = 1             // "(location = 1)" em "shader_vertex.glsl"
-	number_of_dimensions = 4 // vec4 em "shader_vertex.glsl"
-	gl.VertexAttribPointer(location, number_of_dimensions, gl.FLOAT, false, 0, gl.PtrOffset(0))
-	gl.EnableVertexAttribArray(location)
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
-
-	// Vamos então definir polígonos utilizando os vértices do array
-	// model_coefficients.
-	//
-	// Para referência sobre os modos de renderização, veja slides 124-130 do documento Aula_04_Modelagem_Geometrica_3D.pdf.
-	//
-	// Este vetor "indices" define a TOPOLOGIA (veja slides 64-71 do documento Aula_04_Modelagem_Geometrica_3D.pdf).
-	//
-	indices := []uint32{
-		3, 2, 1, 1, 0, 3,
-	}
-
-	// Criamos um primeiro objeto virtual (SceneObject) que se refere às faces
-	// coloridas do cubo.
-
-	// Adicionamos o objeto criado acima na nossa cena virtual (g_VirtualScene).
-
-	// Criamos um buffer OpenGL para armazenar os índices acima
-	var indices_id uint32
-	gl.GenBuffers(1, &indices_id)
-
-	// "Ligamos" o buffer. Note que o tipo agora é gl.ELEMENT_ARRAY_BUFFER.
-	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, indices_id)
-
-	// Alocamos memória para o buffer.
-	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, nil, gl.STATIC_DRAW)
-
-	// Copiamos os valores do array indices[] para dentro do buffer.
-	gl.BufferSubData(gl.ELEMENT_ARRAY_BUFFER, 0, len(indices)*4, gl.Ptr(indices))
-
-	// NÃO faça a chamada abaixo! Diferente de um VBO (gl.ARRAY_BUFFER), um
-	// array de índices (gl.ELEMENT_ARRAY_BUFFER) não pode ser "desligado",
-	// caso contrário o VAO irá perder a informação sobre os índices.
-	//
-	// glBindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0); // XXX Errado!
-	//
-
-	// "Desligamos" o VAO, evitando assim que operações posteriores venham a
-	// alterar o mesmo. Isso evita bugs.
-	gl.BindVertexArray(0)
-
-	// Retornamos o ID do VAO. Isso é tudo que será necessário para renderizar
-	// os triângulos definidos acima. Veja a chamada glDrawElements() em main().
-
-	CommonFaceGeometry2.FirstIndex = gl.PtrOffset(0) // Primeiro índice está em indices[0]
-	CommonFaceGeometry2.NumIndices = len(indices)    // Último índice está em indices[35]; total de 36 índices.
-	CommonFaceGeometry2.RenderingMode = gl.TRIANGLES // Índices correspondem ao tipo de rasterização gl.TRIANGLES.
-	CommonFaceGeometry2.Vertexes = model_coefficients
-	CommonFaceGeometry2.VaoID = vertex_array_object_id
-
+// BuildFace returns a single block face; every face of every block is drawn
+// by rotating and translating this same quad. It is split along the diagonal
+// between vertices 1 and 3.
+func BuildFace() MeshData {
+	return buildFace([]uint32{3, 2, 1, 1, 0, 3})
 }
 
-func BuildFaceEdges() {
-	// Primeiro, definimos os atributos de cada vértice.
-
-	// A posição de cada vértice é definida por coeficientes em um sistema de
-	// coordenadas local de cada modelo geométrico. Note o uso de coordenadas
-	// homogêneas.  Veja as seguintes referências:
-	//
-	//  - slides 35-48 do documento Aula_08_Sistemas_de_Coordenadas.pdf;
-	//  - slides 184-190 do documento Aula_08_Sistemas_de_Coordenadas.pdf;
-	//
-	// Este vetor "model_coefficients" define a GEOMETRIA (veja slides 64-71 do documento Aula_04_Modelagem_Geometrica_3D.pdf).
-	//
-	model_coefficients := []float32{
-		// Vértices de um cubo
-		//    X      Y     Z     W
-		-0.5, 0.5, 0.0, 1.0, // 0
-		0.5, 0.5, 0.0, 1.0, // 1
-		0.5, -0.5, 0.0, 1.0, // 2
-		-0.5, -0.5, 0.0, 1.0, // 3
-	}
-	// Criamos o identificador (ID) de um Vertex Buffer Object (VBO).  Um VBO é
-	// um buffer de memória que irá conter os valores de um certo atributo de
-	// um conjunto de vértices; por exemplo: posição, cor, normais, coordenadas
-	// de textura.  Neste exemplo utilizaremos vários VBOs, um para cada tipo de atributo.
-	// Agora criamos um VBO para armazenarmos um atributo: posição.
-	var VBO_model_coefficients_id uint32
-	gl.GenBuffers(1, &VBO_model_coefficients_id)
-
-	// Criamos o identificador (ID) de um Vertex Array Object (VAO).  Um VAO
-	// contém a definição de vários atributos de um certo conjunto de vértices;
-	// isto é, um VAO irá conter ponteiros para vários VBOs.
-	var vertex_array_object_id uint32
-	gl.GenVertexArrays(1, &vertex_array_object_id)
-
-	// "Ligamos" o VAO ("bind"). Informamos que iremos atualizar o VAO cujo ID
-	// está contido na variável "vertex_array_object_id".
-	gl.BindVertexArray(vertex_array_object_id)
-
-	// "Ligamos" o VBO ("bind"). Informamos que o VBO cujo ID está contido na
-	// variável VBO_model_coefficients_id será modificado a seguir. A
-	// constante "gl.ARRAY_BUFFER" informa que esse buffer é de fato um VBO, e
-	// irá conter atributos de vértices.
-	gl.BindBuffer(gl.ARRAY_BUFFER, VBO_model_coefficients_id)
-
-	// Alocamos memória para o VBO "ligado" acima. Como queremos armazenar
-	// nesse VBO todos os valores contidos no array "model_coefficients", pedimos
-	// para alocar um número de bytes exatamente igual ao tamanho ("size")
-	// desse array. A constante "gl.STATIC_DRAW" dá uma dica para o driver da
-	// GPU sobre como utilizaremos os dados do VBO. Neste caso, estamos dizendo
-	// que não pretendemos alterar tais dados (são estáticos: "STATIC"), e
-	// também dizemos que tais dados serão utilizados para renderizar ou
-	// desenhar ("DRAW").  Pense que:
-	//
-	//            glBufferData()  ==  malloc() do C  ==  new do C++.
-	//
-
-	gl.BufferData(gl.ARRAY_BUFFER, len(model_coefficients)*4, nil, gl.STATIC_DRAW)
-
-	// Finalmente, copiamos os valores do array model_coefficients para dentro do
-	// VBO "ligado" acima.  Pense que:
-	//
-	//            glBufferSubData()  ==  memcpy() do C.
-	//
-	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(model_coefficients)*4, gl.Ptr(model_coefficients))
-
-	// Precisamos então informar um índice de "local" ("location"), o qual será
-	// utilizado no shader "shader_vertex.glsl" para acessar os valores
-	// armazenados no VBO "ligado" acima. Também, informamos a dimensão (número de
-	// coeficientes) destes atributos. Como em nosso caso são pontos em coordenadas
-	// homogêneas, temos quatro coeficientes por vértice (X,Y,Z,W). Isso define
-	// um tipo de dado chamado de "vec4" em "shader_vertex.glsl": um vetor com
-	// quatro coeficientes. Finalmente, informamos que os dados estão em ponto
-	// flutuante com 32 bits (gl.FLOAT).
-	// Esta função também informa que o VBO "ligado" acima em glBindBuffer()
-	// está dentro do VAO "ligado" acima por glBindVertexArray().
-	// Veja https://www.khronos.org/opengl/wiki/Vertex_Specification#Vertex_Buffer_Object
-	location := uint32(0)            // "(location = 0)" em "shader_vertex.glsl"
-	number_of_dimensions := int32(4) // vec4 em "shader_vertex.glsl"
-	gl.VertexAttribPointer(location, number_of_dimensions, gl.FLOAT, false, 0, nil)
-
-	// "Ativamos" os atributos. Informamos que os atributos com índice de local
-	// definido acima, na variável "location", deve ser utilizado durante o
-	// rendering.
-	gl.EnableVertexAttribArray(location)
-
-	// "Desligamos" o VBO, evitando assim que operações posteriores venham a
-	// alterar o mesmo. Isso evita bugs.
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+// BuildFlippedFace is BuildFace split along the diagonal between vertices 0 and 2
+func BuildFlippedFace() MeshData {
+	return buildFace([]uint32{0, 3, 2, 2, 1, 0})
+}
 
-	// Vamos então definir polígonos utilizando os vértices do array
-	// model_coefficients.
-	//
-	// Para referência sobre os modos de renderização, veja slides 124-130 do documento Aula_04_Modelagem_Geometrica_3D.pdf.
-	//
-	// Este vetor "indices" define a TOPOLOGIA (veja slides 64-71 do documento Aula_04_Modelagem_Geometrica_3D.pdf).
-	//
-	indices := []uint32{
-		3, 2, 1, 1, 0, 3,
+func buildFace(indices []uint32) MeshData {
+	face := MeshData{
+		Indices:       indices,
+		RenderingMode: gl.TRIANGLES,
 	}
+	face.AddAttribute(AttributePosition, 4, append([]float32{}, faceVertices...))
+	face.AddAttribute(AttributeCorner, 1, []float32{0, 1, 2, 3})
 
-	// Adicionamos o objeto criado acima na nossa cena virtual (g_VirtualScene).
-
-	// Criamos um buffer OpenGL para armazenar os índices acima
-	var indices_id uint32
-	gl.GenBuffers(1, &indices_id)
-
-	// "Ligamos" o buffer. Note que o tipo agora é gl.ELEMENT_ARRAY_BUFFER.
-	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, indices_id)
-
-	// Alocamos memória para o buffer.
-	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, nil, gl.STATIC_DRAW)
-
-	// Copiamos os valores do array indices[] para dentro do buffer.
-	gl.BufferSubData(gl.ELEMENT_ARRAY_BUFFER, 0, len(indices)*4, gl.Ptr(indices))
-
-	// NÃO faça a chamada abaixo! Diferente de um VBO (gl.ARRAY_BUFFER), um
-	// array de índices (gl.ELEMENT_ARRAY_BUFFER) não pode ser "desligado",
-	// caso contrário o VAO irá perder a informação sobre os índices.
-	//
-	// glBindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0); // XXX Errado!
-	//
-
-	// "Desligamos" o VAO, evitando assim que operações posteriores venham a
-	// alterar o mesmo. Isso evita bugs.
-	gl.BindVertexArray(0)
+	return face
+}
 
-	// Retornamos o ID do VAO. Isso é tudo que será necessário para renderizar
-	// os triângulos definidos acima. Veja a chamada glDrawElements() em main().
-	cubeEdges := GeometryInformation{}
-	cubeEdges.FirstIndex = gl.PtrOffset(0) // Primeiro índice está em indices[0]
-	cubeEdges.NumIndices = len(indices)    // Último índice está em indices[35]; total de 36 índices.
-	cubeEdges.RenderingMode = gl.LINES     // Índices correspondem ao tipo de rasterização gl.TRIANGLES.
-	cubeEdges.VaoID = vertex_array_object_id
-	CommonFaceEdgeGeometry = cubeEdges
+// SetFaceLighting sets the per-vertex occlusion and light of the next faces
+// drawn, indexed by the vertex of the quad. They are constant vertex
+// attributes, so the shared face meshes need no per-block buffers.
+func SetFaceLighting(ao, light [4]float32) {
+	gl.VertexAttrib4f(AttributeFaceAO, ao[0], ao[1], ao[2], ao[3])
+	gl.VertexAttrib4f(AttributeFaceLight, light[0], light[1], light[2], light[3])
 }
 
-func BuildFace2() {
-	cubeVertices := []float32{
-		-0.5, -0.5, -0.5, 0.0, 0.0,
-		0.5, -0.5, -0.5, 0.5, 0.0,
-		-0.5, -0.5, 0.5, 0.0, 0.5,
-		0.5, -0.5, -0.5, 0.5, 0.0,
-		0.5, -0.5, 0.5, 0.5, 0.5,
-		-0.5, -0.5, 0.5, 0.0, 0.5,
+// BuildFaceEdges returns the outline of BuildFace
+func BuildFaceEdges() MeshData {
+	edges := MeshData{
+		Indices: []uint32{
+			0, 1, // linha 1
+			1, 2, // linha 2
+			2, 3, // linha 3
+			3, 0, // linha 4
+		},
+		RenderingMode: gl.LINES,
 	}
-	// Configure the vertex data
-	var vao uint32
-	gl.GenVertexArrays(1, &vao)
-	gl.BindVertexArray(vao)
-
-	var VBO_model_coefficients_id uint32
-	gl.GenBuffers(1, &VBO_model_coefficients_id)
-
-	// Criamos o identificador (ID) de um Vertex Array Object (VAO).  Um VAO
-	// contém a definição de vários atributos de um certo conjunto de vértices;
-	// isto é, um VAO irá conter ponteiros para vários VBOs.
-	var vertex_array_object_id uint32
-	gl.GenVertexArrays(1, &vertex_array_object_id)
-
-	// "Ligamos" o VAO ("bind"). Informamos que iremos atualizar o VAO cujo ID
-	// está contido na variável "vertex_array_object_id".
-	gl.BindVertexArray(vertex_array_object_id)
+	edges.AddAttribute(AttributePosition, 4, append([]float32{}, faceVertices...))
 
-	// "Ligamos" o VBO ("bind"). Informamos que o VBO cujo ID está contido na
-	// variável VBO_model_coefficients_id será modificado a seguir. A
-	// constante "gl.ARRAY_BUFFER" informa que esse buffer é de fato um VBO, e
-	// irá conter atributos de vértices.
-	gl.BindBuffer(gl.ARRAY_BUFFER, VBO_model_coefficients_id)
-
-	// Alocamos memória para o VBO "ligado" acima. Como queremos armazenar
-	// nesse VBO todos os valores contidos no array "model_coefficients", pedimos
-	// para alocar um número de bytes exatamente igual ao tamanho ("size")
-	// desse array. A constante "gl.STATIC_DRAW" dá uma dica para o driver da
-	// GPU sobre como utilizaremos os dados do VBO. Neste caso, estamos dizendo
-	// que não pretendemos alterar tais dados (são estáticos: "STATIC"), e
-	// também dizemos que tais dados serão utilizados para renderizar ou
-	// desenhar ("DRAW").  Pense que:
-	//
-	//            glBufferData()  ==  malloc() do C  ==  new do C++.
-	//
-
-	gl.BufferData(gl.ARRAY_BUFFER, len(cubeVertices)*4, nil, gl.STATIC_DRAW)
-
-	// Finalmente, copiamos os valores do array model_coefficients para dentro do
-	// VBO "ligado" acima.  Pense que:
-	//
-	//            glBufferSubData()  ==  memcpy() do C.
-	//
-	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(cubeVertices)*4, gl.Ptr(cubeVertices))
-
-	// Precisamos então informar um índice de "local" ("location"), o qual será
-	// utilizado no shader "shader_vertex.glsl" para acessar os valores
-	// armazenados no VBO "ligado" acima. Também, informamos a dimensão (número de
-	// coeficientes) destes atributos. Como em nosso caso são pontos em coordenadas
-	// homogêneas, temos quatro coeficientes por vértice (X,Y,Z,W). Isso define
-	// um tipo de dado chamado de "vec4" em "shader_vertex.glsl": um vetor com
-	// quatro coeficientes. Finalmente, informamos que os dados estão em ponto
-	// flutuante com 32 bits (gl.FLOAT).
-	// Esta função também informa que o VBO "ligado" acima em glBindBuffer()
-	// está dentro do VAO "ligado" acima por glBindVertexArray().
-	// Veja https://www.khronos.org/opengl/wiki/Vertex_Specification#Vertex_Buffer_Object
-	location := uint32(0)            // "(location = 0)" em "shader_vertex.glsl"
-	number_of_dimensions := int32(4) // vec4 em "shader_vertex.glsl"
-	gl.VertexAttribPointer(location, number_of_dimensions, gl.FLOAT, false, 0, nil)
-
-	// "Ativamos" os atributos. Informamos que os atributos com índice de local
-	// definido acima, na variável "location", deve ser utilizado durante o
-	// rendering.
-	gl.EnableVertexAttribArray(location)
+	return edges
+}
 
-	// "Desligamos" o VBO, evitando assim que operações posteriores venham a
-	// alterar o mesmo. Isso evita bugs.
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
-	CommonFaceGeometry.VaoID = vao
+// InitCommonMeshes uploads the shared meshes, it must be called after the
+// OpenGL context is created
+func InitCommonMeshes(program, crosshairProgram *shaders.Program) {
+	FaceMesh = NewMesh(BuildFace(), NewMaterial(program, shaders.ObjectBlock))
+	FlippedFaceMesh = NewMesh(BuildFlippedFace(), FaceMesh.Material)
+	FaceEdgesMesh = NewMesh(BuildFaceEdges(), NewMaterial(program, shaders.ObjectBlock))
+	FaceEdgesMesh.Material.Uniforms["black"] = true
+	CrosshairMesh = NewMesh(BuildCrosshair(), NewMaterial(crosshairProgram, shaders.ObjectBlock))
 }
@@ -0,0 +1,62 @@
+package geometry
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
+)
+
+// Material describes how a mesh is shaded: the shader program, the textures
+// bound to texture units 0..n and the uniform values set before drawing
+type Material struct {
+	Shader   *shaders.Program
+	Textures []uint32
+	Uniforms map[string]interface{} // int32, float32, bool, mgl32.Vec2, mgl32.Vec3, mgl32.Vec4 or mgl32.Mat4
+	ObjectID shaders.ObjectID       // "object_id" uniform, see standard_shader_fragment.glsl
+}
+
+func NewMaterial(shader *shaders.Program, objectID shaders.ObjectID, textures ...uint32) *Material {
+	return &Material{
+		Shader:   shader,
+		Textures: textures,
+		Uniforms: map[string]interface{}{},
+		ObjectID: objectID,
+	}
+}
+
+// Apply makes the material program current and uploads its textures and uniforms
+func (m *Material) Apply() {
+	m.Shader.Use()
+
+	for unit, texture := range m.Textures {
+		gl.ActiveTexture(gl.TEXTURE0 + uint32(unit))
+		gl.BindTexture(gl.TEXTURE_2D, texture)
+	}
+
+	m.Shader.SetInt("object_id", int32(m.ObjectID))
+
+	for name, value := range m.Uniforms {
+		switch v := value.(type) {
+		case int32:
+			m.Shader.SetInt(name, v)
+		case int:
+			m.Shader.SetInt(name, int32(v))
+		case bool:
+			m.Shader.SetBool(name, v)
+		case float32:
+			m.Shader.SetFloat(name, v)
+		case mgl32.Vec2:
+			m.Shader.SetVec2(name, v)
+		case mgl32.Vec3:
+			m.Shader.SetVec3(name, v)
+		case mgl32.Vec4:
+			m.Shader.SetVec4(name, v)
+		case mgl32.Mat4:
+			m.Shader.SetMat4(name, v)
+		default:
+			panic(fmt.Sprintf("material: unsupported uniform type %T for %v", value, name))
+		}
+	}
+}
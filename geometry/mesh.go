@@ -0,0 +1,213 @@
+package geometry
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/engine/metrics"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
+)
+
+// Locais ("layout (location = N)") dos atributos em "standard_shader_vertex.glsl"
+const (
+	AttributePosition  uint32 = 0
+	AttributeNormal    uint32 = 1
+	AttributeTexCoord  uint32 = 2
+	AttributeCorner    uint32 = 3 // índice do vértice no quadrado da face (0 a 3)
+	AttributeFaceAO    uint32 = 4 // constante por desenho, veja SetFaceLighting
+	AttributeFaceLight uint32 = 5
+)
+
+// VertexAttribute is one per-vertex attribute, stored as Components floats per vertex
+type VertexAttribute struct {
+	Location   uint32
+	Components int32
+	Data       []float32
+}
+
+// MeshData is the CPU-side description of a mesh: its vertex attributes and
+// the indices that define the topology. It has no OpenGL state, so it can be
+// built and inspected without a context.
+type MeshData struct {
+	Attributes    []VertexAttribute
+	Indices       []uint32
+	RenderingMode uint32 // gl.TRIANGLES, gl.LINES...
+}
+
+// AddAttribute appends a vertex attribute to the mesh data
+func (md *MeshData) AddAttribute(location uint32, components int32, data []float32) {
+	md.Attributes = append(md.Attributes, VertexAttribute{
+		Location:   location,
+		Components: components,
+		Data:       data,
+	})
+}
+
+// Attribute returns the attribute bound to the given location, if any
+func (md MeshData) Attribute(location uint32) (VertexAttribute, bool) {
+	for _, attribute := range md.Attributes {
+		if attribute.Location == location {
+			return attribute, true
+		}
+	}
+
+	return VertexAttribute{}, false
+}
+
+// VertexCount is the number of vertices described by the position attribute
+func (md MeshData) VertexCount() int {
+	position, ok := md.Attribute(AttributePosition)
+	if !ok || position.Components == 0 {
+		return 0
+	}
+
+	return len(position.Data) / int(position.Components)
+}
+
+// Validate checks that every attribute has the same vertex count and that
+// indices do not reference missing vertices
+func (md MeshData) Validate() error {
+	if _, ok := md.Attribute(AttributePosition); !ok {
+		return fmt.Errorf("mesh: missing position attribute")
+	}
+
+	vertexCount := md.VertexCount()
+	for _, attribute := range md.Attributes {
+		if attribute.Components < 1 || attribute.Components > 4 {
+			return fmt.Errorf("mesh: attribute %v has %v components", attribute.Location, attribute.Components)
+		}
+		if len(attribute.Data) != vertexCount*int(attribute.Components) {
+			return fmt.Errorf("mesh: attribute %v has %v values, expected %v", attribute.Location, len(attribute.Data), vertexCount*int(attribute.Components))
+		}
+	}
+
+	for _, index := range md.Indices {
+		if int(index) >= vertexCount {
+			return fmt.Errorf("mesh: index %v out of range (%v vertices)", index, vertexCount)
+		}
+	}
+
+	return nil
+}
+
+// Mesh is a MeshData uploaded to the GPU together with the material used to draw it
+type Mesh struct {
+	VaoID         uint32
+	NumIndices    int32
+	RenderingMode uint32
+	Material      *Material
+	vbos          []uint32
+	ebo           uint32
+}
+
+// NewMesh uploads the mesh data to the GPU. The returned mesh owns its VAO and
+// buffers until Destroy is called.
+func NewMesh(data MeshData, material *Material) *Mesh {
+	if err := data.Validate(); err != nil {
+		panic(err)
+	}
+
+	mesh := &Mesh{
+		NumIndices:    int32(len(data.Indices)),
+		RenderingMode: data.RenderingMode,
+		Material:      material,
+	}
+
+	// Um VAO contém a definição de vários atributos de um certo conjunto de
+	// vértices; isto é, um VAO irá conter ponteiros para vários VBOs.
+	gl.GenVertexArrays(1, &mesh.VaoID)
+	gl.BindVertexArray(mesh.VaoID)
+
+	// Um VBO para cada atributo (posição, normal, coordenadas de textura...),
+	// ligado ao "location" correspondente do vertex shader.
+	for _, attribute := range data.Attributes {
+		var vbo uint32
+		gl.GenBuffers(1, &vbo)
+		gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+		gl.BufferData(gl.ARRAY_BUFFER, len(attribute.Data)*4, gl.Ptr(attribute.Data), gl.STATIC_DRAW)
+		gl.VertexAttribPointer(attribute.Location, attribute.Components, gl.FLOAT, false, 0, nil)
+		gl.EnableVertexAttribArray(attribute.Location)
+		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+		mesh.vbos = append(mesh.vbos, vbo)
+	}
+
+	// Buffer de índices. Diferente de um VBO, ele não pode ser "desligado"
+	// antes do VAO, caso contrário o VAO irá perder a informação sobre os índices.
+	gl.GenBuffers(1, &mesh.ebo)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, mesh.ebo)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(data.Indices)*4, gl.Ptr(data.Indices), gl.STATIC_DRAW)
+
+	gl.BindVertexArray(0)
+
+	return mesh
+}
+
+// Destroy frees the GPU resources of the mesh
+func (m *Mesh) Destroy() {
+	if m.VaoID == 0 {
+		return
+	}
+
+	gl.DeleteBuffers(int32(len(m.vbos)), &m.vbos[0])
+	gl.DeleteBuffers(1, &m.ebo)
+	gl.DeleteVertexArrays(1, &m.VaoID)
+
+	m.vbos = nil
+	m.ebo = 0
+	m.VaoID = 0
+}
+
+// Draw renders the mesh with its own material and the given model matrix
+func (m *Mesh) Draw(transform mgl32.Mat4) {
+	m.DrawWithMaterial(m.Material, transform)
+}
+
+// DrawWithProgram renders only the geometry with a program already set up by
+// the caller, e.g. depth-only passes, ignoring the material
+func (m *Mesh) DrawWithProgram(program *shaders.Program, transform mgl32.Mat4) {
+	if m.VaoID == 0 {
+		return
+	}
+
+	program.Use()
+	program.SetMat4("model", transform)
+
+	gl.BindVertexArray(m.VaoID)
+	gl.DrawElements(m.RenderingMode, m.NumIndices, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	gl.BindVertexArray(0)
+	metrics.DrawCall(m.triangles())
+}
+
+// DrawWithMaterial renders the mesh with another material, used when the same
+// geometry is shared by objects with different textures (e.g. block faces)
+func (m *Mesh) DrawWithMaterial(material *Material, transform mgl32.Mat4) {
+	if m.VaoID == 0 {
+		return
+	}
+
+	if material != nil {
+		material.Apply()
+		material.Shader.SetMat4("model", transform)
+	}
+
+	gl.BindVertexArray(m.VaoID)
+	gl.DrawElements(m.RenderingMode, m.NumIndices, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	gl.BindVertexArray(0)
+	metrics.DrawCall(m.triangles())
+}
+
+// Quantidade de triângulos desenhados pela malha, 0 para linhas e pontos
+func (m *Mesh) triangles() int {
+	switch m.RenderingMode {
+	case gl.TRIANGLES:
+		return int(m.NumIndices) / 3
+	case gl.TRIANGLE_STRIP, gl.TRIANGLE_FAN:
+		if m.NumIndices < 3 {
+			return 0
+		}
+		return int(m.NumIndices) - 2
+	}
+
+	return 0
+}
@@ -0,0 +1,135 @@
+package geometry
+
+import (
+	"testing"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Posição do vértice i da malha, sem a coordenada homogênea
+func vertexAt(t *testing.T, md MeshData, i uint32) mgl32.Vec3 {
+	t.Helper()
+	position, _ := md.Attribute(AttributePosition)
+	p := position.Data[int(i)*int(position.Components):]
+	return mgl32.Vec3{p[0], p[1], p[2]}
+}
+
+func TestBuilders(t *testing.T) {
+	obj := []float32{
+		0, 0, 0, 1, 1, 0, 0, 1, 0, 1, 0, 1,
+		0, 0, 1, 1, 1, 0, 1, 1, 0, 1, 1, 1,
+	}
+
+	tests := []struct {
+		name       string
+		data       MeshData
+		mode       uint32
+		vertices   int
+		indices    int
+		attributes map[uint32]int32 // componentes por local
+	}{
+		{"cubo", BuildCube(1, 2, 3, 2, 1, 1, 1), gl.TRIANGLES, 8, 36, map[uint32]int32{AttributePosition: 4, AttributeNormal: 4}},
+		{"arestas do cubo", BuildCubeEdges(0, 0, 0, 1), gl.LINES, 8, 24, map[uint32]int32{AttributePosition: 4}},
+		{"face", BuildFace(), gl.TRIANGLES, 4, 6, map[uint32]int32{AttributePosition: 4, AttributeCorner: 1}},
+		{"face invertida", BuildFlippedFace(), gl.TRIANGLES, 4, 6, map[uint32]int32{AttributePosition: 4, AttributeCorner: 1}},
+		{"arestas da face", BuildFaceEdges(), gl.LINES, 4, 8, map[uint32]int32{AttributePosition: 4}},
+		{"linha", BuildLine(mgl32.Vec3{1, 2, 3}, mgl32.Vec3{4, 5, 6}), gl.LINES, 2, 2, map[uint32]int32{AttributePosition: 4}},
+		{"obj", BuildObj(obj), gl.TRIANGLES, 6, 6, map[uint32]int32{AttributePosition: 4}},
+		{"obj vazio", BuildObj(nil), gl.TRIANGLES, 0, 0, map[uint32]int32{AttributePosition: 4}},
+	}
+
+	for _, test := range tests {
+		if err := test.data.Validate(); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if test.data.RenderingMode != test.mode {
+			t.Errorf("%s: rendering mode = %v, want %v", test.name, test.data.RenderingMode, test.mode)
+		}
+		if got := test.data.VertexCount(); got != test.vertices {
+			t.Errorf("%s: %d vertices, want %d", test.name, got, test.vertices)
+		}
+		if got := len(test.data.Indices); got != test.indices {
+			t.Errorf("%s: %d indices, want %d", test.name, got, test.indices)
+		}
+		if len(test.data.Attributes) != len(test.attributes) {
+			t.Errorf("%s: %d attributes, want %d", test.name, len(test.data.Attributes), len(test.attributes))
+		}
+		for location, components := range test.attributes {
+			attribute, ok := test.data.Attribute(location)
+			if !ok || attribute.Components != components {
+				t.Errorf("%s: attribute %d = %+v, want %d components", test.name, location, attribute.Components, components)
+			}
+		}
+
+		// todos os vértices são usados
+		used := map[uint32]bool{}
+		for _, index := range test.data.Indices {
+			used[index] = true
+		}
+		if len(used) != test.vertices {
+			t.Errorf("%s: indices use %d of %d vertices", test.name, len(used), test.vertices)
+		}
+	}
+}
+
+func TestFaceDiagonal(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     MeshData
+		diagonal [2]uint32
+	}{
+		{"face", BuildFace(), [2]uint32{1, 3}},
+		{"face invertida", BuildFlippedFace(), [2]uint32{0, 2}},
+	}
+
+	for _, test := range tests {
+		first, second := test.data.Indices[:3], test.data.Indices[3:]
+
+		// a aresta comum aos dois triângulos é a diagonal
+		shared := map[uint32]bool{}
+		for _, a := range first {
+			for _, b := range second {
+				if a == b {
+					shared[a] = true
+				}
+			}
+		}
+		if len(shared) != 2 || !shared[test.diagonal[0]] || !shared[test.diagonal[1]] {
+			t.Errorf("%s: triangles share %v, want the diagonal %v", test.name, shared, test.diagonal)
+		}
+
+		// os dois triângulos viram para +z, como a face
+		for _, triangle := range [][]uint32{first, second} {
+			a, b, c := vertexAt(t, test.data, triangle[0]), vertexAt(t, test.data, triangle[1]), vertexAt(t, test.data, triangle[2])
+			if normal := b.Sub(a).Cross(c.Sub(a)); normal.Z() <= 0 {
+				t.Errorf("%s: triangle %v faces %v, want +z", test.name, triangle, normal)
+			}
+		}
+	}
+}
+
+func TestCubeShape(t *testing.T) {
+	center := mgl32.Vec3{1, 2, 3}
+	const size = 2
+	cube := BuildCube(center.X(), center.Y(), center.Z(), size, 1, 1, 1)
+	edges := BuildCubeEdges(center.X(), center.Y(), center.Z(), size)
+
+	for i := uint32(0); i < uint32(cube.VertexCount()); i++ {
+		offset := vertexAt(t, cube, i).Sub(center)
+		for axis := 0; axis < 3; axis++ {
+			if offset[axis] != size/2 && offset[axis] != -size/2 {
+				t.Errorf("vertex %d at %v is not a corner of the cube", i, offset)
+			}
+		}
+	}
+
+	// cada aresta liga cantos que diferem em um eixo só
+	for i := 0; i < len(edges.Indices); i += 2 {
+		difference := vertexAt(t, edges, edges.Indices[i]).Sub(vertexAt(t, edges, edges.Indices[i+1]))
+		if difference.Len() != size {
+			t.Errorf("edge %v has length %v, want %v", edges.Indices[i:i+2], difference.Len(), float32(size))
+		}
+	}
+}
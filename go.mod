@@ -7,9 +7,7 @@ require (
 	github.com/go-gl/gl v0.0.0-20211210172815-726fda9656d6
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20220320163800-277f93cfa958
 	github.com/go-gl/mathgl v1.0.0
+	golang.org/x/image v0.0.0-20210220032944-ac19c3e999fb
 )
 
-require (
-	github.com/tbogdala/noisey v1.0.0 // indirect
-	golang.org/x/image v0.0.0-20210220032944-ac19c3e999fb // indirect
-)
+require github.com/tbogdala/noisey v1.0.0 // indirect
@@ -0,0 +1,425 @@
+package lib
+
+// Amostragem das animações glTF na CPU. O resultado final são as matrizes das
+// juntas (joint matrices) de uma skin, prontas para skinning na CPU ou na GPU.
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type AnimationPath string
+
+const (
+	PathTranslation AnimationPath = "translation"
+	PathRotation    AnimationPath = "rotation"
+	PathScale       AnimationPath = "scale"
+)
+
+type Interpolation string
+
+const (
+	InterpolationLinear      Interpolation = "LINEAR"
+	InterpolationStep        Interpolation = "STEP"
+	InterpolationCubicSpline Interpolation = "CUBICSPLINE"
+)
+
+// AnimationSampler maps keyframe times (Input) to values (Output). For cubic
+// spline samplers every keyframe stores in-tangent, value and out-tangent.
+type AnimationSampler struct {
+	Input         []float32
+	Output        []float32
+	Components    int
+	Interpolation Interpolation
+}
+
+type AnimationChannel struct {
+	Node    int
+	Path    AnimationPath
+	Sampler AnimationSampler
+}
+
+type AnimationClip struct {
+	Name     string
+	Channels []AnimationChannel
+	Duration float32
+}
+
+// NodePose is the local transform of a node at a given animation time
+type NodePose struct {
+	Translation mgl32.Vec3
+	Rotation    mgl32.Quat
+	Scale       mgl32.Vec3
+}
+
+// Componentes dos valores de cada caminho animado
+var pathComponents = map[AnimationPath]int{
+	PathTranslation: 3,
+	PathRotation:    4,
+	PathScale:       3,
+}
+
+// Checks that the sampler values fit the animated path, so Apply can't index
+// past them
+func (c AnimationChannel) validate() error {
+	components, ok := pathComponents[c.Path]
+	if !ok {
+		return fmt.Errorf("unknown path %q", c.Path)
+	}
+	if c.Sampler.Components != components {
+		return fmt.Errorf("%s needs %v components, got %v", c.Path, components, c.Sampler.Components)
+	}
+
+	return c.Sampler.validate()
+}
+
+func (s AnimationSampler) validate() error {
+	if s.Components == 0 || len(s.Input) == 0 {
+		return fmt.Errorf("empty sampler")
+	}
+
+	values := 1
+	switch s.Interpolation {
+	case InterpolationLinear, InterpolationStep:
+	case InterpolationCubicSpline:
+		values = 3
+	default:
+		return fmt.Errorf("unknown interpolation %q", s.Interpolation)
+	}
+
+	if len(s.Output) != len(s.Input)*s.Components*values {
+		return fmt.Errorf("sampler has %v outputs for %v keyframes", len(s.Output)/s.Components, len(s.Input))
+	}
+
+	for i := 1; i < len(s.Input); i++ {
+		if s.Input[i] < s.Input[i-1] {
+			return fmt.Errorf("keyframe times must be increasing")
+		}
+	}
+
+	return nil
+}
+
+// Returns the value at keyframe k, component-wise
+func (s AnimationSampler) keyframe(k int) []float32 {
+	if s.Interpolation == InterpolationCubicSpline {
+		start := (k*3 + 1) * s.Components
+		return s.Output[start : start+s.Components]
+	}
+
+	return s.Output[k*s.Components : (k+1)*s.Components]
+}
+
+func (s AnimationSampler) inTangent(k int) []float32 {
+	start := k * 3 * s.Components
+	return s.Output[start : start+s.Components]
+}
+
+func (s AnimationSampler) outTangent(k int) []float32 {
+	start := (k*3 + 2) * s.Components
+	return s.Output[start : start+s.Components]
+}
+
+// Sample evaluates the sampler at time t. Times outside the keyframe range are
+// clamped. When rotation is true the 4 components are treated as a quaternion
+// (x, y, z, w) and interpolated with slerp.
+func (s AnimationSampler) Sample(t float32, rotation bool) []float32 {
+	out := make([]float32, s.Components)
+	last := len(s.Input) - 1
+
+	if t <= s.Input[0] || last == 0 {
+		copy(out, s.keyframe(0))
+		return out
+	}
+	if t >= s.Input[last] {
+		copy(out, s.keyframe(last))
+		return out
+	}
+
+	// finds the keyframe interval [k, k+1] containing t
+	k := 0
+	for k < last-1 && s.Input[k+1] <= t {
+		k++
+	}
+
+	t0, t1 := s.Input[k], s.Input[k+1]
+	dt := t1 - t0
+	u := float32(0)
+	if dt > 0 {
+		u = (t - t0) / dt
+	}
+
+	switch s.Interpolation {
+	case InterpolationStep:
+		copy(out, s.keyframe(k))
+	case InterpolationCubicSpline:
+		// Hermite spline, tangents are scaled by the keyframe interval
+		p0, m0 := s.keyframe(k), s.outTangent(k)
+		p1, m1 := s.keyframe(k+1), s.inTangent(k+1)
+		u2, u3 := u*u, u*u*u
+		h00 := 2*u3 - 3*u2 + 1
+		h10 := u3 - 2*u2 + u
+		h01 := -2*u3 + 3*u2
+		h11 := u3 - u2
+		for c := range out {
+			out[c] = h00*p0[c] + h10*dt*m0[c] + h01*p1[c] + h11*dt*m1[c]
+		}
+		if rotation {
+			q := quatFromComponents(out).Normalize()
+			copy(out, quatComponents(q))
+		}
+	default:
+		a, b := s.keyframe(k), s.keyframe(k+1)
+		if rotation {
+			q := slerp(quatFromComponents(a), quatFromComponents(b), u)
+			copy(out, quatComponents(q))
+		} else {
+			for c := range out {
+				out[c] = a[c] + (b[c]-a[c])*u
+			}
+		}
+	}
+
+	return out
+}
+
+// Spherical interpolation through the shortest arc
+func slerp(a, b mgl32.Quat, u float32) mgl32.Quat {
+	if a.Dot(b) < 0 {
+		b = b.Scale(-1)
+	}
+
+	return mgl32.QuatSlerp(a, b, u).Normalize()
+}
+
+func quatFromComponents(c []float32) mgl32.Quat {
+	return mgl32.Quat{W: c[3], V: mgl32.Vec3{c[0], c[1], c[2]}}
+}
+
+func quatComponents(q mgl32.Quat) []float32 {
+	return []float32{q.V[0], q.V[1], q.V[2], q.W}
+}
+
+// Apply writes the clip state at time t into pose. Nodes which are not
+// animated by the clip keep their current values.
+func (clip AnimationClip) Apply(t float32, pose []NodePose) {
+	for _, channel := range clip.Channels {
+		if channel.Node < 0 || channel.Node >= len(pose) {
+			continue
+		}
+
+		switch channel.Path {
+		case PathTranslation:
+			v := channel.Sampler.Sample(t, false)
+			pose[channel.Node].Translation = mgl32.Vec3{v[0], v[1], v[2]}
+		case PathRotation:
+			v := channel.Sampler.Sample(t, true)
+			pose[channel.Node].Rotation = quatFromComponents(v)
+		case PathScale:
+			v := channel.Sampler.Sample(t, false)
+			pose[channel.Node].Scale = mgl32.Vec3{v[0], v[1], v[2]}
+		}
+	}
+}
+
+// Matrix returns the local transform T * R * S of a pose
+func (p NodePose) Matrix() mgl32.Mat4 {
+	return mgl32.Translate3D(p.Translation.X(), p.Translation.Y(), p.Translation.Z()).
+		Mul4(p.Rotation.Mat4()).
+		Mul4(mgl32.Scale3D(p.Scale.X(), p.Scale.Y(), p.Scale.Z()))
+}
+
+// RestPose returns the local transforms stored in the file. Nodes defined by a
+// matrix are decomposed, as glTF forbids animating them anyway.
+func (m *GLTFModel) RestPose() []NodePose {
+	pose := make([]NodePose, len(m.Nodes))
+	for i, node := range m.Nodes {
+		if node.HasMatrix {
+			pose[i] = decompose(node.Matrix)
+		} else {
+			pose[i] = NodePose{Translation: node.Translation, Rotation: node.Rotation, Scale: node.Scale}
+		}
+	}
+
+	return pose
+}
+
+// Decomposes an affine transform without shear into translation, rotation and scale
+func decompose(m mgl32.Mat4) NodePose {
+	scale := mgl32.Vec3{m.Col(0).Vec3().Len(), m.Col(1).Vec3().Len(), m.Col(2).Vec3().Len()}
+	rotation := mgl32.Mat3{}
+	for c := 0; c < 3; c++ {
+		column := m.Col(c).Vec3()
+		if scale[c] != 0 {
+			column = column.Mul(1 / scale[c])
+		}
+		rotation.SetCol(c, column)
+	}
+
+	return NodePose{
+		Translation: m.Col(3).Vec3(),
+		Rotation:    mgl32.Mat4ToQuat(rotation.Mat4()).Normalize(),
+		Scale:       scale,
+	}
+}
+
+// GlobalTransforms computes the model-space transform of every node for a pose
+func (m *GLTFModel) GlobalTransforms(pose []NodePose) []mgl32.Mat4 {
+	globals := make([]mgl32.Mat4, len(m.Nodes))
+	visited := make([]bool, len(m.Nodes))
+
+	var visit func(node int, parent mgl32.Mat4)
+	visit = func(node int, parent mgl32.Mat4) {
+		if visited[node] {
+			return
+		}
+		visited[node] = true
+		globals[node] = parent.Mul4(pose[node].Matrix())
+		for _, child := range m.Nodes[node].Children {
+			visit(child, globals[node])
+		}
+	}
+
+	for i, node := range m.Nodes {
+		if node.Parent == -1 {
+			visit(i, mgl32.Ident4())
+		}
+	}
+
+	return globals
+}
+
+// JointMatrices returns, for every joint of the skin, the matrix that takes a
+// vertex from bind pose to the current pose in the space of the skinned mesh node
+func (m *GLTFModel) JointMatrices(skinIndex, meshNode int, globals []mgl32.Mat4) []mgl32.Mat4 {
+	skin := m.Skins[skinIndex]
+	inverseMeshNode := mgl32.Ident4()
+	if meshNode >= 0 {
+		inverseMeshNode = globals[meshNode].Inv()
+	}
+
+	joints := make([]mgl32.Mat4, len(skin.Joints))
+	for i, joint := range skin.Joints {
+		joints[i] = inverseMeshNode.Mul4(globals[joint]).Mul4(skin.InverseBindMatrices[i])
+	}
+
+	return joints
+}
+
+// SkinPositions applies linear blend skinning to the positions of a primitive
+func SkinPositions(primitive GLTFPrimitive, jointMatrices []mgl32.Mat4) []mgl32.Vec3 {
+	if len(primitive.Joints) != len(primitive.Positions) || len(primitive.Weights) != len(primitive.Positions) {
+		return primitive.Positions
+	}
+
+	skinned := make([]mgl32.Vec3, len(primitive.Positions))
+	for i, position := range primitive.Positions {
+		skin := mgl32.Mat4{}
+		for j := 0; j < 4; j++ {
+			weight := primitive.Weights[i][j]
+			joint := int(primitive.Joints[i][j])
+			if weight == 0 || joint >= len(jointMatrices) {
+				continue
+			}
+			skin = skin.Add(jointMatrices[joint].Mul(weight))
+		}
+		skinned[i] = skin.Mul4x1(position.Vec4(1)).Vec3()
+	}
+
+	return skinned
+}
+
+// Animator plays one clip of a model and keeps track of the playback time
+type Animator struct {
+	Model   *GLTFModel
+	Clip    int
+	Time    float32
+	Speed   float32
+	Looping bool
+	Playing bool
+}
+
+func NewAnimator(model *GLTFModel) *Animator {
+	return &Animator{
+		Model:   model,
+		Clip:    -1,
+		Speed:   1,
+		Looping: true,
+	}
+}
+
+// Play starts the clip with the given name, returning false if it does not exist
+func (a *Animator) Play(name string) bool {
+	for i, clip := range a.Model.Animations {
+		if clip.Name == name {
+			if a.Clip != i {
+				a.Clip = i
+				a.Time = 0
+			}
+			a.Playing = true
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *Animator) Stop() {
+	a.Playing = false
+	a.Time = 0
+}
+
+// Update advances the playback time, deltaTime is in seconds
+func (a *Animator) Update(deltaTime float64) {
+	if !a.Playing || a.Clip < 0 {
+		return
+	}
+
+	duration := a.Model.Animations[a.Clip].Duration
+	a.Time += float32(deltaTime) * a.Speed
+	if duration <= 0 {
+		a.Time = 0
+		return
+	}
+
+	if a.Looping {
+		a.Time = float32(math.Mod(float64(a.Time), float64(duration)))
+		if a.Time < 0 {
+			a.Time += duration
+		}
+	} else if a.Time >= duration {
+		a.Time = duration
+		a.Playing = false
+	}
+}
+
+// Pose returns the local transforms of every node at the current time
+func (a *Animator) Pose() []NodePose {
+	pose := a.Model.RestPose()
+	if a.Clip >= 0 {
+		a.Model.Animations[a.Clip].Apply(a.Time, pose)
+	}
+
+	return pose
+}
+
+// JointMatrices returns the joint matrices of the first skinned mesh node
+// using the given skin, or nil if the model has no such skin
+func (a *Animator) JointMatrices(skinIndex int) []mgl32.Mat4 {
+	if skinIndex < 0 || skinIndex >= len(a.Model.Skins) {
+		return nil
+	}
+
+	globals := a.Model.GlobalTransforms(a.Pose())
+	meshNode := -1
+	for i, node := range a.Model.Nodes {
+		if node.Skin == skinIndex && node.Mesh >= 0 {
+			meshNode = i
+			break
+		}
+	}
+
+	return a.Model.JointMatrices(skinIndex, meshNode, globals)
+}
@@ -0,0 +1,209 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const epsilon = 1e-5
+
+func closeTo(a, b []float32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(float64(a[i]-b[i])) > epsilon {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestSampleStep(t *testing.T) {
+	s := AnimationSampler{Input: []float32{0, 1, 2}, Output: []float32{10, 20, 30}, Components: 1, Interpolation: InterpolationStep}
+
+	tests := []struct {
+		t    float32
+		want float32
+	}{
+		{-1, 10}, {0, 10}, {0.99, 10}, {1, 20}, {1.5, 20}, {2, 30}, {5, 30},
+	}
+	for _, test := range tests {
+		if got := s.Sample(test.t, false); !closeTo(got, []float32{test.want}) {
+			t.Errorf("Sample(%v) = %v, want %v", test.t, got, test.want)
+		}
+	}
+}
+
+func TestSampleLinear(t *testing.T) {
+	s := AnimationSampler{
+		Input:         []float32{0, 2, 4},
+		Output:        []float32{0, 0, 0, 2, 4, 6, 2, 4, 6},
+		Components:    3,
+		Interpolation: InterpolationLinear,
+	}
+
+	tests := []struct {
+		t    float32
+		want []float32
+	}{
+		{-1, []float32{0, 0, 0}},
+		{1, []float32{1, 2, 3}},
+		{2, []float32{2, 4, 6}},
+		{3, []float32{2, 4, 6}},
+		{10, []float32{2, 4, 6}},
+	}
+	for _, test := range tests {
+		if got := s.Sample(test.t, false); !closeTo(got, test.want) {
+			t.Errorf("Sample(%v) = %v, want %v", test.t, got, test.want)
+		}
+	}
+}
+
+func TestSampleSlerp(t *testing.T) {
+	quarter := mgl32.QuatRotate(math.Pi/2, mgl32.Vec3{0, 1, 0})
+	s := AnimationSampler{
+		Input:         []float32{0, 1},
+		Output:        append(quatComponents(mgl32.QuatIdent()), quatComponents(quarter)...),
+		Components:    4,
+		Interpolation: InterpolationLinear,
+	}
+
+	want := quatComponents(mgl32.QuatRotate(math.Pi/4, mgl32.Vec3{0, 1, 0}))
+	if got := s.Sample(0.5, true); !closeTo(got, want) {
+		t.Errorf("Sample(0.5) = %v, want %v", got, want)
+	}
+
+	// -q é a mesma rotação; o slerp deve ir pelo caminho curto
+	s.Output = append(quatComponents(mgl32.QuatIdent()), quatComponents(quarter.Scale(-1))...)
+	got := quatFromComponents(s.Sample(0.5, true))
+	if angle := 2 * math.Acos(math.Min(1, math.Abs(float64(got.W)))); math.Abs(angle-math.Pi/4) > 1e-4 {
+		t.Errorf("slerp to -q turned %v rad, want %v", angle, math.Pi/4)
+	}
+}
+
+func TestSampleCubicSpline(t *testing.T) {
+	// tangentes nulas: começa e termina parado, passa pelo meio na metade
+	s := AnimationSampler{
+		Input:         []float32{0, 1},
+		Output:        []float32{0, 0, 0, 0, 10, 0},
+		Components:    1,
+		Interpolation: InterpolationCubicSpline,
+	}
+
+	tests := []struct {
+		t    float32
+		want float32
+	}{
+		{0, 0}, {0.5, 5}, {1, 10},
+	}
+	for _, test := range tests {
+		if got := s.Sample(test.t, false); !closeTo(got, []float32{test.want}) {
+			t.Errorf("Sample(%v) = %v, want %v", test.t, got, test.want)
+		}
+	}
+}
+
+func TestChannelValidate(t *testing.T) {
+	tests := []struct {
+		path       AnimationPath
+		components int
+		ok         bool
+	}{
+		{PathTranslation, 3, true},
+		{PathTranslation, 4, false},
+		{PathScale, 3, true},
+		{PathScale, 1, false},
+		{PathRotation, 4, true},
+		{PathRotation, 3, false},
+		{"weights", 1, false},
+	}
+	for _, test := range tests {
+		channel := AnimationChannel{Path: test.path, Sampler: AnimationSampler{
+			Input:         []float32{0, 1},
+			Output:        make([]float32, 2*test.components),
+			Components:    test.components,
+			Interpolation: InterpolationLinear,
+		}}
+		if err := channel.validate(); (err == nil) != test.ok {
+			t.Errorf("%s with %d components: validate() = %v, want ok %v", test.path, test.components, err, test.ok)
+		}
+	}
+}
+
+func TestSamplerValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sampler AnimationSampler
+	}{
+		{"empty", AnimationSampler{Components: 1, Interpolation: InterpolationLinear}},
+		{"missing outputs", AnimationSampler{Input: []float32{0, 1}, Output: []float32{0}, Components: 1, Interpolation: InterpolationLinear}},
+		{"cubic without tangents", AnimationSampler{Input: []float32{0, 1}, Output: []float32{0, 1}, Components: 1, Interpolation: InterpolationCubicSpline}},
+		{"decreasing times", AnimationSampler{Input: []float32{1, 0}, Output: []float32{0, 1}, Components: 1, Interpolation: InterpolationLinear}},
+		{"unknown interpolation", AnimationSampler{Input: []float32{0}, Output: []float32{0}, Components: 1, Interpolation: "BEZIER"}},
+	}
+	for _, test := range tests {
+		if err := test.sampler.validate(); err == nil {
+			t.Errorf("%s: validate() = nil, want an error", test.name)
+		}
+	}
+}
+
+// Documento com um nó animado por um sampler cuja saída tem o tipo outputType
+func animatedDocument(path, outputType string, components int) []byte {
+	var buffer bytes.Buffer
+	binary.Write(&buffer, binary.LittleEndian, []float32{0, 1})
+	binary.Write(&buffer, binary.LittleEndian, make([]float32, 2*components))
+
+	return []byte(fmt.Sprintf(`{
+		"asset": {"version": "2.0"},
+		"nodes": [{"name": "bone"}],
+		"buffers": [{"byteLength": %d, "uri": "data:application/octet-stream;base64,%s"}],
+		"bufferViews": [
+			{"buffer": 0, "byteOffset": 0, "byteLength": 8},
+			{"buffer": 0, "byteOffset": 8, "byteLength": %d}
+		],
+		"accessors": [
+			{"bufferView": 0, "componentType": 5126, "count": 2, "type": "SCALAR"},
+			{"bufferView": 1, "componentType": 5126, "count": 2, "type": "%s"}
+		],
+		"animations": [{
+			"name": "clip",
+			"samplers": [{"input": 0, "output": 1}],
+			"channels": [{"sampler": 0, "target": {"node": 0, "path": "%s"}}]
+		}]
+	}`, buffer.Len(), base64.StdEncoding.EncodeToString(buffer.Bytes()), 8*components, outputType, path))
+}
+
+func TestParseRejectsMismatchedPath(t *testing.T) {
+	model, err := ParseGLTF(animatedDocument("translation", "VEC3", 3), "")
+	if err != nil {
+		t.Fatalf("valid translation: %v", err)
+	}
+	if len(model.Animations) != 1 || len(model.Animations[0].Channels) != 1 {
+		t.Fatalf("got %d animations, want 1 with 1 channel", len(model.Animations))
+	}
+
+	tests := []struct {
+		path, outputType string
+		components       int
+	}{
+		{"translation", "VEC4", 4},
+		{"rotation", "VEC3", 3},
+		{"scale", "SCALAR", 1},
+	}
+	for _, test := range tests {
+		_, err := ParseGLTF(animatedDocument(test.path, test.outputType, test.components), "")
+		if err == nil || !strings.Contains(err.Error(), test.path) {
+			t.Errorf("%s with %s: err = %v, want an error about the path", test.path, test.outputType, err)
+		}
+	}
+}
@@ -0,0 +1,814 @@
+package lib
+
+// Importador de arquivos glTF 2.0 (.gltf e .glb). Não depende de OpenGL, então
+// tudo o que é produzido aqui fica em memória (CPU) e pode ser testado sem GPU.
+// FONTE: https://registry.khronos.org/glTF/specs/2.0/glTF-2.0.html
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	glbMagic     uint32 = 0x46546C67 // "glTF"
+	glbChunkJSON uint32 = 0x4E4F534A // "JSON"
+	glbChunkBIN  uint32 = 0x004E4942 // "BIN\0"
+)
+
+// Accessor component types
+const (
+	gltfByte          = 5120
+	gltfUnsignedByte  = 5121
+	gltfShort         = 5122
+	gltfUnsignedShort = 5123
+	gltfUnsignedInt   = 5125
+	gltfFloat         = 5126
+)
+
+// Primitive rendering modes, same values as gl.POINTS, gl.LINES, gl.TRIANGLES...
+const (
+	GLTFModePoints        = 0
+	GLTFModeLines         = 1
+	GLTFModeTriangles     = 4
+	GLTFModeTriangleStrip = 5
+)
+
+// GLTFModel is everything imported from a glTF file
+type GLTFModel struct {
+	Meshes     []GLTFMesh
+	Materials  []GLTFMaterial
+	Textures   []GLTFTexture
+	Images     []GLTFImage
+	Nodes      []GLTFNode
+	Skins      []GLTFSkin
+	Animations []AnimationClip
+	RootNodes  []int // root nodes of the default scene
+}
+
+type GLTFMesh struct {
+	Name       string
+	Primitives []GLTFPrimitive
+}
+
+// GLTFPrimitive holds de-interleaved vertex attributes of a mesh primitive
+type GLTFPrimitive struct {
+	Positions []mgl32.Vec3
+	Normals   []mgl32.Vec3
+	TexCoords []mgl32.Vec2
+	Joints    [][4]uint16
+	Weights   []mgl32.Vec4
+	Indices   []uint32
+	Material  int // -1 when the primitive has no material
+	Mode      int
+}
+
+type GLTFMaterial struct {
+	Name             string
+	BaseColorFactor  mgl32.Vec4
+	BaseColorTexture int // -1 when not textured
+	MetallicFactor   float32
+	RoughnessFactor  float32
+	AlphaMode        string
+	AlphaCutoff      float32
+	DoubleSided      bool
+}
+
+type GLTFTexture struct {
+	Image int
+}
+
+// GLTFImage keeps the encoded image bytes (png/jpeg), decoding is left to the caller
+type GLTFImage struct {
+	Name     string
+	MimeType string
+	Data     []byte
+}
+
+type GLTFNode struct {
+	Name        string
+	Parent      int // -1 for root nodes
+	Children    []int
+	Mesh        int // -1 when the node has no mesh
+	Skin        int // -1 when the node has no skin
+	Translation mgl32.Vec3
+	Rotation    mgl32.Quat
+	Scale       mgl32.Vec3
+	Matrix      mgl32.Mat4
+	HasMatrix   bool // if true, Matrix is used instead of the TRS properties
+}
+
+type GLTFSkin struct {
+	Name                string
+	Joints              []int
+	InverseBindMatrices []mgl32.Mat4
+	Skeleton            int // -1 when not specified
+}
+
+// Raw JSON document, only the properties used by the importer are declared
+type gltfDocument struct {
+	Scene  *int `json:"scene"`
+	Scenes []struct {
+		Nodes []int `json:"nodes"`
+	} `json:"scenes"`
+	Nodes []struct {
+		Name        string    `json:"name"`
+		Children    []int     `json:"children"`
+		Mesh        *int      `json:"mesh"`
+		Skin        *int      `json:"skin"`
+		Translation []float32 `json:"translation"`
+		Rotation    []float32 `json:"rotation"`
+		Scale       []float32 `json:"scale"`
+		Matrix      []float32 `json:"matrix"`
+	} `json:"nodes"`
+	Meshes []struct {
+		Name       string `json:"name"`
+		Primitives []struct {
+			Attributes map[string]int `json:"attributes"`
+			Indices    *int           `json:"indices"`
+			Material   *int           `json:"material"`
+			Mode       *int           `json:"mode"`
+		} `json:"primitives"`
+	} `json:"meshes"`
+	Materials []struct {
+		Name                 string `json:"name"`
+		PbrMetallicRoughness *struct {
+			BaseColorFactor  []float32 `json:"baseColorFactor"`
+			BaseColorTexture *struct {
+				Index int `json:"index"`
+			} `json:"baseColorTexture"`
+			MetallicFactor  *float32 `json:"metallicFactor"`
+			RoughnessFactor *float32 `json:"roughnessFactor"`
+		} `json:"pbrMetallicRoughness"`
+		AlphaMode   string   `json:"alphaMode"`
+		AlphaCutoff *float32 `json:"alphaCutoff"`
+		DoubleSided bool     `json:"doubleSided"`
+	} `json:"materials"`
+	Textures []struct {
+		Source *int `json:"source"`
+	} `json:"textures"`
+	Images []struct {
+		Name       string `json:"name"`
+		URI        string `json:"uri"`
+		MimeType   string `json:"mimeType"`
+		BufferView *int   `json:"bufferView"`
+	} `json:"images"`
+	Skins []struct {
+		Name                string `json:"name"`
+		InverseBindMatrices *int   `json:"inverseBindMatrices"`
+		Skeleton            *int   `json:"skeleton"`
+		Joints              []int  `json:"joints"`
+	} `json:"skins"`
+	Animations []struct {
+		Name     string `json:"name"`
+		Channels []struct {
+			Sampler int `json:"sampler"`
+			Target  struct {
+				Node *int   `json:"node"`
+				Path string `json:"path"`
+			} `json:"target"`
+		} `json:"channels"`
+		Samplers []struct {
+			Input         int    `json:"input"`
+			Output        int    `json:"output"`
+			Interpolation string `json:"interpolation"`
+		} `json:"samplers"`
+	} `json:"animations"`
+	Accessors []struct {
+		BufferView    *int   `json:"bufferView"`
+		ByteOffset    int    `json:"byteOffset"`
+		ComponentType int    `json:"componentType"`
+		Normalized    bool   `json:"normalized"`
+		Count         int    `json:"count"`
+		Type          string `json:"type"`
+		Sparse        *struct {
+			Count int `json:"count"`
+		} `json:"sparse"`
+	} `json:"accessors"`
+	BufferViews []struct {
+		Buffer     int `json:"buffer"`
+		ByteOffset int `json:"byteOffset"`
+		ByteLength int `json:"byteLength"`
+		ByteStride int `json:"byteStride"`
+	} `json:"bufferViews"`
+	Buffers []struct {
+		URI        string `json:"uri"`
+		ByteLength int    `json:"byteLength"`
+	} `json:"buffers"`
+}
+
+// LoadGLTF reads a .gltf or .glb file from disk. External buffers and images
+// are resolved relative to the file directory.
+func LoadGLTF(file string) (*GLTFModel, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseGLTF(data, filepath.Dir(file))
+}
+
+// ParseGLTF imports a glTF model from memory. data may be either a JSON .gltf
+// document or a binary .glb container; baseDir is used to resolve external uris.
+func ParseGLTF(data []byte, baseDir string) (*GLTFModel, error) {
+	jsonChunk, binChunk := data, []byte(nil)
+	if len(data) >= 4 && binary.LittleEndian.Uint32(data) == glbMagic {
+		var err error
+		jsonChunk, binChunk, err = splitGLB(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	doc := gltfDocument{}
+	if err := json.Unmarshal(jsonChunk, &doc); err != nil {
+		return nil, fmt.Errorf("gltf: invalid json: %v", err)
+	}
+
+	importer := gltfImporter{doc: &doc, baseDir: baseDir}
+	if err := importer.loadBuffers(binChunk); err != nil {
+		return nil, err
+	}
+
+	return importer.build()
+}
+
+// Splits a .glb container into its JSON and BIN chunks
+func splitGLB(data []byte) ([]byte, []byte, error) {
+	if len(data) < 20 {
+		return nil, nil, fmt.Errorf("gltf: glb file is too short")
+	}
+	if version := binary.LittleEndian.Uint32(data[4:]); version != 2 {
+		return nil, nil, fmt.Errorf("gltf: unsupported glb version %v", version)
+	}
+	length := int(binary.LittleEndian.Uint32(data[8:]))
+	if length > len(data) {
+		return nil, nil, fmt.Errorf("gltf: glb header length %v exceeds file size %v", length, len(data))
+	}
+
+	var jsonChunk, binChunk []byte
+	for offset := 12; offset+8 <= length; {
+		chunkLength := int(binary.LittleEndian.Uint32(data[offset:]))
+		chunkType := binary.LittleEndian.Uint32(data[offset+4:])
+		start := offset + 8
+		if start+chunkLength > length {
+			return nil, nil, fmt.Errorf("gltf: glb chunk at %v is truncated", offset)
+		}
+
+		switch chunkType {
+		case glbChunkJSON:
+			jsonChunk = data[start : start+chunkLength]
+		case glbChunkBIN:
+			if binChunk == nil {
+				binChunk = data[start : start+chunkLength]
+			}
+		}
+
+		offset = start + chunkLength
+	}
+
+	if jsonChunk == nil {
+		return nil, nil, fmt.Errorf("gltf: glb has no JSON chunk")
+	}
+
+	return jsonChunk, binChunk, nil
+}
+
+type gltfImporter struct {
+	doc     *gltfDocument
+	baseDir string
+	buffers [][]byte
+}
+
+// Resolves every buffer, either from the glb BIN chunk, a data uri or an external file
+func (im *gltfImporter) loadBuffers(binChunk []byte) error {
+	for i, buffer := range im.doc.Buffers {
+		var data []byte
+		var err error
+
+		switch {
+		case buffer.URI == "":
+			if i != 0 || binChunk == nil {
+				return fmt.Errorf("gltf: buffer %v has no uri and there is no glb BIN chunk", i)
+			}
+			data = binChunk
+		default:
+			data, err = im.readURI(buffer.URI)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(data) < buffer.ByteLength {
+			return fmt.Errorf("gltf: buffer %v has %v bytes, expected %v", i, len(data), buffer.ByteLength)
+		}
+		im.buffers = append(im.buffers, data)
+	}
+
+	return nil
+}
+
+func (im *gltfImporter) readURI(uri string) ([]byte, error) {
+	if strings.HasPrefix(uri, "data:") {
+		comma := strings.IndexByte(uri, ',')
+		if comma < 0 || !strings.HasSuffix(uri[:comma], ";base64") {
+			return nil, fmt.Errorf("gltf: unsupported data uri")
+		}
+		return base64.StdEncoding.DecodeString(uri[comma+1:])
+	}
+
+	return os.ReadFile(filepath.Join(im.baseDir, filepath.FromSlash(uri)))
+}
+
+func (im *gltfImporter) build() (*GLTFModel, error) {
+	model := &GLTFModel{}
+	doc := im.doc
+
+	for i, n := range doc.Nodes {
+		node := GLTFNode{
+			Name:        n.Name,
+			Parent:      -1,
+			Children:    n.Children,
+			Mesh:        optionalIndex(n.Mesh),
+			Skin:        optionalIndex(n.Skin),
+			Translation: mgl32.Vec3{0, 0, 0},
+			Rotation:    mgl32.QuatIdent(),
+			Scale:       mgl32.Vec3{1, 1, 1},
+		}
+		if len(n.Translation) == 3 {
+			node.Translation = mgl32.Vec3{n.Translation[0], n.Translation[1], n.Translation[2]}
+		}
+		if len(n.Rotation) == 4 {
+			// glTF quaternions are stored as x, y, z, w
+			node.Rotation = mgl32.Quat{W: n.Rotation[3], V: mgl32.Vec3{n.Rotation[0], n.Rotation[1], n.Rotation[2]}}
+		}
+		if len(n.Scale) == 3 {
+			node.Scale = mgl32.Vec3{n.Scale[0], n.Scale[1], n.Scale[2]}
+		}
+		if len(n.Matrix) == 16 {
+			copy(node.Matrix[:], n.Matrix) // column-major, same as mgl32
+			node.HasMatrix = true
+		}
+		for _, child := range n.Children {
+			if child < 0 || child >= len(doc.Nodes) {
+				return nil, fmt.Errorf("gltf: node %v has invalid child %v", i, child)
+			}
+		}
+		model.Nodes = append(model.Nodes, node)
+	}
+	for i, node := range model.Nodes {
+		for _, child := range node.Children {
+			if model.Nodes[child].Parent != -1 {
+				return nil, fmt.Errorf("gltf: node %v has more than one parent", child)
+			}
+			model.Nodes[child].Parent = i
+		}
+	}
+
+	if len(doc.Scenes) > 0 {
+		scene := 0
+		if doc.Scene != nil {
+			scene = *doc.Scene
+		}
+		if scene < 0 || scene >= len(doc.Scenes) {
+			return nil, fmt.Errorf("gltf: invalid default scene %v", scene)
+		}
+		model.RootNodes = doc.Scenes[scene].Nodes
+	} else {
+		for i, node := range model.Nodes {
+			if node.Parent == -1 {
+				model.RootNodes = append(model.RootNodes, i)
+			}
+		}
+	}
+
+	for _, m := range doc.Meshes {
+		mesh := GLTFMesh{Name: m.Name}
+		for _, p := range m.Primitives {
+			primitive, err := im.buildPrimitive(p.Attributes, p.Indices)
+			if err != nil {
+				return nil, fmt.Errorf("gltf: mesh %q: %v", m.Name, err)
+			}
+			primitive.Material = optionalIndex(p.Material)
+			primitive.Mode = GLTFModeTriangles
+			if p.Mode != nil {
+				primitive.Mode = *p.Mode
+			}
+			mesh.Primitives = append(mesh.Primitives, primitive)
+		}
+		model.Meshes = append(model.Meshes, mesh)
+	}
+
+	for _, m := range doc.Materials {
+		material := GLTFMaterial{
+			Name:             m.Name,
+			BaseColorFactor:  mgl32.Vec4{1, 1, 1, 1},
+			BaseColorTexture: -1,
+			MetallicFactor:   1,
+			RoughnessFactor:  1,
+			AlphaMode:        "OPAQUE",
+			AlphaCutoff:      0.5,
+			DoubleSided:      m.DoubleSided,
+		}
+		if pbr := m.PbrMetallicRoughness; pbr != nil {
+			if len(pbr.BaseColorFactor) == 4 {
+				copy(material.BaseColorFactor[:], pbr.BaseColorFactor)
+			}
+			if pbr.BaseColorTexture != nil {
+				material.BaseColorTexture = pbr.BaseColorTexture.Index
+			}
+			if pbr.MetallicFactor != nil {
+				material.MetallicFactor = *pbr.MetallicFactor
+			}
+			if pbr.RoughnessFactor != nil {
+				material.RoughnessFactor = *pbr.RoughnessFactor
+			}
+		}
+		if m.AlphaMode != "" {
+			material.AlphaMode = m.AlphaMode
+		}
+		if m.AlphaCutoff != nil {
+			material.AlphaCutoff = *m.AlphaCutoff
+		}
+		model.Materials = append(model.Materials, material)
+	}
+
+	for _, t := range doc.Textures {
+		model.Textures = append(model.Textures, GLTFTexture{Image: optionalIndex(t.Source)})
+	}
+
+	for i, img := range doc.Images {
+		image := GLTFImage{Name: img.Name, MimeType: img.MimeType}
+		var err error
+		if img.BufferView != nil {
+			image.Data, err = im.bufferViewBytes(*img.BufferView)
+		} else if img.URI != "" {
+			image.Data, err = im.readURI(img.URI)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gltf: image %v: %v", i, err)
+		}
+		model.Images = append(model.Images, image)
+	}
+
+	for _, s := range doc.Skins {
+		skin := GLTFSkin{Name: s.Name, Joints: s.Joints, Skeleton: optionalIndex(s.Skeleton)}
+		for _, joint := range s.Joints {
+			if joint < 0 || joint >= len(model.Nodes) {
+				return nil, fmt.Errorf("gltf: skin %q has invalid joint %v", s.Name, joint)
+			}
+		}
+		if s.InverseBindMatrices != nil {
+			values, components, err := im.readFloats(*s.InverseBindMatrices)
+			if err != nil {
+				return nil, fmt.Errorf("gltf: skin %q: %v", s.Name, err)
+			}
+			if components != 16 || len(values)/16 < len(s.Joints) {
+				return nil, fmt.Errorf("gltf: skin %q has invalid inverse bind matrices", s.Name)
+			}
+			for j := range s.Joints {
+				var m mgl32.Mat4
+				copy(m[:], values[j*16:j*16+16])
+				skin.InverseBindMatrices = append(skin.InverseBindMatrices, m)
+			}
+		} else {
+			for range s.Joints {
+				skin.InverseBindMatrices = append(skin.InverseBindMatrices, mgl32.Ident4())
+			}
+		}
+		model.Skins = append(model.Skins, skin)
+	}
+
+	for _, a := range doc.Animations {
+		clip := AnimationClip{Name: a.Name}
+		for _, c := range a.Channels {
+			if c.Target.Node == nil {
+				continue // extensions may animate things other than nodes
+			}
+			if c.Sampler < 0 || c.Sampler >= len(a.Samplers) {
+				return nil, fmt.Errorf("gltf: animation %q has invalid sampler %v", a.Name, c.Sampler)
+			}
+			path := AnimationPath(c.Target.Path)
+			if path != PathTranslation && path != PathRotation && path != PathScale {
+				continue // morph target weights are not supported
+			}
+
+			s := a.Samplers[c.Sampler]
+			input, inputComponents, err := im.readFloats(s.Input)
+			if err != nil {
+				return nil, fmt.Errorf("gltf: animation %q: %v", a.Name, err)
+			}
+			if inputComponents != 1 {
+				return nil, fmt.Errorf("gltf: animation %q: keyframe times must be scalars", a.Name)
+			}
+			output, components, err := im.readFloats(s.Output)
+			if err != nil {
+				return nil, fmt.Errorf("gltf: animation %q: %v", a.Name, err)
+			}
+
+			interpolation := Interpolation(s.Interpolation)
+			if interpolation == "" {
+				interpolation = InterpolationLinear
+			}
+			channel := AnimationChannel{
+				Node:    *c.Target.Node,
+				Path:    path,
+				Sampler: AnimationSampler{Input: input, Output: output, Components: components, Interpolation: interpolation},
+			}
+			if err := channel.validate(); err != nil {
+				return nil, fmt.Errorf("gltf: animation %q: %v", a.Name, err)
+			}
+
+			clip.Channels = append(clip.Channels, channel)
+			if len(input) > 0 && input[len(input)-1] > clip.Duration {
+				clip.Duration = input[len(input)-1]
+			}
+		}
+		model.Animations = append(model.Animations, clip)
+	}
+
+	return model, nil
+}
+
+func (im *gltfImporter) buildPrimitive(attributes map[string]int, indices *int) (GLTFPrimitive, error) {
+	primitive := GLTFPrimitive{}
+
+	position, ok := attributes["POSITION"]
+	if !ok {
+		return primitive, fmt.Errorf("primitive has no POSITION attribute")
+	}
+	values, components, err := im.readFloats(position)
+	if err != nil {
+		return primitive, err
+	}
+	if components != 3 {
+		return primitive, fmt.Errorf("POSITION must be VEC3")
+	}
+	for i := 0; i < len(values); i += 3 {
+		primitive.Positions = append(primitive.Positions, mgl32.Vec3{values[i], values[i+1], values[i+2]})
+	}
+	vertexCount := len(primitive.Positions)
+
+	if normal, ok := attributes["NORMAL"]; ok {
+		values, components, err := im.readFloats(normal)
+		if err != nil || components != 3 || len(values)/3 != vertexCount {
+			return primitive, fmt.Errorf("invalid NORMAL attribute")
+		}
+		for i := 0; i < len(values); i += 3 {
+			primitive.Normals = append(primitive.Normals, mgl32.Vec3{values[i], values[i+1], values[i+2]})
+		}
+	}
+
+	if texcoord, ok := attributes["TEXCOORD_0"]; ok {
+		values, components, err := im.readFloats(texcoord)
+		if err != nil || components != 2 || len(values)/2 != vertexCount {
+			return primitive, fmt.Errorf("invalid TEXCOORD_0 attribute")
+		}
+		for i := 0; i < len(values); i += 2 {
+			primitive.TexCoords = append(primitive.TexCoords, mgl32.Vec2{values[i], values[i+1]})
+		}
+	}
+
+	if joints, ok := attributes["JOINTS_0"]; ok {
+		values, components, err := im.readUints(joints)
+		if err != nil || components != 4 || len(values)/4 != vertexCount {
+			return primitive, fmt.Errorf("invalid JOINTS_0 attribute")
+		}
+		for i := 0; i < len(values); i += 4 {
+			primitive.Joints = append(primitive.Joints, [4]uint16{uint16(values[i]), uint16(values[i+1]), uint16(values[i+2]), uint16(values[i+3])})
+		}
+	}
+
+	if weights, ok := attributes["WEIGHTS_0"]; ok {
+		values, components, err := im.readFloats(weights)
+		if err != nil || components != 4 || len(values)/4 != vertexCount {
+			return primitive, fmt.Errorf("invalid WEIGHTS_0 attribute")
+		}
+		for i := 0; i < len(values); i += 4 {
+			primitive.Weights = append(primitive.Weights, mgl32.Vec4{values[i], values[i+1], values[i+2], values[i+3]})
+		}
+	}
+
+	if indices != nil {
+		values, components, err := im.readUints(*indices)
+		if err != nil {
+			return primitive, err
+		}
+		if components != 1 {
+			return primitive, fmt.Errorf("indices must be SCALAR")
+		}
+		for _, index := range values {
+			if int(index) >= vertexCount {
+				return primitive, fmt.Errorf("index %v out of range", index)
+			}
+		}
+		primitive.Indices = values
+	} else {
+		for i := 0; i < vertexCount; i++ {
+			primitive.Indices = append(primitive.Indices, uint32(i))
+		}
+	}
+
+	return primitive, nil
+}
+
+func (im *gltfImporter) bufferViewBytes(index int) ([]byte, error) {
+	if index < 0 || index >= len(im.doc.BufferViews) {
+		return nil, fmt.Errorf("invalid buffer view %v", index)
+	}
+	view := im.doc.BufferViews[index]
+	if view.Buffer < 0 || view.Buffer >= len(im.buffers) {
+		return nil, fmt.Errorf("buffer view %v has invalid buffer %v", index, view.Buffer)
+	}
+	buffer := im.buffers[view.Buffer]
+	if view.ByteOffset+view.ByteLength > len(buffer) {
+		return nil, fmt.Errorf("buffer view %v is out of bounds", index)
+	}
+
+	return buffer[view.ByteOffset : view.ByteOffset+view.ByteLength], nil
+}
+
+func componentCount(accessorType string) int {
+	switch accessorType {
+	case "SCALAR":
+		return 1
+	case "VEC2":
+		return 2
+	case "VEC3":
+		return 3
+	case "VEC4", "MAT2":
+		return 4
+	case "MAT3":
+		return 9
+	case "MAT4":
+		return 16
+	}
+
+	return 0
+}
+
+func componentSize(componentType int) int {
+	switch componentType {
+	case gltfByte, gltfUnsignedByte:
+		return 1
+	case gltfShort, gltfUnsignedShort:
+		return 2
+	case gltfUnsignedInt, gltfFloat:
+		return 4
+	}
+
+	return 0
+}
+
+// Calls read for every component of every element of an accessor
+func (im *gltfImporter) eachComponent(index int, read func(raw []byte, componentType int)) (int, error) {
+	if index < 0 || index >= len(im.doc.Accessors) {
+		return 0, fmt.Errorf("invalid accessor %v", index)
+	}
+	accessor := im.doc.Accessors[index]
+	if accessor.Sparse != nil {
+		return 0, fmt.Errorf("accessor %v: sparse accessors are not supported", index)
+	}
+
+	components := componentCount(accessor.Type)
+	size := componentSize(accessor.ComponentType)
+	if components == 0 || size == 0 {
+		return 0, fmt.Errorf("accessor %v has invalid type %v/%v", index, accessor.Type, accessor.ComponentType)
+	}
+
+	// accessors without a buffer view are initialized with zeros
+	if accessor.BufferView == nil {
+		zero := make([]byte, size)
+		for i := 0; i < accessor.Count*components; i++ {
+			read(zero, accessor.ComponentType)
+		}
+		return components, nil
+	}
+
+	view, err := im.bufferViewBytes(*accessor.BufferView)
+	if err != nil {
+		return 0, fmt.Errorf("accessor %v: %v", index, err)
+	}
+	stride := im.doc.BufferViews[*accessor.BufferView].ByteStride
+	if stride == 0 {
+		stride = components * size
+	}
+
+	if accessor.Count > 0 && accessor.ByteOffset+(accessor.Count-1)*stride+components*size > len(view) {
+		return 0, fmt.Errorf("accessor %v is out of bounds", index)
+	}
+
+	for i := 0; i < accessor.Count; i++ {
+		element := view[accessor.ByteOffset+i*stride:]
+		for c := 0; c < components; c++ {
+			read(element[c*size:c*size+size], accessor.ComponentType)
+		}
+	}
+
+	return components, nil
+}
+
+// Reads an accessor as floats, normalizing integer components when requested
+func (im *gltfImporter) readFloats(index int) ([]float32, int, error) {
+	values := []float32{}
+	normalized := index >= 0 && index < len(im.doc.Accessors) && im.doc.Accessors[index].Normalized
+
+	components, err := im.eachComponent(index, func(raw []byte, componentType int) {
+		var value float32
+		switch componentType {
+		case gltfFloat:
+			value = math.Float32frombits(binary.LittleEndian.Uint32(raw))
+		case gltfByte:
+			value = float32(int8(raw[0]))
+			if normalized {
+				value = float32(math.Max(float64(value)/127, -1))
+			}
+		case gltfUnsignedByte:
+			value = float32(raw[0])
+			if normalized {
+				value /= 255
+			}
+		case gltfShort:
+			value = float32(int16(binary.LittleEndian.Uint16(raw)))
+			if normalized {
+				value = float32(math.Max(float64(value)/32767, -1))
+			}
+		case gltfUnsignedShort:
+			value = float32(binary.LittleEndian.Uint16(raw))
+			if normalized {
+				value /= 65535
+			}
+		case gltfUnsignedInt:
+			value = float32(binary.LittleEndian.Uint32(raw))
+		}
+		values = append(values, value)
+	})
+
+	return values, components, err
+}
+
+// Reads an accessor of unsigned integers (indices, joints)
+func (im *gltfImporter) readUints(index int) ([]uint32, int, error) {
+	values := []uint32{}
+	invalid := false
+
+	components, err := im.eachComponent(index, func(raw []byte, componentType int) {
+		switch componentType {
+		case gltfUnsignedByte:
+			values = append(values, uint32(raw[0]))
+		case gltfUnsignedShort:
+			values = append(values, uint32(binary.LittleEndian.Uint16(raw)))
+		case gltfUnsignedInt:
+			values = append(values, binary.LittleEndian.Uint32(raw))
+		default:
+			invalid = true
+		}
+	})
+	if err == nil && invalid {
+		err = fmt.Errorf("accessor %v must hold unsigned integers", index)
+	}
+
+	return values, components, err
+}
+
+func optionalIndex(index *int) int {
+	if index == nil {
+		return -1
+	}
+
+	return *index
+}
+
+// GetRenderableVertices returns the triangles of a mesh formatted in X, Y, Z, W,
+// the same layout produced by Model.GetRenderableVertices for .obj files.
+// When jointMatrices is not nil the vertices are skinned on the CPU first.
+func (m *GLTFModel) GetRenderableVertices(meshIndex int, jointMatrices []mgl32.Mat4) []float32 {
+	var out []float32
+	if meshIndex < 0 || meshIndex >= len(m.Meshes) {
+		return out
+	}
+
+	for _, primitive := range m.Meshes[meshIndex].Primitives {
+		if primitive.Mode != GLTFModeTriangles {
+			continue
+		}
+
+		positions := primitive.Positions
+		if jointMatrices != nil {
+			positions = SkinPositions(primitive, jointMatrices)
+		}
+		for _, index := range primitive.Indices {
+			vec := positions[index]
+			out = append(out, vec.X(), vec.Y(), vec.Z(), 1.0)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,309 @@
+package lib
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// testdata/skinned.* é um triângulo com dois ossos: root na origem e arm em
+// (1, 0, 0), filho de root. O primeiro vértice segue root e os outros dois
+// seguem arm. tip é filho de arm e é definido por uma matriz.
+const (
+	nodeBody = iota
+	nodeRoot
+	nodeArm
+	nodeTip
+)
+
+func loadFixture(t *testing.T, file string) *GLTFModel {
+	t.Helper()
+	model, err := LoadGLTF(file)
+	if err != nil {
+		t.Fatalf("%s: %v", file, err)
+	}
+
+	return model
+}
+
+func vec3sClose(a, b []mgl32.Vec3) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !closeTo(a[i][:], b[i][:]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ApproxEqualThreshold é relativo e falha com resíduos como -0.000000001 contra 0
+func mat4Close(a, b mgl32.Mat4) bool {
+	return closeTo(a[:], b[:])
+}
+
+func TestLoadFixture(t *testing.T) {
+	gltf := loadFixture(t, "testdata/skinned.gltf")
+	glb := loadFixture(t, "testdata/skinned.glb")
+	// o .glb tem o mesmo documento, com o buffer no chunk BIN em vez de num arquivo
+	if !reflect.DeepEqual(gltf, glb) {
+		t.Errorf(".gltf and .glb differ:\n%+v\n%+v", gltf, glb)
+	}
+
+	for name, model := range map[string]*GLTFModel{"gltf": gltf, "glb": glb} {
+		if len(model.Meshes) != 1 || len(model.Meshes[0].Primitives) != 1 {
+			t.Fatalf("%s: got %d meshes, want 1 with 1 primitive", name, len(model.Meshes))
+		}
+		primitive := model.Meshes[0].Primitives[0]
+		want := GLTFPrimitive{
+			Positions: []mgl32.Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}},
+			TexCoords: []mgl32.Vec2{{0, 0}, {1, 0}, {0, 1}}, // bytes normalizados, com stride
+			Joints:    [][4]uint16{{0, 0, 0, 0}, {1, 0, 0, 0}, {1, 0, 0, 0}},
+			Weights:   []mgl32.Vec4{{1, 0, 0, 0}, {1, 0, 0, 0}, {1, 0, 0, 0}},
+			Indices:   []uint32{0, 1, 2},
+			Material:  0,
+			Mode:      GLTFModeTriangles,
+		}
+		if !reflect.DeepEqual(primitive, want) {
+			t.Errorf("%s: primitive = %+v, want %+v", name, primitive, want)
+		}
+
+		material := GLTFMaterial{
+			Name:             "skin",
+			BaseColorFactor:  mgl32.Vec4{1, 0.5, 0.5, 1},
+			BaseColorTexture: 0,
+			MetallicFactor:   0,
+			RoughnessFactor:  1,
+			AlphaMode:        "MASK",
+			AlphaCutoff:      0.5,
+		}
+		if len(model.Materials) != 1 || model.Materials[0] != material {
+			t.Errorf("%s: materials = %+v, want %+v", name, model.Materials, material)
+		}
+		if len(model.Images) != 1 || string(model.Images[0].Data) != "\x89PNG" || model.Images[0].MimeType != "image/png" {
+			t.Errorf("%s: images = %+v, want the png bytes of the buffer view", name, model.Images)
+		}
+
+		parents := []int{-1, -1, nodeRoot, nodeArm}
+		for i, node := range model.Nodes {
+			if node.Parent != parents[i] {
+				t.Errorf("%s: node %q has parent %d, want %d", name, node.Name, node.Parent, parents[i])
+			}
+		}
+		if !reflect.DeepEqual(model.RootNodes, []int{nodeBody, nodeRoot}) {
+			t.Errorf("%s: root nodes = %v", name, model.RootNodes)
+		}
+		if body := model.Nodes[nodeBody]; body.Mesh != 0 || body.Skin != 0 {
+			t.Errorf("%s: body has mesh %d and skin %d, want 0 and 0", name, body.Mesh, body.Skin)
+		}
+		if !model.Nodes[nodeTip].HasMatrix || model.Nodes[nodeArm].HasMatrix {
+			t.Errorf("%s: only tip should be defined by a matrix", name)
+		}
+
+		skin := GLTFSkin{
+			Name:                "rig",
+			Joints:              []int{nodeRoot, nodeArm},
+			InverseBindMatrices: []mgl32.Mat4{mgl32.Ident4(), mgl32.Translate3D(-1, 0, 0)},
+			Skeleton:            -1,
+		}
+		if len(model.Skins) != 1 || !reflect.DeepEqual(model.Skins[0], skin) {
+			t.Errorf("%s: skins = %+v, want %+v", name, model.Skins, skin)
+		}
+	}
+}
+
+func TestGlobalTransforms(t *testing.T) {
+	model := loadFixture(t, "testdata/skinned.glb")
+	pose := model.RestPose()
+
+	tip := pose[nodeTip]
+	if !closeTo(tip.Translation[:], []float32{0, 2, 0}) || !closeTo(tip.Scale[:], []float32{2, 2, 2}) {
+		t.Errorf("tip matrix decomposed into %+v, want translation (0, 2, 0) and scale 2", tip)
+	}
+
+	tests := []struct {
+		name   string
+		change func(pose []NodePose)
+		want   []mgl32.Mat4
+	}{
+		{
+			name:   "pose de descanso",
+			change: func(pose []NodePose) {},
+			want: []mgl32.Mat4{
+				mgl32.Ident4(),
+				mgl32.Ident4(),
+				mgl32.Translate3D(1, 0, 0),
+				mgl32.Translate3D(1, 2, 0).Mul4(mgl32.Scale3D(2, 2, 2)),
+			},
+		},
+		{
+			name:   "a raiz leva os filhos",
+			change: func(pose []NodePose) { pose[nodeRoot].Translation = mgl32.Vec3{0, 0, 3} },
+			want: []mgl32.Mat4{
+				mgl32.Ident4(),
+				mgl32.Translate3D(0, 0, 3),
+				mgl32.Translate3D(1, 0, 3),
+				mgl32.Translate3D(1, 2, 3).Mul4(mgl32.Scale3D(2, 2, 2)),
+			},
+		},
+		{
+			name:   "braço girado",
+			change: func(pose []NodePose) { pose[nodeArm].Rotation = mgl32.QuatRotate(math.Pi/2, mgl32.Vec3{0, 0, 1}) },
+			want: []mgl32.Mat4{
+				mgl32.Ident4(),
+				mgl32.Ident4(),
+				mgl32.Translate3D(1, 0, 0).Mul4(mgl32.HomogRotate3DZ(math.Pi / 2)),
+				mgl32.Translate3D(-1, 0, 0).Mul4(mgl32.HomogRotate3DZ(math.Pi / 2)).Mul4(mgl32.Scale3D(2, 2, 2)),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		pose := model.RestPose()
+		test.change(pose)
+		globals := model.GlobalTransforms(pose)
+		for i := range test.want {
+			if !mat4Close(globals[i], test.want[i]) {
+				t.Errorf("%s: node %q global =\n%v\nwant\n%v", test.name, model.Nodes[i].Name, globals[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestSkinning(t *testing.T) {
+	model := loadFixture(t, "testdata/skinned.gltf")
+	rest := model.Meshes[0].Primitives[0].Positions
+
+	tests := []struct {
+		name   string
+		change func(pose []NodePose)
+		joints []mgl32.Mat4
+		want   []mgl32.Vec3
+	}{
+		{
+			name:   "pose de descanso",
+			change: func(pose []NodePose) {},
+			joints: []mgl32.Mat4{mgl32.Ident4(), mgl32.Ident4()},
+			want:   rest,
+		},
+		{
+			name:   "braço girado",
+			change: func(pose []NodePose) { pose[nodeArm].Rotation = mgl32.QuatRotate(math.Pi/2, mgl32.Vec3{0, 0, 1}) },
+			joints: []mgl32.Mat4{
+				mgl32.Ident4(),
+				mgl32.Translate3D(1, 0, 0).Mul4(mgl32.HomogRotate3DZ(math.Pi / 2)).Mul4(mgl32.Translate3D(-1, 0, 0)),
+			},
+			want: []mgl32.Vec3{{0, 0, 0}, {1, 0, 0}, {0, -1, 0}},
+		},
+		{
+			name:   "raiz movida",
+			change: func(pose []NodePose) { pose[nodeRoot].Translation = mgl32.Vec3{0, 2, 0} },
+			joints: []mgl32.Mat4{mgl32.Translate3D(0, 2, 0), mgl32.Translate3D(0, 2, 0)},
+			want:   []mgl32.Vec3{{0, 2, 0}, {1, 2, 0}, {0, 3, 0}},
+		},
+		{
+			// as juntas ficam no espaço do nó da malha
+			name: "malha movida junto com os ossos",
+			change: func(pose []NodePose) {
+				pose[nodeBody].Translation = mgl32.Vec3{5, 0, 0}
+				pose[nodeRoot].Translation = mgl32.Vec3{5, 0, 0}
+			},
+			joints: []mgl32.Mat4{mgl32.Ident4(), mgl32.Ident4()},
+			want:   rest,
+		},
+	}
+
+	for _, test := range tests {
+		pose := model.RestPose()
+		test.change(pose)
+		joints := model.JointMatrices(0, nodeBody, model.GlobalTransforms(pose))
+		for i := range test.want {
+			if i < len(test.joints) && !mat4Close(joints[i], test.joints[i]) {
+				t.Errorf("%s: joint %d =\n%v\nwant\n%v", test.name, i, joints[i], test.joints[i])
+			}
+		}
+
+		skinned := SkinPositions(model.Meshes[0].Primitives[0], joints)
+		if !vec3sClose(skinned, test.want) {
+			t.Errorf("%s: skinned positions = %v, want %v", test.name, skinned, test.want)
+		}
+
+		var vertices []float32
+		for _, p := range test.want {
+			vertices = append(vertices, p.X(), p.Y(), p.Z(), 1)
+		}
+		if got := model.GetRenderableVertices(0, joints); !closeTo(got, vertices) {
+			t.Errorf("%s: renderable vertices = %v, want %v", test.name, got, vertices)
+		}
+	}
+}
+
+func TestSplitGLB(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/skinned.glb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonLength := int(binary.LittleEndian.Uint32(fixture[12:]))
+	binHeader := 20 + jsonLength
+
+	tests := []struct {
+		name    string
+		change  func(data []byte) []byte
+		wantErr string
+	}{
+		{name: "válido", change: func(data []byte) []byte { return data }},
+		{
+			name: "chunk desconhecido é ignorado",
+			change: func(data []byte) []byte {
+				binary.LittleEndian.PutUint32(data[binHeader+4:], 0x12345678)
+				return data
+			},
+			wantErr: "no uri and there is no glb BIN chunk",
+		},
+		{name: "curto demais", change: func(data []byte) []byte { return data[:16] }, wantErr: "too short"},
+		{
+			name: "versão 1",
+			change: func(data []byte) []byte {
+				binary.LittleEndian.PutUint32(data[4:], 1)
+				return data
+			},
+			wantErr: "unsupported glb version 1",
+		},
+		{name: "arquivo cortado", change: func(data []byte) []byte { return data[:len(data)-4] }, wantErr: "exceeds file size"},
+		{
+			name: "chunk cortado",
+			change: func(data []byte) []byte {
+				binary.LittleEndian.PutUint32(data[binHeader:], 1024)
+				return data
+			},
+			wantErr: "truncated",
+		},
+		{
+			name: "sem chunk JSON",
+			change: func(data []byte) []byte {
+				binary.LittleEndian.PutUint32(data[16:], glbChunkBIN)
+				return data
+			},
+			wantErr: "no JSON chunk",
+		},
+	}
+
+	for _, test := range tests {
+		data := test.change(append([]byte(nil), fixture...))
+		_, err := ParseGLTF(data, "testdata")
+		if test.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("%s: error = %v, want one about %q", test.name, err, test.wantErr)
+		}
+	}
+}
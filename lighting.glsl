@@ -0,0 +1,36 @@
+// Funções de iluminação compartilhadas pelos fragment shaders.
+// Incluído com: #include "lighting.glsl"
+
+// Propriedades espectrais de uma superfície
+struct Surface {
+    vec3 Kd; // Refletância difusa
+    vec3 Ks; // Refletância especular
+    vec3 Ka; // Refletância ambiente
+    float q; // Expoente especular para o modelo de iluminação de Phong
+};
+
+// Modelo de iluminação de Phong. p é o ponto, n a normal, l o sentido da
+// fonte de luz e v o sentido da câmera, todos em coordenadas globais.
+// Veja slide 129 do documento Aula_17_e_18_Modelos_de_Iluminacao.pdf.
+vec3 phong(Surface s, vec4 n, vec4 l, vec4 v)
+{
+    // Espectro da fonte de iluminação
+    vec3 I = vec3(1.0,1.0,1.0);
+
+    // Espectro da luz ambiente
+    vec3 Ia = vec3(0.2,0.2,0.2);
+
+    // Vetor que define o sentido da reflexão especular ideal.
+    vec4 r = -l + (2.0*n) * (dot(n, l));
+
+    // Termo difuso utilizando a lei dos cossenos de Lambert
+    vec3 lambert_diffuse_term = s.Kd * I * max(0, dot(n, l));
+
+    // Termo ambiente
+    vec3 ambient_term = s.Ka * Ia;
+
+    // Termo especular utilizando o modelo de iluminação de Phong
+    vec3 phong_specular_term = s.Ks * I * pow(max(0, dot(r, v)), s.q);
+
+    return lambert_diffuse_term + ambient_term + phong_specular_term;
+}
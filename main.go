@@ -11,124 +11,20 @@ import (
 	"image"
 	"image/draw"
 	_ "image/png"
-	"log"
-	"math"
 	"os"
 	"path"
 	"runtime"
 
 	"github.com/go-gl/gl/v3.3-core/gl"
-	"github.com/go-gl/glfw/v3.3/glfw"
-	"github.com/go-gl/mathgl/mgl32"
-	"github.com/reonardoleis/fcg-glcraft/block"
-	"github.com/reonardoleis/fcg-glcraft/camera"
-	"github.com/reonardoleis/fcg-glcraft/configs"
-	"github.com/reonardoleis/fcg-glcraft/engine/controls"
-	"github.com/reonardoleis/fcg-glcraft/engine/entrypoint"
-
-	"github.com/reonardoleis/fcg-glcraft/engine/scene"
-	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
-	"github.com/reonardoleis/fcg-glcraft/engine/window"
-	"github.com/reonardoleis/fcg-glcraft/geometry"
-	"github.com/reonardoleis/fcg-glcraft/lib"
-	math2 "github.com/reonardoleis/fcg-glcraft/math"
-	"github.com/reonardoleis/fcg-glcraft/player"
-	"github.com/reonardoleis/fcg-glcraft/world"
 )
 
-const windowWidth = 1280
-const windowHeight = 720
-
 func init() {
 	// GLFW event handling must run on the main OS thread
 	runtime.LockOSThread()
 }
 
 func main() {
-
-	window, err := window.NewWindow("fcg-glcraft", windowWidth, windowHeight)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	openGLEntrypoint := entrypoint.NewEntryPoint()
-	err = openGLEntrypoint.Init()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	//mainScene := scene.NewScene()
-
-	cow := lib.NewModel("./cow.obj")
-	cowVertices := cow.GetRenderableVertices()
-	cowGeometry := geometry.BuildObj(cowVertices, cow.VecIndices)
-	cowGeometry.Position = mgl32.Vec3{0, 34, 0}
-	cowGeometry.Tdir = 1
-
-	_, err = shaders.InitShaderProgram("standard")
-	if err != nil {
-		panic(err)
-	}
-
-	_, err = shaders.InitShaderProgram2("crosshair") // LoadShaderFromFiles(...)
-	if err != nil {
-		panic(err)
-	}
-	block.InitBlock() // LoadTextureImage(...)
-
-	for i := 0; i < 7; i++ {
-		geometry.BuildFace(i) // BuildTriangles...
-	}
-
-	geometry.BuildFaceEdges()
-
-	// world.SetPopulatedBlocks()
-
-	gl.Enable(gl.DEPTH_TEST)
-	gl.DepthFunc(gl.LESS)
-	gl.Enable(gl.BLEND)
-	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
-	//gl.Enable(gl.CULL_FACE)
-	//gl.CullFace(gl.BACK)
-	//gl.FrontFace(gl.CCW)
-
-	controlHandler := controls.NewControls(window)
-	controlHandler.StartKeyHandlers()
-
-	camera1 := camera.NewCamera(mgl32.Vec4{0.0, 0.0, 0.0, 1.0}, controlHandler, math.Pi/3, camera.FirstPersonCamera)
-	player1 := player.NewPlayer(mgl32.Vec4{0.0, 128, 0.0, 1.0}, controlHandler, 5, 2.0, configs.JumpHeight, configs.Jumpforce, 2)
-	player1.BeFollowedByCamera(camera1)
-	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-
-	sceneManager := scene.NewSceneManager()
-
-	world := world.NewWorld("", mgl32.Vec3{256, 32, 256}, 2300932812397)
-	world.GenerateWorld()
-	scene1 := scene.NewScene(world, camera1, &player1, controlHandler, scene.GameScene, []*geometry.GeometryInformation{&cowGeometry})
-
-	sceneManager.AddScene(scene1)
-	sceneManager.SetActiveScene(0)
-
-	start := float64(0.0)
-	end := float64(0.0)
-
-	for !window.ShouldClose() {
-		start = glfw.GetTime()
-
-		if controlHandler.IsDown(int(glfw.KeyEnter)) {
-			if sceneManager.ActiveScene == 0 {
-				sceneManager.SetActiveScene(1)
-			} else {
-				sceneManager.SetActiveScene(0)
-			}
-		}
-
-		sceneManager.HandleActiveScene(*window)
-
-		end = glfw.GetTime()
-
-		math2.DeltaTime = end - start
-	}
+	os.Exit(run(os.Args[1:]))
 }
 
 func newTexture(file string) (uint32, error) {
@@ -160,7 +56,7 @@ func newTexture(file string) (uint32, error) {
 	gl.TexImage2D(
 		gl.TEXTURE_2D,
 		0,
-		gl.RGBA,
+		gl.SRGB8_ALPHA8,
 		int32(rgba.Rect.Size().X),
 		int32(rgba.Rect.Size().Y),
 		0,
@@ -0,0 +1,85 @@
+package math2
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// CascadeSplits divides [near, far] in count slices for cascaded shadow maps,
+// blending logarithmic and uniform splits ("practical split scheme"): lambda
+// 1 is fully logarithmic and 0 fully uniform. Returns count+1 distances, from
+// near to far.
+func CascadeSplits(near, far float32, count int, lambda float32) []float32 {
+	splits := make([]float32, count+1)
+	splits[0] = near
+	for i := 1; i <= count; i++ {
+		fraction := float64(i) / float64(count)
+		logarithmic := float64(near) * math.Pow(float64(far/near), fraction)
+		uniform := float64(near) + float64(far-near)*fraction
+		splits[i] = float32(float64(lambda)*logarithmic + (1-float64(lambda))*uniform)
+	}
+	splits[count] = far
+
+	return splits
+}
+
+// FrustumSliceCorners returns the world space corners of the part of a
+// perspective frustum between distances near and far from the camera. view
+// is the camera view matrix (looking at -z), fov the vertical field of view.
+// The four near corners come first.
+func FrustumSliceCorners(view mgl32.Mat4, fov, aspect, near, far float32) [8]mgl32.Vec3 {
+	var corners [8]mgl32.Vec3
+	inverseView := view.Inv()
+	tangent := float32(math.Tan(float64(fov / 2)))
+
+	for i, distance := range []float32{near, far} {
+		halfHeight := distance * tangent
+		halfWidth := halfHeight * aspect
+		for j, corner := range [4][2]float32{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}} {
+			viewPoint := mgl32.Vec4{corner[0] * halfWidth, corner[1] * halfHeight, -distance, 1}
+			corners[i*4+j] = inverseView.Mul4x1(viewPoint).Vec3()
+		}
+	}
+
+	return corners
+}
+
+// LightMatrix returns projection * view of a directional light travelling
+// along direction, with an orthographic box enclosing the corners. The box
+// bounds a sphere around the corners so its size does not change when the
+// camera rotates, and it moves in whole shadow map texels so shadow edges do
+// not shimmer when the camera moves. depthMargin extends the box towards the
+// light, to keep casters outside of the slice.
+func LightMatrix(direction mgl32.Vec3, corners [8]mgl32.Vec3, resolution int, depthMargin float32) mgl32.Mat4 {
+	var center mgl32.Vec3
+	for _, corner := range corners {
+		center = center.Add(corner)
+	}
+	center = center.Mul(1.0 / float32(len(corners)))
+
+	radius := float32(0)
+	for _, corner := range corners {
+		if distance := corner.Sub(center).Len(); distance > radius {
+			radius = distance
+		}
+	}
+	radius = float32(math.Ceil(float64(radius)*16) / 16)
+
+	up := mgl32.Vec4{0, 1, 0, 0}
+	if math.Abs(float64(direction.Normalize().Y())) > 0.99 {
+		up = mgl32.Vec4{1, 0, 0, 0}
+	}
+	// só rotação: a translação fica na projeção, onde é alinhada aos texels
+	lightView := Matrix_Camera_View(mgl32.Vec4{0, 0, 0, 1}, direction.Vec4(0), up)
+
+	lightCenter := lightView.Mul4x1(center.Vec4(1))
+	texel := 2 * radius / float32(resolution)
+	x := float32(math.Floor(float64(lightCenter.X()/texel))) * texel
+	y := float32(math.Floor(float64(lightCenter.Y()/texel))) * texel
+
+	// a câmera da luz olha para -z, então o plano near tem o maior z
+	projection := Matrix_Orthographic(x-radius, x+radius, y-radius, y+radius, lightCenter.Z()+radius+depthMargin, lightCenter.Z()-radius)
+
+	return projection.Mul4(lightView)
+}
@@ -0,0 +1,136 @@
+package math2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func near(a, b, tolerance float32) bool {
+	return math.Abs(float64(a-b)) <= float64(tolerance)
+}
+
+func TestCascadeSplits(t *testing.T) {
+	tests := []struct {
+		name      string
+		near, far float32
+		count     int
+		lambda    float32
+		want      []float32
+	}{
+		{"uniform", 1, 101, 4, 0, []float32{1, 26, 51, 76, 101}},
+		{"logarithmic", 1, 1000, 3, 1, []float32{1, 10, 100, 1000}},
+		{"blend", 1, 1000, 3, 0.5, []float32{1, (10 + 334) / 2.0, (100 + 667) / 2.0, 1000}},
+		{"single cascade", 0.1, 64, 1, 0.75, []float32{0.1, 64}},
+	}
+	for _, test := range tests {
+		got := CascadeSplits(test.near, test.far, test.count, test.lambda)
+		if len(got) != len(test.want) {
+			t.Fatalf("%s: got %d splits, want %d", test.name, len(got), len(test.want))
+		}
+		for i := range got {
+			if !near(got[i], test.want[i], 1e-3*test.want[i]) {
+				t.Errorf("%s: splits = %v, want %v", test.name, got, test.want)
+				break
+			}
+		}
+	}
+
+	// qualquer lambda dá distâncias crescentes, com os extremos exatos
+	for _, lambda := range []float32{0, 0.25, 0.5, 0.75, 1} {
+		splits := CascadeSplits(0.1, 64, 4, lambda)
+		if splits[0] != 0.1 || splits[4] != 64 {
+			t.Errorf("lambda %v: splits go from %v to %v, want 0.1 to 64", lambda, splits[0], splits[4])
+		}
+		for i := 1; i < len(splits); i++ {
+			if splits[i] <= splits[i-1] {
+				t.Errorf("lambda %v: splits %v aren't increasing", lambda, splits)
+			}
+		}
+	}
+}
+
+func TestFrustumSliceCorners(t *testing.T) {
+	// câmera na origem olhando para -z, 90° de abertura vertical
+	corners := FrustumSliceCorners(mgl32.Ident4(), math.Pi/2, 2, 1, 10)
+	want := [8]mgl32.Vec3{
+		{-2, -1, -1}, {2, -1, -1}, {2, 1, -1}, {-2, 1, -1},
+		{-20, -10, -10}, {20, -10, -10}, {20, 10, -10}, {-20, 10, -10},
+	}
+	for i := range corners {
+		if !corners[i].ApproxEqualThreshold(want[i], 1e-4) {
+			t.Errorf("corner %d = %v, want %v", i, corners[i], want[i])
+		}
+	}
+
+	// com a câmera movida, os cantos acompanham a câmera
+	eye := mgl32.Vec3{10, 5, -3}
+	view := mgl32.LookAtV(eye, eye.Add(mgl32.Vec3{1, 0, 0}), mgl32.Vec3{0, 1, 0})
+	moved := FrustumSliceCorners(view, math.Pi/2, 2, 1, 10)
+	if center := moved[0].Add(moved[2]).Mul(0.5); !center.ApproxEqualThreshold(mgl32.Vec3{11, 5, -3}, 1e-4) {
+		t.Errorf("center of the near corners = %v, want one unit ahead of the camera", center)
+	}
+}
+
+func TestLightMatrixContainsSlices(t *testing.T) {
+	const resolution = 2048
+
+	eyes := []mgl32.Vec3{{0, 0, 0}, {123.4, 40, -57.9}, {-300.25, 70.5, 12.75}}
+	targets := []mgl32.Vec3{{0, 0, -1}, {1, -0.3, 0.2}, {-0.5, 0.8, 0.5}}
+	directions := []mgl32.Vec3{
+		{-0.3, -1, -0.2},
+		{0, -1, 0}, // sol a pino usa outro vetor up
+		{-1, -0.05, 0},
+	}
+
+	for _, eye := range eyes {
+		for _, target := range targets {
+			view := mgl32.LookAtV(eye, eye.Add(target), mgl32.Vec3{0, 1, 0})
+			splits := CascadeSplits(0.1, 64, 3, 0.75)
+			for cascade := 0; cascade < 3; cascade++ {
+				corners := FrustumSliceCorners(view, math.Pi/3, 16.0/9, splits[cascade], splits[cascade+1])
+				for _, direction := range directions {
+					light := LightMatrix(direction, corners, resolution, 64)
+					for _, corner := range corners {
+						clip := light.Mul4x1(corner.Vec4(1))
+						ndc := clip.Vec3().Mul(1 / clip.W())
+						for axis := 0; axis < 3; axis++ {
+							if ndc[axis] < -1 || ndc[axis] > 1 {
+								t.Errorf("eye %v, cascade %d, sun %v: corner %v lands at %v, outside the shadow map", eye, cascade, direction, corner, ndc)
+								break
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestLightMatrixStable(t *testing.T) {
+	const resolution = 1024
+	direction := mgl32.Vec3{-0.3, -1, -0.2}
+
+	lightMatrix := func(eye, target mgl32.Vec3) mgl32.Mat4 {
+		view := mgl32.LookAtV(eye, eye.Add(target), mgl32.Vec3{0, 1, 0})
+		return LightMatrix(direction, FrustumSliceCorners(view, math.Pi/3, 16.0/9, 1, 20), resolution, 64)
+	}
+
+	// girar a câmera não muda o tamanho da caixa
+	a := lightMatrix(mgl32.Vec3{5, 10, 5}, mgl32.Vec3{1, 0, 0})
+	b := lightMatrix(mgl32.Vec3{5, 10, 5}, mgl32.Vec3{0, -0.2, 1})
+	if !near(a.Col(0).Vec3().Len(), b.Col(0).Vec3().Len(), 1e-6) {
+		t.Errorf("box scale changed with the camera rotation: %v and %v", a.Col(0).Vec3().Len(), b.Col(0).Vec3().Len())
+	}
+
+	// mover a câmera desloca a caixa em texels inteiros
+	c := lightMatrix(mgl32.Vec3{5.013, 10, 5.007}, mgl32.Vec3{1, 0, 0})
+	texel := float32(2) / resolution // em NDC
+	for axis := 0; axis < 2; axis++ {
+		shift := (c.At(axis, 3) - a.At(axis, 3)) / texel
+		if !near(shift, float32(math.Round(float64(shift))), 1e-2) {
+			t.Errorf("axis %d: box moved %v texels, want a whole number", axis, shift)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package math2
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Plane is the set of points p where Normal·p + D = 0. Points with positive
+// distance are on the side the normal points to.
+type Plane struct {
+	Normal mgl32.Vec3
+	D      float32
+}
+
+// Distance from a point to the plane, negative if it is behind the normal
+func (p Plane) Distance(point mgl32.Vec3) float32 {
+	return p.Normal.Dot(point) + p.D
+}
+
+func (p Plane) normalize() Plane {
+	length := p.Normal.Len()
+	if length == 0 {
+		return p
+	}
+
+	return Plane{Normal: p.Normal.Mul(1 / length), D: p.D / length}
+}
+
+const (
+	FrustumLeft = iota
+	FrustumRight
+	FrustumBottom
+	FrustumTop
+	FrustumNear
+	FrustumFar
+)
+
+// Frustum is the visible volume of a camera, as six planes pointing inwards
+type Frustum struct {
+	Planes [6]Plane
+}
+
+// NewFrustum extracts the frustum planes from a projection * view matrix
+// (Gribb & Hartmann). A point q is visible when -w <= x, y, z <= w for
+// (x, y, z, w) = viewProjection * q, and every inequality is one plane.
+func NewFrustum(viewProjection mgl32.Mat4) Frustum {
+	row := func(i int) mgl32.Vec4 {
+		return viewProjection.Row(i)
+	}
+
+	planeFrom := func(v mgl32.Vec4) Plane {
+		return Plane{Normal: v.Vec3(), D: v.W()}.normalize()
+	}
+
+	var f Frustum
+	f.Planes[FrustumLeft] = planeFrom(row(3).Add(row(0)))
+	f.Planes[FrustumRight] = planeFrom(row(3).Sub(row(0)))
+	f.Planes[FrustumBottom] = planeFrom(row(3).Add(row(1)))
+	f.Planes[FrustumTop] = planeFrom(row(3).Sub(row(1)))
+	f.Planes[FrustumNear] = planeFrom(row(3).Add(row(2)))
+	f.Planes[FrustumFar] = planeFrom(row(3).Sub(row(2)))
+
+	return f
+}
+
+// ContainsPoint tells if the point is inside the frustum
+func (f Frustum) ContainsPoint(point mgl32.Vec3) bool {
+	for _, plane := range f.Planes {
+		if plane.Distance(point) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IntersectsAABB tells if an axis-aligned box is at least partially inside the
+// frustum. For each plane only the box corner furthest along the normal is
+// tested, so boxes near the frustum corners may be reported as visible.
+func (f Frustum) IntersectsAABB(min, max mgl32.Vec3) bool {
+	for _, plane := range f.Planes {
+		corner := min
+		for axis := 0; axis < 3; axis++ {
+			if plane.Normal[axis] >= 0 {
+				corner[axis] = max[axis]
+			}
+		}
+
+		if plane.Distance(corner) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
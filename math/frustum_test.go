@@ -0,0 +1,145 @@
+package math2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type box struct {
+	name     string
+	min, max mgl32.Vec3
+}
+
+func corners(b box) []mgl32.Vec3 {
+	points := []mgl32.Vec3{}
+	for i := 0; i < 8; i++ {
+		point := b.min
+		for axis := 0; axis < 3; axis++ {
+			if i&(1<<axis) != 0 {
+				point[axis] = b.max[axis]
+			}
+		}
+		points = append(points, point)
+	}
+
+	return points
+}
+
+// Confere cada caixa contra o frustum: dentro tem todos os cantos visíveis,
+// cruzando tem alguns e fora não é visível
+func checkBoxes(t *testing.T, frustum Frustum, inside, intersecting, outside []box) {
+	t.Helper()
+
+	for _, b := range inside {
+		if !frustum.IntersectsAABB(b.min, b.max) {
+			t.Errorf("%s: inside box culled", b.name)
+		}
+		for _, corner := range corners(b) {
+			if !frustum.ContainsPoint(corner) {
+				t.Errorf("%s: corner %v of an inside box is outside", b.name, corner)
+			}
+		}
+	}
+
+	for _, b := range intersecting {
+		if !frustum.IntersectsAABB(b.min, b.max) {
+			t.Errorf("%s: intersecting box culled", b.name)
+		}
+		in := 0
+		for _, corner := range corners(b) {
+			if frustum.ContainsPoint(corner) {
+				in++
+			}
+		}
+		if in == 0 || in == 8 {
+			t.Errorf("%s: %d of 8 corners inside, want some", b.name, in)
+		}
+	}
+
+	for _, b := range outside {
+		if frustum.IntersectsAABB(b.min, b.max) {
+			t.Errorf("%s: outside box not culled", b.name)
+		}
+	}
+}
+
+func TestFrustumPerspective(t *testing.T) {
+	// 90° de abertura e aspecto 1: a meia largura visível é igual à distância
+	projection := mgl32.Perspective(math.Pi/2, 1, 1, 100)
+	view := mgl32.LookAtV(mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 0, -1}, mgl32.Vec3{0, 1, 0})
+	frustum := NewFrustum(projection.Mul4(view))
+
+	for i, plane := range frustum.Planes {
+		if length := plane.Normal.Len(); math.Abs(float64(length-1)) > 1e-5 {
+			t.Errorf("plane %d has normal of length %v, want 1", i, length)
+		}
+	}
+
+	near, far := frustum.Planes[FrustumNear], frustum.Planes[FrustumFar]
+	if d := near.Distance(mgl32.Vec3{0, 0, -1}); math.Abs(float64(d)) > 1e-4 {
+		t.Errorf("near plane is %v from z = -1, want 0", d)
+	}
+	if d := far.Distance(mgl32.Vec3{0, 0, -100}); math.Abs(float64(d)) > 1e-2 {
+		t.Errorf("far plane is %v from z = -100, want 0", d)
+	}
+
+	checkBoxes(t, frustum,
+		[]box{
+			{"ahead", mgl32.Vec3{-1, -1, -11}, mgl32.Vec3{1, 1, -9}},
+			{"off center", mgl32.Vec3{5, -8, -30}, mgl32.Vec3{10, -4, -20}},
+		},
+		[]box{
+			{"across the near plane", mgl32.Vec3{-0.1, -0.1, -2}, mgl32.Vec3{0.1, 0.1, 0}},
+			{"across the right plane", mgl32.Vec3{8, -1, -11}, mgl32.Vec3{12, 1, -9}},
+			{"across the far plane", mgl32.Vec3{-1, -1, -110}, mgl32.Vec3{1, 1, -90}},
+		},
+		[]box{
+			{"behind", mgl32.Vec3{-1, -1, 5}, mgl32.Vec3{1, 1, 10}},
+			{"too far", mgl32.Vec3{-1, -1, -200}, mgl32.Vec3{1, 1, -150}},
+			{"left", mgl32.Vec3{-30, -1, -11}, mgl32.Vec3{-20, 1, -9}},
+			{"above", mgl32.Vec3{-1, 20, -11}, mgl32.Vec3{1, 25, -9}},
+		},
+	)
+}
+
+func TestFrustumMovedCamera(t *testing.T) {
+	// câmera em (10, 5, 10) olhando para +x, como o jogador andando no mundo
+	projection := mgl32.Perspective(math.Pi/3, 16.0/9, 0.1, 64)
+	eye := mgl32.Vec3{10, 5, 10}
+	view := mgl32.LookAtV(eye, eye.Add(mgl32.Vec3{1, 0, 0}), mgl32.Vec3{0, 1, 0})
+	frustum := NewFrustum(projection.Mul4(view))
+
+	if !frustum.ContainsPoint(mgl32.Vec3{30, 5, 10}) {
+		t.Errorf("point in front of the camera is outside")
+	}
+	if frustum.ContainsPoint(mgl32.Vec3{-10, 5, 10}) {
+		t.Errorf("point behind the camera is inside")
+	}
+
+	checkBoxes(t, frustum,
+		[]box{{"chunk ahead", mgl32.Vec3{25, 0, 5}, mgl32.Vec3{35, 10, 15}}},
+		[]box{{"block around the camera", mgl32.Vec3{8, 4, 8}, mgl32.Vec3{12, 6, 12}}},
+		[]box{
+			{"chunk behind", mgl32.Vec3{-20, 0, 0}, mgl32.Vec3{-4, 10, 16}},
+			{"chunk to the side", mgl32.Vec3{12, 0, 40}, mgl32.Vec3{16, 10, 56}},
+			{"chunk past the far plane", mgl32.Vec3{90, 0, 5}, mgl32.Vec3{106, 10, 21}},
+		},
+	)
+}
+
+func TestFrustumOrthographic(t *testing.T) {
+	// mapas de sombra usam projeções ortográficas
+	projection := mgl32.Ortho(-10, 10, -5, 5, 0, 50)
+	frustum := NewFrustum(projection)
+
+	checkBoxes(t, frustum,
+		[]box{{"centered", mgl32.Vec3{-9, -4, -49}, mgl32.Vec3{9, 4, -1}}},
+		[]box{{"across the top", mgl32.Vec3{-1, 4, -10}, mgl32.Vec3{1, 6, -5}}},
+		[]box{
+			{"right of the box", mgl32.Vec3{11, -1, -10}, mgl32.Vec3{12, 1, -5}},
+			{"in front of near", mgl32.Vec3{-1, -1, 1}, mgl32.Vec3{1, 1, 2}},
+		},
+	)
+}
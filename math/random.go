@@ -9,3 +9,8 @@ func SetSeed(seed int64) {
 func RandInt(min, max int) int {
 	return rand.Intn(max-min+1) + min
 }
+
+// RandIntFrom is RandInt with its own source, for generation that must repeat with the same seed
+func RandIntFrom(source *rand.Rand, min, max int) int {
+	return source.Intn(max-min+1) + min
+}
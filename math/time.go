@@ -1,6 +1,7 @@
 package math2
 
-// DeltaTime is used for smooth animations. It is updated every frame with end - start
+// DeltaTime is the time simulated by the current step, configs.FixedTimestep
+// while the scenes are updated
 var (
 	DeltaTime float64 = 0.0
 )
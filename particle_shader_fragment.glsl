@@ -0,0 +1,23 @@
+#version 330 core
+
+in vec2 uv;
+in vec4 particle_color;
+
+uniform sampler2D particle_texture;
+uniform bool textured;
+uniform float brightness;
+
+out vec4 color;
+
+void main()
+{
+    color = particle_color;
+    if (textured) {
+        color *= texture(particle_texture, uv);
+    }
+    if (color.a < 0.05) {
+        discard;
+    }
+
+    color.rgb *= brightness;
+}
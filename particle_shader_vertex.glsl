@@ -0,0 +1,27 @@
+#version 330 core
+
+// Partículas (engine/particles): um quadrado por instância, sempre de frente
+// para a câmera
+layout (location = 0) in vec2 corner;
+layout (location = 1) in vec4 center_size;
+layout (location = 2) in vec4 instance_color;
+layout (location = 3) in vec4 instance_uv;
+
+uniform mat4 view;
+uniform mat4 projection;
+
+out vec2 uv;
+out vec4 particle_color;
+
+void main()
+{
+    // eixos direita e cima da câmera, linhas da rotação da view
+    vec3 right = vec3(view[0][0], view[1][0], view[2][0]);
+    vec3 up = vec3(view[0][1], view[1][1], view[2][1]);
+
+    vec3 position = center_size.xyz + (right * corner.x + up * corner.y) * center_size.w;
+    gl_Position = projection * view * vec4(position, 1.0);
+
+    uv = instance_uv.xy + (corner + 0.5) * instance_uv.zw;
+    particle_color = instance_color;
+}
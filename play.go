@@ -0,0 +1,401 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/controls"
+	"github.com/reonardoleis/fcg-glcraft/engine/entrypoint"
+	"github.com/reonardoleis/fcg-glcraft/engine/metrics"
+	"github.com/reonardoleis/fcg-glcraft/engine/postprocess"
+	"github.com/reonardoleis/fcg-glcraft/engine/replay"
+	"github.com/reonardoleis/fcg-glcraft/engine/scene"
+	"github.com/reonardoleis/fcg-glcraft/engine/settings"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
+	"github.com/reonardoleis/fcg-glcraft/engine/shadows"
+	"github.com/reonardoleis/fcg-glcraft/engine/ui"
+	"github.com/reonardoleis/fcg-glcraft/engine/window"
+	"github.com/reonardoleis/fcg-glcraft/geometry"
+	"github.com/reonardoleis/fcg-glcraft/lib"
+	math2 "github.com/reonardoleis/fcg-glcraft/math"
+	"github.com/reonardoleis/fcg-glcraft/world"
+	"github.com/reonardoleis/fcg-glcraft/world/chunk"
+	"github.com/reonardoleis/fcg-glcraft/world/saves"
+)
+
+// play opens the game window. With -world it goes straight into that world,
+// creating it if needed; otherwise it starts in the title screen.
+func play(args []string) int {
+	flags := newFlagSet("play", "")
+	worldName := flags.String("world", "", "entra direto neste mundo, criando-o se não existir")
+	seed := flags.String("seed", "", "semente do mundo criado por -world; texto é convertido, vazio é aleatória")
+	generator := flags.String("generator", chunk.GeneratorDefault, "gerador do mundo criado por -world")
+	savesDir := flags.String("dir", saves.DefaultDir, "pasta dos mundos salvos")
+	width := flags.Int("width", 1280, "largura da janela; 0 usa a do monitor em tela cheia")
+	height := flags.Int("height", 720, "altura da janela; 0 usa a do monitor em tela cheia")
+	fullscreen := flags.Bool("fullscreen", false, "abre em tela cheia no monitor principal")
+	recordPath := flags.String("record", "", "grava a entrada do jogo neste arquivo")
+	replayPath := flags.String("replay", "", "reproduz uma gravação feita com -record na janela")
+	var overrides overrideFlag
+	flags.Var(&overrides, "set", "muda uma configuração, como video.view_distance=2 ou world.cave_threshold=0.5 para os mundos novos; pode repetir")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	switch {
+	case flags.NArg() > 0:
+		return usageError(flags, "unexpected argument %q", flags.Arg(0))
+	case !validGenerator(*generator):
+		return usageError(flags, "unknown generator %q, expected one of %v", *generator, chunk.Generators)
+	case *replayPath != "" && (*worldName != "" || *recordPath != ""):
+		return usageError(flags, "-replay plays the recorded world and can't be used with -world or -record")
+	}
+
+	worldConfig := configs.DefaultWorld()
+	overrides, err := overrides.applyWorld(&worldConfig)
+	if err != nil {
+		return usageError(flags, "%v", err)
+	}
+
+	gameSettings, err := settings.Load(settings.DefaultPath)
+	if err != nil {
+		log.Println(err)
+	}
+	for _, override := range overrides {
+		if err := gameSettings.Set(override.key, override.value); err != nil {
+			return usageError(flags, "%v", err)
+		}
+	}
+	if *fullscreen {
+		gameSettings.Video.Fullscreen = true
+	}
+	if !gameSettings.Video.Fullscreen && (*width <= 0 || *height <= 0) {
+		return usageError(flags, "the window size must be positive, got %dx%d", *width, *height)
+	}
+	configs.Video = gameSettings.Video
+
+	var recording *replay.Recording
+	if *replayPath != "" {
+		recording, err = replay.Load(*replayPath)
+		if err != nil {
+			log.Println(err)
+			return exitError
+		}
+	}
+
+	var startInfo *saves.WorldInfo
+	if *worldName != "" {
+		info, err := loadOrCreateWorld(*savesDir, *worldName, *seed, *generator, worldConfig)
+		if err != nil {
+			log.Println(err)
+			return exitError
+		}
+		startInfo = &info
+	}
+
+	gameWindow, err := window.NewWindow(window.Options{
+		Title:      "fcg-glcraft",
+		Width:      *width,
+		Height:     *height,
+		Fullscreen: gameSettings.Video.Fullscreen,
+		VSync:      gameSettings.Video.VSync,
+		// o MSAA fica no alvo da cena do pós-processamento, que não pode ser
+		// copiado para uma janela multiamostrada
+	})
+	if err != nil {
+		log.Println(err)
+		return exitError
+	}
+	screen := func() ui.Screen {
+		return ui.NewScreen(gameWindow.Width, gameWindow.Height, gameWindow.Scale, gameWindow.CursorScale())
+	}
+
+	openGLEntrypoint := entrypoint.NewEntryPoint()
+	err = openGLEntrypoint.Init()
+	if err != nil {
+		log.Println(err)
+		return exitError
+	}
+
+	//mainScene := scene.NewScene()
+
+	shaders.Programs.Defines = append(shaders.Programs.Defines, shadows.Defines()...)
+	shaders.Programs.Defines = append(shaders.Programs.Defines, world.Defines()...)
+
+	standardProgram, err := shaders.Programs.Load("standard")
+	if err != nil {
+		log.Println(err)
+		return exitError
+	}
+
+	crosshairProgram, err := shaders.Programs.Load("crosshair") // LoadShaderFromFiles(...)
+	if err != nil {
+		log.Println(err)
+		return exitError
+	}
+
+	for _, name := range []string{"shadow", "sky", "particle"} {
+		if _, err := shaders.Programs.Load(name); err != nil {
+			log.Println(err)
+			return exitError
+		}
+	}
+
+	uiProgram, err := shaders.Programs.Load("ui")
+	if err != nil {
+		log.Println(err)
+		return exitError
+	}
+
+	// pós-processamento configurado por postprocess.json, se existir
+	postProcessDescription, err := postprocess.LoadDescription("postprocess.json")
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Println(err)
+		}
+		postProcessDescription = postprocess.DefaultDescription()
+	}
+
+	postProcess, err := postprocess.NewPipeline(shaders.Programs, postProcessDescription, int32(gameWindow.Width), int32(gameWindow.Height), gameSettings.Video.MSAA)
+	if err != nil {
+		log.Println(err)
+		return exitError
+	}
+
+	// recompila os shaders quando os arquivos .glsl forem alterados
+	shaders.Programs.Watch(time.Second)
+	block.InitBlock() // LoadTextureImage(...)
+
+	geometry.InitCommonMeshes(standardProgram, crosshairProgram)
+
+	cow := lib.NewModel("./cow.obj")
+	cowVertices := cow.GetRenderableVertices()
+	cowGeometry := geometry.GeometryInformation{
+		Mesh: geometry.NewMesh(geometry.BuildObj(cowVertices), geometry.NewMaterial(standardProgram, shaders.ObjectOBJ)),
+	}
+	cowGeometry.Position = mgl32.Vec3{0, 34, 0}
+	cowGeometry.Tdir = 1
+
+	// world.SetPopulatedBlocks()
+
+	gl.Enable(gl.DEPTH_TEST)
+	gl.DepthFunc(gl.LESS)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	//gl.Enable(gl.CULL_FACE)
+	//gl.CullFace(gl.BACK)
+	//gl.FrontFace(gl.CCW)
+
+	controlHandler := controls.NewControls(gameWindow.Window)
+
+	font := ui.DefaultFont()
+	uiBatch := ui.NewBatch(uiProgram)
+	hud := scene.NewHUD(uiBatch, font, screen())
+	debugOverlay := scene.NewDebugOverlay(uiBatch, font, screen())
+
+	sceneManager := scene.NewSceneManager()
+	menus := &scene.Menus{
+		Manager:      &sceneManager,
+		Window:       gameWindow,
+		Controls:     controlHandler,
+		Font:         font,
+		Batch:        uiBatch,
+		Screen:       screen(),
+		SavesDir:     *savesDir,
+		Settings:     &gameSettings,
+		SettingsPath: settings.DefaultPath,
+		WorldConfig:  worldConfig,
+		NewGame: func(info saves.WorldInfo) *scene.GameScene {
+			// o jogo só lê a entrada enquanto está no topo da pilha
+			game := scene.NewGame(info, controlHandler.Route())
+			game.Objs = []*geometry.GeometryInformation{&cowGeometry}
+			game.PostProcess = postProcess
+			game.HUD = hud
+			game.Debug = debugOverlay
+
+			return game
+		},
+	}
+
+	// o viewport e a projeção já foram atualizados pela janela
+	gameWindow.OnResize(func(w *window.Window) {
+		if err := postProcess.Resize(int32(w.Width), int32(w.Height)); err != nil {
+			log.Println(err)
+		}
+		hud.Context.Resize(screen())
+		debugOverlay.Context.Resize(screen())
+		menus.Resize(screen())
+	})
+
+	// uma gravação é reproduzida no lugar da entrada da janela, sem os menus
+	var driver *replay.Driver
+	var replayed *scene.GameScene
+	if recording != nil {
+		replayed = menus.NewGame(recording.Header.World)
+		sceneManager.Push(replayed)
+		driver = replay.NewDriver(recording, replayed)
+	} else {
+		controlHandler.StartKeyHandlers()
+		menus.Start()
+		if startInfo != nil {
+			menus.Play(*startInfo)
+		}
+	}
+
+	var recorder *replay.Recorder
+	closeRecorder := func() {
+		if recorder == nil {
+			return
+		}
+		if err := recorder.Close(); err != nil {
+			log.Println(err)
+		}
+		recorder = nil
+	}
+
+	// o jogo avança em passos fixos, para que as gravações se repitam iguais
+	step := configs.FixedTimestep
+	accumulator := step
+	previous := glfw.GetTime()
+
+	for !gameWindow.ShouldClose() {
+		for _, err := range shaders.Programs.ReloadChanged() {
+			log.Println(err)
+		}
+
+		top := sceneManager.Top()
+		for steps := 0; accumulator >= step && steps < configs.MaxStepsPerFrame; steps++ {
+			accumulator -= step
+			math2.DeltaTime = step
+
+			if driver != nil {
+				stepped, ok := driver.Next()
+				if !ok {
+					gameWindow.SetShouldClose(true)
+					break
+				}
+
+				controls.DefaultActions.Update()
+				if stepped {
+					sceneManager.Update(step)
+				}
+				continue
+			}
+
+			controls.DefaultActions.Update()
+
+			// Tab pausa o jogo e volta dele
+			if controlHandler.Pressed(controls.ActionPause) {
+				menus.TogglePause()
+			}
+			// Esc fecha a tela de cima; só sai do jogo pela tela de título
+			if controlHandler.Pressed(controls.ActionBack) {
+				menus.Back()
+			}
+			if controlHandler.Pressed(controls.ActionFullscreen) {
+				menus.ToggleFullscreen()
+			}
+			if controlHandler.Pressed(controls.ActionChat) {
+				menus.OpenConsole("")
+			} else if controlHandler.Pressed(controls.ActionCommand) {
+				menus.OpenConsole("/")
+			}
+
+			// grava o primeiro jogo iniciado, até ele ser fechado
+			game := menus.Game()
+			if recorder != nil && recorder.Game() != game {
+				closeRecorder()
+			}
+			if recorder == nil && game != nil && *recordPath != "" {
+				recorder, err = replay.NewRecorder(*recordPath, game, menus.GameInfo(), step)
+				if err != nil {
+					log.Println(err)
+				}
+				*recordPath = ""
+			}
+
+			stepped := game != nil && sceneManager.Top() == game
+			if recorder != nil {
+				recorder.Sync()
+			}
+			sceneManager.Update(step)
+			if recorder != nil {
+				recorder.Tick(stepped)
+				recorder.Sync()
+			}
+		}
+
+		// quadros lentos demais descartam o atraso em vez de acelerar o jogo
+		if accumulator >= step {
+			accumulator = 0
+		}
+
+		// minimizada não há onde desenhar
+		if !gameWindow.Minimized() {
+			sceneManager.Render()
+		}
+
+		gameWindow.SwapBuffers()
+		glfw.PollEvents()
+		if recorder != nil {
+			recorder.Frame()
+		}
+
+		now := glfw.GetTime()
+		frameTime := now - previous
+		previous = now
+
+		// o quadro em que a cena muda pode incluir a geração de um mundo, que
+		// não deve contar como tempo de jogo
+		if sceneManager.Top() == top {
+			accumulator += frameTime
+		}
+		metrics.Record(metrics.FrameTime, frameTime)
+		metrics.EndFrame()
+	}
+
+	if driver != nil {
+		return report(recording, replay.StateOf(replayed, driver.Ticks()))
+	}
+
+	closeRecorder()
+	menus.SaveGame()
+	return exitOK
+}
+
+// Carrega o mundo de -world ou o cria com a semente e o gerador pedidos
+func loadOrCreateWorld(dir, name, seed, generator string, config configs.WorldConfig) (saves.WorldInfo, error) {
+	if saves.Exists(dir, name) {
+		if seed != "" {
+			log.Printf("world %q already exists, -seed is ignored", name)
+		}
+		return saves.Load(dir, name)
+	}
+
+	now := time.Now()
+	info := scene.NewWorldInfo(name, scene.ParseSeed(seed, now), generator, config, now)
+	if err := saves.Save(dir, info); err != nil {
+		return saves.WorldInfo{}, err
+	}
+
+	return info, nil
+}
+
+// Compara o estado ao fim de um replay com o gravado
+func report(recording *replay.Recording, state replay.State) int {
+	if err := recording.Final.Compare(state); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitMismatch
+	}
+
+	fmt.Printf("replay ok: %d ticks, player at %v, world %016x\n", state.Ticks, state.Position, state.WorldHash)
+	return exitOK
+}
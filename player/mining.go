@@ -0,0 +1,51 @@
+package player
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// GameMode changes how the player interacts with the world
+type GameMode int
+
+const (
+	Survival GameMode = iota // blocos levam block.Hardness segundos para quebrar
+	Creative                 // blocos quebram com um clique
+)
+
+// Mining tracks the progress of breaking the targeted block in survival
+type Mining struct {
+	Target   mgl32.Vec3
+	Active   bool
+	Progress float32 // de 0 a 1
+}
+
+// Update advances the mining of target by deltaTime seconds while digging,
+// restarting when the target changes. It returns true when the block breaks.
+func (m *Mining) Update(target *mgl32.Vec4, digging bool, hardness, deltaTime float32) bool {
+	if !digging || target == nil {
+		m.Reset()
+		return false
+	}
+
+	if !m.Active || m.Target != target.Vec3() {
+		m.Target = target.Vec3()
+		m.Active = true
+		m.Progress = 0
+	}
+
+	if hardness <= 0 {
+		m.Progress = 1
+	} else {
+		m.Progress += deltaTime / hardness
+	}
+
+	if m.Progress >= 1 {
+		m.Reset()
+		return true
+	}
+
+	return false
+}
+
+// Reset stops mining
+func (m *Mining) Reset() {
+	*m = Mining{}
+}
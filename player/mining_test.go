@@ -0,0 +1,54 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestMining(t *testing.T) {
+	stone := &mgl32.Vec4{1, 2, 3, 1}
+	dirt := &mgl32.Vec4{1, 3, 3, 1}
+
+	// passos em sequência sobre o mesmo Mining
+	steps := []struct {
+		name      string
+		target    *mgl32.Vec4
+		digging   bool
+		hardness  float32
+		deltaTime float32
+		broken    bool
+		progress  float32
+	}{
+		{"começa a minerar", stone, true, 2, 0.5, false, 0.25},
+		{"continua", stone, true, 2, 0.5, false, 0.5},
+		{"troca de alvo recomeça", dirt, true, 1, 0.25, false, 0.25},
+		{"volta ao primeiro alvo recomeça", stone, true, 2, 0.5, false, 0.25},
+		{"soltar o botão recomeça", stone, false, 2, 0.5, false, 0},
+		{"mira em nada recomeça", nil, true, 2, 0.5, false, 0},
+		{"começa de novo", stone, true, 2, 1, false, 0.5},
+		{"quebra ao completar", stone, true, 2, 1, true, 0},
+		{"depois de quebrar começa do zero", stone, true, 2, 0.5, false, 0.25},
+		{"dureza zero quebra na hora", dirt, true, 0, 0.01, true, 0},
+		{"dureza negativa quebra na hora", dirt, true, -1, 0, true, 0},
+		{"passo maior que a dureza quebra", stone, true, 0.5, 1, true, 0},
+	}
+
+	var mining Mining
+	for _, step := range steps {
+		broken := mining.Update(step.target, step.digging, step.hardness, step.deltaTime)
+		if broken != step.broken {
+			t.Errorf("%s: broken = %v, want %v", step.name, broken, step.broken)
+		}
+		if mining.Progress != step.progress {
+			t.Errorf("%s: progress = %v, want %v", step.name, mining.Progress, step.progress)
+		}
+		// depois de quebrar ou parar não há alvo
+		if active := step.progress > 0; mining.Active != active {
+			t.Errorf("%s: active = %v, want %v", step.name, mining.Active, active)
+		}
+		if mining.Active && mining.Target != step.target.Vec3() {
+			t.Errorf("%s: target = %v, want %v", step.name, mining.Target, step.target.Vec3())
+		}
+	}
+}
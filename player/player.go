@@ -3,17 +3,17 @@ package player
 import (
 	"math"
 
-	"github.com/go-gl/gl/v3.3-core/gl"
-	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/reonardoleis/fcg-glcraft/block"
 	"github.com/reonardoleis/fcg-glcraft/camera"
 	"github.com/reonardoleis/fcg-glcraft/collisions"
 	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
 	"github.com/reonardoleis/fcg-glcraft/geometry"
 	"github.com/reonardoleis/fcg-glcraft/world/chunk"
 
 	"github.com/reonardoleis/fcg-glcraft/engine/controls"
+	"github.com/reonardoleis/fcg-glcraft/engine/particles"
 	math2 "github.com/reonardoleis/fcg-glcraft/math"
 	"github.com/reonardoleis/fcg-glcraft/world"
 )
@@ -23,6 +23,7 @@ var (
 )
 
 type Player struct {
+	Name                      string // usado pelos comandos do console
 	Position                  mgl32.Vec4
 	Camera                    *camera.Camera
 	IsGrounded                bool
@@ -34,14 +35,10 @@ type Player struct {
 	defaultSpeed              float32
 	RunningMultiplier         float32
 	ControlHandler            controls.Controls
-	PlayerPhi                 float64
-	PlayerTheta               float64
 	MovementVector            mgl32.Vec4
 	Height                    float32
 	HitAt                     *mgl32.Vec4
 	ClosestEmptySpace         *mgl32.Vec4
-	_mouseLeftDownLastUpdate  bool
-	_mouseRightDownLastUpdate bool
 	Collider                  *collisions.CubeCollider
 	BoundingBox               *collisions.CubeBoundingBox
 	BoundingBox2              *collisions.CubeBoundingBox
@@ -54,6 +51,10 @@ type Player struct {
 	ArmAnimationDir           int
 	Body                      geometry.GeometryInformation
 	IsThirdPerson             bool
+	_wasInWater               bool
+	GameMode                  GameMode
+	Mining                    Mining
+	Health                    int
 }
 
 func NewPlayer(playerPosition mgl32.Vec4, controlHandler controls.Controls, walkingSpeed, runningMultiplier, jumpHeight, jumpSpeed, height float32) Player {
@@ -65,8 +66,6 @@ func NewPlayer(playerPosition mgl32.Vec4, controlHandler controls.Controls, walk
 		WalkingSpeed:              walkingSpeed,
 		RunningMultiplier:         runningMultiplier,
 		defaultSpeed:              walkingSpeed,
-		PlayerPhi:                 0.0,
-		PlayerTheta:               0.0,
 		MovementVector:            mgl32.Vec4{},
 		JumpHeight:                jumpHeight,
 		JumpSpeed:                 jumpSpeed,
@@ -75,20 +74,17 @@ func NewPlayer(playerPosition mgl32.Vec4, controlHandler controls.Controls, walk
 		Height:                    height,
 		HitAt:                     &mgl32.Vec4{},
 		ClosestEmptySpace:         &mgl32.Vec4{},
-		_mouseLeftDownLastUpdate:  true,
-		_mouseRightDownLastUpdate: true,
 		Collider:                  collisions.NewCubeCollider(),
 		BoundingBox:               collisions.NewCubeBoundingBox(playerPosition.Vec3(), configs.PlayerWidth*0.5, configs.PlayerHeight*0.5),
 		BoundingBox2:              collisions.NewCubeBoundingBox(playerPosition.Vec3(), configs.PlayerWidth, configs.PlayerHeight),
 		BoundingBoxFutureVertices: [8]mgl32.Vec3{},
 		SelectedBlock:             block.BlockDirt,
 		LastChunk:                 0,
-		Arm:                       geometry.BuildCube(0, 0, 0, 1, 0, 0, 0),
-		Body:                      geometry.BuildCube(0, 0, 0, 1, 0, 0, 0),
 		ArmAnimationOffset:        0,
 		ArmAnimationDir:           1,
 		IsAnimatingArm:            false,
 		IsThirdPerson:             false,
+		Health:                    configs.MaxHealth,
 	}
 }
 
@@ -105,19 +101,15 @@ func (p *Player) SetPosition(newPosition mgl32.Vec4) {
 	p.Position = newPosition
 }
 
+// Turns the camera with the mouse and walks towards where it looks, ignoring
+// the pitch. The yaw comes only from the camera.
 func (p *Player) HandleLookDirection() {
-	if p.ControlHandler.MousePositionChanged() {
-		dx, _ := p.ControlHandler.GetMouseDeltas()
-		p.PlayerTheta -= 0.01 * dx
-		p.PlayerTheta = p.PlayerTheta
-		// p.PlayerPhi += 0.01 * dy
-	}
+	p.Camera.Turn()
+	yaw := p.Camera.CameraTheta
 
-	vx := float32(math.Cos(float64(p.PlayerPhi)) * math.Sin(float64(p.PlayerTheta)))
-	vy := float32(math.Sin(float64(0)))
-	vz := float32(math.Cos(float64(p.PlayerPhi)) * math.Cos(float64(p.PlayerTheta)))
-	// // fmt.Println(vx, vy, vz)
-	p.MovementVector = mgl32.Vec4{vx, vy, vz, 0.0}
+	vx := float32(math.Sin(yaw))
+	vz := float32(math.Cos(yaw))
+	p.MovementVector = mgl32.Vec4{vx, 0.0, vz, 0.0}
 }
 
 func (p Player) GetFrontAndBackDirections() (behind mgl32.Vec3, front mgl32.Vec3) {
@@ -230,7 +222,7 @@ func (p *Player) Update(world *world.World, chunk *chunk.Chunk) {
 
 	}
 
-	if p.ControlHandler.IsDown(int(glfw.KeySpace)) && !p.IsJumping() && collidedBelow {
+	if p.ControlHandler.Held(controls.ActionJump) && !p.IsJumping() && collidedBelow {
 		p._originalY = newPosition.Y()
 		p._isJumping = true
 	}
@@ -263,7 +255,7 @@ func (p *Player) Update(world *world.World, chunk *chunk.Chunk) {
 	}
 
 	// w,a,s,d movement handler, checking the collision of the "future position"
-	if p.ControlHandler.IsDown(int(glfw.KeyW)) {
+	if p.ControlHandler.Held(controls.ActionMoveForward) {
 		if p.WalkingSpeed >= deltaTime {
 			for i := deltaTime; i < p.WalkingSpeed*deltaTime; i += deltaTime {
 				newPosition = newPosition.Add(w.Mul(-1).Mul(deltaTime))
@@ -312,7 +304,7 @@ func (p *Player) Update(world *world.World, chunk *chunk.Chunk) {
 			}
 		}
 	}
-	if p.ControlHandler.IsDown(int(glfw.KeyS)) {
+	if p.ControlHandler.Held(controls.ActionMoveBack) {
 		if p.WalkingSpeed >= deltaTime {
 			for i := deltaTime; i < p.WalkingSpeed*deltaTime; i += deltaTime {
 				newPosition = newPosition.Add(w.Mul(deltaTime))
@@ -362,7 +354,7 @@ func (p *Player) Update(world *world.World, chunk *chunk.Chunk) {
 		}
 
 	}
-	if p.ControlHandler.IsDown(int(glfw.KeyD)) {
+	if p.ControlHandler.Held(controls.ActionMoveRight) {
 		if p.WalkingSpeed >= deltaTime {
 			for i := deltaTime; i < p.WalkingSpeed*deltaTime; i += deltaTime {
 				newPosition = newPosition.Add(u.Mul(deltaTime))
@@ -411,7 +403,7 @@ func (p *Player) Update(world *world.World, chunk *chunk.Chunk) {
 			}
 		}
 	}
-	if p.ControlHandler.IsDown(int(glfw.KeyA)) {
+	if p.ControlHandler.Held(controls.ActionMoveLeft) {
 		if p.WalkingSpeed >= deltaTime {
 			for i := deltaTime; i < p.WalkingSpeed*deltaTime; i += deltaTime {
 				newPosition = newPosition.Add(u.Mul(-1).Mul(deltaTime))
@@ -461,7 +453,7 @@ func (p *Player) Update(world *world.World, chunk *chunk.Chunk) {
 		}
 	}
 
-	p.IsThirdPerson = p.ControlHandler.IsToggled(int(glfw.KeyQ))
+	p.IsThirdPerson = p.ControlHandler.Toggled(controls.ActionToggleView)
 	p.Camera.IsLookAt = p.IsThirdPerson
 
 	if collidedBelow {
@@ -470,63 +462,63 @@ func (p *Player) Update(world *world.World, chunk *chunk.Chunk) {
 
 	}
 
+	fallSpeed := float32(0)
+	if deltaTime > 0 {
+		fallSpeed = (p.Position.Y() - newPosition.Y()) / deltaTime
+	}
 	p.Position = newPosition
+	p.handleWaterEntry(world, fallSpeed)
+
+	if p.ControlHandler.Pressed(controls.ActionToggleGameMode) {
+		if p.GameMode == Creative {
+			p.GameMode = Survival
+		} else {
+			p.GameMode = Creative
+		}
+	}
 
 	// handles block breaking
-	if p.ControlHandler.IsDown(int(glfw.MouseButtonLeft)) && !p._mouseLeftDownLastUpdate && p.HitAt != nil {
-		p._mouseLeftDownLastUpdate = true
-		world.RemoveBlockFrom(p.HitAt)
-		p.HitAt = nil
+	if p.GameMode == Creative {
+		p.Mining.Reset()
+		if p.ControlHandler.Pressed(controls.ActionBreak) && p.HitAt != nil {
+			world.RemoveBlockFrom(p.HitAt)
+			p.HitAt = nil
+		}
+	} else {
+		hardness := float32(0)
+		if p.HitAt != nil {
+			if target := world.GetBlockAt(int(p.HitAt.X()), int(p.HitAt.Y()), int(p.HitAt.Z())); target != nil {
+				hardness = block.Hardness(target.BlockType)
+			}
+		}
+		if p.Mining.Update(p.HitAt, p.ControlHandler.Held(controls.ActionBreak), hardness, deltaTime) {
+			world.RemoveBlockFrom(p.HitAt)
+			p.HitAt = nil
+		}
 	}
-	if p.ControlHandler.IsDown(int(glfw.MouseButtonLeft)) {
+	if p.ControlHandler.Held(controls.ActionBreak) {
 		if !p.IsAnimatingArm {
 			p.IsAnimatingArm = true
 			p.ArmAnimationOffset = 0.001
 		}
 	}
-	if !p.ControlHandler.IsDown(int(glfw.MouseButtonLeft)) {
-		p._mouseLeftDownLastUpdate = false
-
-	}
 
 	// handles block placement
-	if p.ControlHandler.IsDown(int(glfw.MouseButtonRight)) && p.ClosestEmptySpace != nil && !p._mouseRightDownLastUpdate {
+	if p.ControlHandler.Pressed(controls.ActionPlace) && p.ClosestEmptySpace != nil {
 		world.AddBlockAt(p.ClosestEmptySpace.Vec3(), false, p.SelectedBlock)
 		p.ClosestEmptySpace = nil
-		p._mouseRightDownLastUpdate = true
-	}
-	if !p.ControlHandler.IsDown(int(glfw.MouseButtonRight)) {
-		p._mouseRightDownLastUpdate = false
 	}
 
 	// handles block selection
-	if p.ControlHandler.IsDown(int(glfw.Key1)) {
-		p.SelectedBlock = block.GetBlockTypes()[0]
-	}
-	if p.ControlHandler.IsDown(int(glfw.Key2)) {
-		p.SelectedBlock = block.GetBlockTypes()[1]
-	}
-	if p.ControlHandler.IsDown(int(glfw.Key3)) {
-		p.SelectedBlock = block.GetBlockTypes()[2]
-	}
-	if p.ControlHandler.IsDown(int(glfw.Key4)) {
-		p.SelectedBlock = block.GetBlockTypes()[3]
-	}
-	if p.ControlHandler.IsDown(int(glfw.Key5)) {
-		p.SelectedBlock = block.GetBlockTypes()[4]
-	}
-	if p.ControlHandler.IsDown(int(glfw.Key6)) {
-		p.SelectedBlock = block.GetBlockTypes()[5]
-	}
-	if p.ControlHandler.IsDown(int(glfw.Key7)) {
-		p.SelectedBlock = block.GetBlockTypes()[6]
-	}
-	if p.ControlHandler.IsDown(int(glfw.Key8)) {
-		p.SelectedBlock = block.GetBlockTypes()[7]
+	blockTypes := block.GetBlockTypes()
+	for slot, action := range controls.HotbarActions {
+		if slot < len(blockTypes) && p.ControlHandler.Pressed(action) {
+			p.SelectedBlock = blockTypes[slot]
+		}
 	}
 
 	// handles running
-	if p.ControlHandler.IsToggled(int(glfw.KeyLeftShift)) {
+	if p.ControlHandler.Held(controls.ActionSprint) {
 		p.WalkingSpeed = p.defaultSpeed * p.RunningMultiplier
 	} else {
 		p.WalkingSpeed = p.defaultSpeed
@@ -539,27 +531,6 @@ func (p *Player) Update(world *world.World, chunk *chunk.Chunk) {
 	p.Camera.Follow(p.Position.Add(mgl32.Vec4{0.0, float32(configs.PlayerHeight) / 2, 0.0, 0.0}))
 	p.Camera.Update()
 
-	_, u = p.Camera.GetWU()
-
-	// handle arm drawing
-	armPos := p.Position.Vec3().Add(p.Camera.ViewVector.Vec3().Mul(0.5 + p.ArmAnimationOffset)).Add(u.Vec3().Mul((1)))
-
-	armMatrix := math2.Matrix_Identity()
-
-	armMatrix = armMatrix.Mul4(math2.Matrix_Translate(armPos[0], armPos[1], armPos[2]))
-	armMatrix = armMatrix.Mul4(math2.Matrix_Rotate_Y(float32(p.Camera.CameraTheta))).Mul4(math2.Matrix_Rotate_X(float32(-p.Camera.CameraPhi))).Mul4(math2.Matrix_Scale(0.5, 0.5, 1.3))
-
-	if p.IsThirdPerson {
-		//bodyMatrix := math2.Matrix_Identity().Mul4(math2.Matrix_Translate(p.Position[0], p.Position[1], p.Position[2])).Mul4(math2.Matrix_Scale(1, 3, 1))
-		gl.BindVertexArray(1)
-		//p.Body.Draw(&bodyMatrix, 2)
-		gl.BindVertexArray(0)
-	}
-
-	gl.BindVertexArray(1)
-	p.Arm.Draw(&armMatrix, 2)
-	gl.BindVertexArray(0)
-
 	// handle arm animation
 	if p.IsAnimatingArm {
 
@@ -581,6 +552,31 @@ func (p *Player) Update(world *world.World, chunk *chunk.Chunk) {
 
 }
 
+// Draws the player arm in front of the camera. The meshes are created on the
+// first draw, so a player can be simulated without an OpenGL context.
+func (p *Player) Draw() {
+	if p.Arm.Mesh == nil {
+		p.Arm.Mesh = geometry.NewMesh(geometry.BuildCube(0, 0, 0, 1, 0, 0, 0), geometry.NewMaterial(shaders.Get("standard"), shaders.ObjectPlayerArm))
+		p.Body.Mesh = geometry.NewMesh(geometry.BuildCube(0, 0, 0, 1, 0, 0, 0), geometry.NewMaterial(shaders.Get("standard"), shaders.ObjectPlayerBody))
+	}
+
+	_, u := p.Camera.GetWU()
+
+	armPos := p.Position.Vec3().Add(p.Camera.ViewVector.Vec3().Mul(0.5 + p.ArmAnimationOffset)).Add(u.Vec3().Mul((1)))
+
+	armMatrix := math2.Matrix_Identity()
+
+	armMatrix = armMatrix.Mul4(math2.Matrix_Translate(armPos[0], armPos[1], armPos[2]))
+	armMatrix = armMatrix.Mul4(math2.Matrix_Rotate_Y(float32(p.Camera.CameraTheta))).Mul4(math2.Matrix_Rotate_X(float32(-p.Camera.CameraPhi))).Mul4(math2.Matrix_Scale(0.5, 0.5, 1.3))
+
+	if p.IsThirdPerson {
+		//bodyMatrix := math2.Matrix_Identity().Mul4(math2.Matrix_Translate(p.Position[0], p.Position[1], p.Position[2])).Mul4(math2.Matrix_Scale(1, 3, 1))
+		//p.Body.Draw(&bodyMatrix)
+	}
+
+	p.Arm.Draw(&armMatrix)
+}
+
 // Updates player bounding box
 func (p *Player) UpdateBoundingBox(newPosition mgl32.Vec4) collisions.CubeBoundingBox {
 	//r := 0.3
@@ -624,6 +620,10 @@ func (p *Player) HandleBlockInteractions(world *world.World, chunk *chunk.Chunk)
 	px, py, pz := p.GetRoundedPosition()
 	shouldBreak := false
 
+	// sem bloco na mira, nada fica selecionado
+	p.HitAt = nil
+	p.ClosestEmptySpace = nil
+
 	// bounding box
 	for s := 0.0; s < 10.0; s += 0.01 {
 		if shouldBreak {
@@ -693,6 +693,26 @@ func (p *Player) HandleWorldLimits(world *world.World) {
 	}
 }
 
+// Splashes when the player goes from the air into water
+func (p *Player) handleWaterEntry(world *world.World, fallSpeed float32) {
+	x, y, z := p.GetRoundedPosition()
+	water := world.GetBlockAt(x, y, z)
+	inWater := water != nil && water.BlockType == block.BlockWater
+
+	if inWater && !p._wasInWater {
+		// as gotas saem da superfície, que pode estar acima dos pés
+		surface := y
+		for above := world.GetBlockAt(x, surface+1, z); above != nil && above.BlockType == block.BlockWater; above = world.GetBlockAt(x, surface+1, z) {
+			surface++
+		}
+
+		splashAt := mgl32.Vec3{p.Position.X(), float32(surface) + float32(configs.BlockSize)/2, p.Position.Z()}
+		world.Particles.Emit(particles.WaterSplash(splashAt, fallSpeed, block.ParticleTexture(block.BlockWater)))
+	}
+
+	p._wasInWater = inWater
+}
+
 func (p Player) GetRoundedPosition() (int, int, int) {
 	roundedX := int(math.Round(float64(p.Position.X())))
 	roundedY := int(math.Round(float64(p.Position.Y())))
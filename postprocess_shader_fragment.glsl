@@ -0,0 +1,126 @@
+#version 330 core
+
+// Cada estágio é compilado como uma variante com STAGE_<NOME> definido
+in vec2 uv;
+
+uniform sampler2D color_texture; // saída do estágio anterior
+uniform sampler2D depth_texture; // profundidade da cena
+uniform vec2 texel_size;
+uniform mat4 inverse_projection;
+
+uniform int fog_mode;
+uniform vec3 fog_color;
+uniform float fog_start;
+uniform float fog_end;
+uniform float fog_density;
+uniform float fog_sky;
+
+uniform vec3 underwater_tint;
+uniform float exposure;
+uniform float gamma;
+
+out vec4 color;
+
+#ifdef STAGE_FOG
+// Distância do pixel até a câmera, desfazendo a projeção
+float view_distance(float depth)
+{
+    vec4 ndc = vec4(uv * 2.0 - 1.0, depth * 2.0 - 1.0, 1.0);
+    vec4 view_position = inverse_projection * ndc;
+    return length(view_position.xyz / view_position.w);
+}
+
+float fog_factor(float dist)
+{
+    if (fog_mode == FOG_LINEAR) {
+        return clamp((dist - fog_start) / max(fog_end - fog_start, 0.0001), 0.0, 1.0);
+    }
+    if (fog_mode == FOG_EXPONENTIAL) {
+        float d = dist * fog_density;
+        return 1.0 - exp(-d * d);
+    }
+    return 0.0;
+}
+#endif
+
+#ifdef STAGE_TONEMAP
+// Aproximação da curva ACES (Narkowicz)
+vec3 aces(vec3 x)
+{
+    return clamp((x * (2.51 * x + 0.03)) / (x * (2.43 * x + 0.59) + 0.14), 0.0, 1.0);
+}
+#endif
+
+#ifdef STAGE_FXAA
+float luma(vec3 c)
+{
+    return dot(c, vec3(0.299, 0.587, 0.114));
+}
+
+// FXAA simplificado: borra ao longo da borda quando o contraste local é alto
+vec3 fxaa()
+{
+    vec3 rgb_nw = texture(color_texture, uv + vec2(-1.0, -1.0) * texel_size).rgb;
+    vec3 rgb_ne = texture(color_texture, uv + vec2(1.0, -1.0) * texel_size).rgb;
+    vec3 rgb_sw = texture(color_texture, uv + vec2(-1.0, 1.0) * texel_size).rgb;
+    vec3 rgb_se = texture(color_texture, uv + vec2(1.0, 1.0) * texel_size).rgb;
+    vec3 rgb_m = texture(color_texture, uv).rgb;
+
+    float luma_nw = luma(rgb_nw);
+    float luma_ne = luma(rgb_ne);
+    float luma_sw = luma(rgb_sw);
+    float luma_se = luma(rgb_se);
+    float luma_m = luma(rgb_m);
+    float luma_min = min(luma_m, min(min(luma_nw, luma_ne), min(luma_sw, luma_se)));
+    float luma_max = max(luma_m, max(max(luma_nw, luma_ne), max(luma_sw, luma_se)));
+
+    vec2 dir = vec2(-((luma_nw + luma_ne) - (luma_sw + luma_se)),
+                    ((luma_nw + luma_sw) - (luma_ne + luma_se)));
+    float reduce = max((luma_nw + luma_ne + luma_sw + luma_se) * 0.25 * (1.0 / 8.0), 1.0 / 128.0);
+    float scale = 1.0 / (min(abs(dir.x), abs(dir.y)) + reduce);
+    dir = clamp(dir * scale, vec2(-8.0), vec2(8.0)) * texel_size;
+
+    vec3 rgb_a = 0.5 * (texture(color_texture, uv + dir * (1.0 / 3.0 - 0.5)).rgb +
+                        texture(color_texture, uv + dir * (2.0 / 3.0 - 0.5)).rgb);
+    vec3 rgb_b = rgb_a * 0.5 + 0.25 * (texture(color_texture, uv - dir * 0.5).rgb +
+                                       texture(color_texture, uv + dir * 0.5).rgb);
+
+    float luma_b = luma(rgb_b);
+    if (luma_b < luma_min || luma_b > luma_max) {
+        return rgb_a;
+    }
+    return rgb_b;
+}
+#endif
+
+void main()
+{
+    color = texture(color_texture, uv);
+
+#ifdef STAGE_FOG
+    float depth = texture(depth_texture, uv).r;
+    // profundidade 1 é o céu, que só recebe névoa se pedido
+    if (depth < 1.0 || fog_sky > 0.5) {
+        float dist = depth < 1.0 ? view_distance(depth) : 1.0e6;
+        color.rgb = mix(color.rgb, fog_color, fog_factor(dist));
+    }
+#endif
+
+#ifdef STAGE_UNDERWATER
+    color.rgb *= underwater_tint;
+#endif
+
+#ifdef STAGE_TONEMAP
+    color.rgb = aces(color.rgb * exposure);
+#endif
+
+#ifdef STAGE_GAMMA
+    color.rgb = pow(max(color.rgb, vec3(0.0)), vec3(1.0 / gamma));
+#endif
+
+#ifdef STAGE_FXAA
+    color.rgb = fxaa();
+#endif
+
+    color.a = 1.0;
+}
@@ -0,0 +1,15 @@
+#version 330 core
+
+// Passes de tela cheia do pós-processamento (engine/postprocess). O quadrado
+// das faces dos blocos é escalado para cobrir toda a tela.
+layout (location = 0) in vec4 model_coefficients;
+
+uniform mat4 model;
+
+out vec2 uv;
+
+void main()
+{
+    gl_Position = model * model_coefficients;
+    uv = model_coefficients.xy + 0.5;
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"os"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/world"
+	"github.com/reonardoleis/fcg-glcraft/world/chunk"
+	"github.com/reonardoleis/fcg-glcraft/world/saves"
+)
+
+// renderMap writes a PNG of a saved world seen from above, one pixel per
+// block, centered on the origin
+func renderMap(args []string) int {
+	flags := newFlagSet("render-map", "NAME")
+	savesDir := flags.String("dir", saves.DefaultDir, "pasta dos mundos salvos")
+	radius := flags.Int("radius", 8, "chunks desenhados a partir da origem em cada direção")
+	output := flags.String("o", "", "arquivo PNG de saída (padrão NAME.png)")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	r := *radius
+
+	if flags.NArg() != 1 {
+		return usageError(flags, "expected one world name, got %d arguments", flags.NArg())
+	}
+	if r < 0 {
+		return usageError(flags, "-radius can't be negative, got %d", *radius)
+	}
+
+	info, err := saves.Load(*savesDir, flags.Arg(0))
+	if err != nil {
+		log.Println(err)
+		return exitError
+	}
+	if *output == "" {
+		*output = info.Name + ".png"
+	}
+
+	w := headlessWorld(info)
+	side := (2*r + 1) * configs.ChunkSize
+	img := image.NewRGBA(image.Rect(0, 0, side, side))
+	for i := -r; i <= r; i++ {
+		for j := -r; j <= r; j++ {
+			c := w.NewGeneratedChunk(i, j)
+			for x := 0; x < configs.ChunkSize; x++ {
+				for z := 0; z < configs.ChunkSize; z++ {
+					px := (i+r)*configs.ChunkSize + x
+					py := (j+r)*configs.ChunkSize + z
+					img.SetRGBA(px, py, columnColor(c, x, z))
+				}
+			}
+		}
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		log.Println(err)
+		return exitError
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		log.Println(err)
+		return exitError
+	}
+	if err := file.Close(); err != nil {
+		log.Println(err)
+		return exitError
+	}
+
+	fmt.Printf("wrote %s (%dx%d blocks)\n", *output, side, side)
+	return exitOK
+}
+
+// Cria o mundo de info sem janela, só para gerar chunks
+func headlessWorld(info saves.WorldInfo) *world.World {
+	configs.World = info.Config
+	w := world.NewWorld(info.Name, mgl32.Vec3(info.Config.Size), info.Seed, info.Generator)
+	// o terreno distante só serve para desenhar na janela
+	w.LOD.Destroy()
+	w.LOD = nil
+
+	return w
+}
+
+// Cor do bloco mais alto da coluna (x, z) do chunk, mais escura quanto mais
+// baixo ele está
+func columnColor(c *chunk.Chunk, x, z int) color.RGBA {
+	for y := configs.WorldHeight - 1; y >= 0; y-- {
+		b := c.GetBlockAtNotOffsetted(x, y, z)
+		if b == nil {
+			continue
+		}
+
+		base := block.MapColor(b.BlockType)
+		if base.A == 0 {
+			continue
+		}
+
+		shade := 0.5 + 0.5*float32(y)/float32(configs.WorldHeight)
+		return color.RGBA{
+			R: uint8(float32(base.R) * shade),
+			G: uint8(float32(base.G) * shade),
+			B: uint8(float32(base.B) * shade),
+			A: 255,
+		}
+	}
+
+	return color.RGBA{0, 0, 0, 255}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"log"
+
+	"github.com/reonardoleis/fcg-glcraft/engine/replay"
+)
+
+// verifyReplay replays a recording made with play -record without a window and
+// checks that it ends in the recorded state, for regression tests
+func verifyReplay(args []string) int {
+	flags := newFlagSet("replay", "FILE")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if flags.NArg() != 1 {
+		return usageError(flags, "expected one recording, got %d arguments", flags.NArg())
+	}
+
+	recording, err := replay.Load(flags.Arg(0))
+	if err != nil {
+		log.Println(err)
+		return exitError
+	}
+
+	return report(recording, replay.RunHeadless(recording))
+}
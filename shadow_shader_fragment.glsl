@@ -0,0 +1,6 @@
+#version 330 core
+
+// Só a profundidade é escrita, nenhuma cor
+void main()
+{
+}
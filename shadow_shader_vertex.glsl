@@ -0,0 +1,13 @@
+#version 330 core
+
+// Passe de profundidade dos mapas de sombra (engine/shadows). Cada cascata
+// desenha a cena do ponto de vista do sol.
+layout (location = 0) in vec4 model_coefficients;
+
+uniform mat4 model;
+uniform mat4 light_space;
+
+void main()
+{
+    gl_Position = light_space * model * model_coefficients;
+}
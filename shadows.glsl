@@ -0,0 +1,56 @@
+// Mapas de sombra em cascata do sol, veja engine/shadows.
+// Incluído com: #include "shadows.glsl"
+// MAX_SHADOW_CASCADES é definido pelo Go (shadows.Defines).
+
+uniform sampler2DArray shadow_map;
+uniform bool shadows_enabled;
+uniform int shadow_cascades;
+uniform float shadow_splits[MAX_SHADOW_CASCADES]; // distância da câmera onde cada cascata termina
+uniform mat4 light_space[MAX_SHADOW_CASCADES];
+uniform float shadow_bias;
+
+// Sentido do ponto para o sol, em coordenadas globais
+uniform vec4 sun_direction;
+
+// Fração da luz do sol que chega ao ponto p (1 iluminado, 0 na sombra).
+// depth é a distância de p até a câmera, usada para escolher a cascata, e n
+// a normal da superfície, usada para aumentar o bias em superfícies inclinadas.
+float sun_visibility(vec4 p, float depth, vec4 n)
+{
+    if (!shadows_enabled) {
+        return 1.0;
+    }
+
+    int cascade = -1;
+    for (int i = 0; i < MAX_SHADOW_CASCADES; i++) {
+        if (i < shadow_cascades && depth <= shadow_splits[i]) {
+            cascade = i;
+            break;
+        }
+    }
+    if (cascade < 0) {
+        return 1.0;
+    }
+
+    vec4 light_position = light_space[cascade] * p;
+    vec3 coords = light_position.xyz / light_position.w * 0.5 + 0.5;
+    if (coords.z > 1.0) {
+        return 1.0;
+    }
+
+    // cascatas mais distantes têm texels maiores e precisam de mais bias
+    float slope = 1.0 - max(dot(n, sun_direction), 0.0);
+    float bias = shadow_bias * (1.0 + 2.0*slope) * float(cascade + 1);
+
+    // PCF: média de 3x3 comparações ao redor do ponto
+    vec2 texel = 1.0 / vec2(textureSize(shadow_map, 0).xy);
+    float lit = 0.0;
+    for (int x = -1; x <= 1; x++) {
+        for (int y = -1; y <= 1; y++) {
+            float closest = texture(shadow_map, vec3(coords.xy + vec2(x, y)*texel, float(cascade))).r;
+            lit += coords.z - bias > closest ? 0.0 : 1.0;
+        }
+    }
+
+    return lit / 9.0;
+}
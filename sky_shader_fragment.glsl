@@ -0,0 +1,82 @@
+#version 330 core
+
+in vec4 position_world;
+in vec4 position_model;
+in vec3 sky_ray;
+
+uniform int object_id;
+
+// Cores do céu (sky.Palette)
+uniform vec3 sky_zenith;
+uniform vec3 sky_horizon;
+
+// Sentido da câmera para o sol e quanto já é noite (0 dia, 1 noite)
+uniform vec4 sun_direction;
+uniform float night_factor;
+
+// Camada de nuvens
+uniform sampler2D cloud_map;
+uniform vec2 cloud_offset;
+uniform float cloud_scale;
+uniform float cloud_radius;
+uniform vec4 camera_position;
+
+out vec4 color;
+
+float hash(vec3 p)
+{
+    return fract(sin(dot(p, vec3(12.9898, 78.233, 37.719))) * 43758.5453);
+}
+
+void main()
+{
+    // Distância ao centro do quadrado, 1 na borda
+    float r = length(position_model.xy) * 2.0;
+
+    if (object_id == SKY_DOME) {
+        vec3 direction = normalize(sky_ray);
+
+        // Gradiente do horizonte ao topo; abaixo do horizonte fica a cor do horizonte
+        float height = clamp(direction.y, 0.0, 1.0);
+        color = vec4(mix(sky_horizon, sky_zenith, sqrt(height)), 1.0);
+
+        // Brilho ao redor do sol
+        float glow = pow(max(dot(direction, sun_direction.xyz), 0.0), 32.0) * (1.0 - night_factor);
+        color.rgb += vec3(1.0, 0.8, 0.5) * glow * 0.5;
+
+        // Estrelas: algumas células da esfera de direções acendem à noite
+        vec3 cell = floor(direction * 300.0);
+        if (direction.y > 0.0 && hash(cell) > 0.9985) {
+            color.rgb += vec3(0.6 + 0.4*hash(cell + 1.0)) * night_factor;
+        }
+    }
+
+    if (object_id == SUN) {
+        float disk = 1.0 - smoothstep(0.45, 0.5, r);
+        float glow = pow(max(1.0 - r, 0.0), 3.0) * 0.6;
+        color = vec4(1.0, 0.95, 0.8, clamp(disk + glow, 0.0, 1.0));
+    }
+
+    if (object_id == MOON) {
+        float disk = 1.0 - smoothstep(0.45, 0.5, r);
+        // crateras em "pixels" grandes, no estilo dos blocos
+        float craters = 0.85 + 0.15 * hash(floor(vec3(position_model.xy * 12.0, 1.0)));
+        color = vec4(vec3(0.9, 0.92, 1.0) * craters, disk);
+    }
+
+    if (object_id == CLOUDS) {
+        vec2 uv = (position_world.xz + cloud_offset) / cloud_scale;
+        float density = texture(cloud_map, uv).r;
+
+        // some perto da borda da camada para não aparecer o recorte
+        float distance_to_camera = length(position_world.xz - camera_position.xz);
+        float fade = 1.0 - smoothstep(cloud_radius * 0.5, cloud_radius, distance_to_camera);
+
+        vec3 tint = mix(vec3(1.0), sky_horizon, 0.3) * mix(1.0, 0.2, night_factor);
+        color = vec4(tint, density * fade * 0.85);
+    }
+
+    // As cores acima são sRGB; a cena é composta em espaço linear e a
+    // correção gamma é feita no pós-processamento
+    color.rgb = pow(color.rgb, vec3(2.2));
+}
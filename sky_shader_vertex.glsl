@@ -0,0 +1,34 @@
+#version 330 core
+
+// Céu, sol, lua e nuvens (engine/sky). Todos usam o quadrado das faces dos blocos.
+layout (location = 0) in vec4 model_coefficients;
+
+uniform mat4 model;
+uniform mat4 view;
+uniform mat4 projection;
+uniform int object_id;
+
+out vec4 position_world;
+out vec4 position_model;
+out vec3 sky_ray; // direção do céu vista pelo pixel, só para SKY_DOME
+
+void main()
+{
+    position_model = model_coefficients;
+
+    if (object_id == SKY_DOME) {
+        // O quadrado cobre a tela toda; a direção de cada pixel é obtida
+        // desfazendo a projeção e a rotação da câmera
+        vec4 screen = model * model_coefficients;
+        gl_Position = vec4(screen.xy, 0.0, 1.0);
+
+        vec4 ray = inverse(projection * mat4(mat3(view))) * vec4(screen.xy, 1.0, 1.0);
+        sky_ray = ray.xyz / ray.w;
+        position_world = vec4(0.0, 0.0, 0.0, 1.0);
+        return;
+    }
+
+    position_world = model * model_coefficients;
+    gl_Position = projection * view * position_world;
+    sky_ray = vec3(0.0);
+}
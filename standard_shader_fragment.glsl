@@ -10,6 +10,10 @@ in vec4 normal;
 // Posição do vértice atual no sistema de coordenadas local do modelo.
 in vec4 position_model;
 
+// Oclusão ambiente e luz interpoladas entre os cantos da face
+in float ambient_occlusion;
+in float smooth_light;
+
 
 // Matrizes computadas no código C++ e enviadas para a GPU
 uniform mat4 model;
@@ -17,6 +21,7 @@ uniform mat4 view;
 uniform mat4 projection;
 uniform bool black;
 uniform int object_id;
+uniform float alpha_cutoff; // maior que zero no passe cutout, veja engine/render
 
 
 // Variáveis para acesso das imagens de textura
@@ -28,9 +33,12 @@ out vec4 color;
 // Constantes
 #define M_PI   3.14159265358979323846
 #define M_PI_2 1.57079632679489661923
-#define OBJ 1
-#define PLAYER_ARM 2
-#define PLAYER_BODY 3
+
+// Os IDs dos objetos (BLOCK, OBJ, PLAYER_ARM, PLAYER_BODY) são definidos pelo
+// Go, veja engine/shaders/objects.go
+
+#include "lighting.glsl"
+#include "shadows.glsl"
 
 void main()
 {
@@ -68,18 +76,22 @@ void main()
     
     // Obtemos a refletância difusa a partir da leitura da imagem TextureImage0
     vec4 Kd0 = texture(TextureImage0, vec2(U,V)).rgba;
-    vec4 Kd1 = vec4(0.47, 0.65, 1.0, 1.0);
 
-    float dist = length(position_relative_to_cam.xyz);
-    float density = 0.070;
-    float gradient = 1.0;
-    float visibility = exp(-pow((dist*density), gradient));
-    visibility = visibility;
-    
-    color.rgb = mix(Kd1, Kd0, visibility).rgb;
+    color.rgb = Kd0.rgb;
+
+    if (object_id == BLOCK) {
+        // As faces não têm normais: usamos a normal do plano do triângulo,
+        // virada para a câmera
+        vec4 face_normal = vec4(normalize(cross(dFdx(p.xyz), dFdy(p.xyz))), 0.0);
+        if (dot(face_normal, v) < 0.0) {
+            face_normal = -face_normal;
+        }
 
-    if (dist <= 90000) {
-        color.rgb = Kd0.rgb;
+        // Luz do sol, que some à noite
+        float sun_height = clamp(sun_direction.y * 4.0, 0.0, 1.0);
+        float sun = max(dot(face_normal, sun_direction), 0.0) * sun_height * sun_visibility(p, -position_relative_to_cam.z, face_normal);
+
+        color.rgb *= (0.4 + 0.6*ambient_occlusion) * smooth_light * (0.55 + 0.45*sun);
     }
     
     if(black){
@@ -103,71 +115,24 @@ void main()
         color.a = 1.0;
     }
 
-    if(object_id == OBJ) {
-        p = position_world;
-
-    // Normal do fragmento atual, interpolada pelo rasterizador a partir das
-    // normais de cada vértice.
-    n = normalize(normal);
-
-
-
-    // Vetor que define o sentido da câmera em relação ao ponto atual.
-    v = normalize(camera_position - p);
+    if (alpha_cutoff > 0.0 && color.a < alpha_cutoff) {
+        discard;
+    }
 
+    if(object_id == OBJ) {
         // Vetor que define o sentido da fonte de luz em relação ao ponto atual.
-    // Vetor que define o sentido da fonte de luz em relação ao ponto atual.
-    vec4 l = v;
-
-    // Vetor que define o sentido da reflexão especular ideal.
-    vec4 r = -l + (2.0*n) * (dot(n, l)); // 
-
-       // Parâmetros que definem as propriedades espectrais da superfície
-    vec3 Kd; // Refletância difusa
-    vec3 Ks; // Refletância especular
-    vec3 Ka; // Refletância ambiente
-    float q; // Expoente especular para o modelo de iluminação de Phong
-
-        // Propriedades espectrais do coelho
-        Kd = vec3(0.08,0.4,0.8);
-        Ks = vec3(0.8,0.8,0.8);
-        Ka = Kd/2;
-        q = 32.0;
-  // Espectro da fonte de iluminação
-    // Espectro da fonte de iluminação
-    vec3 I = vec3(1.0,1.0,1.0); // PREENCH AQUI o espectro da fonte de luz
-
-    // Espectro da luz ambiente
-    vec3 Ia = vec3(0.2,0.2,0.2); // PREENCHA AQUI o espectro da luz ambiente
-
-    // Termo difuso utilizando a lei dos cossenos de Lambert
-    vec3 lambert_diffuse_term = vec3(0.0,0.0,0.0); // PREENCHA AQUI o termo difuso de Lambert
-    lambert_diffuse_term = Kd * I * max(0, dot(n, l));
-    // Termo ambiente
-    vec3 ambient_term = vec3(0.0,0.0,0.0); // PREENCHA AQUI o termo ambiente
-    ambient_term = Ka * Ia;
-
-    // Termo especular utilizando o modelo de iluminação de Phong
-    vec3 phong_specular_term  = vec3(0.0,0.0,0.0); // PREENCH AQUI o termo especular de Phong
-    phong_specular_term = Ks * I * pow(max(0, dot(r, v)), q);
-
-    // NOTE: Se você quiser fazer o rendering de objetos transparentes, é
-    // necessário:
-    // 1) Habilitar a operação de "blending" de OpenGL logo antes de realizar o
-    //    desenho dos objetos transparentes, com os comandos abaixo no código C++:
-    //      glEnable(GL_BLEND);
-    //      glBlendFunc(GL_SRC_ALPHA, GL_ONE_MINUS_SRC_ALPHA);
-    // 2) Realizar o desenho de todos objetos transparentes *após* ter desenhado
-    //    todos os objetos opacos; e
-    // 3) Realizar o desenho de objetos transparentes ordenados de acordo com
-    //    suas distâncias para a câmera (desenhando primeiro objetos
-    //    transparentes que estão mais longe da câmera).
-    // Alpha default = 1 = 100% opaco = 0% transparente
-    color.a = 1;
-
-    // Cor final do fragmento calculada com uma combinação dos termos difuso,
-    // especular, e ambiente. Veja slide 129 do documento Aula_17_e_18_Modelos_de_Iluminacao.pdf.
-    color.rgb = lambert_diffuse_term + ambient_term + phong_specular_term;
+        // A luz sai da câmera.
+        l = v;
+
+        // Propriedades espectrais da vaca
+        Surface s;
+        s.Kd = vec3(0.08,0.4,0.8);
+        s.Ks = vec3(0.8,0.8,0.8);
+        s.Ka = s.Kd/2;
+        s.q = 32.0;
+
+        color.rgb = phong(s, n, l, v);
+        color.a = 1;
     }
 
     if (object_id == PLAYER_ARM) {
@@ -178,8 +143,29 @@ void main()
         color.rgba = vec4(0.95, 0.89, 0.56, 1.0);
     }
 
-    // Cor final com correção gamma, considerando monitor sRGB.
-    // Veja https://en.wikipedia.org/w/index.php?title=Gamma_correction&oldid=751281772#Windows.2C_Mac.2C_sRGB_and_TV.2Fvideo_standard_gammas
-    //color.rgb = pow(color.rgb, vec3(1.0,1.0,1.0)/2.2);
+    if (object_id == TERRAIN_LOD) {
+        // Terreno distante (world/lod): água na superfície do mar, grama acima.
+        // SEA_SURFACE_HEIGHT é definido pelo Go (world.Defines).
+        vec3 albedo = position_world.y < SEA_SURFACE_HEIGHT + 0.1 ? vec3(0.16,0.32,0.75) : vec3(0.35,0.6,0.25);
+        albedo = pow(albedo, vec3(2.2)); // as cores acima são sRGB
+
+        // Mesma luz do sol dos blocos próximos, para as duas distâncias combinarem
+        float sun_height = clamp(sun_direction.y * 4.0, 0.0, 1.0);
+        float sun = max(dot(n, sun_direction), 0.0) * sun_height * sun_visibility(p, -position_relative_to_cam.z, n);
+
+        color.rgb = albedo * (0.55 + 0.45*sun);
+        color.a = 1;
+    }
+
+    // Contorno do bloco mirado e rachaduras da mineração (engine/selection)
+    if (object_id == SELECTION) {
+        color = vec4(0.0, 0.0, 0.0, 0.7);
+    }
+    if (object_id == CRACKS) {
+        color = vec4(0.05, 0.05, 0.05, Kd0.r * 0.8);
+    }
+
+    // A cor sai em espaço linear: névoa, tone mapping e correção gamma são
+    // feitos depois, no pós-processamento (engine/postprocess)
 } 
 
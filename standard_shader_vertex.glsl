@@ -6,6 +6,13 @@ layout (location = 0) in vec4 model_coefficients;
 layout (location = 1) in vec4 normal_coefficients;
 layout (location = 2) in vec2 texture_coefficients;
 
+// Oclusao ambiente e luz suave das faces dos blocos (veja block/lighting.go).
+// face_ao e face_light sao constantes por face, com um valor por canto, e
+// corner_index escolhe o valor do vertice atual.
+layout (location = 3) in float corner_index;
+layout (location = 4) in vec4 face_ao;
+layout (location = 5) in vec4 face_light;
+
 // Matrizes computadas no c�digo C++ e enviadas para a GPU
 uniform mat4 model;
 uniform mat4 view;
@@ -20,6 +27,8 @@ out vec4 position_world;
 out vec4 position_model;
 out vec4 normal;
 out vec2 texcoords;
+out float ambient_occlusion;
+out float smooth_light;
 
 void main()
 {
@@ -64,5 +73,9 @@ void main()
 
     // Coordenadas de textura obtidas do arquivo OBJ (se existirem!)
     texcoords = texture_coefficients;
+
+    int corner = int(corner_index + 0.5);
+    ambient_occlusion = face_ao[corner];
+    smooth_light = face_light[corner];
 }
 
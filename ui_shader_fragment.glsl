@@ -0,0 +1,24 @@
+#version 330 core
+
+in vec2 uv;
+in vec4 tint;
+
+uniform sampler2D ui_texture;
+uniform int texture_kind; // ui.TextureKind
+
+out vec4 color;
+
+void main()
+{
+    vec4 texel = texture(ui_texture, uv);
+
+    if (texture_kind == 2) {
+        // máscara de fonte: só o canal vermelho, usado como alfa
+        texel = vec4(1.0, 1.0, 1.0, texel.r);
+    } else if (texture_kind == 1) {
+        // texturas sRGB são lidas em espaço linear, mas a interface vai direto para a tela
+        texel.rgb = pow(texel.rgb, vec3(1.0 / 2.2));
+    }
+
+    color = texel * tint;
+}
@@ -0,0 +1,18 @@
+#version 330 core
+
+// Interface 2D (engine/ui), em pixels da tela
+layout (location = 0) in vec2 position;
+layout (location = 1) in vec2 texture_coordinates;
+layout (location = 2) in vec4 vertex_color;
+
+uniform mat4 projection;
+
+out vec2 uv;
+out vec4 tint;
+
+void main()
+{
+    gl_Position = projection * vec4(position, 0.0, 1.0);
+    uv = texture_coordinates;
+    tint = vertex_color;
+}
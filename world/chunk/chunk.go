@@ -6,8 +6,6 @@ import (
 
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/reonardoleis/fcg-glcraft/block"
-	"github.com/reonardoleis/fcg-glcraft/camera"
-	"github.com/reonardoleis/fcg-glcraft/collisions"
 	"github.com/reonardoleis/fcg-glcraft/configs"
 	math2 "github.com/reonardoleis/fcg-glcraft/math"
 
@@ -18,6 +16,10 @@ type BiomeType byte
 
 var (
 	SerialChunkID uint64 = 0
+
+	// BlockLanded is called when a falling block lands, with its world position.
+	// Chunks are updated in goroutines, so it must be safe to call concurrently.
+	BlockLanded func(position mgl32.Vec3, blockType block.BlockType)
 )
 
 type BlockInformation = byte
@@ -33,6 +35,13 @@ type Chunk struct {
 	BiomeType         BiomeType
 	Blocks            [][][]*block.Block
 	BlocksInformation [][][]BlockInformation
+	sections          *sectionsVisibility
+	tops              *columnTops
+
+	// Adjacent returns the loaded chunk dx, dz chunks away, or nil. The world
+	// sets it once the chunk is loaded; before that, the blocks across the
+	// borders are considered empty.
+	Adjacent func(dx, dz int) *Chunk
 }
 
 func NewChunk(offset mgl32.Vec2, biomeType BiomeType) *Chunk {
@@ -43,6 +52,8 @@ func NewChunk(offset mgl32.Vec2, biomeType BiomeType) *Chunk {
 		ID:        SerialChunkID,
 		Offset:    offset,
 		BiomeType: biomeType,
+		sections:  newSectionsVisibility(),
+		tops:      &columnTops{},
 	}
 }
 
@@ -61,6 +72,51 @@ func (c *Chunk) allocateBlockSlice() {
 	}
 }
 
+// blockAround returns the block at a position relative to the chunk, which
+// may be in an adjacent chunk
+func (c *Chunk) blockAround(x, y, z int) *block.Block {
+	if y < 0 || y >= configs.WorldHeight {
+		return nil
+	}
+
+	dx, dz := floorDiv(x, configs.ChunkSize), floorDiv(z, configs.ChunkSize)
+	if dx == 0 && dz == 0 {
+		return c.Blocks[x][y][z]
+	}
+	adjacent := c.adjacentChunk(dx, dz)
+	if adjacent == nil {
+		return nil
+	}
+
+	return adjacent.Blocks[x-dx*configs.ChunkSize][y][z-dz*configs.ChunkSize]
+}
+
+// adjacentChunk returns the chunk dx, dz chunks away if it is loaded and
+// generated, the chunk itself for 0, 0
+func (c *Chunk) adjacentChunk(dx, dz int) *Chunk {
+	if dx == 0 && dz == 0 {
+		return c
+	}
+	if c.Adjacent == nil {
+		return nil
+	}
+
+	adjacent := c.Adjacent(dx, dz)
+	if adjacent == nil || adjacent.Blocks == nil {
+		return nil
+	}
+
+	return adjacent
+}
+
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return (a+1)/b - 1
+	}
+
+	return a / b
+}
+
 // Compute neighbors of each chunk block
 func (c *Chunk) SetNeighbors() {
 	for x := 0; x < configs.ChunkSize; x++ {
@@ -188,15 +244,27 @@ func (c *Chunk) SetNeighbors() {
 					}
 
 				}
+
+				// faces entre blocos translúcidos iguais (água com água, vidro com vidro) não são
+				// desenhadas, nem nas bordas do chunk
+				currentBlock := c.Blocks[blockPositionX][blockPositionY][blockPositionZ]
+				for face, offset := range block.FaceNormals {
+					neighbor := c.blockAround(blockPositionX+offset[0], blockPositionY+offset[1], blockPositionZ+offset[2])
+					if neighbor != nil && block.HidesInternalFace(currentBlock, neighbor, face) {
+						currentBlock.Neighbors[face] = 1
+					}
+				}
 			}
 		}
 	}
+
+	c.SetLighting()
 }
 
 // Places a tree at given position within the chunk
-func (c *Chunk) PlaceTree(x, y, z int) {
+func (c *Chunk) PlaceTree(random *rand.Rand, x, y, z int) {
 	position := mgl32.Vec3{float32(x), float32(y), float32(z)}
-	treeHeight := math2.RandInt(2, 4)
+	treeHeight := math2.RandIntFrom(random, 2, 4)
 
 	for i := 0; i <= treeHeight; i++ {
 		blockPosition := position.Add(mgl32.Vec3{0.0, float32(i), 0.0})
@@ -224,30 +292,33 @@ func (c *Chunk) PlaceTree(x, y, z int) {
 	c.AddBlockAtNotOffsetted(int(blockPosition.X()), int(blockPosition.Y()), int(blockPosition.Z()), &treeBlock)
 }
 
-// Generates a chunk
-func (c *Chunk) GenerateChunk(noiseSource *noisey.OpenSimplexGenerator) {
-	c.allocateBlockSlice()
-
-	// Height map with base stone world generation
-	for x := 0; x < int(configs.ChunkSize); x++ {
-		for z := 0; z < int(configs.ChunkSize); z++ {
-			/*blockHeight := float64(configs.WorldHeight/2) + math.Round(float64(configs.WorldHeight)/2)*
-			noiseSource.Get2D((float64(x)+float64((float32(configs.ChunkSize)*c.Offset[0])))/float64((c.Offset[0]+1)*float32(configs.ChunkSize)),
-				(float64(z)+float64(float32(configs.ChunkSize)*c.Offset[1])/float64((c.Offset[1]+1)*float32(configs.ChunkSize))))*/
+// Blocks below this height are filled with water when generating the world
+const SeaLevel = 32
 
-			blockWithOffsetX := float64((x + (configs.ChunkSize * int(c.Offset[0]))))
-			blockWithOffsetZ := float64((z + (configs.ChunkSize * int(c.Offset[1]))))
+// TerrainHeight returns the height of the highest stone block of the world
+// column (x, z) before caves, water and trees are generated. It only samples
+// the noise, so it can be used without generating the chunk.
+func TerrainHeight(noiseSource *noisey.OpenSimplexGenerator, x, z int) int {
+	normalizingQuotientX := float64(configs.World.ChunkSmoothness * configs.ChunkSize)
+	normalizingQuotientZ := float64(configs.World.ChunkSmoothness * configs.ChunkSize)
 
-			normalizingQuotientX := float64(configs.ChunkSmoothness * configs.ChunkSize)
-			normalizingQuotientZ := float64(configs.ChunkSmoothness * configs.ChunkSize)
+	noiseParamX := float64(x) / normalizingQuotientX
+	noiseParamZ := float64(z) / normalizingQuotientZ
 
-			noiseParamX := blockWithOffsetX / normalizingQuotientX
-			noiseParamZ := blockWithOffsetZ / normalizingQuotientZ
+	blockHeight := noiseSource.Get2D(noiseParamX, noiseParamZ)
+	return int(math.Round(float64(configs.WorldHeight/2) + (math.Round(float64(configs.WorldHeight)/2) * blockHeight)))
+}
 
-			// fmt.Println("Gerando bloco", blockWithOffsetX, blockWithOffsetZ, normalizingQuotientX, normalizingQuotientZ)
+// Generates a chunk. The trees and ores come from seed, so the same seed
+// always generates the same chunk, whatever order the chunks are generated in.
+func (c *Chunk) GenerateChunk(noiseSource *noisey.OpenSimplexGenerator, seed int64) {
+	c.allocateBlockSlice()
+	random := rand.New(rand.NewSource(seed))
 
-			blockHeight := noiseSource.Get2D(float64(noiseParamX), float64(noiseParamZ))
-			blockHeight = math.Round(float64(configs.WorldHeight/2) + (math.Round(float64(configs.WorldHeight)/2) * blockHeight))
+	// Height map with base stone world generation
+	for x := 0; x < int(configs.ChunkSize); x++ {
+		for z := 0; z < int(configs.ChunkSize); z++ {
+			blockHeight := float64(TerrainHeight(noiseSource, x+(configs.ChunkSize*int(c.Offset[0])), z+(configs.ChunkSize*int(c.Offset[1]))))
 			for y := blockHeight; y >= 0; y-- {
 				newBlock := block.NewBlock(float32(x)+(float32(configs.ChunkSize)*c.Offset[0]), float32(int(y)), float32(z)+(float32(configs.ChunkSize)*c.Offset[1]), float32(configs.BlockSize), false, false, block.BlockStone)
 				c.Blocks[x][int(y)][z] = &newBlock
@@ -262,16 +333,16 @@ func (c *Chunk) GenerateChunk(noiseSource *noisey.OpenSimplexGenerator) {
 				blockWithOffsetX := float64((x + (configs.ChunkSize * int(c.Offset[0]))))
 				blockWithOffsetZ := float64((z + (configs.ChunkSize * int(c.Offset[1]))))
 
-				normalizingQuotientX := float64(configs.ChunkSmoothness)
-				normalizingQuotientY := float64(configs.ChunkSmoothness)
-				normalizingQuotientZ := float64(configs.ChunkSmoothness)
+				normalizingQuotientX := float64(configs.World.ChunkSmoothness)
+				normalizingQuotientY := float64(configs.World.ChunkSmoothness)
+				normalizingQuotientZ := float64(configs.World.ChunkSmoothness)
 
 				noiseParamX := blockWithOffsetX / normalizingQuotientX
 				noiseParamY := float64(y) / normalizingQuotientY
 				noiseParamZ := blockWithOffsetZ / normalizingQuotientZ
 
 				noise := noiseSource.Get3D(noiseParamX, noiseParamY, noiseParamZ)
-				if noise >= float64(configs.CaveThreshold) && y < configs.CaveMinHeight && y != 0 && c.Blocks[x][y][z] != nil && c.Blocks[x][y][z].BlockType != block.BlockWater {
+				if noise >= float64(configs.World.CaveThreshold) && y < configs.World.CaveMinHeight && y != 0 && c.Blocks[x][y][z] != nil && c.Blocks[x][y][z].BlockType != block.BlockWater {
 					c.Blocks[x][y][z] = nil
 					c.BlocksInformation[x][y][z] = BlockInformationCave
 				}
@@ -284,13 +355,13 @@ func (c *Chunk) GenerateChunk(noiseSource *noisey.OpenSimplexGenerator) {
 		for y := 0; y < int(configs.WorldHeight); y++ {
 			for z := 0; z < int(configs.ChunkSize); z++ {
 
-				shouldPlaceTree := math2.RandInt(0, 100) >= 95
+				shouldPlaceTree := math2.RandIntFrom(random, 0, 100) >= 95
 				blockBelow := c.GetBlockAtNotOffsetted(x, y-1, z)
 				if blockBelow != nil && blockBelow.BlockType == block.BlockGrass && shouldPlaceTree && x > 0 && x < configs.ChunkSize-1 && z > 0 && z < configs.ChunkSize-1 {
-					c.PlaceTree(x, y, z)
+					c.PlaceTree(random, x, y, z)
 				}
 
-				if y < 32 && c.Blocks[x][y][z] == nil {
+				if y < SeaLevel && c.Blocks[x][y][z] == nil {
 
 					for index := y; index > 1 && c.BlocksInformation[x][y][z] != BlockInformationCave; index-- {
 
@@ -310,8 +381,8 @@ func (c *Chunk) GenerateChunk(noiseSource *noisey.OpenSimplexGenerator) {
 	}
 
 	// Grass, coal and iron generation
-	r1 := rand.New(rand.NewSource(int64(math2.RandInt(0, 1000000000000000))))
-	r2 := rand.New(rand.NewSource(int64(math2.RandInt(0, 1000000000000000))))
+	r1 := rand.New(rand.NewSource(random.Int63()))
+	r2 := rand.New(rand.NewSource(random.Int63()))
 	coalNoiser := noisey.NewOpenSimplexGenerator(r1)
 	ironNoiser := noisey.NewOpenSimplexGenerator(r2)
 	for x := 0; x < configs.ChunkSize; x++ {
@@ -328,18 +399,18 @@ func (c *Chunk) GenerateChunk(noiseSource *noisey.OpenSimplexGenerator) {
 							if c.Blocks[x][height][z] != nil || c.BlocksInformation[x][height][z] == BlockInformationCave {
 								shouldPlaceGrass = false
 								if c.BlocksInformation[x][height][z] == BlockInformationCave {
-									if noiseSource.Get3D(float64(x), float64(y), float64(z)) >= float64(configs.CaveDirtThreshold) {
+									if noiseSource.Get3D(float64(x), float64(y), float64(z)) >= float64(configs.World.CaveDirtThreshold) {
 										currentBlock.BlockType = block.BlockDirt
 									}
 
 								}
 
-								coalNoise := coalNoiser.Get3D(float64(x)/float64(configs.CaveContentSmoothness), float64(y), float64(z)/float64(configs.CaveContentSmoothness))
-								ironNoise := ironNoiser.Get3D(float64(x)/float64(configs.CaveContentSmoothness), float64(y), float64(z)/float64(configs.CaveContentSmoothness))
-								if y < 50 && coalNoise >= configs.CaveCoalThreshold[0] && coalNoise <= configs.CaveCoalThreshold[1] {
+								coalNoise := coalNoiser.Get3D(float64(x)/float64(configs.World.CaveContentSmoothness), float64(y), float64(z)/float64(configs.World.CaveContentSmoothness))
+								ironNoise := ironNoiser.Get3D(float64(x)/float64(configs.World.CaveContentSmoothness), float64(y), float64(z)/float64(configs.World.CaveContentSmoothness))
+								if y < 50 && coalNoise >= configs.World.CaveCoalThreshold[0] && coalNoise <= configs.World.CaveCoalThreshold[1] {
 									currentBlock.BlockType = block.BlockCoal
 								}
-								if y < 40 && ironNoise >= configs.CaveIronThreshold[0] && ironNoise <= configs.CaveIronThreshold[1] {
+								if y < 40 && ironNoise >= configs.World.CaveIronThreshold[0] && ironNoise <= configs.World.CaveIronThreshold[1] {
 									currentBlock.BlockType = block.BlockIron
 								}
 								break
@@ -361,10 +432,10 @@ func (c *Chunk) GenerateChunk(noiseSource *noisey.OpenSimplexGenerator) {
 		for y := 0; y < int(configs.WorldHeight); y++ {
 			for z := 0; z < int(configs.ChunkSize); z++ {
 
-				shouldPlaceTree := math2.RandInt(0, 100) >= 99
+				shouldPlaceTree := math2.RandIntFrom(random, 0, 100) >= 99
 				blockBelow := c.GetBlockAtNotOffsetted(x, y-1, z)
 				if blockBelow != nil && blockBelow.BlockType == block.BlockGrass && shouldPlaceTree && x > 0 && x < configs.ChunkSize-1 && z > 0 && z < configs.ChunkSize-1 {
-					c.PlaceTree(x, y, z)
+					c.PlaceTree(random, x, y, z)
 				}
 			}
 		}
@@ -373,6 +444,40 @@ func (c *Chunk) GenerateChunk(noiseSource *noisey.OpenSimplexGenerator) {
 	c.SetNeighbors()
 }
 
+// Terrain generators a world can be created with
+const (
+	GeneratorDefault = "default"
+	GeneratorFlat    = "flat"
+)
+
+var Generators = []string{GeneratorDefault, GeneratorFlat}
+
+// Altura da grama no gerador plano
+const FlatHeight = SeaLevel
+
+// Generates a flat chunk: stone, then three layers of dirt under the grass
+func (c *Chunk) GenerateFlatChunk() {
+	c.allocateBlockSlice()
+
+	for x := 0; x < configs.ChunkSize; x++ {
+		for z := 0; z < configs.ChunkSize; z++ {
+			for y := 0; y <= FlatHeight; y++ {
+				var blockType block.BlockType = block.BlockStone
+				if y == FlatHeight {
+					blockType = block.BlockGrass
+				} else if y >= FlatHeight-3 {
+					blockType = block.BlockDirt
+				}
+
+				newBlock := block.NewBlock(float32(x)+(float32(configs.ChunkSize)*c.Offset[0]), float32(y), float32(z)+(float32(configs.ChunkSize)*c.Offset[1]), float32(configs.BlockSize), false, false, blockType)
+				c.Blocks[x][y][z] = &newBlock
+			}
+		}
+	}
+
+	c.SetNeighbors()
+}
+
 // Get block at given position without offsetting chunk positions
 func (c *Chunk) GetBlockAtNotOffsetted(x, y, z int) *block.Block {
 	_x := x
@@ -499,10 +604,16 @@ func (c *Chunk) RemoveBlockFrom(position mgl32.Vec4) {
 
 	if c.Blocks[int(offsettedX)][int(position.Y())][int(offsettedZ)].IsBreakable {
 		c.Blocks[int(offsettedX)][int(position.Y())][int(offsettedZ)] = nil
+		c.MarkSectionDirty(int(position.Y()))
 		c.SetNeighbors()
 	}
 }
 
+// Converts chunk positions to world positions
+func (c Chunk) GetWorldPositions(x, y, z float32) (float32, float32, float32) {
+	return x + (c.Offset[0] * float32(configs.ChunkSize)), y, z + (c.Offset[1] * float32(configs.ChunkSize))
+}
+
 // Converts world positions to chunk positions
 func (c Chunk) GetOffsettedPositions(x, y, z float32) (float32, float32, float32) {
 	return x - (c.Offset[0] * float32(configs.ChunkSize)), y, z - (c.Offset[1] * float32(configs.ChunkSize))
@@ -517,6 +628,7 @@ func (c *Chunk) AddBlockAt(position mgl32.Vec3, ephemeral bool, blockType block.
 	newBlock := block.NewBlock(x, float32(y), z, 1, true, ephemeral, blockType)
 	newBlock.WithEdges = false
 	c.Blocks[int(offsettedX)][int(y)][int(offsettedZ)] = &newBlock
+	c.MarkSectionDirty(int(y))
 
 	c.SetNeighbors()
 }
@@ -530,44 +642,43 @@ func (c *Chunk) AddBlockAtNotOffsetted(x, y, z int, block *block.Block) {
 	}
 
 	c.Blocks[_x][y][_z] = block
+	c.MarkSectionDirty(y)
 
 	c.SetNeighbors()
 }
 
-func (c *Chunk) GetBlocksToRender() []*block.Block {
-	blocksToRender := make([]*block.Block, 0)
-	for _, x := range c.Blocks {
-		for _, y := range x {
-			for _, currentBlock := range y {
-
-				if currentBlock != nil && currentBlock.CountNeighbors() != 6 && currentBlock.BlockType != block.BlockAir && currentBlock.Position.Y() >= camera.ActiveCamera.Position.Y()-configs.HeightViewDistance && currentBlock.Position.Y() <= camera.ActiveCamera.Position.Y()+configs.HeightViewDistance {
-					//
-
-					frustum := camera.ActiveCamera.GetFrustum()
-
-					frustumCollider := collisions.NewFrustumCollider(frustum)
-
-					shouldAdd := false
-					vertices := currentBlock.GetFutureVertices()
-					for _, vertex := range vertices {
-						if frustumCollider.CollidesWithBlock(vertex) {
-							shouldAdd = true
-							break
-						}
-					}
+// SetBlockType replaces the block at a position within the chunk, removing it
+// for air. The faces are only updated by RefreshNeighbors, so that many
+// blocks can change at once.
+func (c *Chunk) SetBlockType(x, y, z int, blockType block.BlockType) {
+	if x < 0 || x >= configs.ChunkSize || y < 0 || y >= configs.WorldHeight || z < 0 || z >= configs.ChunkSize {
+		return
+	}
 
-					if shouldAdd {
-						blocksToRender = append(blocksToRender, currentBlock)
-					}
+	c.Blocks[x][y][z] = nil
+	if blockType != block.BlockAir {
+		worldX, _, worldZ := c.GetWorldPositions(float32(x), 0, float32(z))
+		newBlock := block.NewBlock(worldX, float32(y), worldZ, float32(configs.BlockSize), false, false, blockType)
+		c.Blocks[x][y][z] = &newBlock
+	}
+	c.MarkSectionDirty(y)
+}
 
-					continue
+// RefreshNeighbors computes the faces of every block again. SetNeighbors only
+// hides faces, so removed blocks would leave holes without it.
+func (c *Chunk) RefreshNeighbors() {
+	for x := range c.Blocks {
+		for y := range c.Blocks[x] {
+			for _, b := range c.Blocks[x][y] {
+				if b != nil {
+					b.Neighbors = [6]byte{}
+					b.HasWaterAbove = false
 				}
-
 			}
 		}
 	}
 
-	return blocksToRender
+	c.SetNeighbors()
 }
 
 // Updates the chunk
@@ -588,13 +699,14 @@ func (c *Chunk) Update() {
 					if blockBelow == nil {
 						currentBlock.IsFalling = true
 						currentBlock.IsBreakable = false
+						c.MarkSectionDirty(y)
 					}
 				}
 
 				if currentBlock.BlockType == block.BlockSand && currentBlock.IsFalling {
 					blockBelow := c.GetBlockAtNotOffsetted(x, y-1, z)
 					if blockBelow == nil {
-						currentBlock.Position = currentBlock.Position.Sub(mgl32.Vec4{0.0, configs.BlockFallingSpeed * float32(math2.DeltaTime), 0.0, 1.0})
+						currentBlock.Position = currentBlock.Position.Sub(mgl32.Vec4{0.0, configs.World.BlockFallingSpeed * float32(math2.DeltaTime), 0.0, 1.0})
 					}
 
 					currentWorldPositionY := math.Ceil(float64(currentBlock.Position.Y()))
@@ -606,7 +718,14 @@ func (c *Chunk) Update() {
 						copy.Position = mgl32.Vec4{float32(x), blockBelow2.Position.Y() + 1, float32(z), 1.0}
 						c.Blocks[x][y][z] = nil
 						c.Blocks[x][int(blockBelow2.Position.Y()+1)][z] = &copy
+						c.MarkSectionDirty(y)
+						c.MarkSectionDirty(int(blockBelow2.Position.Y() + 1))
 						c.SetNeighbors()
+
+						if BlockLanded != nil {
+							worldX, _, worldZ := c.GetWorldPositions(float32(x), 0, float32(z))
+							BlockLanded(mgl32.Vec3{worldX, blockBelow2.Position.Y() + 1, worldZ}, copy.BlockType)
+						}
 					}
 				}
 
@@ -0,0 +1,90 @@
+package chunk
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/collisions"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+)
+
+// Number of [SectionSize]³ sections stacked in a chunk
+const NumSections = (configs.WorldHeight + configs.SectionSize - 1) / configs.SectionSize
+
+// Bounds returns the world space AABB of the whole chunk. Blocks are centered
+// at integer positions, so the box extends half a block past them.
+func (c *Chunk) Bounds() (mins, maxes mgl32.Vec3) {
+	return c.blockRangeBounds(0, configs.WorldHeight-1)
+}
+
+// SectionBounds returns the world space AABB of one section of the chunk
+func (c *Chunk) SectionBounds(section int) (mins, maxes mgl32.Vec3) {
+	fromY := section * configs.SectionSize
+	toY := fromY + configs.SectionSize - 1
+	if toY >= configs.WorldHeight {
+		toY = configs.WorldHeight - 1
+	}
+
+	return c.blockRangeBounds(fromY, toY)
+}
+
+func (c *Chunk) blockRangeBounds(fromY, toY int) (mins, maxes mgl32.Vec3) {
+	half := float32(configs.BlockSize) / 2
+	originX := c.Offset[0] * float32(configs.ChunkSize)
+	originZ := c.Offset[1] * float32(configs.ChunkSize)
+
+	mins = mgl32.Vec3{originX - half, float32(fromY) - half, originZ - half}
+	maxes = mgl32.Vec3{originX + float32(configs.ChunkSize-1) + half, float32(toY) + half, originZ + float32(configs.ChunkSize-1) + half}
+
+	return mins, maxes
+}
+
+// GetBlocksToRender returns the visible blocks of the chunk between the
+// heights minY and maxY which are not fully surrounded. The frustum is tested
+// against the chunk, then each section, then each block, so hidden chunks
+// cost a single test. Sections for which sectionVisible returns false (e.g.
+// closed caves) are skipped; a nil sectionVisible keeps every section.
+func (c *Chunk) GetBlocksToRender(frustum collisions.FrustumCollider, minY, maxY float32, sectionVisible func(section int) bool) []*block.Block {
+	blocksToRender := make([]*block.Block, 0)
+
+	if !frustum.CollidesWithAABB(c.Bounds()) {
+		return blocksToRender
+	}
+
+	for section := 0; section < NumSections; section++ {
+		if sectionVisible != nil && !sectionVisible(section) {
+			continue
+		}
+
+		sectionMins, sectionMaxes := c.SectionBounds(section)
+		if sectionMaxes.Y() < minY || sectionMins.Y() > maxY || !frustum.CollidesWithAABB(sectionMins, sectionMaxes) {
+			continue
+		}
+
+		fromY := section * configs.SectionSize
+		toY := fromY + configs.SectionSize
+		if toY > configs.WorldHeight {
+			toY = configs.WorldHeight
+		}
+
+		for x := 0; x < configs.ChunkSize; x++ {
+			for y := fromY; y < toY; y++ {
+				for z := 0; z < configs.ChunkSize; z++ {
+					currentBlock := c.Blocks[x][y][z]
+					if currentBlock == nil || currentBlock.CountNeighbors() == 6 || currentBlock.BlockType == block.BlockAir {
+						continue
+					}
+
+					if currentBlock.Position.Y() < minY || currentBlock.Position.Y() > maxY {
+						continue
+					}
+
+					if frustum.CollidesWithBlock(currentBlock.Position.Vec3()) {
+						blocksToRender = append(blocksToRender, currentBlock)
+					}
+				}
+			}
+		}
+	}
+
+	return blocksToRender
+}
@@ -0,0 +1,128 @@
+package chunk
+
+import (
+	"sync"
+
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+)
+
+// Luz mínima dentro de cavernas e quanto a luz do céu cai por bloco de profundidade
+const (
+	MinSkyLight     float32 = 0.25
+	SkyLightFalloff float32 = 0.15
+)
+
+// Altura do bloco opaco mais alto de cada coluna. Só é calculada de novo
+// depois que algum bloco do chunk muda, já que SetLighting roda a cada tick.
+type columnTops struct {
+	mutex   sync.Mutex
+	heights [][]int
+	dirty   bool
+}
+
+func (t *columnTops) invalidate() {
+	t.mutex.Lock()
+	t.dirty = true
+	t.mutex.Unlock()
+}
+
+// columnTops returns the height of the highest opaque block of each column,
+// -1 for empty columns
+func (c *Chunk) columnTops() [][]int {
+	c.tops.mutex.Lock()
+	defer c.tops.mutex.Unlock()
+
+	if c.tops.heights != nil && !c.tops.dirty {
+		return c.tops.heights
+	}
+
+	if c.tops.heights == nil {
+		c.tops.heights = make([][]int, configs.ChunkSize)
+		for x := range c.tops.heights {
+			c.tops.heights[x] = make([]int, configs.ChunkSize)
+		}
+	}
+	for x := range c.tops.heights {
+		for z := range c.tops.heights[x] {
+			c.tops.heights[x][z] = -1
+			for y := configs.WorldHeight - 1; y >= 0; y-- {
+				if isOpaque(c.Blocks[x][y][z]) {
+					c.tops.heights[x][z] = y
+					break
+				}
+			}
+		}
+	}
+	c.tops.dirty = false
+
+	return c.tops.heights
+}
+
+// skyLight is 1 above the highest opaque block of each column and fades
+// below it, so caves and overhangs get darker the deeper they are. tops holds
+// the columns of the chunk and of the eight around it, indexed by [dx+1][dz+1];
+// columns of chunks that aren't loaded are open to the sky.
+func skyLight(x, y, z int, tops *[3][3][][]int) float32 {
+	dx, dz := floorDiv(x, configs.ChunkSize), floorDiv(z, configs.ChunkSize)
+	columns := tops[dx+1][dz+1]
+	if columns == nil {
+		return 1
+	}
+
+	top := columns[x-dx*configs.ChunkSize][z-dz*configs.ChunkSize]
+	if y > top {
+		return 1
+	}
+
+	light := 1 - SkyLightFalloff*float32(top-y+1)
+	if light < MinSkyLight {
+		return MinSkyLight
+	}
+
+	return light
+}
+
+// SetLighting computes the ambient occlusion and smooth light of every
+// visible face of the chunk. Blocks of adjacent chunks that aren't loaded are
+// considered empty.
+func (c *Chunk) SetLighting() {
+	if c.Blocks == nil {
+		return
+	}
+
+	var tops [3][3][][]int
+	for dx := -1; dx <= 1; dx++ {
+		for dz := -1; dz <= 1; dz++ {
+			if adjacent := c.adjacentChunk(dx, dz); adjacent != nil {
+				tops[dx+1][dz+1] = adjacent.columnTops()
+			}
+		}
+	}
+
+	for x := 0; x < configs.ChunkSize; x++ {
+		for y := 0; y < configs.WorldHeight; y++ {
+			for z := 0; z < configs.ChunkSize; z++ {
+				currentBlock := c.Blocks[x][y][z]
+				if currentBlock == nil || currentBlock.BlockType == block.BlockAir {
+					continue
+				}
+
+				solid := func(dx, dy, dz int) bool {
+					return isOpaque(c.blockAround(x+dx, y+dy, z+dz))
+				}
+				light := func(dx, dy, dz int) float32 {
+					return skyLight(x+dx, y+dy, z+dz, &tops)
+				}
+
+				for face := range currentBlock.Neighbors {
+					if currentBlock.Neighbors[face] == 1 {
+						continue
+					}
+					currentBlock.Lighting[face] = block.ComputeFaceLighting(face, solid, light)
+				}
+				currentBlock.HasLighting = true
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package chunk
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+)
+
+// Índices de Neighbors e Lighting
+const (
+	faceNorth = 0 // +x
+	faceEast  = 2 // +z
+)
+
+// Quatro chunks planos, de (0, 0) a (1, 1), ligados entre si se linked
+func flatChunks(linked bool) map[[2]int]*Chunk {
+	chunks := map[[2]int]*Chunk{}
+	for x := 0; x <= 1; x++ {
+		for z := 0; z <= 1; z++ {
+			c := NewChunk(mgl32.Vec2{float32(x), float32(z)}, 0)
+			c.GenerateFlatChunk()
+			chunks[[2]int{x, z}] = c
+		}
+	}
+
+	if linked {
+		for key, c := range chunks {
+			key := key
+			c.Adjacent = func(dx, dz int) *Chunk {
+				return chunks[[2]int{key[0] + dx, key[1] + dz}]
+			}
+		}
+	}
+
+	return chunks
+}
+
+// Põe os blocos nas posições do mundo e calcula a luz de todos os chunks
+func placeBlocks(chunks map[[2]int]*Chunk, positions [][3]int) {
+	for _, p := range positions {
+		c := chunks[[2]int{p[0] / configs.ChunkSize, p[2] / configs.ChunkSize}]
+		c.SetBlockType(p[0]%configs.ChunkSize, p[1], p[2]%configs.ChunkSize, block.BlockStone)
+	}
+	for _, c := range chunks {
+		c.RefreshNeighbors()
+	}
+}
+
+// Luz de uma face do bloco em base com os outros blocos em volta, em
+// posições relativas a ele
+func faceLightingAt(linked bool, base [3]int, around [][3]int, face int) block.FaceLighting {
+	chunks := flatChunks(linked)
+	positions := [][3]int{base}
+	for _, offset := range around {
+		positions = append(positions, [3]int{base[0] + offset[0], base[1] + offset[1], base[2] + offset[2]})
+	}
+	placeBlocks(chunks, positions)
+
+	c := chunks[[2]int{base[0] / configs.ChunkSize, base[2] / configs.ChunkSize}]
+	return c.Blocks[base[0]%configs.ChunkSize][base[1]][base[2]%configs.ChunkSize].Lighting[face]
+}
+
+func TestLightingAcrossChunks(t *testing.T) {
+	const y = FlatHeight + 1
+	interior := [3]int{5, y, 5}
+	border := [3]int{configs.ChunkSize - 1, y, configs.ChunkSize - 1}
+
+	tests := []struct {
+		name   string
+		around [][3]int
+		face   int
+	}{
+		{"chão do chunk vizinho em x", nil, faceNorth},
+		{"chão do chunk vizinho em z", nil, faceEast},
+		{"bloco no chunk vizinho", [][3]int{{1, 1, 0}}, faceNorth},
+		{"bloco no chunk da diagonal", [][3]int{{1, 1, 1}}, faceNorth},
+		{"teto sobre o chunk vizinho", [][3]int{{1, 4, 0}, {1, 4, 1}, {1, 4, -1}}, faceNorth},
+	}
+
+	for _, test := range tests {
+		want := faceLightingAt(true, interior, test.around, test.face)
+		if got := faceLightingAt(true, border, test.around, test.face); got != want {
+			t.Errorf("%s: lighting at the border = %+v, want %+v as inside the chunk", test.name, got, want)
+		}
+		// sem os vizinhos carregados o outro lado fica vazio
+		if got := faceLightingAt(false, border, test.around, test.face); got == want {
+			t.Errorf("%s: lighting without the adjacent chunks = %+v, want it to differ", test.name, got)
+		}
+	}
+}
+
+func TestColumnTops(t *testing.T) {
+	c := NewChunk(mgl32.Vec2{}, 0)
+	c.GenerateFlatChunk()
+
+	tests := []struct {
+		name      string
+		y         int
+		blockType block.BlockType
+		want      int
+	}{
+		{"terreno plano", -1, block.BlockAir, FlatHeight},
+		{"bloco acima do chão", FlatHeight + 6, block.BlockStone, FlatHeight + 6},
+		{"bloco transparente não conta", FlatHeight + 9, block.BlockGlass, FlatHeight + 6},
+		{"bloco removido", FlatHeight + 6, block.BlockAir, FlatHeight},
+		{"coluna cavada", FlatHeight, block.BlockAir, FlatHeight - 1},
+	}
+
+	for _, test := range tests {
+		if test.y >= 0 {
+			c.SetBlockType(3, test.y, 4, test.blockType)
+		}
+		if got := c.columnTops()[3][4]; got != test.want {
+			t.Errorf("%s: top = %d, want %d", test.name, got, test.want)
+		}
+	}
+
+	// sem mudanças a altura não é calculada de novo
+	tops := c.columnTops()
+	tops[3][4] = -5
+	if got := c.columnTops()[3][4]; got != -5 {
+		t.Errorf("top was recomputed without any block changing: %d", got)
+	}
+}
@@ -0,0 +1,186 @@
+package chunk
+
+// Grafo de visibilidade das seções (cave culling). Para cada seção [16x16x16]
+// guardamos quais pares de faces da seção se "enxergam" através de blocos não
+// opacos. A busca em largura em world.VisibleSections usa esse grafo para
+// descartar cavernas que não podem ser vistas da posição da câmera.
+
+import (
+	"sync"
+
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+)
+
+// SectionFace is a face of a section, in the same order as block.Block.Neighbors
+type SectionFace int
+
+const (
+	FaceNorth SectionFace = iota // +x
+	FaceSouth                    // -x
+	FaceEast                     // +z
+	FaceWest                     // -z
+	FaceUpper                    // +y
+	FaceLower                    // -y
+	NumFaces
+)
+
+// Offsets (x, y, z) of the neighbor section across each face
+var FaceDirections = [NumFaces][3]int{
+	FaceNorth: {1, 0, 0},
+	FaceSouth: {-1, 0, 0},
+	FaceEast:  {0, 0, 1},
+	FaceWest:  {0, 0, -1},
+	FaceUpper: {0, 1, 0},
+	FaceLower: {0, -1, 0},
+}
+
+func (f SectionFace) Opposite() SectionFace {
+	return f ^ 1
+}
+
+// SectionVisibility is a symmetric 6x6 relation: bit from*6+to is set when a
+// path of non-opaque blocks connects the two faces
+type SectionVisibility uint64
+
+// AllVisible is the visibility of an empty section
+const AllVisible SectionVisibility = 1<<(NumFaces*NumFaces) - 1
+
+func (v SectionVisibility) CanSee(from, to SectionFace) bool {
+	return v&(1<<(uint(from)*uint(NumFaces)+uint(to))) != 0
+}
+
+func (v *SectionVisibility) connect(from, to SectionFace) {
+	*v |= 1 << (uint(from)*uint(NumFaces) + uint(to))
+	*v |= 1 << (uint(to)*uint(NumFaces) + uint(from))
+}
+
+// ComputeSectionVisibility flood fills the non-opaque cells of a section of
+// size³ cells. Every region connects all the faces it touches. opaque is
+// called with cell coordinates in [0, size).
+func ComputeSectionVisibility(size int, opaque func(x, y, z int) bool) SectionVisibility {
+	var visibility SectionVisibility
+	visited := make([]bool, size*size*size)
+	index := func(x, y, z int) int {
+		return (x*size+y)*size + z
+	}
+
+	queue := make([][3]int, 0, size*size)
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			for z := 0; z < size; z++ {
+				// só regiões que tocam a borda interessam
+				if x != 0 && x != size-1 && y != 0 && y != size-1 && z != 0 && z != size-1 {
+					continue
+				}
+				if visited[index(x, y, z)] || opaque(x, y, z) {
+					continue
+				}
+
+				touched := [NumFaces]bool{}
+				visited[index(x, y, z)] = true
+				queue = append(queue[:0], [3]int{x, y, z})
+
+				for len(queue) > 0 {
+					cell := queue[0]
+					queue = queue[1:]
+
+					for face, direction := range FaceDirections {
+						nx, ny, nz := cell[0]+direction[0], cell[1]+direction[1], cell[2]+direction[2]
+						if nx < 0 || nx >= size || ny < 0 || ny >= size || nz < 0 || nz >= size {
+							touched[face] = true
+							continue
+						}
+						if visited[index(nx, ny, nz)] || opaque(nx, ny, nz) {
+							continue
+						}
+						visited[index(nx, ny, nz)] = true
+						queue = append(queue, [3]int{nx, ny, nz})
+					}
+				}
+
+				for from := SectionFace(0); from < NumFaces; from++ {
+					for to := from; to < NumFaces; to++ {
+						if touched[from] && touched[to] {
+							visibility.connect(from, to)
+						}
+					}
+				}
+
+				if visibility == AllVisible {
+					return visibility
+				}
+			}
+		}
+	}
+
+	return visibility
+}
+
+// Blocos que bloqueiam a visão
+func isOpaque(b *block.Block) bool {
+	return b != nil && !b.Transparent && b.BlockType != block.BlockAir && !b.IsFalling
+}
+
+// Cache do grafo de visibilidade de um chunk, recalculado sob demanda
+type sectionsVisibility struct {
+	visibility [NumSections]SectionVisibility
+	dirty      [NumSections]bool
+	mutex      sync.Mutex
+}
+
+func newSectionsVisibility() *sectionsVisibility {
+	sv := &sectionsVisibility{}
+	for section := range sv.dirty {
+		sv.dirty[section] = true
+	}
+
+	return sv
+}
+
+// MarkSectionDirty schedules the visibility of the section containing height
+// y, and the column heights used by the lighting, to be recomputed
+func (c *Chunk) MarkSectionDirty(y int) {
+	if y < 0 || y >= configs.WorldHeight {
+		return
+	}
+
+	c.tops.invalidate()
+	c.sections.mutex.Lock()
+	c.sections.dirty[y/configs.SectionSize] = true
+	c.sections.mutex.Unlock()
+}
+
+// MarkVisibilityDirty schedules every section of the chunk to be recomputed
+func (c *Chunk) MarkVisibilityDirty() {
+	c.tops.invalidate()
+	c.sections.mutex.Lock()
+	for section := range c.sections.dirty {
+		c.sections.dirty[section] = true
+	}
+	c.sections.mutex.Unlock()
+}
+
+// SectionVisibility returns the visibility graph of a section, recomputing it
+// if any block of the section changed
+func (c *Chunk) SectionVisibility(section int) SectionVisibility {
+	if section < 0 || section >= NumSections || c.Blocks == nil {
+		return AllVisible
+	}
+
+	c.sections.mutex.Lock()
+	defer c.sections.mutex.Unlock()
+
+	if c.sections.dirty[section] {
+		fromY := section * configs.SectionSize
+		c.sections.visibility[section] = ComputeSectionVisibility(configs.SectionSize, func(x, y, z int) bool {
+			if x >= configs.ChunkSize || z >= configs.ChunkSize || fromY+y >= configs.WorldHeight {
+				return false
+			}
+			return isOpaque(c.Blocks[x][fromY+y][z])
+		})
+		c.sections.dirty[section] = false
+	}
+
+	return c.sections.visibility[section]
+}
@@ -0,0 +1,109 @@
+package chunk
+
+import "testing"
+
+const testSectionSize = 8
+
+// Pares de faces que se enxergam, cada par nas duas ordens
+func connectedPairs(v SectionVisibility) map[[2]SectionFace]bool {
+	pairs := map[[2]SectionFace]bool{}
+	for from := SectionFace(0); from < NumFaces; from++ {
+		for to := SectionFace(0); to < NumFaces; to++ {
+			if v.CanSee(from, to) {
+				pairs[[2]SectionFace{from, to}] = true
+			}
+		}
+	}
+
+	return pairs
+}
+
+func TestSectionVisibility(t *testing.T) {
+	middle := testSectionSize / 2
+
+	tests := []struct {
+		name   string
+		opaque func(x, y, z int) bool
+		faces  []SectionFace // faces ligadas entre si; as outras não veem nada
+	}{
+		{
+			name:   "sealed",
+			opaque: func(x, y, z int) bool { return true },
+		},
+		{
+			name:   "open",
+			opaque: func(x, y, z int) bool { return false },
+			faces:  []SectionFace{FaceNorth, FaceSouth, FaceEast, FaceWest, FaceUpper, FaceLower},
+		},
+		{
+			name: "tunnel along x",
+			opaque: func(x, y, z int) bool {
+				return y != middle || z != middle
+			},
+			faces: []SectionFace{FaceNorth, FaceSouth},
+		},
+		{
+			name: "bend from +x to +y",
+			opaque: func(x, y, z int) bool {
+				alongX := y == middle && z == middle && x >= middle
+				alongY := x == middle && z == middle && y >= middle
+				return !alongX && !alongY
+			},
+			faces: []SectionFace{FaceNorth, FaceUpper},
+		},
+		{
+			name: "closed cave",
+			opaque: func(x, y, z int) bool {
+				inside := x > 1 && x < testSectionSize-2 && y > 1 && y < testSectionSize-2 && z > 1 && z < testSectionSize-2
+				return !inside
+			},
+		},
+		{
+			name: "dead end",
+			opaque: func(x, y, z int) bool {
+				return y != middle || z != middle || x > middle
+			},
+			faces: []SectionFace{FaceSouth},
+		},
+	}
+
+	for _, test := range tests {
+		visibility := ComputeSectionVisibility(testSectionSize, test.opaque)
+
+		want := map[[2]SectionFace]bool{}
+		for _, from := range test.faces {
+			for _, to := range test.faces {
+				want[[2]SectionFace{from, to}] = true
+			}
+		}
+
+		got := connectedPairs(visibility)
+		for pair := range want {
+			if !got[pair] {
+				t.Errorf("%s: face %d can't see face %d", test.name, pair[0], pair[1])
+			}
+		}
+		for pair := range got {
+			if !want[pair] {
+				t.Errorf("%s: face %d sees face %d", test.name, pair[0], pair[1])
+			}
+		}
+	}
+}
+
+func TestSectionVisibilityOpenIsAllVisible(t *testing.T) {
+	if got := ComputeSectionVisibility(testSectionSize, func(x, y, z int) bool { return false }); got != AllVisible {
+		t.Errorf("empty section = %b, want AllVisible", got)
+	}
+}
+
+func TestSectionFaceOpposite(t *testing.T) {
+	for face := SectionFace(0); face < NumFaces; face++ {
+		direction, opposite := FaceDirections[face], FaceDirections[face.Opposite()]
+		for axis := range direction {
+			if direction[axis] != -opposite[axis] {
+				t.Errorf("face %d and its opposite %d point along %v and %v", face, face.Opposite(), direction, opposite)
+			}
+		}
+	}
+}
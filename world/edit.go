@@ -0,0 +1,127 @@
+package world
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/camera"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+)
+
+// Fill replaces every block in the box between the corners from and to, both
+// included, with blockType; air removes them. The whole box must be in
+// loaded chunks. It returns how many blocks changed.
+func (w *World) Fill(from, to [3]int, blockType block.BlockType) (int, error) {
+	for i := range from {
+		if from[i] > to[i] {
+			from[i], to[i] = to[i], from[i]
+		}
+	}
+	if from[1] < 0 || to[1] >= configs.WorldHeight {
+		return 0, fmt.Errorf("y must be between 0 and %d", configs.WorldHeight-1)
+	}
+
+	// confere tudo antes de mudar, para não deixar a caixa pela metade
+	chunkFromX, chunkFromZ := ChunkOf(from[0]), ChunkOf(from[2])
+	chunkToX, chunkToZ := ChunkOf(to[0]), ChunkOf(to[2])
+	for i := chunkFromX; i <= chunkToX; i++ {
+		for j := chunkFromZ; j <= chunkToZ; j++ {
+			if w.Chunks[i][j] == nil {
+				return 0, fmt.Errorf("chunk %d %d is not loaded", i, j)
+			}
+		}
+	}
+
+	w.chunksLock.Lock()
+	changed := 0
+	for x := from[0]; x <= to[0]; x++ {
+		for z := from[2]; z <= to[2]; z++ {
+			c := w.Chunks[ChunkOf(x)][ChunkOf(z)]
+			localX, localZ := x-ChunkOf(x)*configs.ChunkSize, z-ChunkOf(z)*configs.ChunkSize
+			for y := from[1]; y <= to[1]; y++ {
+				current := c.GetBlockAtNotOffsetted(localX, y, localZ)
+				if current == nil && blockType == block.BlockAir || current != nil && current.BlockType == blockType {
+					continue
+				}
+
+				c.SetBlockType(localX, y, localZ, blockType)
+				changed++
+			}
+		}
+	}
+	w.chunksLock.Unlock()
+
+	if changed == 0 {
+		return 0, nil
+	}
+
+	// as faces nas bordas da caixa dependem dos blocos do outro lado, que
+	// podem estar nos chunks vizinhos
+	var refresh [][2]int
+	for i := ChunkOf(from[0] - 1); i <= ChunkOf(to[0]+1); i++ {
+		for j := ChunkOf(from[2] - 1); j <= ChunkOf(to[2]+1); j++ {
+			refresh = append(refresh, [2]int{i, j})
+		}
+	}
+	w.refreshChunks(refresh)
+
+	return changed, nil
+}
+
+// SetBlock replaces the block at a position, as Fill with a single block
+func (w *World) SetBlock(x, y, z int, blockType block.BlockType) (bool, error) {
+	changed, err := w.Fill([3]int{x, y, z}, [3]int{x, y, z}, blockType)
+	return changed > 0, err
+}
+
+// LoadChunksAround generates the chunks around the chunk (x, z) that are
+// missing, e.g. before the player is moved there
+func (w *World) LoadChunksAround(x, z int) {
+	w.HandleChunkChange(x, z)
+	w.SwapFutureChunks()
+}
+
+// RegenerateChunk generates the loaded chunk (x, z) again from the seed,
+// discarding what changed in it. It returns false if it isn't loaded.
+func (w *World) RegenerateChunk(x, z int) bool {
+	if w.Chunks[x][z] == nil {
+		return false
+	}
+
+	generated := w.NewGeneratedChunk(x, z)
+	w.chunksLock.Lock()
+	w.Chunks[x][z] = generated
+	w.linkChunk(generated)
+	w.chunksLock.Unlock()
+
+	refresh := make([][2]int, len(aroundOffsets))
+	for i, offset := range aroundOffsets {
+		refresh[i] = [2]int{x + offset[0], z + offset[1]}
+	}
+	w.refreshChunks(refresh)
+
+	return true
+}
+
+// Recalcula as faces dos chunks carregados em keys e os blocos desenhados em
+// volta da câmera, depois de os blocos mudarem
+func (w *World) refreshChunks(keys [][2]int) {
+	w.chunksLock.Lock()
+	for _, key := range keys {
+		if c := w.Chunks[key[0]][key[1]]; c != nil {
+			c.RefreshNeighbors()
+		}
+	}
+	w.chunksLock.Unlock()
+
+	if camera.ActiveCamera != nil {
+		position := camera.ActiveCamera.Position
+		w.RequestPopulatedBlocks(float32(ChunkOf(int(math.Floor(float64(position.X()))))), float32(ChunkOf(int(math.Floor(float64(position.Z()))))), viewDistance())
+	}
+}
+
+// ChunkOf returns the chunk that holds the block at the world coordinate x, or z
+func ChunkOf(x int) int {
+	return int(math.Floor(float64(x) / float64(configs.ChunkSize)))
+}
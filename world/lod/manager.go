@@ -0,0 +1,290 @@
+package lod
+
+import (
+	"runtime"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/collisions"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
+	"github.com/reonardoleis/fcg-glcraft/geometry"
+)
+
+// Level is a ring of chunks, up to MaxDistance chunks from the player,
+// rendered as a heightmap sampled every Step blocks
+type Level struct {
+	Step        int
+	MaxDistance int
+}
+
+// Levels from the finest to the coarsest. Chunks within configs.Video.ViewDistance
+// are rendered block by block, chunks further than the last level with
+// coarsestStep up to configs.Video.LODViewDistance, and the ones beyond that
+// are not rendered at all.
+var Levels = []Level{
+	{Step: 2, MaxDistance: 4},
+	{Step: 4, MaxDistance: 12},
+}
+
+const coarsestStep = 8
+
+// StepAt returns the sampling step of the chunk at (chunkX, chunkZ) when the
+// player is at chunk (centerX, centerZ): 1 for full detail chunks and 0 for
+// chunks out of range
+func StepAt(chunkX, chunkZ, centerX, centerZ int) int {
+	distance := abs(chunkX - centerX)
+	if abs(chunkZ-centerZ) > distance {
+		distance = abs(chunkZ - centerZ)
+	}
+
+	if distance <= int(configs.Video.ViewDistance) {
+		return 1
+	}
+
+	for _, level := range Levels {
+		if distance <= level.MaxDistance {
+			return level.Step
+		}
+	}
+
+	if distance <= configs.Video.LODViewDistance {
+		return coarsestStep
+	}
+
+	return 0
+}
+
+type chunkKey struct {
+	X, Z int
+}
+
+// O que define a malha de um chunk: seu passo e o dos vizinhos
+type chunkSpec struct {
+	Step          int
+	NeighborSteps [NumNeighbors]int
+}
+
+type buildResult struct {
+	key  chunkKey
+	spec chunkSpec
+	data geometry.MeshData
+}
+
+type lodChunk struct {
+	spec chunkSpec
+	mesh *geometry.Mesh
+}
+
+// Manager keeps the LOD meshes of the chunks around the player. Meshes are
+// built from the height function by background workers, without generating
+// the chunks, and uploaded on the OpenGL thread by Update. When the player
+// moves, a chunk keeps its old mesh until the new one is ready, so changing
+// levels never opens holes in the terrain.
+type Manager struct {
+	height   HeightFunc
+	material *geometry.Material
+	chunks   map[chunkKey]*lodChunk
+	desired  map[chunkKey]chunkSpec
+	pending  map[chunkKey]bool
+	backlog  map[chunkKey]bool // chunks que não couberam na fila de jobs
+	jobs     chan buildResult
+	results  chan buildResult
+	done     chan struct{}
+	centerX  int
+	centerZ  int
+	started  bool
+	// distâncias de visão usadas pelo último SetCenter
+	viewDistance    float32
+	lodViewDistance int
+}
+
+func NewManager(height HeightFunc) *Manager {
+	workers := runtime.NumCPU() / 2
+	if workers < 1 {
+		workers = 1
+	}
+
+	return newManager(height, workers)
+}
+
+// Cria o gerenciador com um número de workers; sem nenhum, os jobs ficam na fila
+func newManager(height HeightFunc, workers int) *Manager {
+	m := &Manager{
+		height:  height,
+		chunks:  make(map[chunkKey]*lodChunk),
+		desired: make(map[chunkKey]chunkSpec),
+		pending: make(map[chunkKey]bool),
+		backlog: make(map[chunkKey]bool),
+		jobs:    make(chan buildResult, (2*configs.Video.LODViewDistance+1)*(2*configs.Video.LODViewDistance+1)),
+		results: make(chan buildResult, 256),
+		done:    make(chan struct{}),
+	}
+
+	for i := 0; i < workers; i++ {
+		go m.worker()
+	}
+
+	return m
+}
+
+func (m *Manager) worker() {
+	for job := range m.jobs {
+		job.data = BuildChunkMesh(job.key.X, job.key.Z, job.spec.Step, job.spec.NeighborSteps, m.height)
+		select {
+		case m.results <- job:
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// Destroy stops the workers and frees the meshes. The manager can't be used
+// afterwards.
+func (m *Manager) Destroy() {
+	close(m.done)
+	close(m.jobs)
+
+	for key, current := range m.chunks {
+		current.mesh.Destroy()
+		delete(m.chunks, key)
+	}
+}
+
+func (m *Manager) specAt(chunkX, chunkZ int) chunkSpec {
+	spec := chunkSpec{Step: StepAt(chunkX, chunkZ, m.centerX, m.centerZ)}
+	spec.NeighborSteps = [NumNeighbors]int{
+		NeighborNorth: StepAt(chunkX+1, chunkZ, m.centerX, m.centerZ),
+		NeighborSouth: StepAt(chunkX-1, chunkZ, m.centerX, m.centerZ),
+		NeighborEast:  StepAt(chunkX, chunkZ+1, m.centerX, m.centerZ),
+		NeighborWest:  StepAt(chunkX, chunkZ-1, m.centerX, m.centerZ),
+	}
+
+	return spec
+}
+
+func (m *Manager) schedule(key chunkKey, spec chunkSpec) {
+	if m.pending[key] {
+		return
+	}
+
+	// nunca bloqueia: os workers podem estar esperando Update consumir os resultados
+	select {
+	case m.jobs <- buildResult{key: key, spec: spec}:
+		m.pending[key] = true
+		delete(m.backlog, key)
+	default:
+		m.backlog[key] = true
+	}
+}
+
+// SetCenter updates the chunk the player is at, scheduling the chunks whose
+// level changed to be rebuilt and dropping the ones out of range. A change of
+// the view distances has the same effect as moving.
+func (m *Manager) SetCenter(centerX, centerZ int) {
+	if m.started && centerX == m.centerX && centerZ == m.centerZ &&
+		m.viewDistance == configs.Video.ViewDistance && m.lodViewDistance == configs.Video.LODViewDistance {
+		return
+	}
+	m.started = true
+	m.centerX, m.centerZ = centerX, centerZ
+	m.viewDistance, m.lodViewDistance = configs.Video.ViewDistance, configs.Video.LODViewDistance
+
+	m.desired = make(map[chunkKey]chunkSpec)
+	for chunkX := centerX - configs.Video.LODViewDistance; chunkX <= centerX+configs.Video.LODViewDistance; chunkX++ {
+		for chunkZ := centerZ - configs.Video.LODViewDistance; chunkZ <= centerZ+configs.Video.LODViewDistance; chunkZ++ {
+			spec := m.specAt(chunkX, chunkZ)
+			if spec.Step <= 1 {
+				continue
+			}
+
+			key := chunkKey{chunkX, chunkZ}
+			m.desired[key] = spec
+			if current, ok := m.chunks[key]; !ok || current.spec != spec {
+				m.schedule(key, spec)
+			}
+		}
+	}
+
+	for key, current := range m.chunks {
+		if _, ok := m.desired[key]; !ok {
+			current.mesh.Destroy()
+			delete(m.chunks, key)
+		}
+	}
+}
+
+// Update uploads the meshes built since the last frame. It must be called
+// from the OpenGL thread.
+func (m *Manager) Update() {
+	for key := range m.backlog {
+		if spec, ok := m.desired[key]; ok {
+			m.schedule(key, spec)
+		} else {
+			delete(m.backlog, key)
+		}
+	}
+
+	for {
+		select {
+		case result := <-m.results:
+			m.upload(result)
+		default:
+			return
+		}
+	}
+}
+
+func (m *Manager) upload(result buildResult) {
+	delete(m.pending, result.key)
+
+	spec, ok := m.desired[result.key]
+	if !ok {
+		return
+	}
+	if spec != result.spec {
+		// o jogador se moveu enquanto a malha era construída
+		m.schedule(result.key, spec)
+		return
+	}
+
+	if m.material == nil {
+		m.material = geometry.NewMaterial(shaders.Get("standard"), shaders.ObjectTerrainLOD)
+	}
+
+	if current, ok := m.chunks[result.key]; ok {
+		current.mesh.Destroy()
+	}
+	m.chunks[result.key] = &lodChunk{spec: spec, mesh: geometry.NewMesh(result.data, m.material)}
+}
+
+// Loaded returns how many LOD chunks have a mesh
+func (m *Manager) Loaded() int {
+	return len(m.chunks)
+}
+
+// Pending returns how many LOD meshes are being built or waiting for a worker
+func (m *Manager) Pending() int {
+	return len(m.pending) + len(m.backlog)
+}
+
+// Draw renders the LOD chunks inside the frustum
+func (m *Manager) Draw(frustum collisions.FrustumCollider) {
+	half := float32(configs.BlockSize) / 2
+	for key, current := range m.chunks {
+		mins := mgl32.Vec3{float32(key.X*configs.ChunkSize) - half, 0, float32(key.Z*configs.ChunkSize) - half}
+		maxes := mins.Add(mgl32.Vec3{float32(configs.ChunkSize), float32(configs.WorldHeight), float32(configs.ChunkSize)})
+		if !frustum.CollidesWithAABB(mins, maxes) {
+			continue
+		}
+
+		current.mesh.Draw(mgl32.Ident4())
+	}
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+
+	return a
+}
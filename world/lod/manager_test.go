@@ -0,0 +1,213 @@
+package lod
+
+import (
+	"testing"
+
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/geometry"
+)
+
+// Muda as distâncias de visão durante o teste
+func setViewDistances(t *testing.T, view float32, lod int) {
+	previous := configs.Video
+	t.Cleanup(func() { configs.Video = previous })
+	configs.Video.ViewDistance = view
+	configs.Video.LODViewDistance = lod
+}
+
+func TestStepAt(t *testing.T) {
+	setViewDistances(t, 1, 20)
+
+	tests := []struct {
+		name             string
+		chunkX, chunkZ   int
+		centerX, centerZ int
+		want             int
+	}{
+		{"chunk do jogador", 0, 0, 0, 0, 1},
+		{"na distância de visão", 1, -1, 0, 0, 1},
+		{"primeiro nível", 2, 0, 0, 0, 2},
+		{"fim do primeiro nível", -4, 3, 0, 0, 2},
+		{"segundo nível", 0, 5, 0, 0, 4},
+		{"fim do segundo nível", 12, 12, 0, 0, 4},
+		{"nível mais grosso", 13, 0, 0, 0, coarsestStep},
+		{"fim da distância de LOD", -20, 7, 0, 0, coarsestStep},
+		{"fora do alcance", 21, 0, 0, 0, 0},
+		{"relativo ao jogador", 105, -98, 100, -100, 4},
+		{"distância é o maior eixo", 3, 13, 0, 0, coarsestStep},
+	}
+
+	for _, test := range tests {
+		if got := StepAt(test.chunkX, test.chunkZ, test.centerX, test.centerZ); got != test.want {
+			t.Errorf("%s: step = %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSpecAt(t *testing.T) {
+	setViewDistances(t, 1, 20)
+	m := newManager(bumpyHeight, 0)
+	m.centerX, m.centerZ = 10, 10
+
+	tests := []struct {
+		name           string
+		chunkX, chunkZ int
+		want           chunkSpec
+	}{
+		{"dentro de um nível", 17, 10, chunkSpec{4, [NumNeighbors]int{4, 4, 4, 4}}},
+		{"ao lado do detalhe completo", 12, 10, chunkSpec{2, [NumNeighbors]int{2, 1, 2, 2}}},
+		{"entre dois níveis", 14, 10, chunkSpec{2, [NumNeighbors]int{4, 2, 2, 2}}},
+		{"canto entre dois níveis", 14, 14, chunkSpec{2, [NumNeighbors]int{4, 2, 4, 2}}},
+		{"na borda do alcance", 10, 30, chunkSpec{coarsestStep, [NumNeighbors]int{coarsestStep, coarsestStep, 0, coarsestStep}}},
+	}
+
+	for _, test := range tests {
+		if got := m.specAt(test.chunkX, test.chunkZ); got != test.want {
+			t.Errorf("%s: spec = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+// Faz o papel dos workers e de Update: tira os jobs da fila e guarda as
+// malhas como se tivessem sido construídas
+func finishJobs(m *Manager) map[chunkKey]chunkSpec {
+	built := map[chunkKey]chunkSpec{}
+	for {
+		select {
+		case job := <-m.jobs:
+			delete(m.pending, job.key)
+			m.chunks[job.key] = &lodChunk{spec: job.spec, mesh: &geometry.Mesh{}}
+			built[job.key] = job.spec
+		default:
+			return built
+		}
+	}
+}
+
+func TestSetCenter(t *testing.T) {
+	setViewDistances(t, 1, 6)
+	m := newManager(bumpyHeight, 0)
+
+	m.SetCenter(0, 0)
+	if got, want := len(m.desired), 13*13-3*3; got != want {
+		t.Fatalf("%d chunks desired, want %d", got, want)
+	}
+	if got := m.Pending(); got != len(m.desired) {
+		t.Errorf("%d chunks pending after the first SetCenter, want %d", got, len(m.desired))
+	}
+	finishJobs(m)
+
+	m.SetCenter(0, 0)
+	if built := finishJobs(m); len(built) != 0 {
+		t.Errorf("SetCenter without moving rebuilt %d chunks", len(built))
+	}
+
+	steps := []struct {
+		name             string
+		centerX, centerZ int
+		view             float32
+	}{
+		{"anda um chunk", 1, 0, 1},
+		{"anda na diagonal", 2, 1, 1},
+		{"aumenta a distância de visão", 2, 1, 2},
+		{"volta para a origem", 0, 0, 2},
+	}
+
+	for _, step := range steps {
+		before := map[chunkKey]chunkSpec{}
+		for key, current := range m.chunks {
+			before[key] = current.spec
+		}
+
+		configs.Video.ViewDistance = step.view
+		m.SetCenter(step.centerX, step.centerZ)
+		built := finishJobs(m)
+		if len(built) == 0 {
+			t.Errorf("%s: nothing was rebuilt", step.name)
+		}
+
+		for key, spec := range m.desired {
+			if spec != m.specAt(key.X, key.Z) {
+				t.Errorf("%s: chunk %v desired with %+v", step.name, key, spec)
+			}
+			// só é construído de novo o que mudou de nível ou de vizinhos
+			old, existed := before[key]
+			if _, rebuilt := built[key]; rebuilt == (existed && old == spec) {
+				t.Errorf("%s: chunk %v rebuilt = %v, spec %+v -> %+v", step.name, key, rebuilt, old, spec)
+			}
+		}
+		for key := range m.chunks {
+			if _, ok := m.desired[key]; !ok {
+				t.Errorf("%s: chunk %v out of range kept its mesh", step.name, key)
+			}
+		}
+		if len(m.chunks) != len(m.desired) {
+			t.Errorf("%s: %d meshes for %d chunks", step.name, len(m.chunks), len(m.desired))
+		}
+	}
+}
+
+func TestBacklog(t *testing.T) {
+	// a fila de jobs é do tamanho da distância de LOD da criação
+	setViewDistances(t, 1, 2)
+	m := newManager(bumpyHeight, 0)
+	capacity := cap(m.jobs)
+
+	configs.Video.LODViewDistance = 6
+	m.SetCenter(0, 0)
+	desired := len(m.desired)
+	if len(m.pending) != capacity || len(m.backlog) != desired-capacity || m.Pending() != desired {
+		t.Fatalf("%d pending and %d in the backlog, want %d and %d", len(m.pending), len(m.backlog), capacity, desired-capacity)
+	}
+
+	// cada Update manda para a fila o que couber
+	built := map[chunkKey]bool{}
+	for updates := 0; m.Pending() > 0; updates++ {
+		if updates > desired {
+			t.Fatal("backlog never emptied")
+		}
+		for key := range finishJobs(m) {
+			built[key] = true
+		}
+		m.Update()
+	}
+	if len(built) != desired {
+		t.Errorf("%d chunks built, want %d", len(built), desired)
+	}
+}
+
+func TestStaleResult(t *testing.T) {
+	setViewDistances(t, 1, 6)
+	m := newManager(bumpyHeight, 0)
+	m.SetCenter(0, 0)
+	finishJobs(m)
+
+	// resultados construídos antes do jogador andar
+	key := chunkKey{3, 0}
+	current := m.chunks[key].spec
+	stale := current
+	stale.Step = 16
+	m.pending[key] = true
+	m.results <- buildResult{key: key, spec: stale}
+	gone := chunkKey{-6, 0}
+	m.pending[gone] = true
+	m.SetCenter(1, 0)
+	finishJobs(m)
+	m.results <- buildResult{key: gone, spec: chunkSpec{Step: coarsestStep}}
+
+	m.Update()
+	// a malha antiga fica até a nova ficar pronta
+	if m.chunks[key].spec != current {
+		t.Errorf("stale mesh replaced chunk %v", key)
+	}
+	if _, ok := m.chunks[gone]; ok {
+		t.Errorf("result for chunk %v out of range was kept", gone)
+	}
+	if m.pending[gone] {
+		t.Errorf("chunk %v out of range is still pending", gone)
+	}
+	rebuilt := finishJobs(m)
+	if spec, ok := rebuilt[key]; !ok || spec != m.desired[key] {
+		t.Errorf("stale chunk %v was rebuilt with %+v, want %+v", key, spec, m.desired[key])
+	}
+}
@@ -0,0 +1,137 @@
+package lod
+
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/geometry"
+)
+
+// HeightFunc returns the height of the top of the surface of the world column
+// (x, z), in world units. It must be safe to call from several goroutines.
+type HeightFunc func(x, z int) float32
+
+// Vizinhos de um chunk, na mesma ordem de chunk.SectionFace
+const (
+	NeighborNorth = iota // +x
+	NeighborSouth        // -x
+	NeighborEast         // +z
+	NeighborWest         // -z
+	NumNeighbors
+)
+
+// BuildChunkMesh builds a heightmap mesh for chunk (chunkX, chunkZ), sampling
+// height every step blocks. neighborSteps holds the step of each neighbor
+// chunk; on edges shared with a coarser neighbor the heights are interpolated
+// between the neighbor's samples, so both meshes meet without cracks. Every
+// edge also gets a skirt hanging below it, which hides the gaps against full
+// detail chunks and while neighbors are being rebuilt.
+func BuildChunkMesh(chunkX, chunkZ, step int, neighborSteps [NumNeighbors]int, height HeightFunc) geometry.MeshData {
+	cells := configs.ChunkSize / step
+	side := cells + 1
+	originX := chunkX * configs.ChunkSize
+	originZ := chunkZ * configs.ChunkSize
+	half := float32(configs.BlockSize) / 2
+
+	// altura de um vértice da grade, ajustada nas bordas com vizinhos mais grossos
+	vertexHeight := func(i, j int) float32 {
+		x, z := originX+i*step, originZ+j*step
+
+		edgeStep, along, fixed := 0, 0, 0
+		switch {
+		case i == cells && neighborSteps[NeighborNorth] > step:
+			edgeStep, along, fixed = neighborSteps[NeighborNorth], z, x
+		case i == 0 && neighborSteps[NeighborSouth] > step:
+			edgeStep, along, fixed = neighborSteps[NeighborSouth], z, x
+		case j == cells && neighborSteps[NeighborEast] > step:
+			edgeStep, along, fixed = neighborSteps[NeighborEast], x, z
+		case j == 0 && neighborSteps[NeighborWest] > step:
+			edgeStep, along, fixed = neighborSteps[NeighborWest], x, z
+		}
+
+		if edgeStep == 0 || floorMod(along, edgeStep) == 0 {
+			return height(x, z)
+		}
+
+		from := along - floorMod(along, edgeStep)
+		t := float32(along-from) / float32(edgeStep)
+		var a, b float32
+		if i == 0 || i == cells {
+			a, b = height(fixed, from), height(fixed, from+edgeStep)
+		} else {
+			a, b = height(from, fixed), height(from+edgeStep, fixed)
+		}
+
+		return a + (b-a)*t
+	}
+
+	heights := make([]float32, side*side)
+	for i := 0; i < side; i++ {
+		for j := 0; j < side; j++ {
+			heights[i*side+j] = vertexHeight(i, j)
+		}
+	}
+
+	positions := make([]float32, 0, 4*side*(side+4))
+	normals := make([]float32, 0, 4*side*(side+4))
+	indices := make([]uint32, 0, 6*cells*(cells+4))
+
+	addVertex := func(i, j int, y float32, normal mgl32.Vec3) uint32 {
+		positions = append(positions, float32(originX+i*step)-half, y, float32(originZ+j*step)-half, 1)
+		normals = append(normals, normal.X(), normal.Y(), normal.Z(), 0)
+		return uint32(len(positions)/4 - 1)
+	}
+
+	gridNormals := make([]mgl32.Vec3, side*side)
+	for i := 0; i < side; i++ {
+		for j := 0; j < side; j++ {
+			x, z := originX+i*step, originZ+j*step
+			dx := height(x+step, z) - height(x-step, z)
+			dz := height(x, z+step) - height(x, z-step)
+			gridNormals[i*side+j] = mgl32.Vec3{-dx, float32(2 * step), -dz}.Normalize()
+			addVertex(i, j, heights[i*side+j], gridNormals[i*side+j])
+		}
+	}
+
+	for i := 0; i < cells; i++ {
+		for j := 0; j < cells; j++ {
+			a := uint32(i*side + j)
+			b := uint32(i*side + j + 1)
+			c := uint32((i+1)*side + j)
+			d := uint32((i+1)*side + j + 1)
+			indices = append(indices, a, b, c, c, b, d)
+		}
+	}
+
+	// saias: cada vértice da borda é repetido mais abaixo
+	skirtDepth := float32(2*step + 2)
+	edges := [NumNeighbors][][2]int{}
+	for k := 0; k < side; k++ {
+		edges[NeighborNorth] = append(edges[NeighborNorth], [2]int{cells, k})
+		edges[NeighborSouth] = append(edges[NeighborSouth], [2]int{0, k})
+		edges[NeighborEast] = append(edges[NeighborEast], [2]int{k, cells})
+		edges[NeighborWest] = append(edges[NeighborWest], [2]int{k, 0})
+	}
+
+	for _, edge := range edges {
+		var previousTop, previousBottom uint32
+		for k, cell := range edge {
+			top := uint32(cell[0]*side + cell[1])
+			bottom := addVertex(cell[0], cell[1], heights[top]-skirtDepth, gridNormals[top])
+			if k > 0 {
+				indices = append(indices, previousTop, previousBottom, top, top, previousBottom, bottom)
+			}
+			previousTop, previousBottom = top, bottom
+		}
+	}
+
+	data := geometry.MeshData{Indices: indices, RenderingMode: gl.TRIANGLES}
+	data.AddAttribute(geometry.AttributePosition, 4, positions)
+	data.AddAttribute(geometry.AttributeNormal, 4, normals)
+
+	return data
+}
+
+func floorMod(a, b int) int {
+	return ((a % b) + b) % b
+}
@@ -0,0 +1,145 @@
+package lod
+
+import (
+	"testing"
+
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/geometry"
+)
+
+// Altura irregular, para que a interpolação nas bordas apareça
+func bumpyHeight(x, z int) float32 {
+	return float32(floorMod(x*x+3*z*z+5*x*z, 17))
+}
+
+// Altura do vértice (i, j) da grade da malha
+func gridHeight(data geometry.MeshData, step, i, j int) float32 {
+	side := configs.ChunkSize/step + 1
+	position, _ := data.Attribute(geometry.AttributePosition)
+	return position.Data[4*(i*side+j)+1]
+}
+
+func TestBuildChunkMeshCounts(t *testing.T) {
+	tests := []struct {
+		name string
+		step int
+	}{
+		{"passo 2", 2},
+		{"passo 4", 4},
+		{"passo 8", 8},
+		{"passo do chunk inteiro", configs.ChunkSize},
+	}
+
+	for _, test := range tests {
+		flat := func(x, z int) float32 { return 10 }
+		data := BuildChunkMesh(3, -2, test.step, [NumNeighbors]int{}, flat)
+		if err := data.Validate(); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		cells := configs.ChunkSize / test.step
+		side := cells + 1
+		// grade mais uma fileira de saia por borda
+		if got, want := data.VertexCount(), side*side+NumNeighbors*side; got != want {
+			t.Errorf("%s: %d vertices, want %d", test.name, got, want)
+		}
+		if got, want := len(data.Indices), 6*cells*cells+NumNeighbors*6*cells; got != want {
+			t.Errorf("%s: %d indices, want %d", test.name, got, want)
+		}
+
+		position, _ := data.Attribute(geometry.AttributePosition)
+		normal, _ := data.Attribute(geometry.AttributeNormal)
+		half := float32(configs.BlockSize) / 2
+		for v := 0; v < data.VertexCount(); v++ {
+			x, y, z := position.Data[4*v], position.Data[4*v+1], position.Data[4*v+2]
+			if x < 3*16-half || x > 4*16-half || z < -2*16-half || z > -16-half {
+				t.Errorf("%s: vertex %d at (%v, %v) is outside the chunk", test.name, v, x, z)
+				break
+			}
+			if v < side*side && y != 10 || v >= side*side && y != 10-float32(2*test.step+2) {
+				t.Errorf("%s: vertex %d at height %v", test.name, v, y)
+				break
+			}
+			if normal.Data[4*v+1] != 1 {
+				t.Errorf("%s: normal of vertex %d on flat ground is %v", test.name, v, normal.Data[4*v:4*v+3])
+				break
+			}
+		}
+	}
+}
+
+func TestBuildChunkMeshSeams(t *testing.T) {
+	const chunkX, chunkZ = 2, -1
+
+	tests := []struct {
+		name          string
+		step          int
+		neighbor      int
+		neighborStep  int
+		interpolation int // passo das amostras que a borda segue, 0 sem interpolação
+	}{
+		{"vizinho norte mais grosso", 2, NeighborNorth, 4, 4},
+		{"vizinho sul mais grosso", 2, NeighborSouth, 8, 8},
+		{"vizinho leste mais grosso", 4, NeighborEast, 8, 8},
+		{"vizinho oeste mais grosso", 2, NeighborWest, 4, 4},
+		{"vizinho com o mesmo passo", 4, NeighborNorth, 4, 0},
+		{"vizinho mais fino", 4, NeighborEast, 2, 0},
+		{"vizinho em detalhe completo", 2, NeighborWest, 1, 0},
+	}
+
+	for _, test := range tests {
+		var neighborSteps [NumNeighbors]int
+		neighborSteps[test.neighbor] = test.neighborStep
+		data := BuildChunkMesh(chunkX, chunkZ, test.step, neighborSteps, bumpyHeight)
+
+		cells := configs.ChunkSize / test.step
+		originX, originZ := chunkX*configs.ChunkSize, chunkZ*configs.ChunkSize
+		for k := 0; k <= cells; k++ {
+			// vértice k da borda, e a coordenada do mundo ao longo dela
+			var i, j, x, z int
+			switch test.neighbor {
+			case NeighborNorth:
+				i, j = cells, k
+			case NeighborSouth:
+				i, j = 0, k
+			case NeighborEast:
+				i, j = k, cells
+			case NeighborWest:
+				i, j = k, 0
+			}
+			x, z = originX+i*test.step, originZ+j*test.step
+
+			want := bumpyHeight(x, z)
+			if test.interpolation > 0 {
+				// a borda segue a linha entre as amostras do vizinho
+				along, at := &z, func(v int) float32 { return bumpyHeight(x, v) }
+				if test.neighbor == NeighborEast || test.neighbor == NeighborWest {
+					along, at = &x, func(v int) float32 { return bumpyHeight(v, z) }
+				}
+				from := *along - floorMod(*along, test.interpolation)
+				fraction := float32(*along-from) / float32(test.interpolation)
+				want = at(from) + (at(from+test.interpolation)-at(from))*fraction
+			}
+
+			if got := gridHeight(data, test.step, i, j); got != want {
+				t.Errorf("%s: edge vertex %d at (%d, %d) has height %v, want %v", test.name, k, x, z, got, want)
+			}
+		}
+
+		// a borda oposta não é interpolada
+		opposite := test.neighbor ^ 1
+		i, j := 0, cells/2+1
+		switch opposite {
+		case NeighborNorth:
+			i = cells
+		case NeighborEast:
+			i, j = cells/2+1, cells
+		case NeighborWest:
+			i, j = cells/2+1, 0
+		}
+		if got, want := gridHeight(data, test.step, i, j), bumpyHeight(originX+i*test.step, originZ+j*test.step); got != want {
+			t.Errorf("%s: opposite edge vertex (%d, %d) has height %v, want %v", test.name, i, j, got, want)
+		}
+	}
+}
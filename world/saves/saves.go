@@ -0,0 +1,158 @@
+package saves
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/reonardoleis/fcg-glcraft/configs"
+)
+
+// Pasta padrão dos mundos salvos, relativa ao diretório do jogo
+const DefaultDir = "worlds"
+
+// Arquivo com as informações de cada mundo, dentro da pasta dele
+const infoFile = "world.json"
+
+// Versão do formato de world.json. Mundos sem versão são anteriores a Config
+// e usam a configuração padrão.
+const SchemaVersion = 1
+
+var ErrInvalidName = errors.New("invalid world name")
+
+// WorldInfo is what is saved of a world. The terrain is generated again from
+// Seed, Generator and Config when the world is loaded.
+type WorldInfo struct {
+	Version        int                 `json:"version"`
+	Name           string              `json:"name"`
+	Seed           int64               `json:"seed"`
+	Generator      string              `json:"generator"`
+	Config         configs.WorldConfig `json:"config"`
+	Time           float64             `json:"time"` // segundos de jogo, veja world.World.Time
+	PlayerPosition [3]float32          `json:"player_position"`
+	GameMode       int                 `json:"game_mode"`
+	Created        time.Time           `json:"created"`
+	LastPlayed     time.Time           `json:"last_played"`
+}
+
+// Pasta de um mundo. O nome vira o nome da pasta, então não pode ter separadores.
+func worldDir(dir, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\:*?"<>|`) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	return filepath.Join(dir, name), nil
+}
+
+// Exists tells if a world named name is saved in dir
+func Exists(dir, name string) bool {
+	path, err := worldDir(dir, name)
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(filepath.Join(path, infoFile))
+	return err == nil
+}
+
+// List returns the worlds saved in dir, the most recently played first. A
+// missing dir has no worlds.
+func List(dir string) ([]WorldInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	worlds := []WorldInfo{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		info, err := Load(dir, entry.Name())
+		if err != nil {
+			// pastas que não são mundos são ignoradas
+			continue
+		}
+		worlds = append(worlds, info)
+	}
+
+	sort.SliceStable(worlds, func(i, j int) bool {
+		return worlds[i].LastPlayed.After(worlds[j].LastPlayed)
+	})
+
+	return worlds, nil
+}
+
+// Load reads the world named name from dir
+func Load(dir, name string) (WorldInfo, error) {
+	path, err := worldDir(dir, name)
+	if err != nil {
+		return WorldInfo{}, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(path, infoFile))
+	if err != nil {
+		return WorldInfo{}, err
+	}
+
+	// parâmetros ausentes do arquivo ficam com o valor padrão
+	info := WorldInfo{Config: configs.DefaultWorld()}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return WorldInfo{}, fmt.Errorf("%s: %w", name, err)
+	}
+	if info.Version > SchemaVersion {
+		return WorldInfo{}, fmt.Errorf("%s: saved by a newer version of the game (%d > %d)", name, info.Version, SchemaVersion)
+	}
+	if err := info.Config.Validate(); err != nil {
+		return WorldInfo{}, fmt.Errorf("%s: %w", name, err)
+	}
+	info.Name = name
+
+	return info, nil
+}
+
+// Save writes info to dir, creating the folder of the world if needed
+func Save(dir string, info WorldInfo) error {
+	path, err := worldDir(dir, info.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return err
+	}
+
+	info.Version = SchemaVersion
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// escreve num arquivo temporário para não corromper o mundo se falhar no meio
+	temporary := filepath.Join(path, infoFile+".tmp")
+	if err := os.WriteFile(temporary, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(temporary, filepath.Join(path, infoFile))
+}
+
+// Delete removes the world named name and everything in its folder
+func Delete(dir, name string) error {
+	path, err := worldDir(dir, name)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(path)
+}
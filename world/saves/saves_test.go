@@ -0,0 +1,197 @@
+package saves
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reonardoleis/fcg-glcraft/configs"
+)
+
+func TestWorldDir(t *testing.T) {
+	tests := []struct {
+		name  string
+		world string
+		valid bool
+	}{
+		{"nome comum", "Meu Mundo", true},
+		{"espaços em volta", "  mundo  ", true},
+		{"vazio", "", false},
+		{"só espaços", "   ", false},
+		{"ponto", ".", false},
+		{"diretório acima", "..", false},
+		{"separador", "a/b", false},
+		{"separador do Windows", `a\b`, false},
+		{"dois pontos", "c:", false},
+		{"curinga", "mundo*", false},
+	}
+
+	for _, test := range tests {
+		path, err := worldDir("worlds", test.world)
+		if test.valid {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			} else if filepath.Dir(path) != "worlds" {
+				t.Errorf("%s: path %q is outside the saves folder", test.name, path)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrInvalidName) {
+			t.Errorf("%s: error = %v, want ErrInvalidName", test.name, err)
+		}
+	}
+}
+
+// Escreve world.json direto, para arquivos que Save não gera
+func writeInfo(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, infoFile), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	played := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	saved := WorldInfo{Name: "salvo", Seed: 42, Generator: "flat", Config: configs.DefaultWorld(), Time: 90, GameMode: 1, LastPlayed: played}
+	if err := Save(dir, saved); err != nil {
+		t.Fatal(err)
+	}
+	writeInfo(t, dir, "antigo", `{"name": "antigo", "seed": 7}`)
+	writeInfo(t, dir, "novo", `{"version": 2, "seed": 7}`)
+	writeInfo(t, dir, "quebrado", `{"seed": `)
+	writeInfo(t, dir, "config ruim", `{"version": 1, "config": {"tick_rate": 0}}`)
+	writeInfo(t, dir, "renomeado", `{"version": 1, "name": "outro nome"}`)
+
+	tests := []struct {
+		name    string
+		world   string
+		want    func(info *WorldInfo)
+		wantErr string
+	}{
+		{
+			name:  "salvo por Save",
+			world: "salvo",
+			want:  func(info *WorldInfo) { *info = saved; info.Version = SchemaVersion },
+		},
+		{
+			name:  "sem versão usa a configuração padrão",
+			world: "antigo",
+			want:  func(info *WorldInfo) { info.Name, info.Seed = "antigo", 7 },
+		},
+		{
+			name:  "o nome é o da pasta",
+			world: "renomeado",
+			want:  func(info *WorldInfo) { info.Version, info.Name = 1, "renomeado" },
+		},
+		{name: "versão mais nova", world: "novo", wantErr: "newer version"},
+		{name: "JSON quebrado", world: "quebrado", wantErr: "quebrado"},
+		{name: "configuração inválida", world: "config ruim", wantErr: "tick_rate"},
+		{name: "não existe", world: "nenhum", wantErr: "no such file"},
+		{name: "nome inválido", world: "../salvo", wantErr: ErrInvalidName.Error()},
+	}
+
+	for _, test := range tests {
+		info, err := Load(dir, test.world)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%s: error = %v, want one about %q", test.name, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		want := WorldInfo{Config: configs.DefaultWorld()}
+		test.want(&want)
+		if !reflect.DeepEqual(info, want) {
+			t.Errorf("%s: info = %+v, want %+v", test.name, info, want)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	if worlds, err := List(filepath.Join(dir, "nenhuma")); worlds != nil || err != nil {
+		t.Errorf("missing folder listed %v, %v", worlds, err)
+	}
+
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+	for _, info := range []WorldInfo{
+		{Name: "meio", LastPlayed: day(2)},
+		{Name: "recente", LastPlayed: day(3)},
+		{Name: "antigo", LastPlayed: day(1)},
+	} {
+		info.Config = configs.DefaultWorld()
+		if err := Save(dir, info); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// pastas e arquivos que não são mundos
+	if err := os.Mkdir(filepath.Join(dir, "vazia"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notas.txt"), []byte("oi"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeInfo(t, dir, "quebrado", "{")
+	writeInfo(t, dir, "futuro", `{"version": 99}`)
+
+	worlds, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, info := range worlds {
+		names = append(names, info.Name)
+	}
+	if want := []string{"recente", "meio", "antigo"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("listed %v, want %v", names, want)
+	}
+}
+
+func TestSaveDelete(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := Save(dir, WorldInfo{Name: "a/b"}); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("saving a/b: %v, want ErrInvalidName", err)
+	}
+	if err := Delete(dir, ".."); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("deleting ..: %v, want ErrInvalidName", err)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Fatalf("saves folder is gone: %v", err)
+	}
+
+	info := WorldInfo{Name: "mundo", Config: configs.DefaultWorld()}
+	if err := Save(dir, info); err != nil {
+		t.Fatal(err)
+	}
+	if !Exists(dir, "mundo") {
+		t.Fatal("saved world doesn't exist")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "mundo", infoFile+".tmp")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+
+	if err := Delete(dir, "mundo"); err != nil {
+		t.Fatal(err)
+	}
+	if Exists(dir, "mundo") {
+		t.Error("deleted world still exists")
+	}
+	if Exists(dir, "..") {
+		t.Error("invalid name exists")
+	}
+}
@@ -0,0 +1,47 @@
+package world
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Duração de um dia completo do mundo, em segundos
+const DayLength float64 = 20 * 60
+
+// Hora do dia em que um mundo novo começa (manhã)
+const StartTimeOfDay float64 = 0.1
+
+// AdvanceTime moves the world clock forward
+func (w *World) AdvanceTime(seconds float64) {
+	w.Time += seconds
+}
+
+// TimeOfDay returns the fraction of the current day in [0, 1): 0 is sunrise,
+// 0.25 noon, 0.5 sunset and 0.75 midnight
+func (w *World) TimeOfDay() float64 {
+	timeOfDay := math.Mod(w.Time/DayLength, 1)
+	if timeOfDay < 0 {
+		timeOfDay++
+	}
+
+	return timeOfDay
+}
+
+// SetTimeOfDay moves the clock to the given fraction of the current day
+func (w *World) SetTimeOfDay(timeOfDay float64) {
+	w.Time = (math.Floor(w.Time/DayLength) + timeOfDay) * DayLength
+}
+
+// SunDirection points from the ground to the sun
+func (w *World) SunDirection() mgl32.Vec3 {
+	return SunDirectionAt(w.TimeOfDay())
+}
+
+// SunDirectionAt returns the direction to the sun at a time of the day. The
+// sun rises at +x, sets at -x and is tilted towards +z so it never passes
+// exactly overhead.
+func SunDirectionAt(timeOfDay float64) mgl32.Vec3 {
+	angle := 2 * math.Pi * timeOfDay
+	return mgl32.Vec3{float32(math.Cos(angle)), float32(math.Sin(angle)), 0.35}.Normalize()
+}
@@ -0,0 +1,103 @@
+package world
+
+import (
+	"math"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+)
+
+// The blocks of a section are only sorted again after the camera moves this
+// much since they were last sorted. Sections are small, so their order among
+// themselves is sorted every frame.
+const TranslucentResortDistance float32 = 1
+
+type translucentSection struct {
+	key        SectionKey
+	center     mgl32.Vec3
+	blocks     []*block.Block
+	sortedFrom mgl32.Vec3
+	sorted     bool
+}
+
+// TranslucentQueue keeps the translucent blocks grouped by chunk section and
+// returns them back to front, so blending composes them in the right order
+type TranslucentQueue struct {
+	sections []*translucentSection
+	ordered  []*block.Block
+}
+
+func NewTranslucentQueue(blocks []*block.Block) *TranslucentQueue {
+	q := &TranslucentQueue{}
+	sections := map[SectionKey]*translucentSection{}
+
+	for _, b := range blocks {
+		key := sectionOf(b.Position.Vec3())
+		section, ok := sections[key]
+		if !ok {
+			half := float32(configs.SectionSize) / 2
+			section = &translucentSection{
+				key: key,
+				center: mgl32.Vec3{
+					float32(key.ChunkX*configs.ChunkSize) + half - 0.5,
+					float32(key.Section*configs.SectionSize) + half - 0.5,
+					float32(key.ChunkZ*configs.ChunkSize) + half - 0.5,
+				},
+			}
+			sections[key] = section
+			q.sections = append(q.sections, section)
+		}
+		section.blocks = append(section.blocks, b)
+	}
+
+	return q
+}
+
+func sectionOf(position mgl32.Vec3) SectionKey {
+	blockX := int(math.Floor(float64(position.X()) + 0.5))
+	blockY := int(math.Floor(float64(position.Y()) + 0.5))
+	blockZ := int(math.Floor(float64(position.Z()) + 0.5))
+
+	return SectionKey{
+		ChunkX:  int(math.Floor(float64(blockX) / float64(configs.ChunkSize))),
+		ChunkZ:  int(math.Floor(float64(blockZ) / float64(configs.ChunkSize))),
+		Section: blockY / configs.SectionSize,
+	}
+}
+
+// Len is the number of blocks in the queue
+func (q *TranslucentQueue) Len() int {
+	count := 0
+	for _, section := range q.sections {
+		count += len(section.blocks)
+	}
+
+	return count
+}
+
+// Sorted returns the blocks from the furthest to the closest to the camera
+func (q *TranslucentQueue) Sorted(cameraPosition mgl32.Vec3) []*block.Block {
+	distance := func(p mgl32.Vec3) float32 {
+		return p.Sub(cameraPosition).LenSqr()
+	}
+
+	sort.SliceStable(q.sections, func(i, j int) bool {
+		return distance(q.sections[i].center) > distance(q.sections[j].center)
+	})
+
+	q.ordered = q.ordered[:0]
+	for _, section := range q.sections {
+		if !section.sorted || section.sortedFrom.Sub(cameraPosition).Len() > TranslucentResortDistance {
+			sort.SliceStable(section.blocks, func(i, j int) bool {
+				return distance(section.blocks[i].Position.Vec3()) > distance(section.blocks[j].Position.Vec3())
+			})
+			section.sortedFrom = cameraPosition
+			section.sorted = true
+		}
+		q.ordered = append(q.ordered, section.blocks...)
+	}
+
+	return q.ordered
+}
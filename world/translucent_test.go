@@ -0,0 +1,77 @@
+package world
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/block"
+)
+
+func translucentBlocks(xs ...float32) []*block.Block {
+	blocks := make([]*block.Block, len(xs))
+	for i, x := range xs {
+		b := block.NewBlock(x, 0, 0, 1, false, false, block.BlockWater)
+		blocks[i] = &b
+	}
+
+	return blocks
+}
+
+func sortedXs(q *TranslucentQueue, camera mgl32.Vec3) []float32 {
+	var xs []float32
+	for _, b := range q.Sorted(camera) {
+		xs = append(xs, b.Position.X())
+	}
+
+	return xs
+}
+
+func TestTranslucentOrder(t *testing.T) {
+	// duas seções, a do chunk 0 e a do chunk 1
+	blocks := translucentBlocks(0, 5, 20, 25)
+
+	tests := []struct {
+		name   string
+		camera mgl32.Vec3
+		want   []float32
+	}{
+		{"antes do chunk 0", mgl32.Vec3{-10, 0, 0}, []float32{25, 20, 5, 0}},
+		{"depois do chunk 1", mgl32.Vec3{40, 0, 0}, []float32{0, 5, 20, 25}},
+		{"entre os chunks", mgl32.Vec3{12, 0, 0}, []float32{25, 20, 0, 5}},
+	}
+
+	for _, test := range tests {
+		q := NewTranslucentQueue(blocks)
+		if q.Len() != len(blocks) {
+			t.Fatalf("Len() = %d, want %d", q.Len(), len(blocks))
+		}
+		if got := sortedXs(q, test.camera); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Sorted() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestTranslucentResort(t *testing.T) {
+	q := NewTranslucentQueue(translucentBlocks(2, 6))
+
+	// a ordem de uma seção só é refeita quando a câmera anda mais que
+	// TranslucentResortDistance desde a última vez
+	steps := []struct {
+		name   string
+		camera mgl32.Vec3
+		want   []float32
+	}{
+		{"primeira ordenação", mgl32.Vec3{4.2, 0, 0}, []float32{2, 6}},
+		{"perto demais para refazer", mgl32.Vec3{3.7, 0, 0}, []float32{2, 6}},
+		{"ainda dentro do limite", mgl32.Vec3{4.2, 0, 0.9}, []float32{2, 6}},
+		{"longe o bastante", mgl32.Vec3{3, 0, 0}, []float32{6, 2}},
+		{"volta além do limite", mgl32.Vec3{5, 0, 0}, []float32{2, 6}},
+	}
+
+	for _, step := range steps {
+		if got := sortedXs(q, step.camera); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: Sorted(%v) = %v, want %v", step.name, step.camera, got, step.want)
+		}
+	}
+}
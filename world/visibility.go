@@ -0,0 +1,117 @@
+package world
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/collisions"
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/world/chunk"
+)
+
+// SectionKey identifies a [16x16x16] section of a chunk in the world
+type SectionKey struct {
+	ChunkX, ChunkZ int
+	Section        int
+}
+
+type sectionVisit struct {
+	key      SectionKey
+	entry    chunk.SectionFace // face pela qual a busca entrou na seção, -1 na seção da câmera
+	traveled uint8             // direções já percorridas desde a câmera
+}
+
+// VisibleSections does a breadth-first search from the camera section through
+// the section visibility graphs. A section is reached only if the face it was
+// entered from can see the face being left, the search never turns back
+// towards the camera and only sections inside the frustum are visited. Only
+// chunks within radius of (centerX, centerZ) are considered.
+func (w *World) VisibleSections(cameraPosition mgl32.Vec3, frustum collisions.FrustumCollider, centerX, centerZ, radius int) map[SectionKey]bool {
+	w.chunksLock.RLock()
+	defer w.chunksLock.RUnlock()
+
+	return w.visibleSections(cameraPosition, frustum, centerX, centerZ, radius)
+}
+
+// VisibleSections sem chunksLock, para quem já o segura
+func (w *World) visibleSections(cameraPosition mgl32.Vec3, frustum collisions.FrustumCollider, centerX, centerZ, radius int) map[SectionKey]bool {
+	visible := map[SectionKey]bool{}
+
+	inRange := func(chunkX, chunkZ int) *chunk.Chunk {
+		if chunkX < centerX-radius || chunkX > centerX+radius || chunkZ < centerZ-radius || chunkZ > centerZ+radius {
+			return nil
+		}
+		return w.Chunks[chunkX][chunkZ]
+	}
+
+	// blocos são centrados em posições inteiras
+	blockX := int(math.Floor(float64(cameraPosition.X()) + 0.5))
+	blockY := int(math.Floor(float64(cameraPosition.Y()) + 0.5))
+	blockZ := int(math.Floor(float64(cameraPosition.Z()) + 0.5))
+	cameraChunkX := int(math.Floor(float64(blockX) / float64(configs.ChunkSize)))
+	cameraChunkZ := int(math.Floor(float64(blockZ) / float64(configs.ChunkSize)))
+
+	queue := []sectionVisit{}
+	if blockY >= 0 && blockY < configs.WorldHeight && inRange(cameraChunkX, cameraChunkZ) != nil {
+		start := SectionKey{cameraChunkX, cameraChunkZ, blockY / configs.SectionSize}
+		queue = append(queue, sectionVisit{key: start, entry: -1})
+		visible[start] = true
+	} else {
+		// câmera acima ou abaixo do mundo: a busca começa pela camada de seções mais próxima
+		section, entry := chunk.NumSections-1, chunk.FaceUpper
+		if blockY < 0 {
+			section, entry = 0, chunk.FaceLower
+		}
+		for chunkX := centerX - radius; chunkX <= centerX+radius; chunkX++ {
+			for chunkZ := centerZ - radius; chunkZ <= centerZ+radius; chunkZ++ {
+				currentChunk := inRange(chunkX, chunkZ)
+				if currentChunk == nil || !frustum.CollidesWithAABB(currentChunk.SectionBounds(section)) {
+					continue
+				}
+				key := SectionKey{chunkX, chunkZ, section}
+				queue = append(queue, sectionVisit{key: key, entry: entry, traveled: 1 << uint(entry.Opposite())})
+				visible[key] = true
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		visit := queue[0]
+		queue = queue[1:]
+
+		visibility := w.Chunks[visit.key.ChunkX][visit.key.ChunkZ].SectionVisibility(visit.key.Section)
+
+		for face := chunk.SectionFace(0); face < chunk.NumFaces; face++ {
+			if visit.traveled&(1<<uint(face.Opposite())) != 0 {
+				continue
+			}
+			if visit.entry >= 0 && !visibility.CanSee(visit.entry, face) {
+				continue
+			}
+
+			direction := chunk.FaceDirections[face]
+			next := SectionKey{
+				ChunkX:  visit.key.ChunkX + direction[0],
+				ChunkZ:  visit.key.ChunkZ + direction[2],
+				Section: visit.key.Section + direction[1],
+			}
+			if next.Section < 0 || next.Section >= chunk.NumSections || visible[next] {
+				continue
+			}
+
+			nextChunk := inRange(next.ChunkX, next.ChunkZ)
+			if nextChunk == nil || !frustum.CollidesWithAABB(nextChunk.SectionBounds(next.Section)) {
+				continue
+			}
+
+			visible[next] = true
+			queue = append(queue, sectionVisit{
+				key:      next,
+				entry:    face.Opposite(),
+				traveled: visit.traveled | 1<<uint(face),
+			})
+		}
+	}
+
+	return visible
+}
@@ -1,18 +1,27 @@
 package world
 
 import (
+	"encoding/binary"
 	"fmt"
+	"hash/fnv"
 	"math"
 	"math/rand"
 	"sort"
-	"time"
+	"sync"
+	"sync/atomic"
 
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/reonardoleis/fcg-glcraft/block"
 	"github.com/reonardoleis/fcg-glcraft/camera"
+	"github.com/reonardoleis/fcg-glcraft/collisions"
 	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/metrics"
+	"github.com/reonardoleis/fcg-glcraft/engine/particles"
+	"github.com/reonardoleis/fcg-glcraft/engine/render"
+	"github.com/reonardoleis/fcg-glcraft/engine/shaders"
 	math2 "github.com/reonardoleis/fcg-glcraft/math"
 	"github.com/reonardoleis/fcg-glcraft/world/chunk"
+	"github.com/reonardoleis/fcg-glcraft/world/lod"
 	"github.com/tbogdala/noisey"
 )
 
@@ -22,46 +31,112 @@ var (
 	LoopCount = 1
 )
 
+// SeaSurfaceHeight is the height of the distant terrain over the sea: the
+// columns below sea level are drawn flat, at the top of the water blocks
+const SeaSurfaceHeight = float32(chunk.SeaLevel-1) + float32(configs.BlockSize)/2
+
+// Defines returns the "#define"s used by the shaders that draw the distant terrain
+func Defines() []shaders.Define {
+	return []shaders.Define{
+		{Name: "SEA_SURFACE_HEIGHT", Value: fmt.Sprintf("%.2f", SeaSurfaceHeight)},
+	}
+}
+
 type World struct {
 	Name                        string
 	Size                        mgl32.Vec3
 	Blocks                      WorldBlocks
-	Chunks                      map[int]map[int]*chunk.Chunk
-	FutureChunks                map[int]map[int]*chunk.Chunk
+	Chunks                      map[int]map[int]*chunk.Chunk // só muda na thread principal, com chunksLock
+	FutureChunks                map[int]map[int]*chunk.Chunk // mapa novo de HandleChunkChange, trocado por SwapFutureChunks
 	ShouldUpdateChunks          bool
-	LockFutureChunks            bool
-	PopulatedBlocks             [][]*block.Block
-	NextPopulatedBlocks         [][]*block.Block
-	NextPopulatedBlocksReady    bool
-	NextPopulatedBlocksFree     bool
+	PopulatedBlocks             [][]*block.Block  // indexado por render.Pass
+	Translucent                 *TranslucentQueue // blocos de PopulatedBlocks[render.PassTranslucent] por seção
 	ShouldUpdatePopulatedBlocks bool
 	Seed                        int64
-	Time                        int64
+	Generator                   string  // chunk.GeneratorDefault ou chunk.GeneratorFlat
+	Time                        float64 // segundos de jogo desde a criação do mundo, veja time.go
 	Tick                        float64
 	GlobalNoise                 *noisey.OpenSimplexGenerator
+	LOD                         *lod.Manager // nil não desenha o terreno distante, como nos replays sem janela
+	Deterministic               bool         // atualiza os chunks no quadro, sem goroutines, para gravações e replays
+	loadedDistance              int          // raio dos chunks gerados pelo último HandleChunkChange
+	Particles                   *particles.System
+
+	chunksLock sync.RWMutex         // protege os mapas de chunks e os blocos deles do worker de RequestPopulatedBlocks
+	populating int32                // 1 enquanto o worker de RequestPopulatedBlocks roda, atômico
+	updating   int32                // 1 enquanto a goroutine de updateChunks roda, atômico
+	populated  chan populatedBlocks // resultado do worker, lido por SwapPopulatedBlocks
+	generation int                  // muda a cada SetPopulatedBlocks, descarta resultados antigos do worker
+}
+
+// Câmera e distâncias de onde os blocos a desenhar são calculados, copiadas
+// na thread principal para que o worker não leia a câmera enquanto ela anda
+type populateView struct {
+	position   mgl32.Vec3
+	frustum    collisions.FrustumCollider
+	minY, maxY float32 // altura dos blocos desenhados, veja configs.Video.HeightViewDistance
+	distance   int
+}
+
+func newPopulateView(c *camera.Camera, viewDistance int) populateView {
+	// o frustum é calculado uma vez e testado contra cada chunk, seção e bloco
+	position := c.Position.Vec3()
+	return populateView{
+		position: position,
+		frustum:  *collisions.NewFrustumCollider(c.GetFrustum()),
+		minY:     position.Y() - configs.Video.HeightViewDistance,
+		maxY:     position.Y() + configs.Video.HeightViewDistance,
+		distance: viewDistance,
+	}
 }
 
-func NewWorld(worldName string, size mgl32.Vec3, seed int64) *World {
-	r := rand.New(rand.NewSource(time.Now().Unix()))
+// Blocos a desenhar calculados em volta de um chunk
+type populatedBlocks struct {
+	blocks      [][]*block.Block
+	translucent *TranslucentQueue
+	generation  int
+}
+
+func NewWorld(worldName string, size mgl32.Vec3, seed int64, generator string) *World {
+	r := rand.New(rand.NewSource(seed))
 	noiser := noisey.NewOpenSimplexGenerator(r)
-	return &World{
-		Name:                    worldName,
-		Size:                    size,
-		Seed:                    seed,
-		Time:                    0,
-		GlobalNoise:             &noiser,
-		FutureChunks:            make(map[int]map[int]*chunk.Chunk),
-		ShouldUpdateChunks:      false,
-		LockFutureChunks:        false,
-		NextPopulatedBlocksFree: true,
+	surfaceHeight := func(x, z int) float32 {
+		height := chunk.FlatHeight
+		if generator != chunk.GeneratorFlat {
+			height = chunk.TerrainHeight(&noiser, x, z)
+		}
+		if height < chunk.SeaLevel-1 {
+			return SeaSurfaceHeight
+		}
+
+		return float32(height) + float32(configs.BlockSize)/2
+	}
+
+	w := &World{
+		Name:               worldName,
+		Size:               size,
+		Seed:               seed,
+		Generator:          generator,
+		Time:               StartTimeOfDay * DayLength,
+		GlobalNoise:        &noiser,
+		ShouldUpdateChunks: false,
+		LOD:                lod.NewManager(surfaceHeight),
+		populated:          make(chan populatedBlocks, 1),
+	}
+
+	w.Particles = particles.NewSystem(configs.Video.MaxParticles, seed, w.IsSolidAt)
+	chunk.BlockLanded = func(position mgl32.Vec3, blockType block.BlockType) {
+		w.Particles.Emit(particles.BlockLanded(position, block.ParticleTexture(blockType)))
 	}
+
+	return w
 }
 
-func (w World) GetBlockFrom(wx, wy, wz int, playerSize float32) *block.Block {
+func (w *World) GetBlockFrom(wx, wy, wz int, playerSize float32) *block.Block {
 	return w.Blocks[wx][wy-int(playerSize)][wz]
 }
 
-func (w World) FindHighestBlock(wx, wz int) *block.Block {
+func (w *World) FindHighestBlock(wx, wz int) *block.Block {
 	var highestBlock *block.Block
 	keys := []int{}
 
@@ -80,74 +155,335 @@ func (w World) FindHighestBlock(wx, wz int) *block.Block {
 	return highestBlock
 }
 
-// Handles chunk change when player moves from one chunk to another
+// Handles chunk change when player moves from one chunk to another,
+// by filling FutureChunks with the loaded chunks and the ones generated
+// around (offsetX, offsetZ). Chunks keeps the old map until SwapFutureChunks,
+// so the worker of RequestPopulatedBlocks never sees it half filled.
 func (w *World) HandleChunkChange(offsetX, offsetZ int) {
-	for w.LockFutureChunks {
-
+	future := make(map[int]map[int]*chunk.Chunk, len(w.Chunks))
+	for i, chunkRow := range w.Chunks {
+		future[i] = make(map[int]*chunk.Chunk, len(chunkRow))
+		for j, c := range chunkRow {
+			future[i][j] = c
+		}
 	}
 
-	w.LockFutureChunks = true
-	for i := offsetX - 2; i <= offsetX+2; i++ {
-		if len(w.FutureChunks[int(i)]) == 0 {
-			w.FutureChunks[int(i)] = make(map[int]*chunk.Chunk)
+	distance := loadDistance()
+	for i := offsetX - distance; i <= offsetX+distance; i++ {
+		if len(future[int(i)]) == 0 {
+			future[int(i)] = make(map[int]*chunk.Chunk)
 		}
-		for j := offsetZ - 2; j <= offsetZ+2; j++ {
-			if w.FutureChunks[int(i)][int(j)] == nil {
-				w.FutureChunks[int(i)][int(j)] = chunk.NewChunk(mgl32.Vec2{float32(i), float32(j)}, 0)
-				w.FutureChunks[int(i)][int(j)].GenerateChunk(w.GlobalNoise)
+		for j := offsetZ - distance; j <= offsetZ+distance; j++ {
+			if future[int(i)][int(j)] == nil {
+				future[int(i)][int(j)] = w.NewGeneratedChunk(i, j)
+				metrics.Add(metrics.ChunksGenerated, 1)
 			}
 
 		}
 	}
 
+	w.FutureChunks = future
+	w.loadedDistance = distance
 	w.ShouldUpdateChunks = true
-	w.LockFutureChunks = false
+}
+
+// SwapFutureChunks replaces the chunks by the ones of the last
+// HandleChunkChange, if it wasn't swapped yet, and stitches the generated
+// chunks to the loaded ones
+func (w *World) SwapFutureChunks() {
+	if !w.ShouldUpdateChunks {
+		return
+	}
+
+	var generated [][2]int
+	for i, chunkRow := range w.FutureChunks {
+		for j := range chunkRow {
+			if w.Chunks[i][j] == nil {
+				generated = append(generated, [2]int{i, j})
+			}
+		}
+	}
+
+	w.chunksLock.Lock()
+	w.Chunks = w.FutureChunks
+	w.stitchChunks(generated)
+	w.chunksLock.Unlock()
+
+	w.FutureChunks = nil
+	w.ShouldUpdateChunks = false
+}
+
+// O próprio chunk e os oito em volta dele
+var aroundOffsets = [][2]int{{0, 0}, {1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+
+// Liga o chunk aos chunks carregados em volta dele. Quem chama segura chunksLock.
+func (w *World) linkChunk(c *chunk.Chunk) {
+	x, z := int(c.Offset[0]), int(c.Offset[1])
+	c.Adjacent = func(dx, dz int) *chunk.Chunk {
+		return w.Chunks[x+dx][z+dz]
+	}
+}
+
+// Liga os chunks carregados em keys aos vizinhos e calcula de novo as faces
+// e a luz deles e dos oito em volta, já que as faces e a luz nas bordas
+// dependem dos blocos do outro lado. Quem chama segura chunksLock.
+func (w *World) stitchChunks(keys [][2]int) {
+	for _, key := range keys {
+		if c := w.Chunks[key[0]][key[1]]; c != nil {
+			w.linkChunk(c)
+		}
+	}
+
+	// os blocos dos chunks novos só escondem faces dos vizinhos e a luz é
+	// toda calculada de novo, então SetNeighbors basta, sem RefreshNeighbors
+	stitched := map[[2]int]bool{}
+	for _, key := range keys {
+		for _, offset := range aroundOffsets {
+			around := [2]int{key[0] + offset[0], key[1] + offset[1]}
+			if stitched[around] {
+				continue
+			}
+			stitched[around] = true
+
+			if c := w.Chunks[around[0]][around[1]]; c != nil {
+				c.SetNeighbors()
+			}
+		}
+	}
+}
+
+// Recalcula as faces dos outros chunks que tocam o bloco (x, z), já que as
+// faces nas bordas deles dependem dele. Quem chama segura chunksLock.
+func (w *World) refreshBorder(x, z int) {
+	own := [2]int{ChunkOf(x), ChunkOf(z)}
+	for i := ChunkOf(x - 1); i <= ChunkOf(x+1); i++ {
+		for j := ChunkOf(z - 1); j <= ChunkOf(z+1); j++ {
+			if c := w.Chunks[i][j]; c != nil && [2]int{i, j} != own {
+				c.RefreshNeighbors()
+			}
+		}
+	}
+}
+
+// Chunks gerados em volta do jogador: os desenhados e mais um, para que as
+// colisões e a água nas bordas tenham vizinhos
+func loadDistance() int {
+	return viewDistance() + 1
+}
+
+// Raio dos chunks desenhados, lido das configurações na thread principal
+func viewDistance() int {
+	return int(configs.Video.ViewDistance)
+}
+
+// ViewDistanceChanged tells if the view distance changed since the chunks
+// around the player were generated, so HandleChunkChange must run again
+func (w *World) ViewDistanceChanged() bool {
+	return w.loadedDistance != loadDistance()
+}
+
+// NewGeneratedChunk creates the chunk at (x, z) with the generator of the
+// world, without adding it to the world
+func (w *World) NewGeneratedChunk(x, z int) *chunk.Chunk {
+	c := chunk.NewChunk(mgl32.Vec2{float32(x), float32(z)}, 0)
+	w.generateChunk(c)
+
+	return c
+}
+
+// Generates the blocks of a new chunk with the generator of the world
+func (w *World) generateChunk(c *chunk.Chunk) {
+	if w.Generator == chunk.GeneratorFlat {
+		c.GenerateFlatChunk()
+		return
+	}
+
+	c.GenerateChunk(w.GlobalNoise, w.chunkSeed(c))
+}
+
+// Semente de um chunk, derivada da semente do mundo e da posição do chunk
+func (w *World) chunkSeed(c *chunk.Chunk) int64 {
+	x, z := int64(c.Offset[0]), int64(c.Offset[1])
+	return w.Seed ^ x*73856093 ^ z*19349663
+}
+
+// Destroy stops the background work of the world and frees its meshes
+func (w *World) Destroy() {
+	if w.LOD != nil {
+		w.LOD.Destroy()
+	}
+}
+
+// LoadedChunks returns how many chunks are generated and kept in memory
+func (w *World) LoadedChunks() int {
+	w.chunksLock.RLock()
+	defer w.chunksLock.RUnlock()
+
+	loaded := 0
+	for _, chunkRow := range w.Chunks {
+		loaded += len(chunkRow)
+	}
+
+	return loaded
+}
+
+// Chunks carregados em ordem de x e depois de z, para que sejam atualizados
+// sempre na mesma ordem nos replays
+func (w *World) sortedChunks() []*chunk.Chunk {
+	rows := make([]int, 0, len(w.Chunks))
+	for i := range w.Chunks {
+		rows = append(rows, i)
+	}
+	sort.Ints(rows)
+
+	var chunks []*chunk.Chunk
+	for _, i := range rows {
+		columns := make([]int, 0, len(w.Chunks[i]))
+		for j := range w.Chunks[i] {
+			columns = append(columns, j)
+		}
+		sort.Ints(columns)
+
+		for _, j := range columns {
+			if w.Chunks[i][j] != nil {
+				chunks = append(chunks, w.Chunks[i][j])
+			}
+		}
+	}
+
+	return chunks
+}
+
+// Hash returns a hash of the blocks of every loaded chunk, so that two runs of
+// the same world can be compared, e.g. at the end of a replay
+func (w *World) Hash() uint64 {
+	w.chunksLock.RLock()
+	defer w.chunksLock.RUnlock()
+
+	hash := fnv.New64a()
+	var buffer [24]byte
+	for _, c := range w.sortedChunks() {
+		binary.LittleEndian.PutUint32(buffer[0:], math.Float32bits(c.Offset[0]))
+		binary.LittleEndian.PutUint32(buffer[4:], math.Float32bits(c.Offset[1]))
+		hash.Write(buffer[:8])
+
+		for x := 0; x < configs.ChunkSize; x++ {
+			for y := 0; y < configs.WorldHeight; y++ {
+				for z := 0; z < configs.ChunkSize; z++ {
+					b := c.GetBlockAtNotOffsetted(x, y, z)
+					if b == nil {
+						continue
+					}
+
+					// a posição muda enquanto a areia cai
+					binary.LittleEndian.PutUint32(buffer[0:], uint32(x))
+					binary.LittleEndian.PutUint32(buffer[4:], uint32(y))
+					binary.LittleEndian.PutUint32(buffer[8:], uint32(z))
+					binary.LittleEndian.PutUint32(buffer[12:], math.Float32bits(b.Position.X()))
+					binary.LittleEndian.PutUint32(buffer[16:], math.Float32bits(b.Position.Y()))
+					binary.LittleEndian.PutUint32(buffer[20:], math.Float32bits(b.Position.Z()))
+					hash.Write(buffer[:])
+					hash.Write([]byte{b.BlockType, b.WaterForce})
+				}
+			}
+		}
+	}
+
+	return hash.Sum64()
 }
 
 // Initial world generation
 func (w *World) GenerateWorld() {
-	w.Chunks = make(map[int]map[int]*chunk.Chunk)
+	chunks := make(map[int]map[int]*chunk.Chunk)
+	var keys [][2]int
 	for i := -10; i <= 10; i++ {
-		w.Chunks[i] = make(map[int]*chunk.Chunk)
+		chunks[i] = make(map[int]*chunk.Chunk)
 		for j := -10; j <= 10; j++ {
-			w.Chunks[i][j] = chunk.NewChunk(mgl32.Vec2{float32(i), float32(j)}, 0)
-			w.Chunks[i][j].GenerateChunk(w.GlobalNoise)
+			chunks[i][j] = w.NewGeneratedChunk(i, j)
+			keys = append(keys, [2]int{i, j})
 		}
 	}
 
-	w.SetPopulatedBlocks(0, 0)
+	w.chunksLock.Lock()
+	w.Chunks = chunks
+	w.stitchChunks(keys)
+	w.chunksLock.Unlock()
+	w.loadedDistance = loadDistance()
+
+	w.SetPopulatedBlocks(0, 0, viewDistance())
 }
 
-// Updates blocks that should be rendered
-func (w *World) SetPopulatedBlocks(offsetX, offsetZ float32) {
+// SetPopulatedBlocks updates the blocks that should be rendered within
+// viewDistance chunks of (offsetX, offsetZ) right away
+func (w *World) SetPopulatedBlocks(offsetX, offsetZ float32, viewDistance int) {
+	w.generation++
+	next := w.populateBlocks(offsetX, offsetZ, newPopulateView(camera.ActiveCamera, viewDistance))
+	w.PopulatedBlocks, w.Translucent = next.blocks, next.translucent
+}
 
-	if !w.NextPopulatedBlocksFree {
+// RequestPopulatedBlocks asks a worker to update the blocks that should be
+// rendered within viewDistance chunks of (offsetX, offsetZ); SwapPopulatedBlocks
+// picks the result. The caller reads viewDistance from the settings, which
+// may be replaced while the worker runs, and the camera is copied before it
+// starts. Only one worker runs at a time, so requests made while it is busy
+// are dropped. Deterministic worlds update right away.
+func (w *World) RequestPopulatedBlocks(offsetX, offsetZ float32, viewDistance int) {
+	if w.Deterministic {
+		w.SetPopulatedBlocks(offsetX, offsetZ, viewDistance)
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&w.populating, 0, 1) {
 		return
 	}
 
-	w.NextPopulatedBlocksFree = false
-	w.NextPopulatedBlocksReady = false
-	w.NextPopulatedBlocks = make([][]*block.Block, 0)
-	w.NextPopulatedBlocks = append(w.NextPopulatedBlocks, []*block.Block{}) // opacos
-	w.NextPopulatedBlocks = append(w.NextPopulatedBlocks, []*block.Block{}) // transparentes
-	for i := offsetX - configs.ViewDistance; i <= offsetX+configs.ViewDistance; i++ {
-		for j := offsetZ - configs.ViewDistance; j <= offsetZ+configs.ViewDistance; j++ {
-			chunkRenderableBlocks := w.Chunks[int(i)][int(j)].GetBlocksToRender()
+	generation := w.generation
+	view := newPopulateView(camera.ActiveCamera, viewDistance)
+	go func() {
+		next := w.populateBlocks(offsetX, offsetZ, view)
+		next.generation = generation
+		w.populated <- next
+	}()
+}
+
+// SwapPopulatedBlocks replaces the rendered blocks by the ones of the last
+// RequestPopulatedBlocks, if the worker finished
+func (w *World) SwapPopulatedBlocks() {
+	select {
+	case next := <-w.populated:
+		// um SetPopulatedBlocks depois do pedido já tem blocos mais novos
+		if next.generation == w.generation {
+			w.PopulatedBlocks, w.Translucent = next.blocks, next.translucent
+		}
+		atomic.StoreInt32(&w.populating, 0)
+	default:
+	}
+}
+
+// Calcula os blocos a desenhar a até view.distance chunks de (offsetX,
+// offsetZ). Os blocos não mudam enquanto ele roda, quem os muda espera chunksLock.
+func (w *World) populateBlocks(offsetX, offsetZ float32, view populateView) populatedBlocks {
+	w.chunksLock.RLock()
+	defer w.chunksLock.RUnlock()
+
+	blocks := make([][]*block.Block, render.NumPasses)
+	visibleSections := w.visibleSections(view.position, view.frustum, int(offsetX), int(offsetZ), view.distance)
+	for i := int(offsetX) - view.distance; i <= int(offsetX)+view.distance; i++ {
+		for j := int(offsetZ) - view.distance; j <= int(offsetZ)+view.distance; j++ {
+			chunkX, chunkZ := i, j
+			// a distância de visão pode ter aumentado antes dos chunks serem gerados
+			if w.Chunks[chunkX][chunkZ] == nil {
+				continue
+			}
+			chunkRenderableBlocks := w.Chunks[chunkX][chunkZ].GetBlocksToRender(view.frustum, view.minY, view.maxY, func(section int) bool {
+				return visibleSections[SectionKey{chunkX, chunkZ, section}]
+			})
 			for _, renderableBlock := range chunkRenderableBlocks {
-				if renderableBlock.BlockType == block.BlockGlass || renderableBlock.BlockType == block.BlockWater || renderableBlock.BlockType == block.BlockLeaves {
-					w.NextPopulatedBlocks[1] = append(w.NextPopulatedBlocks[1], renderableBlock)
-				} else {
-					w.NextPopulatedBlocks[0] = append(w.NextPopulatedBlocks[0], renderableBlock)
-				}
+				pass := renderableBlock.RenderPass()
+				blocks[pass] = append(blocks[pass], renderableBlock)
 			}
 		}
 	}
 
-	sort.SliceStable(w.NextPopulatedBlocks[1], func(i, j int) bool {
-		return math2.Distance(camera.ActiveCamera.Position, w.NextPopulatedBlocks[1][i].Position) >
-			math2.Distance(camera.ActiveCamera.Position, w.NextPopulatedBlocks[1][j].Position)
-	})
-
 	/*frustum := camera.ActiveCamera.GetFrustum()
 
 	ftlFbl := frustum.Fbl.Sub(frustum.Ftl)
@@ -194,32 +530,18 @@ func (w *World) SetPopulatedBlocks(offsetX, offsetZ float32) {
 		ftrFbrMod = ftrFbr.Normalize().Mul(multVert)
 	}*/
 
-	blocks := [][]*block.Block{}
-	blocks = make([][]*block.Block, 0)
-	blocks = append(blocks, []*block.Block{}) // opacos
-	blocks = append(blocks, []*block.Block{}) // transparentes
-	for _, row := range w.NextPopulatedBlocks {
-		for _, _block := range row {
-
-			if _block.BlockType == block.BlockGlass || _block.BlockType == block.BlockWater || _block.BlockType == block.BlockLeaves {
-				blocks[1] = append(blocks[1], _block)
-			} else {
-				blocks[0] = append(blocks[0], _block)
-			}
-
-		}
+	// a ordenação de trás para frente é feita por seção ao desenhar
+	return populatedBlocks{
+		blocks:      blocks,
+		translucent: NewTranslucentQueue(blocks[render.PassTranslucent]),
 	}
-
-	w.NextPopulatedBlocks = blocks
-	w.NextPopulatedBlocksReady = true
-	w.NextPopulatedBlocksFree = true
 }
 
 func (w *World) HasNextBlockAt(p mgl32.Vec3) bool {
 	_x := float32(int(p.X()))
 	_y := float32(int(p.Y()))
 	_z := float32(int(p.Z()))
-	for _, blockTypes := range w.NextPopulatedBlocks {
+	for _, blockTypes := range w.PopulatedBlocks {
 		for _, futureBlock := range blockTypes {
 			if futureBlock.Position.X() == _x && futureBlock.Position.Y() == _y && futureBlock.Position.Z() == _z {
 				futureBlock.Hit = true
@@ -245,7 +567,7 @@ func (w *World) PopulateIfEmpty(position mgl32.Vec3) {
 
 func (w *World) Update(roundedPlayerPosition mgl32.Vec3, backOfPlayer, frontOfPlayer mgl32.Vec3, currentChunk *chunk.Chunk) {
 
-	/*maxDist := float64(configs.ViewDistance)
+	/*maxDist := float64(configs.Video.ViewDistance)
 
 	roundedPlayerX, roundedPlayerY, roundedPlayerZ := roundedPlayerPosition.Elem()
 	fromX, toX := math.Max(-float64(w.Size.X()), float64(roundedPlayerX)-maxDist), math.Min(float64(w.Size.X()), float64(roundedPlayerX)+maxDist)
@@ -289,7 +611,7 @@ func (w *World) Update(roundedPlayerPosition mgl32.Vec3, backOfPlayer, frontOfPl
 	// // fmt.Println(playerBehind)
 
 	w.Tick++
-	if w.Tick >= configs.TickRate {
+	if w.Tick >= configs.World.TickRate {
 		w.Tick = 0
 		w.ShouldUpdatePopulatedBlocks = true
 	}
@@ -298,9 +620,9 @@ func (w *World) Update(roundedPlayerPosition mgl32.Vec3, backOfPlayer, frontOfPl
 		w.UpdatePopulatedBlocks(fromX, toX, fromY, toY, fromZ, toZ, mgl32.Vec4{roundedPlayerPosition.X(), roundedPlayerPosition.Y(), roundedPlayerPosition.Z(), 1.0})
 	}*/
 
-	/*for i := currentChunk.Offset[0] - configs.ViewDistance; i <= currentChunk.Offset[0]+configs.ViewDistance; i++ {
-		for j := currentChunk.Offset[1] - configs.ViewDistance; j <= currentChunk.Offset[1]+configs.ViewDistance; j++ {
-			if w.Tick >= configs.TickRate {
+	/*for i := currentChunk.Offset[0] - configs.Video.ViewDistance; i <= currentChunk.Offset[0]+configs.Video.ViewDistance; i++ {
+		for j := currentChunk.Offset[1] - configs.Video.ViewDistance; j <= currentChunk.Offset[1]+configs.Video.ViewDistance; j++ {
+			if w.Tick >= configs.World.TickRate {
 				w.Chunks[int(i)][int(j)].Update()
 				w.Chunks[int(i)][int(j)].SetWatersUpdate()
 			}
@@ -313,30 +635,88 @@ func (w *World) Update(roundedPlayerPosition mgl32.Vec3, backOfPlayer, frontOfPl
 		}
 	}*/
 
-	// for each populated block (renderable block)
-	// draws it ons creen
-	for _, blockClass := range w.PopulatedBlocks {
-		for _, block := range blockClass {
-			block.Draw2()
-		}
+	// terreno distante, fora do alcance dos chunks completos
+	if w.LOD != nil {
+		w.LOD.SetCenter(int(currentChunk.Offset[0]), int(currentChunk.Offset[1]))
+		w.LOD.Update()
+		metrics.Set(metrics.ChunksGenerating, float64(w.LOD.Pending()))
 	}
+	metrics.Set(metrics.ChunksLoaded, float64(w.LoadedChunks()))
 
-	if w.Tick >= configs.TickRate {
+	if w.Tick >= configs.World.TickRate {
 		w.Tick = 0
-		fmt.Println("Blocks drawn last tick: ", len(w.PopulatedBlocks[0])+len(w.PopulatedBlocks[1]))
-		for _, chunkRow := range w.Chunks {
-			for _, chunk := range chunkRow {
-				go chunk.Update()
-				go chunk.SetWatersUpdate()
-			}
+		drawn := 0
+		for _, blocks := range w.PopulatedBlocks {
+			drawn += len(blocks)
+		}
+		metrics.Set(metrics.BlocksDrawn, float64(drawn))
+		chunks := w.sortedChunks()
+		if w.Deterministic {
+			w.updateChunks(chunks)
+		} else if atomic.CompareAndSwapInt32(&w.updating, 0, 1) {
+			// se a atualização anterior ainda roda, esta é pulada
+			go func() {
+				w.updateChunks(chunks)
+				atomic.StoreInt32(&w.updating, 0)
+			}()
 		}
 	}
 
 	w.Tick += math2.DeltaTime
 }
 
+// Atualiza a areia e a água dos chunks, um por vez com chunksLock, para que o
+// worker de RequestPopulatedBlocks nunca veja um chunk pela metade
+func (w *World) updateChunks(chunks []*chunk.Chunk) {
+	for _, c := range chunks {
+		w.chunksLock.Lock()
+		c.Update()
+		c.SetWatersUpdate()
+		w.chunksLock.Unlock()
+	}
+}
+
+// Draw renders the distant terrain and then the populated blocks, one render
+// pass at a time
+func (w *World) Draw() {
+	if w.LOD != nil {
+		w.LOD.Draw(*collisions.NewFrustumCollider(camera.ActiveCamera.GetFrustum()))
+	}
+
+	for pass := render.PassOpaque; pass < render.NumPasses && len(w.PopulatedBlocks) == int(render.NumPasses); pass++ {
+		pass.Begin()
+
+		blocks := w.PopulatedBlocks[pass]
+		if pass == render.PassTranslucent && w.Translucent != nil {
+			blocks = w.Translucent.Sorted(camera.ActiveCamera.Position.Vec3())
+		}
+		for _, block := range blocks {
+			block.Draw2()
+		}
+	}
+	render.End()
+}
+
+// DrawShadowCasters draws the depth of the opaque and cutout blocks being
+// rendered, for the shadow map passes
+func (w *World) DrawShadowCasters(program *shaders.Program) {
+	for pass := render.PassOpaque; pass <= render.PassCutout && int(pass) < len(w.PopulatedBlocks); pass++ {
+		for _, block := range w.PopulatedBlocks[pass] {
+			block.DrawDepth(program)
+		}
+	}
+}
+
 // gets a x,z chunk
 func (w *World) GetChunk(x, z int) *chunk.Chunk {
+	w.chunksLock.RLock()
+	defer w.chunksLock.RUnlock()
+
+	return w.getChunk(x, z)
+}
+
+// GetChunk sem chunksLock, para quem já o segura
+func (w *World) getChunk(x, z int) *chunk.Chunk {
 	chunkRow, chunkColumn := int(math.Floor(float64(x)/float64(configs.ChunkSize))), int(math.Floor(float64(z)/float64(configs.ChunkSize)))
 	if len(w.Chunks[chunkRow]) == 0 {
 		return nil
@@ -349,7 +729,10 @@ func (w *World) GetChunk(x, z int) *chunk.Chunk {
 
 // gets a block at a given position, computing the chunk and getting the block inside the computed chunk
 func (w *World) GetBlockAt(x, y, z int) *block.Block {
-	chunk := w.GetChunk(x, z)
+	w.chunksLock.RLock()
+	defer w.chunksLock.RUnlock()
+
+	chunk := w.getChunk(x, z)
 	if chunk == nil {
 		return nil
 	}
@@ -359,14 +742,34 @@ func (w *World) GetBlockAt(x, y, z int) *block.Block {
 	return blockToReturn
 }
 
+// IsSolidAt tells whether the block at a given position stops particles and other small objects
+func (w *World) IsSolidAt(x, y, z int) bool {
+	b := w.GetBlockAt(x, y, z)
+	return b != nil && b.BlockType != block.BlockAir && b.BlockType != block.BlockWater && !b.IsFalling
+}
+
 func (w *World) RemoveBlockFrom(position *mgl32.Vec4) {
 	chunk := w.GetChunk(int(position.X()), int(position.Z()))
 	if chunk == nil {
 		return
 	}
 
+	x, y, z := int(position.X()), int(position.Y()), int(position.Z())
+
+	w.chunksLock.Lock()
+	removed := chunk.GetBlockAt(x, y, z)
 	chunk.RemoveBlockFrom(*position)
-	go w.SetPopulatedBlocks(chunk.Offset[0], chunk.Offset[1])
+	broken := removed != nil && chunk.GetBlockAt(x, y, z) == nil
+	if broken {
+		w.refreshBorder(x, z)
+	}
+	w.chunksLock.Unlock()
+
+	w.RequestPopulatedBlocks(chunk.Offset[0], chunk.Offset[1], viewDistance())
+
+	if broken {
+		w.Particles.Emit(particles.BlockBreak(position.Vec3(), block.ParticleTexture(removed.BlockType)))
+	}
 }
 
 // add a block at a given position, computing the chunk and getting the block inside the computed chunk
@@ -376,6 +779,14 @@ func (w *World) AddBlockAt(position mgl32.Vec3, ephemeral bool, blockType block.
 		return
 	}
 
+	w.chunksLock.Lock()
 	chunk.AddBlockAt(position, ephemeral, blockType)
-	go w.SetPopulatedBlocks(chunk.Offset[0], chunk.Offset[1])
+	w.refreshBorder(int(position.X()), int(position.Z()))
+	w.chunksLock.Unlock()
+
+	w.RequestPopulatedBlocks(chunk.Offset[0], chunk.Offset[1], viewDistance())
+
+	if !ephemeral {
+		w.Particles.Emit(particles.BlockPlace(position, block.ParticleTexture(blockType)))
+	}
 }
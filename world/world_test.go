@@ -0,0 +1,93 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/reonardoleis/fcg-glcraft/block"
+	"github.com/reonardoleis/fcg-glcraft/world/chunk"
+)
+
+// Mundo plano só com os chunks de -1 a 1 em x e z, ligados entre si
+func newBorderWorld() *World {
+	w := NewWorld("bordas", mgl32.Vec3{}, 1, chunk.GeneratorFlat)
+	w.Chunks = map[int]map[int]*chunk.Chunk{}
+	var keys [][2]int
+	for i := -1; i <= 1; i++ {
+		w.Chunks[i] = map[int]*chunk.Chunk{}
+		for j := -1; j <= 1; j++ {
+			w.Chunks[i][j] = w.NewGeneratedChunk(i, j)
+			keys = append(keys, [2]int{i, j})
+		}
+	}
+	w.stitchChunks(keys)
+
+	return w
+}
+
+func TestChunkBorderFaces(t *testing.T) {
+	const y = chunk.FlatHeight + 3
+
+	tests := []struct {
+		name       string
+		a, b       [3]int // blocos vizinhos, b logo depois de a na direção de face
+		face       int    // face de a voltada para b, índice de Neighbors
+		typeA      block.BlockType
+		typeB      block.BlockType
+		wantHidden bool
+	}{
+		{"vidro na borda em x", [3]int{15, y, 4}, [3]int{16, y, 4}, 0, block.BlockGlass, block.BlockGlass, true},
+		{"vidro na borda negativa", [3]int{-1, y, 4}, [3]int{0, y, 4}, 0, block.BlockGlass, block.BlockGlass, true},
+		{"água na borda em z", [3]int{4, y, 15}, [3]int{4, y, 16}, 2, block.BlockWater, block.BlockWater, true},
+		{"água na borda negativa em z", [3]int{4, y, -1}, [3]int{4, y, 0}, 2, block.BlockWater, block.BlockWater, true},
+		{"vidro e água na borda", [3]int{15, y, 4}, [3]int{16, y, 4}, 0, block.BlockGlass, block.BlockWater, false},
+		{"vidro dentro do chunk", [3]int{5, y, 4}, [3]int{6, y, 4}, 0, block.BlockGlass, block.BlockGlass, true},
+	}
+
+	for _, test := range tests {
+		w := newBorderWorld()
+		if _, err := w.SetBlock(test.a[0], test.a[1], test.a[2], test.typeA); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.SetBlock(test.b[0], test.b[1], test.b[2], test.typeB); err != nil {
+			t.Fatal(err)
+		}
+
+		a, b := w.GetBlockAt(test.a[0], test.a[1], test.a[2]), w.GetBlockAt(test.b[0], test.b[1], test.b[2])
+		opposite := test.face ^ 1
+		if hidden := a.Neighbors[test.face] == 1; hidden != test.wantHidden {
+			t.Errorf("%s: face %d of %v hidden = %v, want %v", test.name, test.face, test.a, hidden, test.wantHidden)
+		}
+		if hidden := b.Neighbors[opposite] == 1; hidden != test.wantHidden {
+			t.Errorf("%s: face %d of %v hidden = %v, want %v", test.name, opposite, test.b, hidden, test.wantHidden)
+		}
+
+		// sem o vizinho, a face volta a ser desenhada
+		if _, err := w.SetBlock(test.b[0], test.b[1], test.b[2], block.BlockAir); err != nil {
+			t.Fatal(err)
+		}
+		if a := w.GetBlockAt(test.a[0], test.a[1], test.a[2]); a.Neighbors[test.face] != 0 {
+			t.Errorf("%s: face %d of %v still hidden after its neighbor was removed", test.name, test.face, test.a)
+		}
+	}
+}
+
+func TestChunkBorderFacesAfterLoading(t *testing.T) {
+	// o chunk 2 é carregado depois que o vidro do chunk 1 já está na borda
+	w := newBorderWorld()
+	if _, err := w.SetBlock(31, chunk.FlatHeight+1, 0, block.BlockGlass); err != nil {
+		t.Fatal(err)
+	}
+
+	w.HandleChunkChange(2, 0)
+	next := w.FutureChunks[2][0]
+	next.SetBlockType(0, chunk.FlatHeight+1, 0, block.BlockGlass)
+	w.SwapFutureChunks()
+
+	if glass := w.GetBlockAt(31, chunk.FlatHeight+1, 0); glass.Neighbors[0] != 1 {
+		t.Error("glass face towards the chunk loaded later is still drawn")
+	}
+	if glass := w.GetBlockAt(32, chunk.FlatHeight+1, 0); glass.Neighbors[1] != 1 {
+		t.Error("glass face of the chunk loaded later is still drawn")
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/reonardoleis/fcg-glcraft/configs"
+	"github.com/reonardoleis/fcg-glcraft/engine/scene"
+	"github.com/reonardoleis/fcg-glcraft/player"
+	"github.com/reonardoleis/fcg-glcraft/world/chunk"
+	"github.com/reonardoleis/fcg-glcraft/world/saves"
+)
+
+// newWorld creates a world folder, as the create world screen does, so that it
+// can be played later with play -world
+func newWorld(args []string) int {
+	flags := newFlagSet("new-world", "NAME")
+	savesDir := flags.String("dir", saves.DefaultDir, "pasta dos mundos salvos")
+	seed := flags.String("seed", "", "semente do mundo; texto é convertido, vazio é aleatória")
+	generator := flags.String("generator", chunk.GeneratorDefault, "gerador do terreno")
+	var overrides overrideFlag
+	flags.Var(&overrides, "set", "muda um parâmetro do mundo, como world.cave_threshold=0.5; pode repetir")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if flags.NArg() != 1 {
+		return usageError(flags, "expected one world name, got %d arguments", flags.NArg())
+	}
+	if !validGenerator(*generator) {
+		return usageError(flags, "unknown generator %q, expected one of %v", *generator, chunk.Generators)
+	}
+
+	config := configs.DefaultWorld()
+	rest, err := overrides.applyWorld(&config)
+	if err != nil {
+		return usageError(flags, "%v", err)
+	}
+	if len(rest) > 0 {
+		return usageError(flags, "only world.* options can be set, got %q", rest[0].key)
+	}
+
+	name := flags.Arg(0)
+	if saves.Exists(*savesDir, name) {
+		log.Printf("world %q already exists in %s", name, *savesDir)
+		return exitError
+	}
+
+	now := time.Now()
+	info := scene.NewWorldInfo(name, scene.ParseSeed(*seed, now), *generator, config, now)
+	if err := saves.Save(*savesDir, info); err != nil {
+		log.Println(err)
+		return exitError
+	}
+
+	fmt.Printf("created world %q with seed %d\n", info.Name, info.Seed)
+	return exitOK
+}
+
+// info prints the saved information of the named worlds, or a summary of all
+// of them without names
+func info(args []string) int {
+	flags := newFlagSet("info", "[NAME...]")
+	savesDir := flags.String("dir", saves.DefaultDir, "pasta dos mundos salvos")
+	asJSON := flags.Bool("json", false, "escreve as informações em JSON")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	var worlds []saves.WorldInfo
+	if flags.NArg() == 0 {
+		all, err := saves.List(*savesDir)
+		if err != nil {
+			log.Println(err)
+			return exitError
+		}
+		worlds = all
+	}
+	for _, name := range flags.Args() {
+		world, err := saves.Load(*savesDir, name)
+		if err != nil {
+			log.Println(err)
+			return exitError
+		}
+		worlds = append(worlds, world)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(worlds); err != nil {
+			log.Println(err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	// sem nomes, uma linha por mundo
+	if flags.NArg() == 0 {
+		if len(worlds) == 0 {
+			fmt.Printf("no worlds in %s\n", *savesDir)
+			return exitOK
+		}
+
+		table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "NAME\tSEED\tGENERATOR\tLAST PLAYED")
+		for _, world := range worlds {
+			fmt.Fprintf(table, "%s\t%d\t%s\t%s\n", world.Name, world.Seed, world.Generator, formatTime(world.LastPlayed))
+		}
+		table.Flush()
+		return exitOK
+	}
+
+	for i, world := range worlds {
+		if i > 0 {
+			fmt.Println()
+		}
+
+		table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(table, "name:\t%s\n", world.Name)
+		fmt.Fprintf(table, "version:\t%d\n", world.Version)
+		fmt.Fprintf(table, "seed:\t%d\n", world.Seed)
+		fmt.Fprintf(table, "generator:\t%s\n", world.Generator)
+		fmt.Fprintf(table, "size:\t%v\n", world.Config.Size)
+		fmt.Fprintf(table, "player:\t%v\n", world.PlayerPosition)
+		fmt.Fprintf(table, "game mode:\t%s\n", gameModeName(player.GameMode(world.GameMode)))
+		fmt.Fprintf(table, "time:\t%.0fs\n", world.Time)
+		fmt.Fprintf(table, "created:\t%s\n", formatTime(world.Created))
+		fmt.Fprintf(table, "last played:\t%s\n", formatTime(world.LastPlayed))
+		table.Flush()
+	}
+
+	return exitOK
+}
+
+func gameModeName(mode player.GameMode) string {
+	if mode == player.Creative {
+		return "creative"
+	}
+
+	return "survival"
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+
+	return t.Local().Format("2006-01-02 15:04")
+}